@@ -51,7 +51,12 @@ func Main(version string, cliCtx *cli.Context) error {
 			return err
 		}
 	}
-	defer batchSubmitter.StopIfRunning()
+	defer func() {
+		batchSubmitter.StopIfRunning()
+		if err := batchSubmitter.Close(); err != nil {
+			l.Error("Unable to close Batch Submitter", "error", err)
+		}
+	}()
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -0,0 +1,288 @@
+package batcher
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// channel is a lightweight wrapper around a channelBuilder which keeps track of
+// the pending and confirmed transactions of a single channel.
+//
+// Functions on channel are not safe for concurrent access.
+type channel struct {
+	log  log.Logger
+	metr metrics.Metricer
+	cfg  ChannelConfig
+
+	// pending channel builder
+	channelBuilder *channelBuilder
+	// Set of unconfirmed txID -> frame data. For tx resubmission
+	pendingTransactions map[txID]txData
+	// Set of unconfirmed txID -> tx hashes of all published versions of the
+	// sent tx, oldest first. For journaling
+	pendingTxHashes map[txID][]common.Hash
+	// Set of confirmed txID -> inclusion block. For determining if the channel is timed out
+	confirmedTransactions map[txID]eth.BlockID
+	// Set of confirmed txID -> frame data. For tx resubmission after an L1 reorg
+	confirmedTxData map[txID]txData
+}
+
+func newChannel(log log.Logger, metr metrics.Metricer, cfg ChannelConfig) (*channel, error) {
+	cb, err := newChannelBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &channel{
+		log:                   log,
+		metr:                  metr,
+		cfg:                   cfg,
+		channelBuilder:        cb,
+		pendingTransactions:   make(map[txID]txData),
+		pendingTxHashes:       make(map[txID][]common.Hash),
+		confirmedTransactions: make(map[txID]eth.BlockID),
+		confirmedTxData:       make(map[txID]txData),
+	}, nil
+}
+
+// restoreChannel restores a closed channel from its journaled state. The given
+// blocks must be the channel's blocks as referenced by the journal. Frames
+// that are confirmed in the journal or that are found in landed are restored
+// as confirmed transactions. Frames found in pending are restored as pending
+// transactions with their journaled transaction hashes, because their
+// transactions are still waiting for inclusion. All other frames are queued
+// for resubmission.
+func restoreChannel(log log.Logger, metr metrics.Metricer, cfg ChannelConfig, jc journalChannel, blocks []*types.Block, landed map[txID]eth.BlockID, pending map[txID]struct{}) (*channel, error) {
+	if len(blocks) != len(jc.Blocks) {
+		return nil, fmt.Errorf("expected %d blocks for channel %s, got %d", len(jc.Blocks), jc.ID, len(blocks))
+	}
+	confirmedTransactions := make(map[txID]eth.BlockID)
+	confirmedTxData := make(map[txID]txData)
+	pendingTransactions := make(map[txID]txData)
+	pendingTxHashes := make(map[txID][]common.Hash)
+	var (
+		frames      []frameData
+		outputBytes int
+	)
+	for _, f := range jc.Frames {
+		frame := frameData{
+			id:   frameID{chID: jc.ID, frameNumber: f.Number},
+			data: f.Data,
+		}
+		outputBytes += len(frame.data)
+		if f.InclusionBlock != nil {
+			confirmedTransactions[frame.id] = *f.InclusionBlock
+		} else if inclusionBlock, ok := landed[frame.id]; ok {
+			confirmedTransactions[frame.id] = inclusionBlock
+		} else if _, ok := pending[frame.id]; ok {
+			pendingTransactions[frame.id] = txData{frame}
+			pendingTxHashes[frame.id] = f.TxHashes
+			continue
+		} else {
+			frames = append(frames, frame)
+			continue
+		}
+		confirmedTxData[frame.id] = txData{frame}
+	}
+
+	cb, err := restoreChannelBuilder(cfg, jc.ID, blocks, frames, outputBytes)
+	if err != nil {
+		return nil, err
+	}
+	for _, inclusionBlock := range confirmedTransactions {
+		cb.FramePublished(inclusionBlock.Number)
+	}
+	return &channel{
+		log:                   log,
+		metr:                  metr,
+		cfg:                   cfg,
+		channelBuilder:        cb,
+		pendingTransactions:   pendingTransactions,
+		pendingTxHashes:       pendingTxHashes,
+		confirmedTransactions: confirmedTransactions,
+		confirmedTxData:       confirmedTxData,
+	}, nil
+}
+
+// ID returns the channel ID.
+func (c *channel) ID() derive.ChannelID {
+	return c.channelBuilder.ID()
+}
+
+// TxFailed records a transaction as failed. It will attempt to resubmit the data
+// in the failed transaction.
+func (c *channel) TxFailed(id txID) {
+	if data, ok := c.pendingTransactions[id]; ok {
+		c.log.Trace("marked transaction as failed", "id", id)
+		// Note: when the batcher is changed to send multiple frames per tx,
+		// this needs to be changed to iterate over all frames of the tx data
+		// and re-queue them.
+		c.channelBuilder.PushFrame(data.Frame())
+		delete(c.pendingTransactions, id)
+		delete(c.pendingTxHashes, id)
+	} else {
+		c.log.Warn("unknown transaction marked as failed", "id", id)
+	}
+	c.recordTxs()
+}
+
+// TxDeferred re-queues the frame of a pending transaction whose submission got
+// deferred before it was sent.
+func (c *channel) TxDeferred(id txID) {
+	if data, ok := c.pendingTransactions[id]; ok {
+		c.log.Trace("marked transaction as deferred", "id", id)
+		c.channelBuilder.PushFrame(data.Frame())
+		delete(c.pendingTransactions, id)
+		delete(c.pendingTxHashes, id)
+	} else {
+		c.log.Warn("unknown transaction marked as deferred", "id", id)
+	}
+	c.recordTxs()
+}
+
+// TxConfirmed marks a transaction as confirmed on L1. Unfortunately even if all frames in
+// a channel have been marked as confirmed on L1 the channel may be invalid & need to be
+// resubmitted. Use isTimedOut to check for this case.
+func (c *channel) TxConfirmed(id txID, inclusionBlock eth.BlockID) {
+	data, ok := c.pendingTransactions[id]
+	if !ok {
+		c.log.Warn("unknown transaction marked as confirmed", "id", id, "block", inclusionBlock)
+		return
+	}
+	c.log.Debug("marked transaction as confirmed", "id", id, "block", inclusionBlock)
+	delete(c.pendingTransactions, id)
+	delete(c.pendingTxHashes, id)
+	c.confirmedTransactions[id] = inclusionBlock
+	c.confirmedTxData[id] = data
+	c.channelBuilder.FramePublished(inclusionBlock.Number)
+	c.recordTxs()
+}
+
+// rewindReorgedTxs re-queues the frames of all confirmed transactions whose
+// inclusion block got reorged out of L1, as reported by reorged. The
+// transactions of reorged blocks have to be assumed to be lost.
+//
+// Note that resubmitting a frame that actually landed is harmless, as duplicate
+// frames are ignored during derivation.
+//
+// It returns whether any transaction got rewound.
+func (c *channel) rewindReorgedTxs(reorged func(inclusionBlock eth.BlockID) bool) bool {
+	rewound := false
+	for id, inclusionBlock := range c.confirmedTransactions {
+		if !reorged(inclusionBlock) {
+			continue
+		}
+		c.log.Warn("confirmed transaction reorged out, resubmitting",
+			"id", id, "block", inclusionBlock)
+		data := c.confirmedTxData[id]
+		c.channelBuilder.PushFrame(data.Frame())
+		delete(c.confirmedTransactions, id)
+		delete(c.confirmedTxData, id)
+		rewound = true
+	}
+	if rewound {
+		c.recordTxs()
+	}
+	return rewound
+}
+
+// isTimedOut returns true if submitted channel has timed out.
+// A channel has timed out if the difference in L1 Inclusion blocks between
+// the first & last included block is greater than or equal to the channel timeout.
+func (c *channel) isTimedOut() bool {
+	// No confirmed transactions => not timed out
+	if len(c.confirmedTransactions) == 0 {
+		return false
+	}
+	// If there are confirmed transactions, find the first + last confirmed block numbers
+	min := uint64(math.MaxUint64)
+	max := uint64(0)
+	for _, inclusionBlock := range c.confirmedTransactions {
+		if inclusionBlock.Number < min {
+			min = inclusionBlock.Number
+		}
+		if inclusionBlock.Number > max {
+			max = inclusionBlock.Number
+		}
+	}
+	return max-min >= c.cfg.ChannelTimeout
+}
+
+// isFullySubmitted returns true if the channel has been fully submitted.
+func (c *channel) isFullySubmitted() bool {
+	return c.channelBuilder.IsFull() && len(c.pendingTransactions)+c.channelBuilder.NumFrames() == 0
+}
+
+// HasFrame returns whether there's any frame of this channel ready to be sent.
+func (c *channel) HasFrame() bool {
+	return c.channelBuilder.HasFrame()
+}
+
+// NextTxData pops the next frame off the channel builder & registers it as a
+// pending transaction. HasFrame must be called prior to check if there's a next
+// frame available.
+func (c *channel) NextTxData() txData {
+	frame := c.channelBuilder.NextFrame()
+	txdata := txData{frame}
+	id := txdata.ID()
+
+	c.log.Trace("returning next tx data", "id", id)
+	c.pendingTransactions[id] = txdata
+	c.recordTxs()
+	return txdata
+}
+
+// TxSent records the hash of a transaction that got published for the given
+// pending transaction. All published hashes are kept, because any of them may
+// get included. It returns whether the hash wasn't known yet.
+func (c *channel) TxSent(id txID, txHash common.Hash) bool {
+	if _, ok := c.pendingTransactions[id]; !ok {
+		return false
+	}
+	for _, h := range c.pendingTxHashes[id] {
+		if h == txHash {
+			return false
+		}
+	}
+	c.pendingTxHashes[id] = append(c.pendingTxHashes[id], txHash)
+	return true
+}
+
+// journalState returns the state of this channel to be journaled.
+func (c *channel) journalState() journalChannel {
+	jc := journalChannel{ID: c.ID()}
+	for _, b := range c.channelBuilder.Blocks() {
+		jc.Blocks = append(jc.Blocks, eth.ToBlockID(b))
+	}
+	for _, f := range c.channelBuilder.frames {
+		jc.Frames = append(jc.Frames, journalFrame{Number: f.id.frameNumber, Data: f.data})
+	}
+	for id, data := range c.pendingTransactions {
+		jf := journalFrame{Number: id.frameNumber, Data: data.frame.data}
+		jf.TxHashes = c.pendingTxHashes[id]
+		jc.Frames = append(jc.Frames, jf)
+	}
+	for id, data := range c.confirmedTxData {
+		inclusionBlock := c.confirmedTransactions[id]
+		jc.Frames = append(jc.Frames, journalFrame{
+			Number:         id.frameNumber,
+			Data:           data.frame.data,
+			InclusionBlock: &inclusionBlock,
+		})
+	}
+	sort.Slice(jc.Frames, func(i, j int) bool {
+		return jc.Frames[i].Number < jc.Frames[j].Number
+	})
+	return jc
+}
+
+func (c *channel) recordTxs() {
+	c.metr.RecordChannelTxs(c.ID(), len(c.pendingTransactions), len(c.confirmedTransactions))
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 var (
@@ -20,6 +21,7 @@ var (
 	ErrMaxDurationReached    = errors.New("max channel duration reached")
 	ErrChannelTimeoutClose   = errors.New("close to channel timeout")
 	ErrSeqWindowClose        = errors.New("close to sequencer window timeout")
+	ErrChannelRestored       = errors.New("channel restored from journal")
 )
 
 type ChannelFullError struct {
@@ -69,6 +71,20 @@ type ChannelConfig struct {
 	// average from experiments to avoid the chances of creating a small
 	// additional leftover frame.
 	ApproxComprRatio float64
+
+	// CompressionAlgo is the compression algorithm to use for channels. Channels
+	// are only compressed with algorithms other than zlib once versioned channels
+	// are active, see ChannelCompressionTime.
+	CompressionAlgo derive.CompressionAlgo
+	// ChannelCompressionTime is the activation time of versioned channels, as
+	// set in the rollup config.
+	ChannelCompressionTime *uint64
+	// ShadowCompression enables exact-size channel filling. The compressed size
+	// of a channel is measured with a shadow compressor, which is flushed after
+	// each block, and a block is only added to the channel if the channel then
+	// still fits into the OutputThreshold. If enabled, the ApproxComprRatio
+	// isn't used.
+	ShadowCompression bool
 }
 
 // Check validates the [ChannelConfig] parameters.
@@ -103,6 +119,34 @@ func (c ChannelConfig) InputThreshold() uint64 {
 	return uint64(float64(c.TargetNumFrames) * float64(c.TargetFrameSize) / c.ApproxComprRatio)
 }
 
+// OutputThreshold calculates the compressed output data threshold in bytes
+// from the given parameters. It is the amount of data that fits into the target
+// number of frames of the target frame size. It is used instead of the
+// InputThreshold if shadow compression is enabled.
+func (c ChannelConfig) OutputThreshold() uint64 {
+	if c.TargetFrameSize <= derive.FrameV0OverHeadSize {
+		return 0
+	}
+	return uint64(c.TargetNumFrames) * (c.TargetFrameSize - derive.FrameV0OverHeadSize)
+}
+
+// IsChannelCompression returns whether versioned channels are active at the
+// given L1 timestamp.
+func (c ChannelConfig) IsChannelCompression(l1Time uint64) bool {
+	return c.ChannelCompressionTime != nil && l1Time >= *c.ChannelCompressionTime
+}
+
+// newChannelOut creates a new channel out with the configured compression
+// algorithm. Zlib channels are created as legacy channels, without a version
+// byte, so that they are valid both before and after the activation of
+// versioned channels.
+func (c ChannelConfig) newChannelOut() (*derive.ChannelOut, error) {
+	if c.CompressionAlgo == derive.Zlib {
+		return derive.NewChannelOut()
+	}
+	return derive.NewVersionedChannelOut(c.CompressionAlgo)
+}
+
 type frameID struct {
 	chID        derive.ChannelID
 	frameNumber uint16
@@ -126,12 +170,31 @@ type channelBuilder struct {
 	timeout uint64
 	// reason for currently set timeout
 	timeoutReason error
+	// L1 block number by which the channel must be fully submitted at the
+	// latest, which is the earlier of the sequencing window end of its oldest
+	// batch and the consensus channel timeout of its first published frame.
+	// Unlike timeout, it doesn't include the SubSafetyMargin.
+	// 0 if no deadline set yet.
+	deadline uint64
 
 	// Reason for the channel being full. Set by setFullErr so it's always
 	// guaranteed to be a ChannelFullError wrapping the specific reason.
 	fullErr error
+	// whether the channel out got closed and all frames were output
+	closed bool
+	// channel id, kept separately from the channel out for restored channels
+	id derive.ChannelID
 	// current channel
 	co *derive.ChannelOut
+	// shadow compressor, only set if shadow compression is enabled. It receives
+	// the same input as the channel out, but is flushed after every block, so
+	// that its output size is the exact compressed size of the channel so far.
+	shadow derive.Compressor
+	// output of the shadow compressor, which is only counted
+	shadowOut countingWriter
+	// compressed size of the blocks added to the channel so far, as measured by
+	// the shadow compressor
+	compressedBytes int
 	// list of blocks in the channel. Saved in case the channel must be rebuilt
 	blocks []*types.Block
 	// frames data queue, to be send as txs
@@ -143,19 +206,58 @@ type channelBuilder struct {
 // newChannelBuilder creates a new channel builder or returns an error if the
 // channel out could not be created.
 func newChannelBuilder(cfg ChannelConfig) (*channelBuilder, error) {
-	co, err := derive.NewChannelOut()
+	co, err := cfg.newChannelOut()
 	if err != nil {
 		return nil, err
 	}
 
-	return &channelBuilder{
+	c := &channelBuilder{
 		cfg: cfg,
+		id:  co.ID(),
 		co:  co,
-	}, nil
+	}
+	if cfg.ShadowCompression {
+		if c.shadow, err = derive.NewCompressor(co.Algo(), &c.shadowOut); err != nil {
+			return nil, fmt.Errorf("creating shadow compressor: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// restoreChannelBuilder creates a closed channel builder for a channel that got
+// restored from the journal. It holds the given blocks and the given frames,
+// which haven't been confirmed yet, and doesn't accept any more blocks. The
+// sequencing window timeout is restored from the blocks, the channel timeout
+// has to be restored by calling FramePublished for all confirmed frames.
+func restoreChannelBuilder(cfg ChannelConfig, id derive.ChannelID, blocks []*types.Block, frames []frameData, outputBytes int) (*channelBuilder, error) {
+	// The compression state cannot be restored, so the channel out stays
+	// empty. It is only used for reporting the input and ready bytes.
+	co, err := derive.NewChannelOut()
+	if err != nil {
+		return nil, err
+	}
+	c := &channelBuilder{
+		cfg:         cfg,
+		id:          id,
+		co:          co,
+		closed:      true,
+		blocks:      blocks,
+		frames:      frames,
+		outputBytes: outputBytes,
+	}
+	for _, block := range blocks {
+		batch, _, err := derive.BlockToBatch(block)
+		if err != nil {
+			return nil, fmt.Errorf("converting block to batch: %w", err)
+		}
+		c.updateSwTimeout(batch)
+	}
+	c.setFullErr(ErrChannelRestored)
+	return c, nil
 }
 
 func (c *channelBuilder) ID() derive.ChannelID {
-	return c.co.ID()
+	return c.id
 }
 
 // InputBytes returns the total amount of input bytes added to the channel.
@@ -185,8 +287,17 @@ func (c *channelBuilder) Reset() error {
 	c.blocks = c.blocks[:0]
 	c.frames = c.frames[:0]
 	c.timeout = 0
+	c.deadline = 0
 	c.fullErr = nil
-	return c.co.Reset()
+	c.closed = false
+	if c.shadow != nil {
+		c.shadowOut.n = 0
+		c.compressedBytes = 0
+		c.shadow.Reset(&c.shadowOut)
+	}
+	err := c.co.Reset()
+	c.id = c.co.ID()
+	return err
 }
 
 // AddBlock adds a block to the channel compression pipeline. IsFull should be
@@ -194,7 +305,10 @@ func (c *channelBuilder) Reset() error {
 // must be started.
 //
 // AddBlock returns a ChannelFullError if called even though the channel is
-// already full. See description of FullErr for details.
+// already full. See description of FullErr for details. If shadow compression
+// is enabled, it also returns a ChannelFullError if the block would make the
+// channel exceed the target output size. The block is not added in this case
+// and the channel is full afterwards.
 //
 // AddBlock also returns the L1BlockInfo that got extracted from the block's
 // first transaction for subsequent use by the caller.
@@ -210,12 +324,26 @@ func (c *channelBuilder) AddBlock(block *types.Block) (derive.L1BlockInfo, error
 		return l1info, fmt.Errorf("converting block to batch: %w", err)
 	}
 
+	var compressedBytes int
+	if c.shadow != nil {
+		if compressedBytes, err = c.shadowCompress(batch); err != nil {
+			return l1info, err
+		}
+		// The first block is always added, so that a single block exceeding the
+		// target can't stall the batcher. It ends up in a channel of its own.
+		if len(c.blocks) > 0 && !c.fitsOutputThreshold(compressedBytes) {
+			c.setFullErr(ErrInputTargetReached)
+			return l1info, c.FullErr()
+		}
+	}
+
 	if _, err = c.co.AddBatch(batch); errors.Is(err, derive.ErrTooManyRLPBytes) {
 		c.setFullErr(err)
 		return l1info, c.FullErr()
 	} else if err != nil {
 		return l1info, fmt.Errorf("adding block to channel out: %w", err)
 	}
+	c.compressedBytes = compressedBytes
 	c.blocks = append(c.blocks, block)
 	c.updateSwTimeout(batch)
 
@@ -244,6 +372,7 @@ func (c *channelBuilder) RegisterL1Block(l1BlockNum uint64) {
 func (c *channelBuilder) FramePublished(l1BlockNum uint64) {
 	timeout := l1BlockNum + c.cfg.ChannelTimeout - c.cfg.SubSafetyMargin
 	c.updateTimeout(timeout, ErrChannelTimeoutClose)
+	c.updateDeadline(l1BlockNum + c.cfg.ChannelTimeout)
 }
 
 // updateDurationTimeout updates the block timeout with the channel duration
@@ -266,6 +395,7 @@ func (c *channelBuilder) updateDurationTimeout(l1BlockNum uint64) {
 func (c *channelBuilder) updateSwTimeout(batch *derive.BatchData) {
 	timeout := uint64(batch.EpochNum) + c.cfg.SeqWindowSize - c.cfg.SubSafetyMargin
 	c.updateTimeout(timeout, ErrSeqWindowClose)
+	c.updateDeadline(uint64(batch.EpochNum) + c.cfg.SeqWindowSize)
 }
 
 // updateTimeout updates the timeout block to the given block number if it is
@@ -280,6 +410,21 @@ func (c *channelBuilder) updateTimeout(timeoutBlockNum uint64, reason error) {
 	}
 }
 
+// updateDeadline updates the deadline to the given block number if it is
+// earlier than the current deadline, or if it is still unset.
+func (c *channelBuilder) updateDeadline(deadlineBlockNum uint64) {
+	if c.deadline == 0 || c.deadline > deadlineBlockNum {
+		c.deadline = deadlineBlockNum
+	}
+}
+
+// CloseToDeadline returns whether the passed L1 block number is within the
+// SubSafetyMargin of the channel's deadline. If no deadline is set yet, it
+// returns false.
+func (c *channelBuilder) CloseToDeadline(l1BlockNum uint64) bool {
+	return c.deadline != 0 && l1BlockNum+c.cfg.SubSafetyMargin >= c.deadline
+}
+
 // checkTimeout checks if the channel is timed out at the given block number and
 // in this case marks the channel as full, if it wasn't full already.
 func (c *channelBuilder) checkTimeout(blockNum uint64) {
@@ -294,12 +439,63 @@ func (c *channelBuilder) TimedOut(blockNum uint64) bool {
 	return c.timeout != 0 && blockNum >= c.timeout
 }
 
+// compressorCloseMargin is the number of bytes reserved for the output that a
+// compressor writes when it is closed, on top of its flushed output. Zlib and
+// zstd both write at most a final empty block and a 4 byte checksum.
+const compressorCloseMargin = 16
+
+// shadowCompress adds the batch to the shadow compressor and flushes it. It
+// returns the resulting compressed size of the channel, including its version
+// byte.
+//
+// Note that the batch can't be removed from the shadow compressor anymore. If
+// it doesn't get added to the channel, the channel must be closed.
+func (c *channelBuilder) shadowCompress(batch *derive.BatchData) (int, error) {
+	if err := rlp.Encode(c.shadow, batch); err != nil {
+		return 0, fmt.Errorf("adding batch to shadow compressor: %w", err)
+	}
+	if err := c.shadow.Flush(); err != nil {
+		return 0, fmt.Errorf("flushing shadow compressor: %w", err)
+	}
+	n := c.shadowOut.n
+	if c.co.Versioned() {
+		n++
+	}
+	return n, nil
+}
+
+// fitsOutputThreshold returns whether a channel of the given compressed size
+// fits into the output threshold, once it is closed.
+func (c *channelBuilder) fitsOutputThreshold(compressedBytes int) bool {
+	return uint64(compressedBytes+compressorCloseMargin) <= c.cfg.OutputThreshold()
+}
+
+// CompressedBytes returns the compressed size of the blocks added to the
+// channel so far, including its version byte, as measured by the shadow
+// compressor. It returns 0 if shadow compression is disabled.
+func (c *channelBuilder) CompressedBytes() int {
+	return c.compressedBytes
+}
+
 // inputTargetReached says whether the target amount of input data has been
 // reached in this channel builder. No more blocks can be added afterwards.
+//
+// If shadow compression is enabled, the target is reached once the channel
+// can't take any more compressed data. Otherwise, the input size is compared
+// to the target size as estimated with the approximate compression ratio.
 func (c *channelBuilder) inputTargetReached() bool {
+	if c.shadow != nil {
+		return !c.fitsOutputThreshold(c.compressedBytes + 1)
+	}
 	return uint64(c.co.InputBytes()) >= c.cfg.InputThreshold()
 }
 
+// IsClosed returns whether the channel got closed and all its frames have
+// been created.
+func (c *channelBuilder) IsClosed() bool {
+	return c.closed
+}
+
 // IsFull returns whether the channel is full.
 // FullErr returns the reason for the channel being full.
 func (c *channelBuilder) IsFull() bool {
@@ -367,6 +563,7 @@ func (c *channelBuilder) closeAndOutputAllFrames() error {
 
 	for {
 		if err := c.outputFrame(); err == io.EOF {
+			c.closed = true
 			return nil
 		} else if err != nil {
 			return err
@@ -396,7 +593,7 @@ func (c *channelBuilder) outputFrame() error {
 	}
 
 	frame := frameData{
-		id:   frameID{chID: c.co.ID(), frameNumber: fn},
+		id:   frameID{chID: c.id, frameNumber: fn},
 		data: buf.Bytes(),
 	}
 	c.frames = append(c.frames, frame)
@@ -438,3 +635,13 @@ func (c *channelBuilder) PushFrame(frame frameData) {
 	}
 	c.frames = append(c.frames, frame)
 }
+
+// countingWriter discards all data written to it, only counting its size.
+type countingWriter struct {
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
@@ -3,6 +3,7 @@ package batcher
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
@@ -637,6 +638,40 @@ func TestFramePublished(t *testing.T) {
 	require.Equal(t, uint64(1000), cb.timeout)
 }
 
+// TestChannelBuilder_CloseToDeadline tests the deadline boundary of
+// CloseToDeadline. The latest L1 block that a frame may safely be included in
+// is the deadline minus the SubSafetyMargin, so the channel becomes urgent
+// exactly at that block, but not one block before it.
+func TestChannelBuilder_CloseToDeadline(t *testing.T) {
+	for _, margin := range []uint64{0, 4} {
+		margin := margin
+		t.Run(fmt.Sprintf("margin=%d", margin), func(t *testing.T) {
+			require := require.New(t)
+			cfg := defaultTestChannelConfig
+			cfg.SubSafetyMargin = margin
+			cb, err := newChannelBuilder(cfg)
+			require.NoError(err)
+			require.False(cb.CloseToDeadline(math.MaxUint64-margin), "no deadline set yet")
+
+			// sequencing window deadline of epoch 100 is block 115
+			cb.updateSwTimeout(&derive.BatchData{BatchV1: derive.BatchV1{EpochNum: 100}})
+			maxInclusionBlock := 100 + cfg.SeqWindowSize - margin
+			require.False(cb.CloseToDeadline(maxInclusionBlock - 1))
+			require.True(cb.CloseToDeadline(maxInclusionBlock))
+			require.True(cb.CloseToDeadline(100 + cfg.SeqWindowSize))
+
+			// an earlier channel timeout of a published frame moves the deadline
+			// forward, a later one doesn't
+			cb.FramePublished(90 + cfg.SeqWindowSize - cfg.ChannelTimeout)
+			maxInclusionBlock -= 10
+			require.False(cb.CloseToDeadline(maxInclusionBlock - 1))
+			require.True(cb.CloseToDeadline(maxInclusionBlock))
+			cb.FramePublished(100)
+			require.False(cb.CloseToDeadline(maxInclusionBlock - 1))
+		})
+	}
+}
+
 func TestChannelBuilder_InputBytes(t *testing.T) {
 	require := require.New(t)
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -690,6 +725,71 @@ func TestChannelBuilder_OutputBytes(t *testing.T) {
 	require.Equal(cb.OutputBytes(), flen)
 }
 
+// TestChannelBuilder_ShadowCompression checks that a channel is filled up to,
+// but not beyond the target output size if shadow compression is enabled.
+func TestChannelBuilder_ShadowCompression(t *testing.T) {
+	for _, algo := range []derive.CompressionAlgo{derive.Zlib, derive.Zstd} {
+		algo := algo
+		t.Run(algo.String(), func(t *testing.T) {
+			require := require.New(t)
+			rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+			cfg := defaultTestChannelConfig
+			cfg.TargetFrameSize = 10_000
+			cfg.MaxFrameSize = 10_000
+			cfg.TargetNumFrames = 4
+			// would never be reached with shadow compression disabled
+			cfg.ApproxComprRatio = 0.0001
+			cfg.CompressionAlgo = algo
+			cfg.ShadowCompression = true
+			cb, err := newChannelBuilder(cfg)
+			require.NoError(err, "newChannelBuilder")
+			require.Zero(cb.CompressedBytes())
+
+			var numBlocks int
+			for {
+				block, _ := dtest.RandomL2Block(rng, rng.Intn(4))
+				if _, err := cb.AddBlock(block); errors.Is(err, ErrInputTargetReached) {
+					break
+				}
+				require.NoError(err)
+				numBlocks++
+				// only the first block may exceed the target on its own
+				if numBlocks > 1 {
+					require.LessOrEqual(uint64(cb.CompressedBytes()+compressorCloseMargin), cfg.OutputThreshold())
+				}
+			}
+			require.Len(cb.Blocks(), numBlocks, "block exceeding the target must not be added")
+			require.Greater(numBlocks, 1)
+
+			require.NoError(cb.OutputFrames())
+			require.LessOrEqual(cb.NumFrames(), cfg.TargetNumFrames)
+			for cb.HasFrame() {
+				require.LessOrEqual(len(cb.NextFrame().data), int(cfg.MaxFrameSize))
+			}
+		})
+	}
+}
+
+// TestChannelBuilder_ShadowCompressionLargeBlock checks that a first block,
+// which exceeds the target output size on its own, is still added.
+func TestChannelBuilder_ShadowCompressionLargeBlock(t *testing.T) {
+	require := require.New(t)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	cfg := defaultTestChannelConfig
+	cfg.TargetFrameSize = 100
+	cfg.MaxFrameSize = 100
+	cfg.TargetNumFrames = 1
+	cfg.ShadowCompression = true
+	cb, err := newChannelBuilder(cfg)
+	require.NoError(err, "newChannelBuilder")
+
+	block, _ := dtest.RandomL2Block(rng, 32)
+	_, err = cb.AddBlock(block)
+	require.NoError(err)
+	require.Len(cb.Blocks(), 1)
+	require.ErrorIs(cb.FullErr(), ErrInputTargetReached)
+}
+
 func defaultChannelBuilderSetup(t *testing.T) (*channelBuilder, ChannelConfig) {
 	t.Helper()
 	cfg := defaultTestChannelConfig
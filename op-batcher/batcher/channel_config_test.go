@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/batcher"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/stretchr/testify/require"
 )
 
@@ -127,3 +128,16 @@ func TestInputThreshold(t *testing.T) {
 		tt.assertion(got)
 	}
 }
+
+// TestOutputThreshold tests the [ChannelConfig.OutputThreshold] function.
+func TestOutputThreshold(t *testing.T) {
+	config := batcher.ChannelConfig{
+		TargetFrameSize: 1000,
+		TargetNumFrames: 3,
+	}
+	require.Equal(t, uint64(3*(1000-derive.FrameV0OverHeadSize)), config.OutputThreshold())
+
+	// frames smaller than the frame overhead can't hold any data
+	config.TargetFrameSize = derive.FrameV0OverHeadSize
+	require.Zero(t, config.OutputThreshold())
+}
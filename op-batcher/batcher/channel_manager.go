@@ -1,12 +1,15 @@
 package batcher
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"math"
+	"sort"
+	"sync"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum/go-ethereum/common"
@@ -14,16 +17,27 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
-var ErrReorg = errors.New("block does not extend existing chain")
+var (
+	ErrReorg         = errors.New("block does not extend existing chain")
+	ErrNoOpenChannel = errors.New("no open channel")
+	ErrEmptyChannel  = errors.New("channel has no blocks")
+	ErrForceClosed   = errors.New("channel force closed")
+)
 
 // channelManager stores a contiguous set of blocks & turns them into channels.
 // Upon receiving tx confirmation (or a tx failure), it does channel error handling.
 //
-// For simplicity, it only creates a single pending channel at a time & waits for
-// the channel to either successfully be submitted or timeout before creating a new
-// channel.
-// Functions on channelManager are not safe for concurrent access.
+// Multiple channels can be open at the same time: while the frames of full
+// channels are still being submitted and confirmed, new blocks are already
+// added to a fresh channel. Timeouts and resubmissions are tracked per channel.
+// Multiple transactions may be in flight at the same time, and their
+// confirmations or failures may be reported in any order.
+//
+// Exported functions on channelManager are safe for concurrent access, so that
+// the state can be inspected and modified through the admin API while the
+// batcher is running.
 type channelManager struct {
+	mu   sync.Mutex
 	log  log.Logger
 	metr metrics.Metricer
 	cfg  ChannelConfig
@@ -32,47 +46,75 @@ type channelManager struct {
 	blocks []*types.Block
 	// last block hash - for reorg detection
 	tip common.Hash
+	// latest L1 head passed to TxData, used to open channels outside of TxData
+	l1Head eth.L1BlockRef
 
 	// Pending data returned by TxData waiting on Tx Confirmed/Failed
 
-	// pending channel builder
-	pendingChannel *channelBuilder
-	// Set of unconfirmed txID -> frame data. For tx resubmission
-	pendingTransactions map[txID]txData
-	// Set of confirmed txID -> inclusion block. For determining if the channel is timed out
-	confirmedTransactions map[txID]eth.BlockID
+	// current channel, which new blocks are added to. nil if there is none.
+	currentChannel *channel
+	// All channels whose frames are not fully submitted yet, in order of
+	// creation. Includes the current channel.
+	channelQueue []*channel
+	// Set of txID -> channel, to look up the channel of a pending transaction
+	txChannels map[txID]*channel
+
+	// optional journal to persist closed channels across restarts. May be nil.
+	journal *journal
 }
 
 func NewChannelManager(log log.Logger, metr metrics.Metricer, cfg ChannelConfig) *channelManager {
 	return &channelManager{
-		log:  log,
-		metr: metr,
-		cfg:  cfg,
-
-		pendingTransactions:   make(map[txID]txData),
-		confirmedTransactions: make(map[txID]eth.BlockID),
+		log:        log,
+		metr:       metr,
+		cfg:        cfg,
+		txChannels: make(map[txID]*channel),
 	}
 }
 
-// Clear clears the entire state of the channel manager.
+// Clear clears the entire state of the channel manager, including the journal.
 // It is intended to be used after an L2 reorg.
 func (s *channelManager) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetState()
+	if s.journal != nil {
+		if err := s.journal.clear(context.Background()); err != nil {
+			s.log.Error("failed to clear journal", "err", err)
+		}
+	}
+}
+
+// clearState clears the in-memory state of the channel manager, but keeps the
+// journal, so that its channels can be restored.
+func (s *channelManager) clearState() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetState()
+}
+
+func (s *channelManager) resetState() {
 	s.log.Trace("clearing channel manager state")
 	s.blocks = s.blocks[:0]
 	s.tip = common.Hash{}
-	s.clearPendingChannel()
+	for _, ch := range s.channelQueue {
+		s.metr.RecordChannelDropped(ch.ID())
+	}
+	s.currentChannel = nil
+	s.channelQueue = nil
+	s.txChannels = make(map[txID]*channel)
+	s.metr.RecordOpenChannels(0)
 }
 
 // TxFailed records a transaction as failed. It will attempt to resubmit the data
 // in the failed transaction.
 func (s *channelManager) TxFailed(id txID) {
-	if data, ok := s.pendingTransactions[id]; ok {
-		s.log.Trace("marked transaction as failed", "id", id)
-		// Note: when the batcher is changed to send multiple frames per tx,
-		// this needs to be changed to iterate over all frames of the tx data
-		// and re-queue them.
-		s.pendingChannel.PushFrame(data.Frame())
-		delete(s.pendingTransactions, id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ch, ok := s.txChannels[id]; ok {
+		delete(s.txChannels, id)
+		ch.TxFailed(id)
+		s.journalChannel(ch)
 	} else {
 		s.log.Warn("unknown transaction marked as failed", "id", id)
 	}
@@ -80,104 +122,233 @@ func (s *channelManager) TxFailed(id txID) {
 	s.metr.RecordBatchTxFailed()
 }
 
+// TxDeferred records a transaction as deferred by the fee policy before it got
+// sent. Its data is re-queued, so that it is returned by TxData again.
+// Contrary to TxFailed, it isn't recorded as a failed transaction.
+func (s *channelManager) TxDeferred(id txID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ch, ok := s.txChannels[id]; ok {
+		delete(s.txChannels, id)
+		ch.TxDeferred(id)
+		s.journalChannel(ch)
+	} else {
+		s.log.Warn("unknown transaction marked as deferred", "id", id)
+	}
+}
+
+// IsUrgent returns whether the transaction's channel is within the
+// SubSafetyMargin of its deadline at the given L1 head, so that the
+// transaction must be submitted regardless of L1 fees.
+func (s *channelManager) IsUrgent(id txID, l1Head uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch, ok := s.txChannels[id]
+	return ok && ch.channelBuilder.CloseToDeadline(l1Head)
+}
+
 // TxConfirmed marks a transaction as confirmed on L1. Unfortunately even if all frames in
 // a channel have been marked as confirmed on L1 the channel may be invalid & need to be
 // resubmitted.
-// This function may reset the pending channel if the pending channel has timed out.
+// This function may rewind the channel manager's state if the transaction's
+// channel has timed out.
 func (s *channelManager) TxConfirmed(id txID, inclusionBlock eth.BlockID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.metr.RecordBatchTxSubmitted()
-	s.log.Debug("marked transaction as confirmed", "id", id, "block", inclusionBlock)
-	if _, ok := s.pendingTransactions[id]; !ok {
+	ch, ok := s.txChannels[id]
+	if !ok {
+		// This can occur if the channel got cleared or invalidated while
+		// there were still transactions of it in flight.
 		s.log.Warn("unknown transaction marked as confirmed", "id", id, "block", inclusionBlock)
-		// TODO: This can occur if we clear the channel while there are still pending transactions
-		// We need to keep track of stale transactions instead
 		return
 	}
-	delete(s.pendingTransactions, id)
-	s.confirmedTransactions[id] = inclusionBlock
-	s.pendingChannel.FramePublished(inclusionBlock.Number)
+	delete(s.txChannels, id)
+	ch.TxConfirmed(id, inclusionBlock)
+	s.journalChannel(ch)
 
 	// If this channel timed out, put the pending blocks back into the local saved blocks
 	// and then reset this state so it can try to build a new channel.
-	if s.pendingChannelIsTimedOut() {
-		s.metr.RecordChannelTimedOut(s.pendingChannel.ID())
-		s.log.Warn("Channel timed out", "id", s.pendingChannel.ID())
-		s.blocks = append(s.pendingChannel.Blocks(), s.blocks...)
-		s.clearPendingChannel()
-	}
-	// If we are done with this channel, record that.
-	if s.pendingChannelIsFullySubmitted() {
-		s.metr.RecordChannelFullySubmitted(s.pendingChannel.ID())
-		s.log.Info("Channel is fully submitted", "id", s.pendingChannel.ID())
-		s.clearPendingChannel()
-	}
-}
-
-// clearPendingChannel resets all pending state back to an initialized but empty state.
-// TODO: Create separate "pending" state
-func (s *channelManager) clearPendingChannel() {
-	s.pendingChannel = nil
-	s.pendingTransactions = make(map[txID]txData)
-	s.confirmedTransactions = make(map[txID]eth.BlockID)
-}
-
-// pendingChannelIsTimedOut returns true if submitted channel has timed out.
-// A channel has timed out if the difference in L1 Inclusion blocks between
-// the first & last included block is greater than or equal to the channel timeout.
-func (s *channelManager) pendingChannelIsTimedOut() bool {
-	if s.pendingChannel == nil {
-		return false // no channel to be timed out
-	}
-	// No confirmed transactions => not timed out
-	if len(s.confirmedTransactions) == 0 {
-		return false
-	}
-	// If there are confirmed transactions, find the first + last confirmed block numbers
-	min := uint64(math.MaxUint64)
-	max := uint64(0)
-	for _, inclusionBlock := range s.confirmedTransactions {
-		if inclusionBlock.Number < min {
-			min = inclusionBlock.Number
+	if ch.isTimedOut() {
+		s.metr.RecordChannelTimedOut(ch.ID())
+		s.log.Warn("Channel timed out", "id", ch.ID())
+		s.handleChannelInvalidated(ch)
+		return
+	}
+
+	s.pruneFullySubmittedChannels()
+}
+
+// handleChannelInvalidated rewinds the channel manager's state to the first
+// block of the given invalidated channel. The channel and all channels opened
+// after it are dropped, and their blocks are put back into the blocks queue,
+// so that they get resubmitted in new channels.
+//
+// All later channels need to be dropped too, because their batches depend on
+// the batches of the invalidated channel being submitted first.
+func (s *channelManager) handleChannelInvalidated(ch *channel) {
+	for i, c := range s.channelQueue {
+		if c != ch {
+			continue
 		}
-		if inclusionBlock.Number > max {
-			max = inclusionBlock.Number
+		var blocks []*types.Block
+		for _, dropped := range s.channelQueue[i:] {
+			blocks = append(blocks, dropped.channelBuilder.Blocks()...)
+			s.dropChannelTxs(dropped)
+			s.unjournalChannel(dropped)
+			if dropped != ch {
+				s.metr.RecordChannelDropped(dropped.ID())
+				s.log.Warn("Dropped channel after invalidated channel", "id", dropped.ID(), "invalidated_id", ch.ID())
+			}
 		}
+		s.blocks = append(blocks, s.blocks...)
+		s.channelQueue = s.channelQueue[:i]
+		s.currentChannel = nil
+		s.metr.RecordOpenChannels(len(s.channelQueue))
+		return
 	}
-	return max-min >= s.cfg.ChannelTimeout
+	s.log.Warn("invalidated channel not found in channel queue", "id", ch.ID())
 }
 
-// pendingChannelIsFullySubmitted returns true if the channel has been fully submitted.
-func (s *channelManager) pendingChannelIsFullySubmitted() bool {
-	if s.pendingChannel == nil {
-		return false // todo: can decide either way here. Nonsensical answer though
+// dropChannelTxs removes all pending transactions of the given channel from the
+// transaction lookup map. Results for these transactions are ignored from now on.
+func (s *channelManager) dropChannelTxs(ch *channel) {
+	for id := range ch.pendingTransactions {
+		delete(s.txChannels, id)
 	}
-	return s.pendingChannel.IsFull() && len(s.pendingTransactions)+s.pendingChannel.NumFrames() == 0
 }
 
-// nextTxData pops off s.datas & handles updating the internal state
-func (s *channelManager) nextTxData() (txData, error) {
-	if s.pendingChannel == nil || !s.pendingChannel.HasFrame() {
-		s.log.Trace("no next tx data")
-		return txData{}, io.EOF // TODO: not enough data error instead
+// pruneFullySubmittedChannels removes all fully submitted channels from the
+// front of the channel queue. Channels that got fully submitted while an older
+// channel is still being submitted are kept, because they would need to be
+// rebuilt if the older channel timed out.
+func (s *channelManager) pruneFullySubmittedChannels() {
+	for len(s.channelQueue) > 0 && s.channelQueue[0].isFullySubmitted() {
+		ch := s.channelQueue[0]
+		s.metr.RecordChannelFullySubmitted(ch.ID())
+		s.log.Info("Channel is fully submitted", "id", ch.ID())
+		s.unjournalChannel(ch)
+		if ch == s.currentChannel {
+			s.currentChannel = nil
+		}
+		s.channelQueue[0] = nil // help GC
+		s.channelQueue = s.channelQueue[1:]
 	}
+	s.metr.RecordOpenChannels(len(s.channelQueue))
+}
 
-	frame := s.pendingChannel.NextFrame()
-	txdata := txData{frame}
-	id := txdata.ID()
+// TxSent records the hash of a transaction that got published with the given
+// tx data, so that it can be checked on L1 after a restart. It must be called
+// for every published version of the transaction, because fee bumps and
+// re-crafting change the hash.
+func (s *channelManager) TxSent(id txID, txHash common.Hash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ch, ok := s.txChannels[id]; ok && ch.TxSent(id, txHash) {
+		s.journalChannel(ch)
+	}
+}
 
-	s.log.Trace("returning next tx data", "id", id)
-	s.pendingTransactions[id] = txdata
-	return txdata, nil
+// journalChannel journals the given channel if a journal is configured and the
+// channel is closed.
+func (s *channelManager) journalChannel(ch *channel) {
+	if s.journal != nil {
+		s.journal.putChannel(ch)
+	}
+}
+
+// unjournalChannel removes the given channel from the journal, if configured.
+func (s *channelManager) unjournalChannel(ch *channel) {
+	if s.journal != nil {
+		s.journal.deleteChannel(ch.ID())
+	}
+}
+
+// restoreChannel restores a closed channel from the journal and adds it to the
+// channel queue. The given blocks must extend the last block that was added to
+// the channel manager. Frames found in landed are treated as confirmed, frames
+// found in pending as sent with the given transaction hashes.
+func (s *channelManager) restoreChannel(jc journalChannel, blocks []*types.Block, landed map[txID]eth.BlockID, pending map[txID]struct{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, block := range blocks {
+		if s.tip != (common.Hash{}) && s.tip != block.ParentHash() {
+			return ErrReorg
+		}
+		s.tip = block.Hash()
+	}
+	ch, err := restoreChannel(s.log, s.metr, s.cfg, jc, blocks, landed, pending)
+	if err != nil {
+		return fmt.Errorf("restoring channel %s: %w", jc.ID, err)
+	}
+	s.channelQueue = append(s.channelQueue, ch)
+	for id := range ch.pendingTransactions {
+		s.txChannels[id] = ch
+	}
+	s.log.Info("Restored channel from journal",
+		"id", ch.ID(),
+		"blocks", len(blocks),
+		"frames_pending", ch.channelBuilder.NumFrames(),
+		"frames_in_flight", len(ch.pendingTransactions),
+		"frames_confirmed", len(ch.confirmedTransactions))
+	ch.recordTxs()
+	if ch.isTimedOut() {
+		s.metr.RecordChannelTimedOut(ch.ID())
+		s.log.Warn("Restored channel timed out", "id", ch.ID())
+		s.handleChannelInvalidated(ch)
+		return nil
+	}
+	s.journalChannel(ch)
+	s.pruneFullySubmittedChannels()
+	return nil
+}
+
+// nextTxData returns the next frame of the oldest channel with frames ready to
+// be sent & handles updating the internal state.
+func (s *channelManager) nextTxData() (txData, error) {
+	for _, ch := range s.channelQueue {
+		if !ch.HasFrame() {
+			continue
+		}
+		txdata := ch.NextTxData()
+		s.txChannels[txdata.ID()] = ch
+		return txdata, nil
+	}
+	s.log.Trace("no next tx data")
+	return txData{}, io.EOF // TODO: not enough data error instead
+}
+
+// hasFrame returns whether any channel has a frame ready to be sent.
+func (s *channelManager) hasFrame() bool {
+	for _, ch := range s.channelQueue {
+		if ch.HasFrame() {
+			return true
+		}
+	}
+	return false
 }
 
 // TxData returns the next tx data that should be submitted to L1.
 //
-// It currently only uses one frame per transaction. If the pending channel is
-// full, it only returns the remaining frames of this channel until it got
-// successfully fully sent to L1. It returns io.EOF if there's no pending frame.
-func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
-	dataPending := s.pendingChannel != nil && s.pendingChannel.HasFrame()
-	s.log.Debug("Requested tx data", "l1Head", l1Head, "data_pending", dataPending, "blocks_pending", len(s.blocks))
+// It currently only uses one frame per transaction. Frames of older channels
+// are returned first. If the current channel is full, a new channel is opened
+// for the pending blocks, while the frames of the full channel are still being
+// submitted. It returns io.EOF if there's no pending frame.
+//
+// Frames of transactions that got confirmed in L1 blocks after l1Head are
+// assumed to have been reorged out and are returned again.
+func (s *channelManager) TxData(l1Head eth.L1BlockRef) (txData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.l1Head = l1Head
+	s.rewindReorgedTxs(func(inclusionBlock eth.BlockID) bool {
+		// Confirmations ahead of the L1 head can only be left over from a reorg.
+		return inclusionBlock.Number > l1Head.Number
+	})
+
+	dataPending := s.hasFrame()
+	s.log.Debug("Requested tx data", "l1Head", l1Head, "data_pending", dataPending,
+		"blocks_pending", len(s.blocks), "open_channels", len(s.channelQueue))
 
 	// Short circuit if there is a pending frame.
 	if dataPending {
@@ -191,7 +362,7 @@ func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
 		return txData{}, io.EOF
 	}
 
-	if err := s.ensurePendingChannel(l1Head); err != nil {
+	if err := s.ensureChannelWithSpace(l1Head); err != nil {
 		return txData{}, err
 	}
 
@@ -202,7 +373,7 @@ func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
 	// Register current L1 head only after all pending blocks have been
 	// processed. Even if a timeout will be triggered now, it is better to have
 	// all pending blocks be included in this channel for submission.
-	s.registerL1Block(l1Head)
+	s.registerL1Block(l1Head.ID())
 
 	if err := s.outputFrames(); err != nil {
 		return txData{}, err
@@ -211,45 +382,97 @@ func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
 	return s.nextTxData()
 }
 
-func (s *channelManager) ensurePendingChannel(l1Head eth.BlockID) error {
-	if s.pendingChannel != nil {
+// InclusionBlocks returns the distinct L1 blocks that the confirmed
+// transactions of all pending channels got included in.
+func (s *channelManager) InclusionBlocks() []eth.BlockID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := make(map[eth.BlockID]struct{})
+	var blocks []eth.BlockID
+	for _, ch := range s.channelQueue {
+		for _, inclusionBlock := range ch.confirmedTransactions {
+			if _, ok := seen[inclusionBlock]; !ok {
+				seen[inclusionBlock] = struct{}{}
+				blocks = append(blocks, inclusionBlock)
+			}
+		}
+	}
+	return blocks
+}
+
+// RewindReorgedTxs re-queues the frames of all confirmed transactions whose
+// inclusion block is reported as reorged out of L1, e.g. because the canonical
+// block at its height has a different hash.
+func (s *channelManager) RewindReorgedTxs(reorged func(inclusionBlock eth.BlockID) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rewindReorgedTxs(reorged)
+}
+
+func (s *channelManager) rewindReorgedTxs(reorged func(inclusionBlock eth.BlockID) bool) {
+	for _, ch := range s.channelQueue {
+		if ch.rewindReorgedTxs(reorged) {
+			s.journalChannel(ch)
+		}
+	}
+}
+
+// ensureChannelWithSpace ensures that there is a current channel that new
+// blocks can be added to. If the current channel is full, a new channel is
+// opened, while the full channel stays in the channel queue until all its
+// frames are submitted.
+//
+// The compression algorithm of the new channel is chosen based on the time of
+// the L1 head, because all frames of the channel can only be included in later
+// L1 blocks.
+func (s *channelManager) ensureChannelWithSpace(l1Head eth.L1BlockRef) error {
+	if s.currentChannel != nil && !s.currentChannel.channelBuilder.IsFull() {
 		return nil
 	}
 
-	cb, err := newChannelBuilder(s.cfg)
+	cfg := s.cfg
+	if !cfg.IsChannelCompression(l1Head.Time) {
+		cfg.CompressionAlgo = derive.Zlib
+	}
+	ch, err := newChannel(s.log, s.metr, cfg)
 	if err != nil {
 		return fmt.Errorf("creating new channel: %w", err)
 	}
-	s.pendingChannel = cb
+	s.currentChannel = ch
+	s.channelQueue = append(s.channelQueue, ch)
 	s.log.Info("Created channel",
-		"id", cb.ID(),
+		"id", ch.ID(),
 		"l1Head", l1Head,
-		"blocks_pending", len(s.blocks))
-	s.metr.RecordChannelOpened(cb.ID(), len(s.blocks))
+		"compression_algo", cfg.CompressionAlgo,
+		"blocks_pending", len(s.blocks),
+		"open_channels", len(s.channelQueue))
+	s.metr.RecordChannelOpened(ch.ID(), len(s.blocks))
+	s.metr.RecordOpenChannels(len(s.channelQueue))
 
 	return nil
 }
 
-// registerL1Block registers the given block at the pending channel.
+// registerL1Block registers the given block at the current channel.
 func (s *channelManager) registerL1Block(l1Head eth.BlockID) {
-	s.pendingChannel.RegisterL1Block(l1Head.Number)
+	s.currentChannel.channelBuilder.RegisterL1Block(l1Head.Number)
 	s.log.Debug("new L1-block registered at channel builder",
 		"l1Head", l1Head,
-		"channel_full", s.pendingChannel.IsFull(),
-		"full_reason", s.pendingChannel.FullErr(),
+		"channel_full", s.currentChannel.channelBuilder.IsFull(),
+		"full_reason", s.currentChannel.channelBuilder.FullErr(),
 	)
 }
 
-// processBlocks adds blocks from the blocks queue to the pending channel until
+// processBlocks adds blocks from the blocks queue to the current channel until
 // either the queue got exhausted or the channel is full.
 func (s *channelManager) processBlocks() error {
+	cb := s.currentChannel.channelBuilder
 	var (
 		blocksAdded int
 		_chFullErr  *ChannelFullError // throw away, just for type checking
 		latestL2ref eth.L2BlockRef
 	)
 	for i, block := range s.blocks {
-		l1info, err := s.pendingChannel.AddBlock(block)
+		l1info, err := cb.AddBlock(block)
 		if errors.As(err, &_chFullErr) {
 			// current block didn't get added because channel is already full
 			break
@@ -259,7 +482,7 @@ func (s *channelManager) processBlocks() error {
 		blocksAdded += 1
 		latestL2ref = l2BlockRefFromBlockAndL1Info(block, l1info)
 		// current block got added but channel is now full
-		if s.pendingChannel.IsFull() {
+		if cb.IsFull() {
 			break
 		}
 	}
@@ -275,34 +498,36 @@ func (s *channelManager) processBlocks() error {
 	s.metr.RecordL2BlocksAdded(latestL2ref,
 		blocksAdded,
 		len(s.blocks),
-		s.pendingChannel.InputBytes(),
-		s.pendingChannel.ReadyBytes())
+		cb.InputBytes(),
+		cb.ReadyBytes())
 	s.log.Debug("Added blocks to channel",
 		"blocks_added", blocksAdded,
 		"blocks_pending", len(s.blocks),
-		"channel_full", s.pendingChannel.IsFull(),
-		"input_bytes", s.pendingChannel.InputBytes(),
-		"ready_bytes", s.pendingChannel.ReadyBytes(),
+		"channel_full", cb.IsFull(),
+		"input_bytes", cb.InputBytes(),
+		"ready_bytes", cb.ReadyBytes(),
 	)
 	return nil
 }
 
 func (s *channelManager) outputFrames() error {
-	if err := s.pendingChannel.OutputFrames(); err != nil {
+	cb := s.currentChannel.channelBuilder
+	if err := cb.OutputFrames(); err != nil {
 		return fmt.Errorf("creating frames with channel builder: %w", err)
 	}
-	if !s.pendingChannel.IsFull() {
+	if !cb.IsFull() {
 		return nil
 	}
+	s.journalChannel(s.currentChannel)
 
-	inBytes, outBytes := s.pendingChannel.InputBytes(), s.pendingChannel.OutputBytes()
+	inBytes, outBytes := cb.InputBytes(), cb.OutputBytes()
 	s.metr.RecordChannelClosed(
-		s.pendingChannel.ID(),
+		cb.ID(),
 		len(s.blocks),
-		s.pendingChannel.NumFrames(),
+		cb.NumFrames(),
 		inBytes,
 		outBytes,
-		s.pendingChannel.FullErr(),
+		cb.FullErr(),
 	)
 
 	var comprRatio float64
@@ -310,12 +535,12 @@ func (s *channelManager) outputFrames() error {
 		comprRatio = float64(outBytes) / float64(inBytes)
 	}
 	s.log.Info("Channel closed",
-		"id", s.pendingChannel.ID(),
+		"id", cb.ID(),
 		"blocks_pending", len(s.blocks),
-		"num_frames", s.pendingChannel.NumFrames(),
+		"num_frames", cb.NumFrames(),
 		"input_bytes", inBytes,
 		"output_bytes", outBytes,
-		"full_reason", s.pendingChannel.FullErr(),
+		"full_reason", cb.FullErr(),
 		"compr_ratio", comprRatio,
 	)
 	return nil
@@ -325,6 +550,8 @@ func (s *channelManager) outputFrames() error {
 // if the block does not extend the last block loaded into the state. If no
 // blocks were added yet, the parent hash check is skipped.
 func (s *channelManager) AddL2Block(block *types.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.tip != (common.Hash{}) && s.tip != block.ParentHash() {
 		return ErrReorg
 	}
@@ -334,6 +561,132 @@ func (s *channelManager) AddL2Block(block *types.Block) error {
 	return nil
 }
 
+// Status returns the status of the blocks and channels of the channel manager.
+func (s *channelManager) Status() rpc.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := rpc.Status{
+		BlocksPending: len(s.blocks),
+		Channels:      make([]rpc.ChannelStatus, 0, len(s.channelQueue)),
+	}
+	for _, ch := range s.channelQueue {
+		cb := ch.channelBuilder
+		chStatus := rpc.ChannelStatus{
+			ID:           ch.ID().String(),
+			Current:      ch == s.currentChannel,
+			Blocks:       make([]eth.BlockID, 0, len(cb.Blocks())),
+			InputBytes:   cb.InputBytes(),
+			ReadyBytes:   cb.ReadyBytes(),
+			OutputBytes:  cb.OutputBytes(),
+			FramesQueued: cb.NumFrames(),
+			PendingTxs:   len(ch.pendingTransactions),
+			ConfirmedTxs: len(ch.confirmedTransactions),
+			TimeoutBlock: cb.timeout,
+		}
+		for _, b := range cb.Blocks() {
+			chStatus.Blocks = append(chStatus.Blocks, eth.ToBlockID(b))
+		}
+		if err := cb.FullErr(); err != nil {
+			chStatus.FullReason = err.Error()
+		}
+		status.Channels = append(status.Channels, chStatus)
+	}
+	return status
+}
+
+// PendingTxs returns all transactions that are in flight, oldest channel first.
+func (s *channelManager) PendingTxs() []rpc.PendingTx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var txs []rpc.PendingTx
+	for _, ch := range s.channelQueue {
+		chTxs := make([]rpc.PendingTx, 0, len(ch.pendingTransactions))
+		for id := range ch.pendingTransactions {
+			tx := rpc.PendingTx{
+				ChannelID:   id.chID.String(),
+				FrameNumber: id.frameNumber,
+			}
+			if txHashes := ch.pendingTxHashes[id]; len(txHashes) > 0 {
+				tx.TxHash = &txHashes[len(txHashes)-1]
+			}
+			chTxs = append(chTxs, tx)
+		}
+		sort.Slice(chTxs, func(i, j int) bool {
+			return chTxs[i].FrameNumber < chTxs[j].FrameNumber
+		})
+		txs = append(txs, chTxs...)
+	}
+	return txs
+}
+
+// ForceCloseChannel adds all pending blocks to the current channel, as far as
+// they fit, and closes it, so that all its frames are ready to be submitted.
+// If there's no current channel that isn't full yet, a new channel is opened
+// for the pending blocks. It returns ErrNoOpenChannel if there's neither an
+// open channel nor pending blocks, and ErrEmptyChannel if the current channel
+// has no blocks.
+func (s *channelManager) ForceCloseChannel() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.currentChannel == nil || s.currentChannel.channelBuilder.IsFull() {
+		if len(s.blocks) == 0 {
+			return ErrNoOpenChannel
+		}
+		if err := s.ensureChannelWithSpace(s.l1Head); err != nil {
+			return err
+		}
+	}
+	if err := s.processBlocks(); err != nil {
+		return err
+	}
+	cb := s.currentChannel.channelBuilder
+	if len(cb.Blocks()) == 0 {
+		return ErrEmptyChannel
+	}
+	if !cb.IsFull() {
+		cb.setFullErr(ErrForceClosed)
+	}
+	s.log.Info("Force closing channel", "id", cb.ID(), "blocks", len(cb.Blocks()))
+	return s.outputFrames()
+}
+
+// SetChannelConfig applies the given config update. Channels that are already
+// open keep their config, the update only applies to channels that are opened
+// afterwards.
+func (s *channelManager) SetChannelConfig(update rpc.ChannelConfigUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg := s.cfg
+	if update.MaxChannelDuration != nil {
+		cfg.MaxChannelDuration = uint64(*update.MaxChannelDuration)
+	}
+	if update.TargetNumFrames != nil {
+		cfg.TargetNumFrames = int(*update.TargetNumFrames)
+	}
+	if update.TargetFrameSize != nil {
+		cfg.TargetFrameSize = uint64(*update.TargetFrameSize)
+	}
+	if err := cfg.Check(); err != nil {
+		return err
+	}
+	if cfg.TargetNumFrames < 1 {
+		return fmt.Errorf("target number of frames must be positive, got %d", cfg.TargetNumFrames)
+	}
+	if cfg.TargetFrameSize > cfg.MaxFrameSize {
+		return fmt.Errorf("target frame size %d exceeds max frame size %d", cfg.TargetFrameSize, cfg.MaxFrameSize)
+	}
+	s.cfg = cfg
+	s.log.Info("Updated channel config",
+		"max_channel_duration", cfg.MaxChannelDuration,
+		"target_num_frames", cfg.TargetNumFrames,
+		"target_frame_size", cfg.TargetFrameSize)
+	return nil
+}
+
 func l2BlockRefFromBlockAndL1Info(block *types.Block, l1info derive.L1BlockInfo) eth.L2BlockRef {
 	return eth.L2BlockRef{
 		Hash:           block.Hash(),
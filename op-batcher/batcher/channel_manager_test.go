@@ -8,61 +8,18 @@ import (
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	derivetest "github.com/ethereum-optimism/optimism/op-node/rollup/derive/test"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/stretchr/testify/require"
 )
 
-// TestPendingChannelTimeout tests that the channel manager
-// correctly identifies when a pending channel is timed out.
-func TestPendingChannelTimeout(t *testing.T) {
-	// Create a new channel manager with a ChannelTimeout
-	log := testlog.Logger(t, log.LvlCrit)
-	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
-		ChannelTimeout: 100,
-	})
-
-	// Pending channel is nil so is cannot be timed out
-	timeout := m.pendingChannelIsTimedOut()
-	require.False(t, timeout)
-
-	// Set the pending channel
-	err := m.ensurePendingChannel(eth.BlockID{})
-	require.NoError(t, err)
-
-	// There are no confirmed transactions so
-	// the pending channel cannot be timed out
-	timeout = m.pendingChannelIsTimedOut()
-	require.False(t, timeout)
-
-	// Manually set a confirmed transactions
-	// To avoid other methods clearing state
-	m.confirmedTransactions[frameID{frameNumber: 0}] = eth.BlockID{Number: 0}
-	m.confirmedTransactions[frameID{frameNumber: 1}] = eth.BlockID{Number: 99}
-
-	// Since the ChannelTimeout is 100, the
-	// pending channel should not be timed out
-	timeout = m.pendingChannelIsTimedOut()
-	require.False(t, timeout)
-
-	// Add a confirmed transaction with a higher number
-	// than the ChannelTimeout
-	m.confirmedTransactions[frameID{
-		frameNumber: 2,
-	}] = eth.BlockID{
-		Number: 101,
-	}
-
-	// Now the pending channel should be timed out
-	timeout = m.pendingChannelIsTimedOut()
-	require.True(t, timeout)
-}
-
 // TestChannelManagerReturnsErrReorg ensures that the channel manager
 // detects a reorg when it has cached L1 blocks.
 func TestChannelManagerReturnsErrReorg(t *testing.T) {
@@ -114,9 +71,9 @@ func TestChannelManagerReturnsErrReorgWhenDrained(t *testing.T) {
 	err := m.AddL2Block(a)
 	require.NoError(t, err)
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.NoError(t, err)
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(t, err, io.EOF)
 
 	err = m.AddL2Block(x)
@@ -128,22 +85,22 @@ func TestChannelManagerNextTxData(t *testing.T) {
 	log := testlog.Logger(t, log.LvlCrit)
 	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{})
 
-	// Nil pending channel should return EOF
+	// Empty channel queue should return EOF
 	returnedTxData, err := m.nextTxData()
 	require.ErrorIs(t, err, io.EOF)
 	require.Equal(t, txData{}, returnedTxData)
 
-	// Set the pending channel
+	// Set the current channel
 	// The nextTxData function should still return EOF
-	// since the pending channel has no frames
-	err = m.ensurePendingChannel(eth.BlockID{})
+	// since the current channel has no frames
+	err = m.ensureChannelWithSpace(eth.L1BlockRef{})
 	require.NoError(t, err)
 	returnedTxData, err = m.nextTxData()
 	require.ErrorIs(t, err, io.EOF)
 	require.Equal(t, txData{}, returnedTxData)
 
-	// Manually push a frame into the pending channel
-	channelID := m.pendingChannel.ID()
+	// Manually push a frame into the current channel
+	channelID := m.currentChannel.ID()
 	frame := frameData{
 		data: []byte{},
 		id: frameID{
@@ -151,8 +108,8 @@ func TestChannelManagerNextTxData(t *testing.T) {
 			frameNumber: uint16(0),
 		},
 	}
-	m.pendingChannel.PushFrame(frame)
-	require.Equal(t, 1, m.pendingChannel.NumFrames())
+	m.currentChannel.channelBuilder.PushFrame(frame)
+	require.Equal(t, 1, m.currentChannel.channelBuilder.NumFrames())
 
 	// Now the nextTxData function should return the frame
 	returnedTxData, err = m.nextTxData()
@@ -160,8 +117,9 @@ func TestChannelManagerNextTxData(t *testing.T) {
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
-	require.Equal(t, 0, m.pendingChannel.NumFrames())
-	require.Equal(t, expectedTxData, m.pendingTransactions[expectedChannelID])
+	require.Equal(t, 0, m.currentChannel.channelBuilder.NumFrames())
+	require.Equal(t, expectedTxData, m.currentChannel.pendingTransactions[expectedChannelID])
+	require.Equal(t, m.currentChannel, m.txChannels[expectedChannelID])
 }
 
 // TestClearChannelManager tests clearing the channel manager.
@@ -182,25 +140,25 @@ func TestClearChannelManager(t *testing.T) {
 	// Channel Manager state should be empty by default
 	require.Empty(t, m.blocks)
 	require.Equal(t, common.Hash{}, m.tip)
-	require.Nil(t, m.pendingChannel)
-	require.Empty(t, m.pendingTransactions)
-	require.Empty(t, m.confirmedTransactions)
+	require.Nil(t, m.currentChannel)
+	require.Empty(t, m.channelQueue)
+	require.Empty(t, m.txChannels)
 
 	// Add a block to the channel manager
 	a, _ := derivetest.RandomL2Block(rng, 4)
 	newL1Tip := a.Hash()
-	l1BlockID := eth.BlockID{
+	l1Block := eth.L1BlockRef{
 		Hash:   a.Hash(),
 		Number: a.NumberU64(),
 	}
 	err := m.AddL2Block(a)
 	require.NoError(t, err)
 
-	// Make sure there is a channel builder
-	err = m.ensurePendingChannel(l1BlockID)
+	// Make sure there is a channel
+	err = m.ensureChannelWithSpace(l1Block)
 	require.NoError(t, err)
-	require.NotNil(t, m.pendingChannel)
-	require.Equal(t, 0, len(m.confirmedTransactions))
+	require.NotNil(t, m.currentChannel)
+	require.Len(t, m.channelQueue, 1)
 
 	// Process the blocks
 	// We should have a pending channel with 1 frame
@@ -208,13 +166,13 @@ func TestClearChannelManager(t *testing.T) {
 	// the list
 	err = m.processBlocks()
 	require.NoError(t, err)
-	err = m.pendingChannel.OutputFrames()
+	err = m.currentChannel.channelBuilder.OutputFrames()
 	require.NoError(t, err)
 	_, err = m.nextTxData()
 	require.NoError(t, err)
 	require.Equal(t, 0, len(m.blocks))
 	require.Equal(t, newL1Tip, m.tip)
-	require.Equal(t, 1, len(m.pendingTransactions))
+	require.Equal(t, 1, len(m.txChannels))
 
 	// Add a new block so we can test clearing
 	// the channel manager with a full state
@@ -233,9 +191,9 @@ func TestClearChannelManager(t *testing.T) {
 	// Check that the entire channel manager state cleared
 	require.Empty(t, m.blocks)
 	require.Equal(t, common.Hash{}, m.tip)
-	require.Nil(t, m.pendingChannel)
-	require.Empty(t, m.pendingTransactions)
-	require.Empty(t, m.confirmedTransactions)
+	require.Nil(t, m.currentChannel)
+	require.Empty(t, m.channelQueue)
+	require.Empty(t, m.txChannels)
 }
 
 // TestChannelManagerTxConfirmed checks the [ChannelManager.TxConfirmed] function.
@@ -251,45 +209,41 @@ func TestChannelManagerTxConfirmed(t *testing.T) {
 
 	// Let's add a valid pending transaction to the channel manager
 	// So we can demonstrate that TxConfirmed's correctness
-	err := m.ensurePendingChannel(eth.BlockID{})
+	err := m.ensureChannelWithSpace(eth.L1BlockRef{})
 	require.NoError(t, err)
-	channelID := m.pendingChannel.ID()
+	ch := m.currentChannel
 	frame := frameData{
 		data: []byte{},
 		id: frameID{
-			chID:        channelID,
+			chID:        ch.ID(),
 			frameNumber: uint16(0),
 		},
 	}
-	m.pendingChannel.PushFrame(frame)
-	require.Equal(t, 1, m.pendingChannel.NumFrames())
+	ch.channelBuilder.PushFrame(frame)
 	returnedTxData, err := m.nextTxData()
 	expectedTxData := txData{frame}
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
-	require.Equal(t, 0, m.pendingChannel.NumFrames())
-	require.Equal(t, expectedTxData, m.pendingTransactions[expectedChannelID])
-	require.Equal(t, 1, len(m.pendingTransactions))
+	require.Equal(t, 1, len(m.txChannels))
 
 	// An unknown pending transaction should not be marked as confirmed
 	// and should not be removed from the pending transactions map
-	actualChannelID := m.pendingChannel.ID()
 	unknownChannelID := derive.ChannelID([derive.ChannelIDLength]byte{0x69})
-	require.NotEqual(t, actualChannelID, unknownChannelID)
+	require.NotEqual(t, ch.ID(), unknownChannelID)
 	unknownTxID := frameID{chID: unknownChannelID, frameNumber: 0}
 	blockID := eth.BlockID{Number: 0, Hash: common.Hash{0x69}}
 	m.TxConfirmed(unknownTxID, blockID)
-	require.Empty(t, m.confirmedTransactions)
-	require.Equal(t, 1, len(m.pendingTransactions))
+	require.Empty(t, ch.confirmedTransactions)
+	require.Equal(t, 1, len(m.txChannels))
 
 	// Now let's mark the pending transaction as confirmed
 	// and check that it is removed from the pending transactions map
 	// and added to the confirmed transactions map
 	m.TxConfirmed(expectedChannelID, blockID)
-	require.Empty(t, m.pendingTransactions)
-	require.Equal(t, 1, len(m.confirmedTransactions))
-	require.Equal(t, blockID, m.confirmedTransactions[expectedChannelID])
+	require.Empty(t, m.txChannels)
+	require.Empty(t, ch.pendingTransactions)
+	require.Equal(t, blockID, ch.confirmedTransactions[expectedChannelID])
 }
 
 // TestChannelManagerTxFailed checks the [ChannelManager.TxFailed] function.
@@ -300,39 +254,37 @@ func TestChannelManagerTxFailed(t *testing.T) {
 
 	// Let's add a valid pending transaction to the channel
 	// manager so we can demonstrate correctness
-	err := m.ensurePendingChannel(eth.BlockID{})
+	err := m.ensureChannelWithSpace(eth.L1BlockRef{})
 	require.NoError(t, err)
-	channelID := m.pendingChannel.ID()
+	ch := m.currentChannel
 	frame := frameData{
 		data: []byte{},
 		id: frameID{
-			chID:        channelID,
+			chID:        ch.ID(),
 			frameNumber: uint16(0),
 		},
 	}
-	m.pendingChannel.PushFrame(frame)
-	require.Equal(t, 1, m.pendingChannel.NumFrames())
+	ch.channelBuilder.PushFrame(frame)
 	returnedTxData, err := m.nextTxData()
 	expectedTxData := txData{frame}
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
-	require.Equal(t, 0, m.pendingChannel.NumFrames())
-	require.Equal(t, expectedTxData, m.pendingTransactions[expectedChannelID])
-	require.Equal(t, 1, len(m.pendingTransactions))
+	require.Equal(t, 1, len(m.txChannels))
 
 	// Trying to mark an unknown pending transaction as failed
 	// shouldn't modify state
 	m.TxFailed(frameID{})
-	require.Equal(t, 0, m.pendingChannel.NumFrames())
-	require.Equal(t, expectedTxData, m.pendingTransactions[expectedChannelID])
+	require.Equal(t, 0, ch.channelBuilder.NumFrames())
+	require.Equal(t, 1, len(m.txChannels))
 
 	// Now we still have a pending transaction
 	// Let's mark it as failed
 	m.TxFailed(expectedChannelID)
-	require.Empty(t, m.pendingTransactions)
+	require.Empty(t, m.txChannels)
+	require.Empty(t, ch.pendingTransactions)
 	// There should be a frame in the pending channel now
-	require.Equal(t, 1, m.pendingChannel.NumFrames())
+	require.Equal(t, 1, ch.channelBuilder.NumFrames())
 }
 
 func TestChannelManager_TxResend(t *testing.T) {
@@ -351,7 +303,7 @@ func TestChannelManager_TxResend(t *testing.T) {
 	err := m.AddL2Block(a)
 	require.NoError(err)
 
-	txdata0, err := m.TxData(eth.BlockID{})
+	txdata0, err := m.TxData(eth.L1BlockRef{})
 	require.NoError(err)
 	txdata0bytes := txdata0.Bytes()
 	data0 := make([]byte, len(txdata0bytes))
@@ -359,13 +311,13 @@ func TestChannelManager_TxResend(t *testing.T) {
 	copy(data0, txdata0bytes)
 
 	// ensure channel is drained
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(err, io.EOF)
 
 	// requeue frame
 	m.TxFailed(txdata0.ID())
 
-	txdata1, err := m.TxData(eth.BlockID{})
+	txdata1, err := m.TxData(eth.L1BlockRef{})
 	require.NoError(err)
 
 	data1 := txdata1.Bytes()
@@ -374,3 +326,351 @@ func TestChannelManager_TxResend(t *testing.T) {
 	require.NoError(err)
 	require.Len(fs, 1)
 }
+
+// pushTestFrames pushes n empty frames into the given channel and marks it as
+// full, so that it doesn't accept any more blocks.
+func pushTestFrames(ch *channel, n int) {
+	for i := 0; i < n; i++ {
+		ch.channelBuilder.PushFrame(frameData{
+			data: []byte{byte(i)},
+			id:   frameID{chID: ch.ID(), frameNumber: uint16(i)},
+		})
+	}
+	ch.channelBuilder.setFullErr(ErrInputTargetReached)
+}
+
+// TestChannelManager_TxConfirmedOutOfOrder checks that the channel manager
+// handles confirmations of concurrently sent transactions in any order.
+func TestChannelManager_TxConfirmedOutOfOrder(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 10,
+	})
+
+	err := m.ensureChannelWithSpace(eth.L1BlockRef{})
+	require.NoError(err)
+	pushTestFrames(m.currentChannel, 3)
+
+	var ids []txID
+	for i := 0; i < 3; i++ {
+		txdata, err := m.nextTxData()
+		require.NoError(err)
+		ids = append(ids, txdata.ID())
+	}
+	require.Len(m.txChannels, 3)
+
+	m.TxConfirmed(ids[2], eth.BlockID{Number: 3})
+	m.TxFailed(ids[0])
+	m.TxConfirmed(ids[1], eth.BlockID{Number: 2})
+	require.Empty(m.txChannels)
+	require.Len(m.channelQueue, 1, "channel must not be fully submitted yet")
+	require.Len(m.channelQueue[0].confirmedTransactions, 2)
+
+	// resend the failed frame and confirm it
+	txdata, err := m.nextTxData()
+	require.NoError(err)
+	require.Equal(ids[0], txdata.ID())
+	m.TxConfirmed(txdata.ID(), eth.BlockID{Number: 4})
+	require.Empty(m.channelQueue, "channel must be fully submitted")
+	require.Nil(m.currentChannel)
+}
+
+// TestChannelManager_L1Reorg checks that the frames of confirmed transactions
+// get resubmitted if they got reorged out of L1.
+func TestChannelManager_L1Reorg(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 10,
+	})
+
+	err := m.ensureChannelWithSpace(eth.L1BlockRef{})
+	require.NoError(err)
+	ch := m.currentChannel
+	pushTestFrames(ch, 3)
+
+	txdata0, err := m.nextTxData()
+	require.NoError(err)
+	txdata1, err := m.nextTxData()
+	require.NoError(err)
+	m.TxConfirmed(txdata0.ID(), eth.BlockID{Number: 5})
+	m.TxConfirmed(txdata1.ID(), eth.BlockID{Number: 7})
+
+	// The third frame is still queued, so the reorged frame gets queued
+	// behind it.
+	_, err = m.TxData(eth.L1BlockRef{Number: 6})
+	require.NoError(err)
+	txdata, err := m.TxData(eth.L1BlockRef{Number: 6})
+	require.NoError(err)
+	require.Equal(txdata1, txdata)
+	require.Len(ch.confirmedTransactions, 1)
+	require.Contains(ch.confirmedTransactions, txdata0.ID())
+}
+
+// TestChannelManager_L1ReorgSameHeight checks that the frames of confirmed
+// transactions get resubmitted if their inclusion block got replaced by a
+// block at the same height.
+func TestChannelManager_L1ReorgSameHeight(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 10,
+	})
+
+	err := m.ensureChannelWithSpace(eth.L1BlockRef{})
+	require.NoError(err)
+	ch := m.currentChannel
+	pushTestFrames(ch, 3)
+
+	txdata0, err := m.nextTxData()
+	require.NoError(err)
+	txdata1, err := m.nextTxData()
+	require.NoError(err)
+	blockA := eth.BlockID{Number: 5, Hash: common.Hash{0xa}}
+	blockB := eth.BlockID{Number: 5, Hash: common.Hash{0xb}}
+	m.TxConfirmed(txdata0.ID(), blockA)
+	m.TxConfirmed(txdata1.ID(), blockB)
+	require.ElementsMatch([]eth.BlockID{blockA, blockB}, m.InclusionBlocks())
+
+	// the height didn't change, so TxData doesn't detect the reorg
+	_, err = m.TxData(eth.L1BlockRef{Number: 5})
+	require.NoError(err)
+	require.Len(ch.confirmedTransactions, 2)
+
+	// blockB is canonical, blockA got reorged out
+	m.RewindReorgedTxs(func(inclusionBlock eth.BlockID) bool {
+		return inclusionBlock != blockB
+	})
+	txdata, err := m.TxData(eth.L1BlockRef{Number: 5})
+	require.NoError(err)
+	require.Equal(txdata0, txdata)
+	require.Len(ch.confirmedTransactions, 1)
+	require.Contains(ch.confirmedTransactions, txdata1.ID())
+}
+
+// TestChannelManager_MultipleChannels checks that new blocks get added to a
+// new channel while the frames of a full channel are still pending, and that
+// frames of older channels are returned first.
+func TestChannelManager_MultipleChannels(t *testing.T) {
+	require := require.New(t)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 10,
+		// every block fills a channel
+		TargetFrameSize:  0,
+		MaxFrameSize:     120_000,
+		ApproxComprRatio: 1.0,
+	})
+
+	a, _ := derivetest.RandomL2Block(rng, 4)
+	require.NoError(m.AddL2Block(a))
+	txdata0, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	ch0 := m.currentChannel
+
+	b := newMiniL2BlockWithNumberParent(0, new(big.Int).Add(a.Number(), common.Big1), a.Hash())
+	require.NoError(m.AddL2Block(b))
+	txdata1, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	ch1 := m.currentChannel
+	require.NotEqual(ch0.ID(), ch1.ID(), "second block must go into a new channel")
+	require.Equal([]*channel{ch0, ch1}, m.channelQueue)
+
+	// fail the first tx, it must be resent before any later frames
+	m.TxFailed(txdata0.ID())
+	txdata, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	require.Equal(txdata0, txdata)
+
+	// the second channel is fully submitted first, but the first channel
+	// is still pending, so both must be kept
+	m.TxConfirmed(txdata1.ID(), eth.BlockID{Number: 1})
+	require.Len(m.channelQueue, 2)
+
+	m.TxConfirmed(txdata0.ID(), eth.BlockID{Number: 2})
+	require.Empty(m.channelQueue)
+}
+
+// TestChannelManager_ChannelTimeoutDropsLaterChannels checks that a timed out
+// channel causes all later channels to be dropped and their blocks to be
+// requeued.
+func TestChannelManager_ChannelTimeoutDropsLaterChannels(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 10,
+	})
+
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	ch0 := m.currentChannel
+	ch0.channelBuilder.blocks = []*types.Block{newMiniL2Block(0)}
+	pushTestFrames(ch0, 2)
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	ch1 := m.currentChannel
+	ch1.channelBuilder.blocks = []*types.Block{newMiniL2Block(1)}
+	pushTestFrames(ch1, 1)
+
+	txdata00, err := m.nextTxData()
+	require.NoError(err)
+	txdata01, err := m.nextTxData()
+	require.NoError(err)
+	txdata10, err := m.nextTxData()
+	require.NoError(err)
+	require.Equal(ch1.ID(), txdata10.ID().chID)
+
+	m.TxConfirmed(txdata00.ID(), eth.BlockID{Number: 1})
+	// confirmation after the channel timeout
+	m.TxConfirmed(txdata01.ID(), eth.BlockID{Number: 11})
+
+	require.Empty(m.channelQueue)
+	require.Nil(m.currentChannel)
+	require.Empty(m.txChannels, "pending txs of dropped channels must be forgotten")
+	require.Equal(append(ch0.channelBuilder.Blocks(), ch1.channelBuilder.Blocks()...), m.blocks)
+
+	// late confirmation of the dropped channel's tx must be ignored
+	m.TxConfirmed(txdata10.ID(), eth.BlockID{Number: 12})
+	require.Empty(m.channelQueue)
+}
+
+// TestChannelManager_CompressionActivation checks that the configured
+// compression algorithm is only used for new channels once versioned channels
+// are active at the L1 head.
+func TestChannelManager_CompressionActivation(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	activation := uint64(100)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout:         10,
+		CompressionAlgo:        derive.Zstd,
+		ChannelCompressionTime: &activation,
+	})
+
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{Time: activation - 1}))
+	co := m.currentChannel.channelBuilder.co
+	require.False(co.Versioned())
+	require.Equal(derive.Zlib, co.Algo())
+
+	m.currentChannel.channelBuilder.setFullErr(ErrInputTargetReached)
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{Time: activation}))
+	co = m.currentChannel.channelBuilder.co
+	require.True(co.Versioned())
+	require.Equal(derive.Zstd, co.Algo())
+}
+
+// TestChannelManager_ForceCloseChannel checks that force closing the current
+// channel adds the pending blocks and outputs all its frames.
+func TestChannelManager_ForceCloseChannel(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, defaultTestChannelConfig)
+
+	require.ErrorIs(m.ForceCloseChannel(), ErrNoOpenChannel)
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	require.ErrorIs(m.ForceCloseChannel(), ErrEmptyChannel)
+
+	require.NoError(m.AddL2Block(newMiniL2Block(0)))
+	require.NoError(m.ForceCloseChannel())
+	cb := m.currentChannel.channelBuilder
+	require.Len(cb.Blocks(), 1)
+	require.Empty(m.blocks)
+	require.ErrorIs(cb.FullErr(), ErrForceClosed)
+	require.True(cb.IsClosed())
+	require.True(cb.HasFrame())
+
+	status := m.Status()
+	require.Len(status.Channels, 1)
+	require.Equal(cb.ID().String(), status.Channels[0].ID)
+	require.Equal([]eth.BlockID{eth.ToBlockID(cb.Blocks()[0])}, status.Channels[0].Blocks)
+	require.Equal(1, status.Channels[0].FramesQueued)
+	require.NotEmpty(status.Channels[0].FullReason)
+
+	txdata, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	m.TxSent(txdata.ID(), common.Hash{0xaa})
+	pending := m.PendingTxs()
+	require.Len(pending, 1)
+	require.Equal(txdata.ID().frameNumber, pending[0].FrameNumber)
+	require.Equal(common.Hash{0xaa}, *pending[0].TxHash)
+}
+
+// TestChannelManager_ForceCloseChannelOpensChannel checks that force closing
+// opens a channel for the pending blocks if there's no open channel.
+func TestChannelManager_ForceCloseChannelOpensChannel(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, defaultTestChannelConfig)
+
+	// the previous channel is full, so the new block is only pending
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	require.NoError(m.AddL2Block(newMiniL2Block(0)))
+	require.NoError(m.ForceCloseChannel())
+	full := m.currentChannel
+	require.NoError(m.AddL2Block(newMiniL2BlockWithNumberParent(0, big.NewInt(1), full.channelBuilder.Blocks()[0].Hash())))
+	require.Len(m.blocks, 1)
+
+	require.NoError(m.ForceCloseChannel())
+	require.NotEqual(full, m.currentChannel, "new channel must be opened")
+	cb := m.currentChannel.channelBuilder
+	require.Len(cb.Blocks(), 1)
+	require.Empty(m.blocks)
+	require.ErrorIs(cb.FullErr(), ErrForceClosed)
+	require.True(cb.HasFrame())
+	require.Equal([]*channel{full, m.currentChannel}, m.channelQueue)
+}
+
+// TestChannelManager_SetChannelConfig checks that config updates are validated
+// and only apply to new channels.
+func TestChannelManager_SetChannelConfig(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, defaultTestChannelConfig)
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{}))
+
+	tooLarge := hexutil.Uint64(defaultTestChannelConfig.MaxFrameSize + 1)
+	require.Error(m.SetChannelConfig(rpc.ChannelConfigUpdate{TargetFrameSize: &tooLarge}))
+	zero := hexutil.Uint64(0)
+	require.Error(m.SetChannelConfig(rpc.ChannelConfigUpdate{TargetNumFrames: &zero}))
+	require.Equal(defaultTestChannelConfig, m.cfg)
+
+	duration, numFrames := hexutil.Uint64(10), hexutil.Uint64(3)
+	require.NoError(m.SetChannelConfig(rpc.ChannelConfigUpdate{
+		MaxChannelDuration: &duration,
+		TargetNumFrames:    &numFrames,
+	}))
+	require.Equal(uint64(10), m.cfg.MaxChannelDuration)
+	require.Equal(3, m.cfg.TargetNumFrames)
+	require.Equal(defaultTestChannelConfig.TargetFrameSize, m.cfg.TargetFrameSize)
+	require.Equal(defaultTestChannelConfig, m.currentChannel.cfg, "open channel must keep its config")
+}
+
+// TestChannelManager_TxDeferred checks that the urgency of a transaction is
+// derived from its channel's deadline and that deferred transactions are
+// re-queued without being counted as failed.
+func TestChannelManager_TxDeferred(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, defaultTestChannelConfig)
+
+	// The mini block's L1 origin is block 100, so the channel's deadline is the
+	// end of the sequencing window at block 115.
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	require.NoError(m.AddL2Block(newMiniL2Block(0)))
+	require.NoError(m.ForceCloseChannel())
+
+	txdata, err := m.TxData(eth.L1BlockRef{Number: 100})
+	require.NoError(err)
+	margin := defaultTestChannelConfig.SubSafetyMargin
+	require.False(m.IsUrgent(txdata.ID(), 115-margin-1))
+	require.True(m.IsUrgent(txdata.ID(), 115-margin))
+
+	m.TxDeferred(txdata.ID())
+	require.Empty(m.txChannels)
+	require.Empty(m.currentChannel.pendingTransactions)
+	require.False(m.IsUrgent(txdata.ID(), 115), "unknown txs are never urgent")
+
+	redo, err := m.TxData(eth.L1BlockRef{Number: 100})
+	require.NoError(err)
+	require.Equal(txdata, redo)
+}
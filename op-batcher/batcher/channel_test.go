@@ -0,0 +1,72 @@
+package batcher
+
+import (
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelTimeout tests that the channel correctly identifies when it is
+// timed out.
+func TestChannelTimeout(t *testing.T) {
+	// Create a new channel with a ChannelTimeout
+	log := testlog.Logger(t, log.LvlCrit)
+	ch, err := newChannel(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 100,
+	})
+	require.NoError(t, err)
+
+	// There are no confirmed transactions so
+	// the channel cannot be timed out
+	require.False(t, ch.isTimedOut())
+
+	// Manually set a confirmed transactions
+	// To avoid other methods clearing state
+	ch.confirmedTransactions[frameID{frameNumber: 0}] = eth.BlockID{Number: 0}
+	ch.confirmedTransactions[frameID{frameNumber: 1}] = eth.BlockID{Number: 99}
+
+	// Since the ChannelTimeout is 100, the
+	// channel should not be timed out
+	require.False(t, ch.isTimedOut())
+
+	// Add a confirmed transaction with a higher number
+	// than the ChannelTimeout
+	ch.confirmedTransactions[frameID{
+		frameNumber: 2,
+	}] = eth.BlockID{
+		Number: 101,
+	}
+
+	// Now the channel should be timed out
+	require.True(t, ch.isTimedOut())
+}
+
+// TestChannelFullySubmitted checks that a channel is only fully submitted once
+// it is full and all its frames are confirmed.
+func TestChannelFullySubmitted(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	ch, err := newChannel(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 100,
+	})
+	require.NoError(t, err)
+
+	frame := frameData{
+		data: []byte{},
+		id:   frameID{chID: ch.ID(), frameNumber: 0},
+	}
+	ch.channelBuilder.PushFrame(frame)
+	require.False(t, ch.isFullySubmitted(), "not full yet")
+
+	ch.channelBuilder.setFullErr(ErrInputTargetReached)
+	require.False(t, ch.isFullySubmitted(), "frame not sent yet")
+
+	txdata := ch.NextTxData()
+	require.False(t, ch.isFullySubmitted(), "frame not confirmed yet")
+
+	ch.TxConfirmed(txdata.ID(), eth.BlockID{Number: 1})
+	require.True(t, ch.isFullySubmitted())
+}
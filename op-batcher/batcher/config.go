@@ -1,6 +1,7 @@
 package batcher
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -10,12 +11,14 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-batcher/flags"
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
-	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
+	"github.com/ethereum-optimism/optimism/op-node/dastore"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
 )
@@ -30,6 +33,10 @@ type Config struct {
 	PollInterval time.Duration
 	From         common.Address
 
+	// MaxPendingTransactions is the maximum number of concurrent pending
+	// transactions sent to the transaction manager. 0 means no limit.
+	MaxPendingTransactions uint64
+
 	TxManagerConfig txmgr.Config
 
 	// RollupConfig is queried at startup
@@ -37,6 +44,17 @@ type Config struct {
 
 	// Channel builder parameters
 	Channel ChannelConfig
+
+	// FeePolicy limits the L1 fees paid for non-urgent batcher transactions.
+	FeePolicy FeePolicyConfig
+
+	// DataDir is the directory to journal closed channels in. If empty, no
+	// journal is kept.
+	DataDir string
+
+	// DAStore is the DA store to write the batch data to, the batcher
+	// transactions then carry the commitment to the data. Calldata is used if nil.
+	DAStore dastore.Store
 }
 
 // Check ensures that the [Config] is valid.
@@ -47,6 +65,9 @@ func (c *Config) Check() error {
 	if err := c.Channel.Check(); err != nil {
 		return err
 	}
+	if c.DAStore != nil && c.Rollup.DACommitmentsTime == nil {
+		return errors.New("a DA store is configured, but no DA commitments activation time is set in the rollup config")
+	}
 	return nil
 }
 
@@ -64,10 +85,9 @@ type CLIConfig struct {
 
 	// MaxChannelDuration is the maximum duration (in #L1-blocks) to keep a
 	// channel open. This allows to more eagerly send batcher transactions
-	// during times of low L2 transaction volume. Note that the effective
-	// L1-block distance between batcher transactions is then MaxChannelDuration
-	// + NumConfirmations because the batcher waits for NumConfirmations blocks
-	// after sending a batcher tx and only then starts a new channel.
+	// during times of low L2 transaction volume. A new channel is started
+	// as soon as the previous one is full, even if the previous channel's
+	// batcher txs are not confirmed yet.
 	//
 	// If 0, duration checks are disabled.
 	MaxChannelDuration uint64
@@ -85,6 +105,12 @@ type CLIConfig struct {
 	// appending new batches.
 	NumConfirmations uint64
 
+	// MaxPendingTransactions is the maximum number of batcher transactions
+	// that can be in flight at the same time. Transactions are sent with
+	// sequential nonces. If 0, the number of pending transactions is not
+	// limited.
+	MaxPendingTransactions uint64
+
 	// SafeAbortNonceTooLowCount is the number of ErrNonceTooLowObservations
 	// required to give up on a tx at a particular nonce without receiving
 	// confirmation.
@@ -94,6 +120,10 @@ type CLIConfig struct {
 	// transaction.
 	ResubmissionTimeout time.Duration
 
+	// TxCancelTimeout is the duration after which a transaction that isn't
+	// mined yet is cancelled. 0 disables cancellation.
+	TxCancelTimeout time.Duration
+
 	// Mnemonic is the HD seed used to derive the wallet private keys for both
 	// the sequence and proposer. Must be used in conjunction with
 	// SequencerHDPath and ProposerHDPath.
@@ -106,7 +136,7 @@ type CLIConfig struct {
 	// PrivateKey is the private key used to submit sequencer transactions.
 	PrivateKey string
 
-	RPCConfig rpc.CLIConfig
+	RPCConfig oprpc.AdminCLIConfig
 
 	/* Optional Params */
 
@@ -123,6 +153,33 @@ type CLIConfig struct {
 	// compression algorithm.
 	ApproxComprRatio float64
 
+	// CompressionAlgo is the name of the compression algorithm to use for
+	// channels, once versioned channels are active.
+	CompressionAlgo string
+
+	// ShadowCompression enables exact-size channel filling, by measuring the
+	// compressed size of channels with a shadow compressor instead of
+	// estimating it with ApproxComprRatio.
+	ShadowCompression bool
+
+	// FeePolicyMaxBaseFeeGwei is the L1 base fee in gwei above which the
+	// submission of non-urgent transactions is deferred. 0 disables deferral.
+	FeePolicyMaxBaseFeeGwei float64
+
+	// FeePolicyMaxTipGwei is the maximum gas tip cap in gwei of non-urgent
+	// transactions. 0 disables the cap.
+	FeePolicyMaxTipGwei float64
+
+	// DataDir is the directory to persist the state of closed channels in, so
+	// that their submission can be continued after a restart. If empty, the
+	// state is only kept in memory.
+	DataDir string
+
+	// DAStore is the location of the DA store to write the batch data to, a
+	// directory or the http(s) URL of a DA server. If empty, the batch data is
+	// posted as calldata.
+	DAStore string
+
 	Stopped bool
 
 	LogConfig oplog.CLIConfig
@@ -151,6 +208,12 @@ func (c CLIConfig) Check() error {
 	if err := c.SignerConfig.Check(); err != nil {
 		return err
 	}
+	if _, err := derive.ParseCompressionAlgo(c.CompressionAlgo); err != nil {
+		return err
+	}
+	if c.FeePolicyMaxBaseFeeGwei < 0 || c.FeePolicyMaxTipGwei < 0 {
+		return errors.New("fee policy limits must not be negative")
+	}
 	return nil
 }
 
@@ -168,19 +231,27 @@ func NewConfig(ctx *cli.Context) CLIConfig {
 		ResubmissionTimeout:       ctx.GlobalDuration(flags.ResubmissionTimeoutFlag.Name),
 
 		/* Optional Flags */
-		MaxChannelDuration: ctx.GlobalUint64(flags.MaxChannelDurationFlag.Name),
-		MaxL1TxSize:        ctx.GlobalUint64(flags.MaxL1TxSizeBytesFlag.Name),
-		TargetL1TxSize:     ctx.GlobalUint64(flags.TargetL1TxSizeBytesFlag.Name),
-		TargetNumFrames:    ctx.GlobalInt(flags.TargetNumFramesFlag.Name),
-		ApproxComprRatio:   ctx.GlobalFloat64(flags.ApproxComprRatioFlag.Name),
-		Stopped:            ctx.GlobalBool(flags.StoppedFlag.Name),
-		Mnemonic:           ctx.GlobalString(flags.MnemonicFlag.Name),
-		SequencerHDPath:    ctx.GlobalString(flags.SequencerHDPathFlag.Name),
-		PrivateKey:         ctx.GlobalString(flags.PrivateKeyFlag.Name),
-		RPCConfig:          rpc.ReadCLIConfig(ctx),
-		LogConfig:          oplog.ReadCLIConfig(ctx),
-		MetricsConfig:      opmetrics.ReadCLIConfig(ctx),
-		PprofConfig:        oppprof.ReadCLIConfig(ctx),
-		SignerConfig:       opsigner.ReadCLIConfig(ctx),
+		TxCancelTimeout:         ctx.GlobalDuration(flags.TxCancelTimeoutFlag.Name),
+		MaxPendingTransactions:  ctx.GlobalUint64(flags.MaxPendingTransactionsFlag.Name),
+		MaxChannelDuration:      ctx.GlobalUint64(flags.MaxChannelDurationFlag.Name),
+		MaxL1TxSize:             ctx.GlobalUint64(flags.MaxL1TxSizeBytesFlag.Name),
+		TargetL1TxSize:          ctx.GlobalUint64(flags.TargetL1TxSizeBytesFlag.Name),
+		TargetNumFrames:         ctx.GlobalInt(flags.TargetNumFramesFlag.Name),
+		ApproxComprRatio:        ctx.GlobalFloat64(flags.ApproxComprRatioFlag.Name),
+		CompressionAlgo:         ctx.GlobalString(flags.CompressionAlgoFlag.Name),
+		ShadowCompression:       ctx.GlobalBool(flags.ShadowCompressionFlag.Name),
+		FeePolicyMaxBaseFeeGwei: ctx.GlobalFloat64(flags.FeePolicyMaxBaseFeeFlag.Name),
+		FeePolicyMaxTipGwei:     ctx.GlobalFloat64(flags.FeePolicyMaxTipFlag.Name),
+		DataDir:                 ctx.GlobalString(flags.DataDirFlag.Name),
+		DAStore:                 ctx.GlobalString(flags.DAStoreFlag.Name),
+		Stopped:                 ctx.GlobalBool(flags.StoppedFlag.Name),
+		Mnemonic:                ctx.GlobalString(flags.MnemonicFlag.Name),
+		SequencerHDPath:         ctx.GlobalString(flags.SequencerHDPathFlag.Name),
+		PrivateKey:              ctx.GlobalString(flags.PrivateKeyFlag.Name),
+		RPCConfig:               oprpc.ReadAdminCLIConfig(ctx),
+		LogConfig:               oplog.ReadCLIConfig(ctx),
+		MetricsConfig:           opmetrics.ReadCLIConfig(ctx),
+		PprofConfig:             oppprof.ReadCLIConfig(ctx),
+		SignerConfig:            opsigner.ReadCLIConfig(ctx),
 	}
 }
@@ -7,26 +7,39 @@ import (
 	"io"
 	"math/big"
 	_ "net/http/pprof"
+	"path/filepath"
 	"sync"
 	"time"
 
+	leveldb "github.com/ipfs/go-ds-leveldb"
+
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
+	"github.com/ethereum-optimism/optimism/op-node/dastore"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	opcrypto "github.com/ethereum-optimism/optimism/op-service/crypto"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 )
 
+const networkTimeout = 2 * time.Second // How long a single network request can take. TODO: put in a config somewhere
+
 // BatchSubmitter encapsulates a service responsible for submitting L2 tx
 // batches to L1 for availability.
 type BatchSubmitter struct {
 	Config // directly embed the config + sources
 
-	txMgr *TransactionManager
-	wg    sync.WaitGroup
-	done  chan struct{}
+	// queue sends the batcher transactions. It is created for every run of the
+	// event loop, with the lifetime context of the run.
+	queue     *txmgr.Queue[txID]
+	feePolicy feePolicy
+	wg        sync.WaitGroup
+	done      chan struct{}
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -37,8 +50,17 @@ type BatchSubmitter struct {
 	// lastStoredBlock is the last block loaded into `state`. If it is empty it should be set to the l2 safe head.
 	lastStoredBlock eth.BlockID
 	lastL1Tip       eth.L1BlockRef
+	// pendingTxs is the number of batcher transactions currently in flight.
+	// It is only accessed from the event loop.
+	pendingTxs uint64
 
 	state *channelManager
+	// journal of closed channels. nil if no data dir is configured.
+	journal *journal
+
+	// publishSignal triggers the event loop to publish the state to L1 before
+	// the next tick, e.g. after a channel got force closed.
+	publishSignal chan struct{}
 }
 
 // NewBatchSubmitterFromCLIConfig initializes the BatchSubmitter, gathering any resources
@@ -73,9 +95,16 @@ func NewBatchSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metri
 		return nil, fmt.Errorf("querying rollup config: %w", err)
 	}
 
+	comprAlgo, err := derive.ParseCompressionAlgo(cfg.CompressionAlgo)
+	if err != nil {
+		return nil, err
+	}
+
 	txManagerConfig := txmgr.Config{
 		ResubmissionTimeout:       cfg.ResubmissionTimeout,
 		ReceiptQueryInterval:      time.Second,
+		CancelTimeout:             cfg.TxCancelTimeout,
+		TxSendTimeout:             10 * time.Minute, // TODO: Select a timeout that makes sense here.
 		NumConfirmations:          cfg.NumConfirmations,
 		SafeAbortNonceTooLowCount: cfg.SafeAbortNonceTooLowCount,
 		From:                      fromAddress,
@@ -83,13 +112,14 @@ func NewBatchSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metri
 	}
 
 	batcherCfg := Config{
-		L1Client:        l1Client,
-		L2Client:        l2Client,
-		RollupNode:      rollupClient,
-		PollInterval:    cfg.PollInterval,
-		TxManagerConfig: txManagerConfig,
-		From:            fromAddress,
-		Rollup:          rcfg,
+		L1Client:               l1Client,
+		L2Client:               l2Client,
+		RollupNode:             rollupClient,
+		PollInterval:           cfg.PollInterval,
+		MaxPendingTransactions: cfg.MaxPendingTransactions,
+		TxManagerConfig:        txManagerConfig,
+		From:                   fromAddress,
+		Rollup:                 rcfg,
 		Channel: ChannelConfig{
 			SeqWindowSize:      rcfg.SeqWindowSize,
 			ChannelTimeout:     rcfg.ChannelTimeout,
@@ -99,7 +129,23 @@ func NewBatchSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metri
 			TargetFrameSize:    cfg.TargetL1TxSize - 1, // subtract 1 byte for version
 			TargetNumFrames:    cfg.TargetNumFrames,
 			ApproxComprRatio:   cfg.ApproxComprRatio,
+
+			CompressionAlgo:        comprAlgo,
+			ChannelCompressionTime: rcfg.ChannelCompressionTime,
+			ShadowCompression:      cfg.ShadowCompression,
+		},
+		FeePolicy: FeePolicyConfig{
+			MaxBaseFee: GweiToWei(cfg.FeePolicyMaxBaseFeeGwei),
+			MaxTipCap:  GweiToWei(cfg.FeePolicyMaxTipGwei),
 		},
+		DataDir: cfg.DataDir,
+	}
+
+	if cfg.DAStore != "" {
+		batcherCfg.DAStore, err = dastore.Open(cfg.DAStore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open DA store at %q: %w", cfg.DAStore, err)
+		}
 	}
 
 	// Validate the batcher config
@@ -123,12 +169,24 @@ func NewBatchSubmitter(ctx context.Context, cfg Config, l log.Logger, m metrics.
 
 	cfg.metr = m
 
+	state := NewChannelManager(l, m, cfg.Channel)
+	var j *journal
+	if cfg.DataDir != "" {
+		store, err := leveldb.NewDatastore(filepath.Join(cfg.DataDir, "journal"), nil) // default leveldb options are fine
+		if err != nil {
+			return nil, fmt.Errorf("failed to open leveldb db for journal: %w", err)
+		}
+		j = newJournal(l, store)
+		state.journal = j
+		cfg.log.Info("journaling closed channels", "data_dir", cfg.DataDir)
+	}
+
 	return &BatchSubmitter{
-		Config: cfg,
-		txMgr: NewTransactionManager(l,
-			cfg.TxManagerConfig, cfg.Rollup.BatchInboxAddress, cfg.Rollup.L1ChainID,
-			cfg.From, cfg.L1Client),
-		state: NewChannelManager(l, m, cfg.Channel),
+		Config:        cfg,
+		feePolicy:     feePolicy{cfg: cfg.FeePolicy},
+		state:         state,
+		journal:       j,
+		publishSignal: make(chan struct{}, 1),
 	}, nil
 
 }
@@ -148,8 +206,10 @@ func (l *BatchSubmitter) Start() error {
 	// TODO: this context only exists because the event loop doesn't reach done
 	// if the tx manager is blocking forever due to e.g. insufficient balance.
 	l.ctx, l.cancel = context.WithCancel(context.Background())
-	l.state.Clear()
+	// Keep the journal, its channels are restored at the start of the loop.
+	l.state.clearState()
 	l.lastStoredBlock = eth.BlockID{}
+	l.pendingTxs = 0
 
 	l.wg.Add(1)
 	go l.loop()
@@ -183,6 +243,192 @@ func (l *BatchSubmitter) Stop() error {
 	return nil
 }
 
+// Close releases the resources of the batch submitter. It must only be called
+// after the batch submitter is stopped.
+func (l *BatchSubmitter) Close() error {
+	if l.journal != nil {
+		return l.journal.Close()
+	}
+	return nil
+}
+
+// ChannelStatus returns the status of the channel pipeline.
+func (l *BatchSubmitter) ChannelStatus() rpc.Status {
+	return l.state.Status()
+}
+
+// PendingTxs returns all batcher transactions that are in flight.
+func (l *BatchSubmitter) PendingTxs() []rpc.PendingTx {
+	return l.state.PendingTxs()
+}
+
+// ForceCloseChannel closes the current channel and triggers the submission of
+// its frames, without waiting for the next poll interval.
+func (l *BatchSubmitter) ForceCloseChannel() error {
+	if err := l.state.ForceCloseChannel(); err != nil {
+		return err
+	}
+	select {
+	case l.publishSignal <- struct{}{}:
+	default: // publishing already triggered
+	}
+	return nil
+}
+
+// SetChannelConfig changes the config of channels opened from now on.
+func (l *BatchSubmitter) SetChannelConfig(update rpc.ChannelConfigUpdate) error {
+	return l.state.SetChannelConfig(update)
+}
+
+// restoreState restores the closed channels from the journal, if configured.
+// If the journal cannot be restored, it is cleared and the batcher starts
+// submitting from the safe head again. The journaled transactions that are
+// still pending are monitored again, and their results are reported back on
+// receiptsCh.
+func (l *BatchSubmitter) restoreState(ctx context.Context, receiptsCh chan<- txmgr.TxReceipt[txID]) {
+	if l.journal == nil {
+		return
+	}
+	pending, err := l.tryRestoreState(ctx)
+	if err != nil {
+		l.log.Warn("Failed to restore channels from journal, starting from the safe head", "err", err)
+		l.state.Clear()
+		l.lastStoredBlock = eth.BlockID{}
+		return
+	}
+	for id, tx := range pending {
+		l.log.Info("Resuming journaled transaction", "id", id, "tx_hash", tx.Hash(), "nonce", tx.Nonce())
+		l.pendingTxs++
+		l.queue.Resume(id, tx, receiptsCh)
+	}
+}
+
+// tryRestoreState restores all journaled channels whose blocks aren't safe yet.
+// The blocks of the channels are loaded from L2 again and checked against the
+// journal. Frames of in-flight transactions that landed on L1 in the meantime
+// are restored as confirmed, and frames of transactions that are still pending
+// are restored as in flight, so they don't get posted again. It returns the
+// pending transactions.
+func (l *BatchSubmitter) tryRestoreState(ctx context.Context) (map[txID]*types.Transaction, error) {
+	jchs, err := l.journal.channels(ctx)
+	if err != nil {
+		return nil, err
+	} else if len(jchs) == 0 {
+		return nil, nil
+	}
+
+	childCtx, cancel := context.WithTimeout(ctx, networkTimeout)
+	syncStatus, err := l.RollupNode.SyncStatus(childCtx)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync status: %w", err)
+	}
+
+	pending := make(map[txID]*types.Transaction)
+	for _, jc := range jchs {
+		last := jc.Blocks[len(jc.Blocks)-1]
+		if last.Number <= syncStatus.SafeL2.Number {
+			l.log.Info("Discarding journaled channel with safe blocks", "id", jc.ID, "last_block", last, "safe", syncStatus.SafeL2)
+			l.journal.deleteChannel(jc.ID)
+			continue
+		}
+
+		blocks := make([]*types.Block, 0, len(jc.Blocks))
+		for _, id := range jc.Blocks {
+			block, err := l.fetchL2Block(ctx, id.Number)
+			if err != nil {
+				return nil, err
+			}
+			if block.Hash() != id.Hash {
+				return nil, fmt.Errorf("journaled block %s got reorged: %w", id, ErrReorg)
+			}
+			blocks = append(blocks, block)
+		}
+
+		landed, pendingTxs := l.journaledTxs(ctx, jc)
+		pendingIDs := make(map[txID]struct{}, len(pendingTxs))
+		for id, tx := range pendingTxs {
+			pendingIDs[id] = struct{}{}
+			pending[id] = tx
+		}
+		if err := l.state.restoreChannel(jc, blocks, landed, pendingIDs); err != nil {
+			return nil, err
+		}
+		l.lastStoredBlock = last
+	}
+	return pending, nil
+}
+
+// journaledTxs looks up the in-flight transactions of the given journaled
+// channel on L1. It returns the frames whose transactions got included, with
+// their inclusion blocks, and the frames whose transactions are still pending,
+// with their transactions. Frames whose transactions cannot be found are not
+// included, and will be resubmitted.
+func (l *BatchSubmitter) journaledTxs(ctx context.Context, jc journalChannel) (landed map[txID]eth.BlockID, pending map[txID]*types.Transaction) {
+	landed = make(map[txID]eth.BlockID)
+	pending = make(map[txID]*types.Transaction)
+	for _, f := range jc.Frames {
+		if len(f.TxHashes) == 0 || f.InclusionBlock != nil {
+			continue
+		}
+		id := frameID{chID: jc.ID, frameNumber: f.Number}
+		inclusionBlock, tx := l.journaledTx(ctx, id, f.TxHashes)
+		if inclusionBlock != nil {
+			landed[id] = *inclusionBlock
+		} else if tx != nil {
+			pending[id] = tx
+		}
+	}
+	return landed, pending
+}
+
+// journaledTx looks up the published versions of a journaled frame's
+// transaction on L1, newest first. It returns the inclusion block if a version
+// got included successfully, or else the newest version that is still pending.
+// If neither is found, the frame has to be resubmitted.
+func (l *BatchSubmitter) journaledTx(ctx context.Context, id txID, txHashes []common.Hash) (*eth.BlockID, *types.Transaction) {
+	var pendingTx *types.Transaction
+	for i := len(txHashes) - 1; i >= 0; i-- {
+		txHash := txHashes[i]
+		childCtx, cancel := context.WithTimeout(ctx, networkTimeout)
+		receipt, err := l.L1Client.TransactionReceipt(childCtx, txHash)
+		cancel()
+		if errors.Is(err, ethereum.NotFound) {
+			if pendingTx != nil {
+				continue
+			}
+			// The transaction may still be waiting in the mempool, in which
+			// case resubmitting the frame would post it twice.
+			childCtx, cancel := context.WithTimeout(ctx, networkTimeout)
+			tx, isPending, err := l.L1Client.TransactionByHash(childCtx, txHash)
+			cancel()
+			if err == nil && isPending {
+				pendingTx = tx
+			} else if err != nil && !errors.Is(err, ethereum.NotFound) {
+				l.log.Warn("Failed to query journaled tx", "id", id, "tx_hash", txHash, "err", err)
+			}
+			continue
+		} else if err != nil {
+			l.log.Warn("Failed to query receipt of journaled tx", "id", id, "tx_hash", txHash, "err", err)
+			continue
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			// The nonce got used by the reverted transaction, so none of the
+			// other versions can get included anymore.
+			l.log.Warn("Journaled transaction reverted, resubmitting frame", "id", id, "tx_hash", txHash)
+			return nil, nil
+		}
+		l.log.Info("Journaled transaction landed", "id", id, "tx_hash", receipt.TxHash, "block_number", receipt.BlockNumber)
+		return &eth.BlockID{Number: receipt.BlockNumber.Uint64(), Hash: receipt.BlockHash}, nil
+	}
+	if pendingTx != nil {
+		l.log.Info("Journaled transaction is still pending", "id", id, "tx_hash", pendingTx.Hash())
+	} else {
+		l.log.Warn("Journaled transaction not found, resubmitting frame", "id", id, "tx_hashes", txHashes)
+	}
+	return nil, pendingTx
+}
+
 // loadBlocksIntoState loads all blocks since the previous stored block
 // It does the following:
 // 1. Fetch the sync status of the sequencer
@@ -226,11 +472,9 @@ func (l *BatchSubmitter) loadBlocksIntoState(ctx context.Context) {
 
 // loadBlockIntoState fetches & stores a single block into `state`. It returns the block it loaded.
 func (l *BatchSubmitter) loadBlockIntoState(ctx context.Context, blockNumber uint64) (*types.Block, error) {
-	ctx, cancel := context.WithTimeout(ctx, networkTimeout)
-	defer cancel()
-	block, err := l.L2Client.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	block, err := l.fetchL2Block(ctx, blockNumber)
 	if err != nil {
-		return nil, fmt.Errorf("getting L2 block: %w", err)
+		return nil, err
 	}
 
 	if err := l.state.AddL2Block(block); err != nil {
@@ -241,6 +485,17 @@ func (l *BatchSubmitter) loadBlockIntoState(ctx context.Context, blockNumber uin
 	return block, nil
 }
 
+// fetchL2Block fetches the L2 block with the given number.
+func (l *BatchSubmitter) fetchL2Block(ctx context.Context, blockNumber uint64) (*types.Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, networkTimeout)
+	defer cancel()
+	block, err := l.L2Client.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("getting L2 block: %w", err)
+	}
+	return block, nil
+}
+
 // calculateL2BlockRangeToStore determines the range (start,end] that should be loaded into the local state.
 // It also takes care of initializing some local state (i.e. will modify l.lastStoredBlock in certain conditions)
 func (l *BatchSubmitter) calculateL2BlockRangeToStore(ctx context.Context) (eth.BlockID, eth.BlockID, error) {
@@ -289,45 +544,33 @@ func (l *BatchSubmitter) loop() {
 
 	ticker := time.NewTicker(l.PollInterval)
 	defer ticker.Stop()
+
+	l.queue = txmgr.NewQueue[txID](l.ctx, "batcher", l.log, l.metr, l.TxManagerConfig, l.L1Client, l.Rollup.L1ChainID, 0)
+	// Every published version of a transaction is journaled, because fee bumps
+	// and re-crafting change its hash.
+	l.queue.SetPublishHook(func(id txID, tx *types.Transaction) {
+		l.state.TxSent(id, tx.Hash())
+	})
+	// The sends stop once the lifetime context is canceled.
+	defer l.queue.Wait()
+
+	receiptsCh := make(chan txmgr.TxReceipt[txID])
+	l.restoreState(l.ctx, receiptsCh)
+
 	for {
 		select {
 		case <-ticker.C:
 			l.loadBlocksIntoState(l.ctx)
+			l.publishStateToL1(receiptsCh)
 
-		blockLoop:
-			for {
-				l1tip, err := l.l1Tip(l.ctx)
-				if err != nil {
-					l.log.Error("Failed to query L1 tip", "error", err)
-					break
-				}
-				l.recordL1Tip(l1tip)
-
-				// Collect next transaction data
-				txdata, err := l.state.TxData(l1tip.ID())
-				if err == io.EOF {
-					l.log.Trace("no transaction data available")
-					break // local for loop
-				} else if err != nil {
-					l.log.Error("unable to get tx data", "err", err)
-					break
-				}
-				// Record TX Status
-				if receipt, err := l.txMgr.SendTransaction(l.ctx, txdata.Bytes()); err != nil {
-					l.recordFailedTx(txdata.ID(), err)
-				} else {
-					l.recordConfirmedTx(txdata.ID(), receipt)
-				}
-
-				// hack to exit this loop. Proper fix is to do request another send tx or parallel tx sending
-				// from the channel manager rather than sending the channel in a loop. This stalls b/c if the
-				// context is cancelled while sending, it will never fully clear the pending txns.
-				select {
-				case <-l.ctx.Done():
-					break blockLoop
-				default:
-				}
-			}
+		case <-l.publishSignal:
+			l.publishStateToL1(receiptsCh)
+
+		case r := <-receiptsCh:
+			l.handleReceipt(r)
+			// A transaction slot got freed up, so try to fill it immediately
+			// instead of waiting for the next tick.
+			l.publishStateToL1(receiptsCh)
 
 		case <-l.done:
 			return
@@ -335,6 +578,109 @@ func (l *BatchSubmitter) loop() {
 	}
 }
 
+// publishStateToL1 sends transactions with all available tx data to L1, as
+// long as fewer than MaxPendingTransactions are in flight. The transactions are
+// sent concurrently, and their results are reported back on receiptsCh.
+func (l *BatchSubmitter) publishStateToL1(receiptsCh chan<- txmgr.TxReceipt[txID]) {
+	for l.MaxPendingTransactions == 0 || l.pendingTxs < l.MaxPendingTransactions {
+		// Stop publishing if the batcher is shutting down.
+		select {
+		case <-l.ctx.Done():
+			return
+		default:
+		}
+
+		l1tip, err := l.l1Tip(l.ctx)
+		if err != nil {
+			l.log.Error("Failed to query L1 tip", "error", err)
+			return
+		}
+		if l1tip != l.lastL1Tip {
+			if err := l.rewindReorgedTxs(l1tip); err != nil {
+				l.log.Error("Failed to check L1 inclusion blocks for reorgs", "error", err)
+				return
+			}
+		}
+		l.recordL1Tip(l1tip)
+
+		// Collect next transaction data
+		txdata, err := l.state.TxData(l1tip)
+		if err == io.EOF {
+			l.log.Trace("no transaction data available")
+			return
+		} else if err != nil {
+			l.log.Error("unable to get tx data", "err", err)
+			return
+		}
+
+		// Transactions are crafted sequentially in the event loop, so that
+		// they get assigned sequential nonces.
+		data, err := l.calldata(txdata, l1tip)
+		if err != nil {
+			l.recordFailedTx(txdata.ID(), err)
+			return
+		}
+		gas, err := core.IntrinsicGas(data, nil, false, true, true, false)
+		if err != nil {
+			l.recordFailedTx(txdata.ID(), fmt.Errorf("failed to calculate intrinsic gas: %w", err))
+			return
+		}
+		urgent := l.state.IsUrgent(txdata.ID(), l1tip.Number)
+		tx, err := l.queue.TrySend(txdata.ID(), txmgr.TxCandidate{
+			To:        l.Rollup.BatchInboxAddress,
+			TxData:    data,
+			GasLimit:  gas,
+			FeePolicy: l.feePolicy.apply(l.metr, urgent),
+		}, receiptsCh)
+		if errors.Is(err, ErrSubmissionDeferred) {
+			// The channels are held until L1 fees drop or they become urgent.
+			l.log.Info("Deferring transaction submission", "id", txdata.ID(), "err", err)
+			l.state.TxDeferred(txdata.ID())
+			return
+		} else if err != nil {
+			l.recordFailedTx(txdata.ID(), err)
+			return
+		}
+		l.log.Info("Sent transaction", "id", txdata.ID(), "tx_hash", tx.Hash(), "nonce", tx.Nonce(), "data_size", len(data))
+		l.pendingTxs++
+	}
+}
+
+// calldata returns the data of the batcher transaction that submits the tx data.
+// If a DA store is configured, and DA commitments are active at the time of the
+// L1 tip, the tx data is written to the DA store, and the batcher transaction
+// only carries the commitment to it. The transaction can only be included in a
+// later L1 block, in which DA commitments are active too.
+func (l *BatchSubmitter) calldata(txdata txData, l1tip eth.L1BlockRef) ([]byte, error) {
+	data := txdata.Bytes()
+	if l.DAStore == nil || !l.Rollup.IsDACommitments(l1tip.Time) {
+		return data, nil
+	}
+	commitment, calldata := derive.DACommitment(data)
+	if err := l.DAStore.Put(l.ctx, commitment, data); err != nil {
+		return nil, fmt.Errorf("failed to write tx data to DA store: %w", err)
+	}
+	l.log.Debug("Wrote tx data to DA store", "id", txdata.ID(), "commitment", commitment)
+	return calldata, nil
+}
+
+// handleReceipt records the result of a sent transaction in the channel manager.
+func (l *BatchSubmitter) handleReceipt(r txmgr.TxReceipt[txID]) {
+	l.pendingTxs--
+	// Record TX Status
+	switch {
+	case errors.Is(r.Err, ErrSubmissionDeferred):
+		// The transaction got deferred when it was re-crafted after its nonce
+		// got used.
+		l.log.Info("Deferring transaction submission", "id", r.ID, "err", r.Err)
+		l.state.TxDeferred(r.ID)
+	case r.Err != nil:
+		l.recordFailedTx(r.ID, r.Err)
+	default:
+		l.recordConfirmedTx(r.ID, r.Receipt)
+	}
+}
+
 func (l *BatchSubmitter) recordL1Tip(l1tip eth.L1BlockRef) {
 	if l.lastL1Tip == l1tip {
 		return
@@ -354,6 +700,35 @@ func (l *BatchSubmitter) recordConfirmedTx(id txID, receipt *types.Receipt) {
 	l.state.TxConfirmed(id, l1block)
 }
 
+// rewindReorgedTxs re-queues the frames of all confirmed transactions whose
+// inclusion block is no longer canonical. Inclusion blocks are checked by hash,
+// so that reorgs that didn't change the height of the L1 chain are detected
+// too. Inclusion blocks ahead of the L1 tip are rewound by TxData.
+func (l *BatchSubmitter) rewindReorgedTxs(l1tip eth.L1BlockRef) error {
+	reorged := make(map[eth.BlockID]struct{})
+	for _, inclusionBlock := range l.state.InclusionBlocks() {
+		if inclusionBlock.Number > l1tip.Number {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(l.ctx, networkTimeout)
+		header, err := l.L1Client.HeaderByNumber(ctx, new(big.Int).SetUint64(inclusionBlock.Number))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("getting L1 block %d: %w", inclusionBlock.Number, err)
+		}
+		if header.Hash() != inclusionBlock.Hash {
+			reorged[inclusionBlock] = struct{}{}
+		}
+	}
+	if len(reorged) > 0 {
+		l.state.RewindReorgedTxs(func(inclusionBlock eth.BlockID) bool {
+			_, ok := reorged[inclusionBlock]
+			return ok
+		})
+	}
+	return nil
+}
+
 // l1Tip gets the current L1 tip as a L1BlockRef. The passed context is assumed
 // to be a lifetime context, so it is internally wrapped with a network timeout.
 func (l *BatchSubmitter) l1Tip(ctx context.Context) (eth.L1BlockRef, error) {
@@ -0,0 +1,71 @@
+package batcher
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var ErrSubmissionDeferred = errors.New("submission deferred by fee policy")
+
+// FeePolicyConfig configures the fee limits of batcher transactions. Urgent
+// transactions, whose channel gets close to timing out, are always submitted
+// at the suggested fees. The zero value disables all limits.
+type FeePolicyConfig struct {
+	// MaxBaseFee is the L1 base fee above which the submission of non-urgent
+	// transactions is deferred. If nil, submission is never deferred.
+	MaxBaseFee *big.Int
+	// MaxTipCap is the maximum gas tip cap of non-urgent transactions. Higher
+	// suggested tips are capped. If nil, the suggested tip is always used.
+	MaxTipCap *big.Int
+}
+
+// GweiToWei converts the given amount of gwei to wei. It returns nil for
+// non-positive amounts, which disables the respective fee limit.
+func GweiToWei(gwei float64) *big.Int {
+	if gwei <= 0 {
+		return nil
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(params.GWei)).Int(nil)
+	return wei
+}
+
+// feePolicy decides whether and at which tip a batcher transaction is submitted.
+type feePolicy struct {
+	cfg FeePolicyConfig
+}
+
+// apply returns the fee policy of a batcher transaction, as applied by the
+// txmgr.Queue when crafting it, and by the tx manager when bumping its fees.
+// It records the fee decision and returns an error wrapping
+// ErrSubmissionDeferred if the submission must be deferred.
+func (p feePolicy) apply(metr metrics.Metricer, urgent bool) func(baseFee, gasTipCap *big.Int) (*big.Int, error) {
+	return func(baseFee, gasTipCap *big.Int) (*big.Int, error) {
+		decision, gasTipCap := p.decide(baseFee, gasTipCap, urgent)
+		metr.RecordFeeDecision(decision)
+		if decision == metrics.FeeDecisionDefer {
+			return nil, fmt.Errorf("%w: L1 basefee %v above max %v", ErrSubmissionDeferred, baseFee, p.cfg.MaxBaseFee)
+		}
+		return gasTipCap, nil
+	}
+}
+
+// decide returns the fee decision for a transaction at the given L1 base fee
+// and suggested tip, together with the tip to use. The decision is one of the
+// metrics.FeeDecision* values. If it is metrics.FeeDecisionDefer, the
+// transaction must not be submitted yet.
+func (p feePolicy) decide(baseFee, suggestedTip *big.Int, urgent bool) (string, *big.Int) {
+	if urgent {
+		return metrics.FeeDecisionUrgent, suggestedTip
+	}
+	if p.cfg.MaxBaseFee != nil && baseFee.Cmp(p.cfg.MaxBaseFee) > 0 {
+		return metrics.FeeDecisionDefer, nil
+	}
+	if p.cfg.MaxTipCap != nil && suggestedTip.Cmp(p.cfg.MaxTipCap) > 0 {
+		return metrics.FeeDecisionCapped, new(big.Int).Set(p.cfg.MaxTipCap)
+	}
+	return metrics.FeeDecisionSubmit, suggestedTip
+}
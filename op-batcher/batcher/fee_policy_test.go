@@ -0,0 +1,71 @@
+package batcher
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+)
+
+func TestFeePolicy_Decide(t *testing.T) {
+	policy := feePolicy{cfg: FeePolicyConfig{
+		MaxBaseFee: big.NewInt(100),
+		MaxTipCap:  big.NewInt(10),
+	}}
+
+	tests := []struct {
+		name     string
+		baseFee  int64
+		tip      int64
+		urgent   bool
+		decision string
+		expTip   *big.Int
+	}{
+		{"submit", 100, 10, false, metrics.FeeDecisionSubmit, big.NewInt(10)},
+		{"capped", 100, 11, false, metrics.FeeDecisionCapped, big.NewInt(10)},
+		{"defer", 101, 1, false, metrics.FeeDecisionDefer, nil},
+		{"urgent", 101, 11, true, metrics.FeeDecisionUrgent, big.NewInt(11)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			decision, tip := policy.decide(big.NewInt(tt.baseFee), big.NewInt(tt.tip), tt.urgent)
+			require.Equal(t, tt.decision, decision)
+			require.Equal(t, tt.expTip, tip)
+		})
+	}
+}
+
+func TestFeePolicy_Disabled(t *testing.T) {
+	var policy feePolicy
+	decision, tip := policy.decide(big.NewInt(1e18), big.NewInt(1e18), false)
+	require.Equal(t, metrics.FeeDecisionSubmit, decision)
+	require.Equal(t, big.NewInt(1e18), tip)
+}
+
+func TestFeePolicy_Apply(t *testing.T) {
+	policy := feePolicy{cfg: FeePolicyConfig{
+		MaxBaseFee: big.NewInt(100),
+		MaxTipCap:  big.NewInt(10),
+	}}
+
+	tip, err := policy.apply(metrics.NoopMetrics, false)(big.NewInt(100), big.NewInt(11))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10), tip)
+
+	_, err = policy.apply(metrics.NoopMetrics, false)(big.NewInt(101), big.NewInt(1))
+	require.ErrorIs(t, err, ErrSubmissionDeferred)
+
+	tip, err = policy.apply(metrics.NoopMetrics, true)(big.NewInt(101), big.NewInt(11))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(11), tip)
+}
+
+func TestGweiToWei(t *testing.T) {
+	require.Nil(t, GweiToWei(0))
+	require.Nil(t, GweiToWei(-1))
+	require.Equal(t, big.NewInt(1_500_000_000), GweiToWei(1.5))
+	require.Equal(t, big.NewInt(100_000_000_000), GweiToWei(100))
+}
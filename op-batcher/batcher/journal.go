@@ -0,0 +1,145 @@
+package batcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// journalChannelsPrefix is the key prefix under which closed channels are
+// journaled, followed by the channel ID.
+const journalChannelsPrefix = "/channels"
+
+// journal persists the state of closed channels, so that the batcher can
+// continue submitting them after a restart instead of re-batching their blocks.
+//
+// Only closed channels are journaled, because the compression state of a
+// channel that still accepts blocks cannot be restored. The blocks of such a
+// channel are loaded again from L2 after a restart.
+//
+// Writes to the journal are best-effort: failures are logged, but don't stop
+// the batcher.
+type journal struct {
+	log   log.Logger
+	store ds.Batching
+}
+
+// journalChannel is the journaled state of a closed channel.
+type journalChannel struct {
+	ID derive.ChannelID `json:"id"`
+	// Blocks are the L2 blocks of the channel, in order.
+	Blocks []eth.BlockID `json:"blocks"`
+	// Frames are all frames of the channel, in order of frame number.
+	Frames []journalFrame `json:"frames"`
+}
+
+// journalFrame is the journaled state of a single frame of a closed channel.
+type journalFrame struct {
+	Number uint16        `json:"number"`
+	Data   hexutil.Bytes `json:"data"`
+	// TxHashes are the hashes of all published versions of the pending
+	// transaction that was sent for this frame, oldest first. Fee bumps and
+	// re-crafting change the hash, and any version may get included. It is
+	// empty if the frame is not in flight.
+	TxHashes []common.Hash `json:"txHashes,omitempty"`
+	// InclusionBlock is the L1 block that the frame got confirmed in. It is nil
+	// if the frame is not confirmed yet.
+	InclusionBlock *eth.BlockID `json:"inclusionBlock,omitempty"`
+}
+
+func newJournal(log log.Logger, store ds.Batching) *journal {
+	return &journal{
+		log:   log,
+		store: store,
+	}
+}
+
+func journalChannelKey(id derive.ChannelID) ds.Key {
+	return ds.NewKey(journalChannelsPrefix).ChildString(id.String())
+}
+
+// putChannel journals the current state of the given channel. It does nothing
+// if the channel isn't closed yet.
+func (j *journal) putChannel(ch *channel) {
+	if !ch.channelBuilder.IsClosed() {
+		return
+	}
+	data, err := json.Marshal(ch.journalState())
+	if err != nil {
+		j.log.Error("failed to encode channel for journal", "id", ch.ID(), "err", err)
+		return
+	}
+	if err := j.store.Put(context.Background(), journalChannelKey(ch.ID()), data); err != nil {
+		j.log.Error("failed to journal channel", "id", ch.ID(), "err", err)
+	}
+}
+
+// deleteChannel removes the given channel from the journal.
+func (j *journal) deleteChannel(id derive.ChannelID) {
+	if err := j.store.Delete(context.Background(), journalChannelKey(id)); err != nil {
+		j.log.Error("failed to delete channel from journal", "id", id, "err", err)
+	}
+}
+
+// channels returns all journaled channels, ordered by their first block.
+func (j *journal) channels(ctx context.Context) ([]journalChannel, error) {
+	res, err := j.store.Query(ctx, query.Query{Prefix: journalChannelsPrefix})
+	if err != nil {
+		return nil, fmt.Errorf("querying journal: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+
+	chs := make([]journalChannel, 0, len(entries))
+	for _, e := range entries {
+		var ch journalChannel
+		if err := json.Unmarshal(e.Value, &ch); err != nil {
+			return nil, fmt.Errorf("decoding journaled channel %s: %w", e.Key, err)
+		}
+		if len(ch.Blocks) == 0 {
+			return nil, fmt.Errorf("journaled channel %s has no blocks", ch.ID)
+		}
+		chs = append(chs, ch)
+	}
+	sort.Slice(chs, func(i, k int) bool {
+		return chs[i].Blocks[0].Number < chs[k].Blocks[0].Number
+	})
+	return chs, nil
+}
+
+// clear removes all channels from the journal.
+func (j *journal) clear(ctx context.Context) error {
+	res, err := j.store.Query(ctx, query.Query{Prefix: journalChannelsPrefix, KeysOnly: true})
+	if err != nil {
+		return fmt.Errorf("querying journal: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return fmt.Errorf("reading journal: %w", err)
+	}
+	var result *multierror.Error
+	for _, e := range entries {
+		if err := j.store.Delete(ctx, ds.NewKey(e.Key)); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+// Close closes the underlying store.
+func (j *journal) Close() error {
+	return j.store.Close()
+}
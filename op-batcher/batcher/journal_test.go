@@ -0,0 +1,175 @@
+package batcher
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var journalTestChannelConfig = ChannelConfig{
+	ChannelTimeout: 10,
+	// every block fills a channel
+	TargetFrameSize:  0,
+	MaxFrameSize:     120_000,
+	ApproxComprRatio: 1.0,
+}
+
+// setupJournaledChannel creates a channel manager with a journal and a single
+// closed channel, whose only frame is in flight.
+func setupJournaledChannel(t *testing.T) (*journal, *types.Block, txData) {
+	log := testlog.Logger(t, log.LvlCrit)
+	j := newJournal(log, sync.MutexWrap(ds.NewMapDatastore()))
+	m := NewChannelManager(log, metrics.NoopMetrics, journalTestChannelConfig)
+	m.journal = j
+
+	block := newMiniL2Block(0)
+	require.NoError(t, m.AddL2Block(block))
+	txdata, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(t, err)
+	m.TxSent(txdata.ID(), common.Hash{0xaa})
+	return j, block, txdata
+}
+
+func TestJournal_RestoreResubmitsPendingFrames(t *testing.T) {
+	require := require.New(t)
+	j, block, txdata := setupJournaledChannel(t)
+
+	jchs, err := j.channels(context.Background())
+	require.NoError(err)
+	require.Len(jchs, 1)
+	jc := jchs[0]
+	require.Equal(txdata.ID().chID, jc.ID)
+	require.Equal([]eth.BlockID{eth.ToBlockID(block)}, jc.Blocks)
+	require.Len(jc.Frames, 1)
+	require.Equal([]common.Hash{{0xaa}}, jc.Frames[0].TxHashes)
+	require.Nil(jc.Frames[0].InclusionBlock)
+
+	// restore into a fresh channel manager, without the tx having landed
+	m := NewChannelManager(testlog.Logger(t, log.LvlCrit), metrics.NoopMetrics, journalTestChannelConfig)
+	m.journal = j
+	require.NoError(m.restoreChannel(jc, []*types.Block{block}, nil, nil))
+	require.Equal(block.Hash(), m.tip)
+
+	restored, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	require.Equal(txdata, restored)
+
+	m.TxConfirmed(restored.ID(), eth.BlockID{Number: 1})
+	require.Empty(m.channelQueue)
+	jchs, err = j.channels(context.Background())
+	require.NoError(err)
+	require.Empty(jchs, "fully submitted channel must be removed from the journal")
+}
+
+func TestJournal_RestoreSkipsLandedFrames(t *testing.T) {
+	require := require.New(t)
+	j, block, txdata := setupJournaledChannel(t)
+
+	jchs, err := j.channels(context.Background())
+	require.NoError(err)
+	require.Len(jchs, 1)
+
+	m := NewChannelManager(testlog.Logger(t, log.LvlCrit), metrics.NoopMetrics, journalTestChannelConfig)
+	m.journal = j
+	landed := map[txID]eth.BlockID{txdata.ID(): {Number: 1}}
+	require.NoError(m.restoreChannel(jchs[0], []*types.Block{block}, landed, nil))
+
+	// The only frame landed, so the channel is fully submitted.
+	require.Empty(m.channelQueue)
+	jchs, err = j.channels(context.Background())
+	require.NoError(err)
+	require.Empty(jchs)
+}
+
+func TestJournal_RestoreKeepsPendingFramesInFlight(t *testing.T) {
+	require := require.New(t)
+	j, block, txdata := setupJournaledChannel(t)
+
+	jchs, err := j.channels(context.Background())
+	require.NoError(err)
+	require.Len(jchs, 1)
+
+	m := NewChannelManager(testlog.Logger(t, log.LvlCrit), metrics.NoopMetrics, journalTestChannelConfig)
+	m.journal = j
+	pending := map[txID]struct{}{txdata.ID(): {}}
+	require.NoError(m.restoreChannel(jchs[0], []*types.Block{block}, nil, pending))
+
+	// The frame's tx is still in the mempool, so it must not be resubmitted.
+	_, err = m.TxData(eth.L1BlockRef{})
+	require.ErrorIs(err, io.EOF)
+	require.Len(m.channelQueue, 1)
+	require.Equal([]common.Hash{{0xaa}}, m.channelQueue[0].pendingTxHashes[txdata.ID()])
+	require.NotZero(m.channelQueue[0].channelBuilder.timeout, "sequencing window timeout must be restored")
+
+	// The pending tx lands later.
+	m.TxConfirmed(txdata.ID(), eth.BlockID{Number: 1})
+	require.Empty(m.channelQueue)
+	jchs, err = j.channels(context.Background())
+	require.NoError(err)
+	require.Empty(jchs)
+}
+
+// TestJournal_TxSentJournalsAllVersions checks that all published versions of
+// a frame's transaction are journaled, so that fee bumps and re-crafted
+// transactions are found on L1 after a restart.
+func TestJournal_TxSentJournalsAllVersions(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	j := newJournal(log, sync.MutexWrap(ds.NewMapDatastore()))
+	m := NewChannelManager(log, metrics.NoopMetrics, journalTestChannelConfig)
+	m.journal = j
+
+	require.NoError(m.AddL2Block(newMiniL2Block(0)))
+	txdata, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	m.TxSent(txdata.ID(), common.Hash{0xaa})
+	m.TxSent(txdata.ID(), common.Hash{0xbb})
+	// resumed transactions get published with a known hash again
+	m.TxSent(txdata.ID(), common.Hash{0xbb})
+
+	jchs, err := j.channels(context.Background())
+	require.NoError(err)
+	require.Len(jchs, 1)
+	require.Equal([]common.Hash{{0xaa}, {0xbb}}, jchs[0].Frames[0].TxHashes)
+
+	pending := m.PendingTxs()
+	require.Len(pending, 1)
+	require.Equal(common.Hash{0xbb}, *pending[0].TxHash, "latest version must be reported")
+}
+
+func TestJournal_Clear(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	j := newJournal(log, sync.MutexWrap(ds.NewMapDatastore()))
+	m := NewChannelManager(log, metrics.NoopMetrics, journalTestChannelConfig)
+	m.journal = j
+
+	// open channels aren't journaled
+	require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	jchs, err := j.channels(context.Background())
+	require.NoError(err)
+	require.Empty(jchs)
+
+	require.NoError(m.AddL2Block(newMiniL2Block(0)))
+	_, err = m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	jchs, err = j.channels(context.Background())
+	require.NoError(err)
+	require.Len(jchs, 1)
+
+	m.Clear()
+	jchs, err = j.channels(context.Background())
+	require.NoError(err)
+	require.Empty(jchs)
+}
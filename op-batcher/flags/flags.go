@@ -3,7 +3,6 @@ package flags
 import (
 	"github.com/urfave/cli"
 
-	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
@@ -75,6 +74,18 @@ var (
 
 	/* Optional flags */
 
+	TxCancelTimeoutFlag = cli.DurationFlag{
+		Name: "tx-cancel-timeout",
+		Usage: "Duration after which a transaction that isn't mined yet is cancelled, by replacing it with a " +
+			"self-transfer at the same nonce. 0 disables cancellation.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "TX_CANCEL_TIMEOUT"),
+	}
+	MaxPendingTransactionsFlag = cli.Uint64Flag{
+		Name:   "max-pending-tx",
+		Usage:  "The maximum number of pending transactions. 0 for no limit.",
+		Value:  1,
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "MAX_PENDING_TX"),
+	}
 	MaxChannelDurationFlag = cli.Uint64Flag{
 		Name:   "max-channel-duration",
 		Usage:  "The maximum duration of L1-blocks to keep a channel open. 0 to disable.",
@@ -105,6 +116,43 @@ var (
 		Value:  0.4,
 		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "APPROX_COMPR_RATIO"),
 	}
+	CompressionAlgoFlag = cli.StringFlag{
+		Name: "compression-algo",
+		Usage: "The compression algorithm to use for channels: zlib or zstd. Algorithms other than zlib " +
+			"are only used once versioned channels are activated in the rollup config.",
+		Value:  "zlib",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "COMPRESSION_ALGO"),
+	}
+	ShadowCompressionFlag = cli.BoolFlag{
+		Name: "shadow-compression",
+		Usage: "Fill channels exactly up to the target size, by measuring their compressed size with a " +
+			"shadow compressor instead of estimating it with the approximate compression ratio",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "SHADOW_COMPRESSION"),
+	}
+	FeePolicyMaxBaseFeeFlag = cli.Float64Flag{
+		Name: "fee-policy.max-base-fee-gwei",
+		Usage: "L1 base fee in gwei above which batcher transactions are deferred, unless their channel " +
+			"is close to timing out. 0 disables deferral.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "FEE_POLICY_MAX_BASE_FEE_GWEI"),
+	}
+	FeePolicyMaxTipFlag = cli.Float64Flag{
+		Name: "fee-policy.max-tip-gwei",
+		Usage: "Maximum gas tip cap in gwei of batcher transactions, unless their channel is close to " +
+			"timing out. 0 disables the cap.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "FEE_POLICY_MAX_TIP_GWEI"),
+	}
+	DataDirFlag = cli.StringFlag{
+		Name: "data-dir",
+		Usage: "Directory to persist the state of closed channels in, so that their " +
+			"submission can be continued after a restart. Not persisted if empty.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "DATA_DIR"),
+	}
+	DAStoreFlag = cli.StringFlag{
+		Name: "da.store",
+		Usage: "Location of the DA store to write the batch data to, a directory or the http(s) URL of a DA server. " +
+			"Batcher transactions then only carry the commitment to the data. Requires DA commitments to be enabled in the rollup config.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "DA_STORE"),
+	}
 	StoppedFlag = cli.BoolFlag{
 		Name:   "stopped",
 		Usage:  "Initialize the batcher in a stopped state. The batcher can be started using the admin_startBatcher RPC",
@@ -141,11 +189,19 @@ var requiredFlags = []cli.Flag{
 }
 
 var optionalFlags = []cli.Flag{
+	TxCancelTimeoutFlag,
+	MaxPendingTransactionsFlag,
 	MaxChannelDurationFlag,
 	MaxL1TxSizeBytesFlag,
 	TargetL1TxSizeBytesFlag,
 	TargetNumFramesFlag,
 	ApproxComprRatioFlag,
+	CompressionAlgoFlag,
+	ShadowCompressionFlag,
+	FeePolicyMaxBaseFeeFlag,
+	FeePolicyMaxTipFlag,
+	DataDirFlag,
+	DAStoreFlag,
 	StoppedFlag,
 	MnemonicFlag,
 	SequencerHDPathFlag,
@@ -159,7 +215,7 @@ func init() {
 	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(envVarPrefix)...)
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(envVarPrefix)...)
 	optionalFlags = append(optionalFlags, opsigner.CLIFlags(envVarPrefix)...)
-	optionalFlags = append(optionalFlags, rpc.CLIFlags(envVarPrefix)...)
+	optionalFlags = append(optionalFlags, oprpc.AdminCLIFlags(envVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
 
 const Namespace = "op_batcher"
@@ -22,6 +23,9 @@ type Metricer interface {
 	// Records all L1 and L2 block events
 	opmetrics.RefMetricer
 
+	// Records stuck tx cancellations of the tx manager
+	txmgr.Metricer
+
 	RecordLatestL1Block(l1ref eth.L1BlockRef)
 	RecordL2BlocksLoaded(l2ref eth.L2BlockRef)
 	RecordChannelOpened(id derive.ChannelID, numPendingBlocks int)
@@ -29,11 +33,16 @@ type Metricer interface {
 	RecordChannelClosed(id derive.ChannelID, numPendingBlocks int, numFrames int, inputBytes int, outputComprBytes int, reason error)
 	RecordChannelFullySubmitted(id derive.ChannelID)
 	RecordChannelTimedOut(id derive.ChannelID)
+	RecordChannelDropped(id derive.ChannelID)
+	RecordChannelTxs(id derive.ChannelID, numPending, numConfirmed int)
+	RecordOpenChannels(num int)
 
 	RecordBatchTxSubmitted()
 	RecordBatchTxSuccess()
 	RecordBatchTxFailed()
 
+	RecordFeeDecision(decision string)
+
 	Document() []opmetrics.DocumentedMetric
 }
 
@@ -43,13 +52,18 @@ type Metrics struct {
 	factory  opmetrics.Factory
 
 	opmetrics.RefMetrics
+	txmgr.TxMetrics
 
 	Info prometheus.GaugeVec
 	Up   prometheus.Gauge
 
-	// label by openend, closed, fully_submitted, timed_out
+	// label by openend, closed, fully_submitted, timed_out, dropped
 	ChannelEvs opmetrics.EventVec
 
+	OpenChannels prometheus.Gauge
+	// label by channel id and pending, confirmed
+	ChannelTxs prometheus.GaugeVec
+
 	PendingBlocksCount prometheus.GaugeVec
 	BlocksAddedCount   prometheus.Gauge
 
@@ -61,6 +75,9 @@ type Metrics struct {
 	ChannelComprRatio   prometheus.Histogram
 
 	BatcherTxEvs opmetrics.EventVec
+
+	// label by submit, capped, defer, urgent
+	FeeDecisionEvs opmetrics.EventVec
 }
 
 var _ Metricer = (*Metrics)(nil)
@@ -80,6 +97,7 @@ func NewMetrics(procName string) *Metrics {
 		factory:  factory,
 
 		RefMetrics: opmetrics.MakeRefMetrics(ns, factory),
+		TxMetrics:  txmgr.MakeTxMetrics(ns, factory),
 
 		Info: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: ns,
@@ -96,6 +114,17 @@ func NewMetrics(procName string) *Metrics {
 
 		ChannelEvs: opmetrics.NewEventVec(factory, ns, "channel", "Channel", []string{"stage"}),
 
+		OpenChannels: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "open_channels",
+			Help:      "Number of channels whose frames are not fully submitted yet.",
+		}),
+		ChannelTxs: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "channel_txs",
+			Help:      "Number of pending and confirmed batcher txs of an open channel.",
+		}, []string{"channel_id", "stage"}),
+
 		PendingBlocksCount: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: ns,
 			Name:      "pending_blocks_count",
@@ -140,6 +169,8 @@ func NewMetrics(procName string) *Metrics {
 		}),
 
 		BatcherTxEvs: opmetrics.NewEventVec(factory, ns, "batcher_tx", "BatcherTx", []string{"stage"}),
+
+		FeeDecisionEvs: opmetrics.NewEventVec(factory, ns, "fee_decision", "Fee policy decision", []string{"decision"}),
 	}
 }
 
@@ -175,10 +206,24 @@ const (
 	StageClosed         = "closed"
 	StageFullySubmitted = "fully_submitted"
 	StageTimedOut       = "timed_out"
+	StageDropped        = "dropped"
 
 	TxStageSubmitted = "submitted"
 	TxStageSuccess   = "success"
 	TxStageFailed    = "failed"
+	TxStagePending   = "pending"
+	TxStageConfirmed = "confirmed"
+
+	// FeeDecisionSubmit means that the tx is submitted at the suggested fees.
+	FeeDecisionSubmit = "submit"
+	// FeeDecisionCapped means that the tx is submitted with a capped tip.
+	FeeDecisionCapped = "capped"
+	// FeeDecisionDefer means that the submission of the tx is deferred,
+	// because the L1 base fee is too high.
+	FeeDecisionDefer = "defer"
+	// FeeDecisionUrgent means that the tx is submitted at the suggested fees,
+	// regardless of the fee policy, because its channel is close to timing out.
+	FeeDecisionUrgent = "urgent"
 )
 
 func (m *Metrics) RecordLatestL1Block(l1ref eth.L1BlockRef) {
@@ -230,10 +275,35 @@ func ClosedReasonToNum(reason error) int {
 
 func (m *Metrics) RecordChannelFullySubmitted(id derive.ChannelID) {
 	m.ChannelEvs.Record(StageFullySubmitted)
+	m.deleteChannelTxs(id)
 }
 
 func (m *Metrics) RecordChannelTimedOut(id derive.ChannelID) {
 	m.ChannelEvs.Record(StageTimedOut)
+	m.deleteChannelTxs(id)
+}
+
+// RecordChannelDropped should be called when an open channel got discarded,
+// e.g. because of an L2 reorg or because an earlier channel timed out.
+func (m *Metrics) RecordChannelDropped(id derive.ChannelID) {
+	m.ChannelEvs.Record(StageDropped)
+	m.deleteChannelTxs(id)
+}
+
+// RecordChannelTxs records the number of pending and confirmed transactions of
+// an open channel. The series of a channel are removed once it is closed out.
+func (m *Metrics) RecordChannelTxs(id derive.ChannelID, numPending, numConfirmed int) {
+	m.ChannelTxs.WithLabelValues(id.String(), TxStagePending).Set(float64(numPending))
+	m.ChannelTxs.WithLabelValues(id.String(), TxStageConfirmed).Set(float64(numConfirmed))
+}
+
+func (m *Metrics) deleteChannelTxs(id derive.ChannelID) {
+	m.ChannelTxs.DeleteLabelValues(id.String(), TxStagePending)
+	m.ChannelTxs.DeleteLabelValues(id.String(), TxStageConfirmed)
+}
+
+func (m *Metrics) RecordOpenChannels(num int) {
+	m.OpenChannels.Set(float64(num))
 }
 
 func (m *Metrics) RecordBatchTxSubmitted() {
@@ -247,3 +317,9 @@ func (m *Metrics) RecordBatchTxSuccess() {
 func (m *Metrics) RecordBatchTxFailed() {
 	m.BatcherTxEvs.Record(TxStageFailed)
 }
+
+// RecordFeeDecision records a decision of the fee policy. See the
+// FeeDecision* constants for the possible decisions.
+func (m *Metrics) RecordFeeDecision(decision string) {
+	m.FeeDecisionEvs.Record(decision)
+}
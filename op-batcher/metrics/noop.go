@@ -4,9 +4,13 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
 
-type noopMetrics struct{ opmetrics.NoopRefMetrics }
+type noopMetrics struct {
+	opmetrics.NoopRefMetrics
+	txmgr.NoopTxMetrics
+}
 
 var NoopMetrics Metricer = new(noopMetrics)
 
@@ -24,7 +28,12 @@ func (*noopMetrics) RecordChannelClosed(derive.ChannelID, int, int, int, int, er
 
 func (*noopMetrics) RecordChannelFullySubmitted(derive.ChannelID) {}
 func (*noopMetrics) RecordChannelTimedOut(derive.ChannelID)       {}
+func (*noopMetrics) RecordChannelDropped(derive.ChannelID)        {}
+func (*noopMetrics) RecordChannelTxs(derive.ChannelID, int, int)  {}
+func (*noopMetrics) RecordOpenChannels(int)                       {}
 
 func (*noopMetrics) RecordBatchTxSubmitted() {}
 func (*noopMetrics) RecordBatchTxSuccess()   {}
 func (*noopMetrics) RecordBatchTxFailed()    {}
+
+func (*noopMetrics) RecordFeeDecision(string) {}
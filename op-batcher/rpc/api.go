@@ -2,11 +2,68 @@ package rpc
 
 import (
 	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
 )
 
 type batcherClient interface {
 	Start() error
 	Stop() error
+	ChannelStatus() Status
+	PendingTxs() []PendingTx
+	ForceCloseChannel() error
+	SetChannelConfig(update ChannelConfigUpdate) error
+}
+
+// Status is the status of the batcher's channel pipeline.
+type Status struct {
+	// BlocksPending is the number of L2 blocks that are loaded, but not yet
+	// added to a channel.
+	BlocksPending int `json:"blocks_pending"`
+	// Channels are all channels that are not fully submitted yet, oldest first.
+	Channels []ChannelStatus `json:"channels"`
+}
+
+// ChannelStatus is the status of a single channel.
+type ChannelStatus struct {
+	ID string `json:"id"`
+	// Current is true for the channel that new blocks are added to.
+	Current bool          `json:"current"`
+	Blocks  []eth.BlockID `json:"blocks"`
+
+	InputBytes  int `json:"input_bytes"`
+	ReadyBytes  int `json:"ready_bytes"`
+	OutputBytes int `json:"output_bytes"`
+
+	FramesQueued int `json:"frames_queued"`
+	PendingTxs   int `json:"pending_txs"`
+	ConfirmedTxs int `json:"confirmed_txs"`
+
+	// FullReason is the reason why the channel is full. Empty if it isn't.
+	FullReason string `json:"full_reason,omitempty"`
+	// TimeoutBlock is the L1 block number at which the channel gets closed
+	// because of a timeout. 0 if no timeout is set yet.
+	TimeoutBlock uint64 `json:"timeout_block"`
+}
+
+// PendingTx is a batcher transaction that is in flight.
+type PendingTx struct {
+	ChannelID   string `json:"channel_id"`
+	FrameNumber uint16 `json:"frame_number"`
+	// TxHash is the hash of the latest published version of the sent
+	// transaction. It is nil if the transaction is still being crafted.
+	TxHash *common.Hash `json:"tx_hash,omitempty"`
+}
+
+// ChannelConfigUpdate contains the channel config parameters to change at
+// runtime. Unset parameters are left unchanged.
+type ChannelConfigUpdate struct {
+	MaxChannelDuration *hexutil.Uint64 `json:"max_channel_duration,omitempty"`
+	TargetNumFrames    *hexutil.Uint64 `json:"target_num_frames,omitempty"`
+	TargetFrameSize    *hexutil.Uint64 `json:"target_frame_size,omitempty"`
 }
 
 type adminAPI struct {
@@ -26,3 +83,26 @@ func (a *adminAPI) StartBatcher(_ context.Context) error {
 func (a *adminAPI) StopBatcher(_ context.Context) error {
 	return a.b.Stop()
 }
+
+// ChannelStatus returns the status of all channels that are not fully
+// submitted yet.
+func (a *adminAPI) ChannelStatus(_ context.Context) (Status, error) {
+	return a.b.ChannelStatus(), nil
+}
+
+// PendingTxs returns all batcher transactions that are in flight.
+func (a *adminAPI) PendingTxs(_ context.Context) ([]PendingTx, error) {
+	return a.b.PendingTxs(), nil
+}
+
+// ForceCloseChannel closes the current channel, so that it gets submitted
+// immediately.
+func (a *adminAPI) ForceCloseChannel(_ context.Context) error {
+	return a.b.ForceCloseChannel()
+}
+
+// SetChannelConfig changes the channel config. The changes apply to all
+// channels that are opened afterwards.
+func (a *adminAPI) SetChannelConfig(_ context.Context, update ChannelConfigUpdate) error {
+	return a.b.SetChannelConfig(update)
+}
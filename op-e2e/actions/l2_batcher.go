@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ethereum-optimism/optimism/op-node/dastore"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
@@ -43,6 +44,9 @@ type BatcherCfg struct {
 	BatcherKey *ecdsa.PrivateKey
 
 	GarbageCfg *GarbageChannelCfg
+
+	// DAStore to write the batch data to, the batch txs then carry the commitment to it. Calldata is used if nil.
+	DAStore dastore.Store
 }
 
 // L2Batcher buffers and submits L2 batches to L1.
@@ -197,6 +201,11 @@ func (s *L2Batcher) ActL2BatchSubmit(t Testing, txOpts ...func(tx *types.Dynamic
 		GasFeeCap: gasFeeCap,
 		Data:      data.Bytes(),
 	}
+	if s.l2BatcherCfg.DAStore != nil {
+		commitment, calldata := derive.DACommitment(rawTx.Data)
+		require.NoError(t, s.l2BatcherCfg.DAStore.Put(t.Ctx(), commitment, rawTx.Data), "need to write batch data to DA store")
+		rawTx.Data = calldata
+	}
 	for _, opt := range txOpts {
 		opt(rawTx)
 	}
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"errors"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -14,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/ethereum-optimism/optimism/op-node/dastore"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
@@ -510,3 +513,110 @@ func TestBigL2Txs(gt *testing.T) {
 	verifier.ActL2PipelineFull(t)
 	require.Equal(t, sequencer.SyncStatus().UnsafeL2, verifier.SyncStatus().SafeL2, "verifier synced sequencer data even though of huge tx in block")
 }
+
+func TestBatcherDACommitments(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	sd.RollupCfg.DACommitmentsTime = new(uint64)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+
+	store, err := dastore.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	verifEngine := NewL2Engine(t, log, sd.L2Cfg, sd.RollupCfg.Genesis.L1, e2eutils.WriteDefaultJWT(t))
+	verifier := NewL2Verifier(t, log, miner.L1Client(t, sd.RollupCfg), store, verifEngine.EngineClient(t, sd.RollupCfg), sd.RollupCfg)
+
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+		DAStore:     store,
+	}, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL2PipelineFull(t)
+
+	// build some L2 blocks, and submit them with a DA commitment
+	sequencer.ActBuildToL1Head(t)
+	miner.ActEmptyBlock(t)
+	sequencer.ActL1HeadSignal(t)
+	sequencer.ActBuildToL1Head(t)
+	batcher.ActSubmitAll(t)
+	miner.ActL1StartBlock(12)(t)
+	miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+	miner.ActL1EndBlock(t)
+
+	// the batch tx only carries the commitment
+	txs := miner.l1Chain.CurrentBlock().Transactions()
+	require.Len(t, txs, 1)
+	require.Len(t, txs[0].Data(), 1+common.HashLength)
+	require.Equal(t, byte(derive.DerivationVersionCommitment), txs[0].Data()[0])
+
+	// the verifier derives the L2 blocks from the data in the DA store
+	verifier.ActL1HeadSignal(t)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, sequencer.L2Unsafe(), verifier.L2Safe(), "verifier syncs the batch from the DA store")
+}
+
+// TestBatcherDACommitmentMismatch tests that the verifier drops a DA commitment when the DA store returns data
+// that does not match it, instead of retrying it forever, and keeps deriving from the later L1 blocks.
+func TestBatcherDACommitmentMismatch(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	p := &e2eutils.TestParams{
+		MaxSequencerDrift:   20,
+		SequencerWindowSize: 4,
+		ChannelTimeout:      4,
+		L1BlockTime:         12,
+	}
+	dp := e2eutils.MakeDeployParams(t, p)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	sd.RollupCfg.DACommitmentsTime = new(uint64)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+
+	dir := t.TempDir()
+	store, err := dastore.NewFileStore(dir)
+	require.NoError(t, err)
+	verifEngine := NewL2Engine(t, log, sd.L2Cfg, sd.RollupCfg.Genesis.L1, e2eutils.WriteDefaultJWT(t))
+	verifier := NewL2Verifier(t, log, miner.L1Client(t, sd.RollupCfg), store, verifEngine.EngineClient(t, sd.RollupCfg), sd.RollupCfg)
+
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+		DAStore:     store,
+	}, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL2PipelineFull(t)
+
+	sequencer.ActBuildToL1Head(t)
+	miner.ActEmptyBlock(t)
+	sequencer.ActL1HeadSignal(t)
+	sequencer.ActBuildToL1Head(t)
+	batcher.ActSubmitAll(t)
+	miner.ActL1StartBlock(12)(t)
+	miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+	miner.ActL1EndBlock(t)
+
+	// the DA store now returns the wrong data for the commitment of the batch
+	txs := miner.l1Chain.CurrentBlock().Transactions()
+	require.Len(t, txs, 1)
+	commitment := common.BytesToHash(txs[0].Data()[1:])
+	require.NoError(t, os.WriteFile(filepath.Join(dir, commitment.Hex()), []byte("bad data"), 0644))
+
+	// the verifier drops the commitment, and processes the L1 block without deriving from it
+	verifier.ActL1HeadSignal(t)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, miner.l1Chain.CurrentBlock().NumberU64(), verifier.SyncStatus().CurrentL1.Number)
+	require.Equal(t, uint64(0), verifier.L2Safe().Number)
+
+	// derivation keeps moving: once the sequencing window of the epoch expires, the verifier derives empty batches
+	for i := uint64(0); i < sd.RollupCfg.SeqWindowSize; i++ {
+		miner.ActEmptyBlock(t)
+	}
+	verifier.ActL1HeadSignal(t)
+	verifier.ActL2PipelineFull(t)
+	require.Greater(t, verifier.L2Safe().L1Origin.Number, uint64(0))
+}
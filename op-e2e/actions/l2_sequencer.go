@@ -33,6 +33,7 @@ type L2Sequencer struct {
 	L2Verifier
 
 	sequencer *driver.Sequencer
+	policy    *driver.RuntimeSequencerPolicy
 
 	failL2GossipUnsafeBlock error // mock error
 
@@ -40,15 +41,17 @@ type L2Sequencer struct {
 }
 
 func NewL2Sequencer(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cfg *rollup.Config, seqConfDepth uint64) *L2Sequencer {
-	ver := NewL2Verifier(t, log, l1, eng, cfg)
+	ver := NewL2Verifier(t, log, l1, nil, eng, cfg)
 	attrBuilder := derive.NewFetchingAttributesBuilder(cfg, l1, eng)
 	seqConfDepthL1 := driver.NewConfDepth(seqConfDepth, ver.l1State.L1Head, l1)
 	l1OriginSelector := &MockL1OriginSelector{
 		actual: driver.NewL1OriginSelector(log, cfg, seqConfDepthL1),
 	}
+	policy := driver.NewRuntimeSequencerPolicy()
 	return &L2Sequencer{
 		L2Verifier:              *ver,
-		sequencer:               driver.NewSequencer(log, cfg, ver.derivation, attrBuilder, l1OriginSelector, metrics.NoopMetrics),
+		sequencer:               driver.NewSequencer(log, cfg, ver.derivation, attrBuilder, l1OriginSelector, policy, metrics.NoopMetrics),
+		policy:                  policy,
 		mockL1OriginSelector:    l1OriginSelector,
 		failL2GossipUnsafeBlock: nil,
 	}
@@ -101,6 +104,16 @@ func (s *L2Sequencer) ActL2EndBlock(t Testing) {
 	// TODO: action-test publishing of payload on p2p
 }
 
+// ActL2CancelBlock cancels the L2 block that is being built, without completing it
+func (s *L2Sequencer) ActL2CancelBlock(t Testing) {
+	if !s.l2Building {
+		t.InvalidAction("cannot cancel L2 block building when no block is being built")
+		return
+	}
+	s.l2Building = false
+	s.sequencer.CancelBuildingBlock(t.Ctx())
+}
+
 // ActL2KeepL1Origin makes the sequencer use the current L1 origin, even if the next origin is available.
 func (s *L2Sequencer) ActL2KeepL1Origin(t Testing) {
 	parent := s.derivation.UnsafeL2Head()
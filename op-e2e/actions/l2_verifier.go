@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/node"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
@@ -55,9 +56,9 @@ type L2API interface {
 	GetProof(ctx context.Context, address common.Address, storage []common.Hash, blockTag string) (*eth.AccountResult, error)
 }
 
-func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cfg *rollup.Config) *L2Verifier {
+func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, daStore derive.DAStore, eng L2API, cfg *rollup.Config) *L2Verifier {
 	metrics := &testutils.TestDerivationMetrics{}
-	pipeline := derive.NewDerivationPipeline(log, cfg, l1, eng, metrics)
+	pipeline := derive.NewDerivationPipeline(log, cfg, l1, daStore, eng, metrics, derive.NoopSafeHeadListener)
 	pipeline.Reset()
 
 	rollupNode := &L2Verifier{
@@ -79,14 +80,20 @@ func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cf
 	apis := []rpc.API{
 		{
 			Namespace:     "optimism",
-			Service:       node.NewNodeAPI(cfg, eng, backend, log, m),
+			Service:       node.NewNodeAPI(cfg, eng, backend, safedb.Disabled, log, m),
+			Public:        true,
+			Authenticated: false,
+		},
+		{
+			Namespace:     "debug",
+			Service:       node.NewDebugAPI(backend, m),
 			Public:        true,
 			Authenticated: false,
 		},
 		{
 			Namespace:     "admin",
 			Version:       "",
-			Service:       node.NewAdminAPI(backend, m),
+			Service:       node.NewAdminAPI(backend, nil, m),
 			Public:        true, // TODO: this field is deprecated. Do we even need this anymore?
 			Authenticated: false,
 		},
@@ -121,6 +128,18 @@ func (s *l2VerifierBackend) StopSequencer(ctx context.Context) (common.Hash, err
 	return common.Hash{}, errors.New("stopping the L2Verifier sequencer is not supported")
 }
 
+func (s *l2VerifierBackend) SequencerPolicy(ctx context.Context) (*driver.SequencerPolicyConfig, error) {
+	return nil, errors.New("the L2Verifier sequencer policy is not supported")
+}
+
+func (s *l2VerifierBackend) SetSequencerPolicy(ctx context.Context, cfg driver.SequencerPolicyConfig) error {
+	return errors.New("the L2Verifier sequencer policy is not supported")
+}
+
+func (s *l2VerifierBackend) DerivationState(ctx context.Context) (*derive.DerivationState, error) {
+	return s.verifier.derivation.DerivationState(), nil
+}
+
 func (s *L2Verifier) L2Finalized() eth.L2BlockRef {
 	return s.derivation.Finalized()
 }
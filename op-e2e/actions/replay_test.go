@@ -0,0 +1,86 @@
+package actions
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/ethereum-optimism/optimism/op-node/cmd/replay"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+// TestReplay records the chain data of a range of L2 blocks, and checks that replaying
+// the derivation offline produces exactly the blocks the verifier derived.
+func TestReplay(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+	verifEngine, verifier := setupVerifier(t, sd, log, miner.L1Client(t, sd.RollupCfg))
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+	}, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL2PipelineFull(t)
+
+	// build and batch-submit a few rounds of L2 blocks
+	for i := 0; i < 3; i++ {
+		miner.ActEmptyBlock(t)
+		sequencer.ActL1HeadSignal(t)
+		sequencer.ActBuildToL1Head(t)
+		batcher.ActSubmitAll(t)
+		miner.ActL1StartBlock(12)(t)
+		miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+		miner.ActL1EndBlock(t)
+	}
+	verifier.ActL1HeadSignal(t)
+	verifier.ActL2PipelineFull(t)
+	end := verifier.L2Safe()
+	require.Equal(t, sequencer.L2Unsafe(), end, "verifier derived all sequenced blocks")
+
+	// start half-way, to replay from a safe head that is not genesis
+	start := end.Number / 2
+	rec, err := replay.Record(t.Ctx(), log, sd.RollupCfg, miner.L1Client(t, sd.RollupCfg),
+		verifEngine.EngineClient(t, sd.RollupCfg), start, end.Number)
+	require.NoError(t, err)
+	require.Equal(t, start, rec.Start.Number)
+
+	// the recording must survive the round-trip through a file
+	path := filepath.Join(t.TempDir(), "recording.json")
+	require.NoError(t, replay.WriteRecording(path, rec))
+	rec, err = replay.LoadRecording(path)
+	require.NoError(t, err)
+
+	derived, err := replay.Replay(t.Ctx(), log, rec, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, derived, int(end.Number-start))
+
+	cl := verifEngine.EthClient()
+	l2Cl := verifEngine.EngineClient(t, sd.RollupCfg)
+	for i, d := range derived {
+		num := start + 1 + uint64(i)
+		require.True(t, d.Canonical, "derived block %d must be canonical", num)
+		require.Equal(t, num, d.Block.Number)
+		ref, err := l2Cl.L2BlockRefByNumber(t.Ctx(), num)
+		require.NoError(t, err)
+		require.Equal(t, ref, d.Block)
+
+		block, err := cl.BlockByNumber(t.Ctx(), new(big.Int).SetUint64(num))
+		require.NoError(t, err)
+		require.Len(t, d.Attributes.Transactions, len(block.Transactions()))
+		for j, tx := range block.Transactions() {
+			data, err := tx.MarshalBinary()
+			require.NoError(t, err)
+			require.Equal(t, eth.Data(data), d.Attributes.Transactions[j], "tx %d of block %d", j, num)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package actions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+// TestSequencerPolicy tests that the runtime sequencer policy forces transactions into the next sealed block,
+// that the transactions are retried if the block building is cancelled, and that verifiers derive the same block.
+func TestSequencerPolicy(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+	verifEngine, verifier := setupVerifier(t, sd, log, miner.L1Client(t, sd.RollupCfg))
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+	}, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL2PipelineFull(t)
+	// the gas of the pre-Regolith L1-info deposit exceeds the block gas limit
+	require.False(t, sd.RollupCfg.IsRegolith(sequencer.L2Unsafe().Time+sd.RollupCfg.BlockTime))
+
+	signer := types.LatestSigner(sd.L2Cfg.Config)
+	cl := seqEngine.EthClient()
+	gasFeeCap := new(big.Int).Add(new(big.Int).Mul(seqEngine.l2Chain.CurrentBlock().BaseFee(), big.NewInt(2)), big.NewInt(2*params.GWei))
+	forcedTx := types.MustSignNewTx(dp.Secrets.Alice, signer, &types.DynamicFeeTx{
+		ChainID:   sd.L2Cfg.Config.ChainID,
+		Nonce:     0,
+		GasTipCap: big.NewInt(2 * params.GWei),
+		GasFeeCap: gasFeeCap,
+		Gas:       params.TxGas,
+		To:        &dp.Addresses.Bob,
+		Value:     e2eutils.Ether(1),
+	})
+	forcedTxData, err := forcedTx.MarshalBinary()
+	require.NoError(t, err)
+	poolTx := types.MustSignNewTx(dp.Secrets.Bob, signer, &types.DynamicFeeTx{
+		ChainID:   sd.L2Cfg.Config.ChainID,
+		Nonce:     0,
+		GasTipCap: big.NewInt(2 * params.GWei),
+		GasFeeCap: gasFeeCap,
+		Gas:       params.TxGas,
+		To:        &dp.Addresses.Alice,
+		Value:     e2eutils.Ether(1),
+	})
+	require.NoError(t, cl.SendTransaction(t.Ctx(), poolTx))
+
+	require.NoError(t, sequencer.policy.SetConfig(driver.SequencerPolicyConfig{
+		NoTxPool:     true,
+		Transactions: []hexutil.Bytes{forcedTxData},
+	}))
+
+	// the forced transaction stays in the policy if the block isn't built
+	sequencer.ActL2StartBlock(t)
+	sequencer.ActL2CancelBlock(t)
+	require.Len(t, sequencer.policy.Config().Transactions, 1)
+
+	sequencer.ActL2StartBlock(t)
+	seqEngine.ActL2IncludeTx(dp.Addresses.Bob)(t) // skipped, the tx pool is disabled
+	sequencer.ActL2EndBlock(t)
+
+	forcedBlock := seqEngine.l2Chain.CurrentBlock()
+	require.Len(t, forcedBlock.Transactions(), 2)
+	require.Equal(t, forcedTx.Hash(), forcedBlock.Transactions()[1].Hash())
+	receipt, err := cl.TransactionReceipt(t.Ctx(), forcedTx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	require.Empty(t, sequencer.policy.Config().Transactions)
+
+	// the forced transaction is only included once, and the tx pool is used again
+	require.NoError(t, sequencer.policy.SetConfig(driver.SequencerPolicyConfig{}))
+	sequencer.ActL2StartBlock(t)
+	seqEngine.ActL2IncludeTx(dp.Addresses.Bob)(t)
+	sequencer.ActL2EndBlock(t)
+
+	block := seqEngine.l2Chain.CurrentBlock()
+	require.Len(t, block.Transactions(), 2)
+	require.Equal(t, poolTx.Hash(), block.Transactions()[1].Hash())
+
+	// the verifier derives the same blocks from the batches
+	batcher.ActSubmitAll(t)
+	miner.ActL1StartBlock(12)(t)
+	miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+	miner.ActL1EndBlock(t)
+	sequencer.ActL1HeadSignal(t)
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL1HeadSignal(t)
+	verifier.ActL2PipelineFull(t)
+
+	require.Equal(t, block.Hash(), sequencer.L2Safe().Hash, "sequencer blocks are not replaced on derivation")
+	require.Equal(t, block.Hash(), verifier.L2Safe().Hash, "verifier derives the sequencer blocks")
+	derivedForcedBlock, err := verifEngine.EthClient().BlockByNumber(t.Ctx(), forcedBlock.Number())
+	require.NoError(t, err)
+	require.Equal(t, forcedBlock.Hash(), derivedForcedBlock.Hash())
+}
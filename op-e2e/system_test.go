@@ -1482,6 +1482,59 @@ func TestStopStartBatcher(t *testing.T) {
 	require.Greater(t, newSeqStatus.SafeL2.Number, seqStatus.SafeL2.Number, "Safe chain did not advance after batcher was restarted")
 }
 
+func TestStopStartProposer(t *testing.T) {
+	parallel(t)
+	if !verboseGethNodes {
+		log.Root().SetHandler(log.DiscardHandler())
+	}
+
+	cfg := DefaultSystemConfig(t)
+	sys, err := cfg.Start()
+	require.Nil(t, err, "Error starting up system")
+	defer sys.Close()
+
+	l2OutputOracle, err := bindings.NewL2OutputOracleCaller(predeploys.DevL2OutputOracleAddr, sys.Clients["l1"])
+	require.Nil(t, err)
+
+	// a forced proposal requires the proposer to be stopped
+	_, err = sys.L2OutputSubmitter.ProposeOutputAt(context.Background(), 0)
+	require.ErrorContains(t, err, "must be stopped")
+
+	err = sys.L2OutputSubmitter.Stop()
+	require.Nil(t, err)
+	require.Error(t, sys.L2OutputSubmitter.Stop(), "stopping twice should fail")
+
+	status, err := sys.L2OutputSubmitter.Status(context.Background())
+	require.Nil(t, err)
+	require.False(t, status.Running)
+	nextBlockNumber, err := l2OutputOracle.NextBlockNumber(&bind.CallOpts{})
+	require.Nil(t, err)
+	require.Equal(t, nextBlockNumber.Uint64(), status.NextCheckpointBlock)
+
+	// Wait for the next checkpoint block to be derived by the verifier, so that
+	// it isn't in the future on L1 anymore. It isn't finalized yet.
+	_, err = waitForBlock(nextBlockNumber, sys.Clients["verifier"], 30*time.Duration(cfg.DeployConfig.L1BlockTime)*time.Second)
+	require.Nil(t, err)
+
+	_, err = sys.L2OutputSubmitter.ProposeOutputAt(context.Background(), nextBlockNumber.Uint64()+1)
+	require.ErrorContains(t, err, "can only propose the next checkpoint block")
+
+	txHash, err := sys.L2OutputSubmitter.ProposeOutputAt(context.Background(), nextBlockNumber.Uint64())
+	require.Nil(t, err)
+	require.NotEqual(t, common.Hash{}, txHash)
+
+	latestBlockNumber, err := l2OutputOracle.LatestBlockNumber(&bind.CallOpts{})
+	require.Nil(t, err)
+	require.Equal(t, nextBlockNumber, latestBlockNumber)
+
+	err = sys.L2OutputSubmitter.Start()
+	require.Nil(t, err)
+	status, err = sys.L2OutputSubmitter.Status(context.Background())
+	require.Nil(t, err)
+	require.True(t, status.Running)
+	require.Equal(t, latestBlockNumber.Uint64(), status.LastProposedBlock)
+}
+
 func safeAddBig(a *big.Int, b *big.Int) *big.Int {
 	return new(big.Int).Add(a, b)
 }
@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/hashicorp/go-multierror"
+)
+
+// healthAlpha is the weight of the latest request result in the health score of an endpoint.
+const healthAlpha = 0.2
+
+var ErrNoEndpoints = errors.New("no RPC endpoints")
+
+// MultiRPCMetrics tracks the health of the endpoints of a MultiRPC.
+type MultiRPCMetrics interface {
+	RecordEndpointHealth(endpoint string, score float64, headLag uint64)
+}
+
+// Endpoint is an RPC endpoint of a MultiRPC.
+type Endpoint struct {
+	// Name identifies the endpoint in logs and metrics. It should not contain any secrets such as API keys.
+	Name string
+	RPC  RPC
+}
+
+type endpointState struct {
+	Endpoint
+
+	// score is a moving average of the request results of the endpoint, between 0 (failing) and 1 (healthy).
+	score float64
+	// head is the latest block number the endpoint reported in a health check.
+	head uint64
+	// lag is the number of blocks the head of the endpoint is behind the head of the best endpoint.
+	lag uint64
+}
+
+// MultiRPC is an RPC client that routes each request to the healthiest of several RPC endpoints,
+// and fails over to the next endpoint if the request fails.
+// The health of an endpoint is scored by the results of its requests, and by how far its head lags behind
+// the other endpoints: endpoints with a lagging head are only used when all other endpoints fail.
+// JSON-RPC error responses are returned as-is, since the endpoint is responsive.
+type MultiRPC struct {
+	lgr       log.Logger
+	endpoints []*endpointState
+
+	healthCheckInterval time.Duration
+	maxHeadLag          uint64
+
+	mu sync.Mutex
+
+	cancel   context.CancelFunc
+	closedCh chan struct{}
+}
+
+type MultiRPCOption func(m *MultiRPC)
+
+// WithHealthCheckInterval specifies the interval at which MultiRPC.Start checks the head of every endpoint.
+func WithHealthCheckInterval(interval time.Duration) MultiRPCOption {
+	return func(m *MultiRPC) {
+		m.healthCheckInterval = interval
+	}
+}
+
+// WithMaxHeadLag specifies the number of blocks the head of an endpoint may lag behind
+// the best endpoint before requests are routed to other endpoints first.
+func WithMaxHeadLag(blocks uint64) MultiRPCOption {
+	return func(m *MultiRPC) {
+		m.maxHeadLag = blocks
+	}
+}
+
+// NewMultiRPC returns a new MultiRPC. Endpoints are initially considered healthy,
+// and preferred in the given order.
+func NewMultiRPC(lgr log.Logger, endpoints []Endpoint, opts ...MultiRPCOption) (*MultiRPC, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	res := &MultiRPC{
+		lgr:                 lgr,
+		healthCheckInterval: 4 * time.Second,
+		maxHeadLag:          2,
+	}
+	for _, e := range endpoints {
+		res.endpoints = append(res.endpoints, &endpointState{Endpoint: e, score: 1})
+	}
+	for _, opt := range opts {
+		opt(res)
+	}
+	return res, nil
+}
+
+// Endpoints returns the endpoints, in the order they were configured.
+func (m *MultiRPC) Endpoints() []Endpoint {
+	out := make([]Endpoint, len(m.endpoints))
+	for i, e := range m.endpoints {
+		out[i] = e.Endpoint
+	}
+	return out
+}
+
+// Start checks the head of every endpoint at the health check interval, and records the health of the endpoints,
+// until the context is canceled or the MultiRPC is closed.
+// Without health checks, the health of an endpoint is only scored by the requests routed to it.
+func (m *MultiRPC) Start(ctx context.Context, metrics MultiRPCMetrics) {
+	ctx, cancel := context.WithCancel(ctx)
+	m.mu.Lock()
+	m.cancel = cancel
+	m.closedCh = make(chan struct{})
+	m.mu.Unlock()
+	go m.healthChecks(ctx, metrics)
+}
+
+func (m *MultiRPC) healthChecks(ctx context.Context, metrics MultiRPCMetrics) {
+	defer close(m.closedCh)
+	ticker := time.NewTicker(m.healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		m.checkHealth(ctx)
+		m.mu.Lock()
+		for _, e := range m.endpoints {
+			metrics.RecordEndpointHealth(e.Name, e.score, e.lag)
+		}
+		m.mu.Unlock()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkHealth fetches the head block number of every endpoint, and updates the health of the endpoints.
+func (m *MultiRPC) checkHealth(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.healthCheckInterval)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, e := range m.endpoints {
+		wg.Add(1)
+		go func(e *endpointState) {
+			defer wg.Done()
+			var head hexutil.Uint64
+			err := e.RPC.CallContext(ctx, &head, "eth_blockNumber")
+			if ctx.Err() != nil {
+				return
+			}
+			m.recordResult(e, err)
+			if err != nil {
+				m.lgr.Warn("RPC endpoint health check failed", "endpoint", e.Name, "err", err)
+				return
+			}
+			m.mu.Lock()
+			e.head = uint64(head)
+			m.mu.Unlock()
+		}(e)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var best uint64
+	for _, e := range m.endpoints {
+		if e.head > best {
+			best = e.head
+		}
+	}
+	for _, e := range m.endpoints {
+		e.lag = best - e.head
+	}
+}
+
+// recordResult updates the health score of the endpoint with the result of a request.
+func (m *MultiRPC) recordResult(e *endpointState, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		e.score = e.score*(1-healthAlpha) + healthAlpha
+	} else {
+		e.score = e.score * (1 - healthAlpha)
+	}
+}
+
+// ranked returns the endpoints from healthiest to least healthy.
+func (m *MultiRPC) ranked() []*endpointState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*endpointState, len(m.endpoints))
+	copy(out, m.endpoints)
+	sort.SliceStable(out, func(i, j int) bool {
+		lagI, lagJ := out[i].lag > m.maxHeadLag, out[j].lag > m.maxHeadLag
+		if lagI != lagJ {
+			return lagJ
+		}
+		return out[i].score > out[j].score
+	})
+	return out
+}
+
+// try calls fn with the endpoints from healthiest to least healthy, until it succeeds.
+func (m *MultiRPC) try(ctx context.Context, method string, fn func(e *endpointState) error) error {
+	var result *multierror.Error
+	for _, e := range m.ranked() {
+		err := fn(e)
+		if ctx.Err() != nil {
+			// the request was canceled by the caller, not failed by the endpoint
+			return err
+		}
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			m.recordResult(e, nil)
+			return err
+		}
+		m.recordResult(e, err)
+		if err == nil {
+			return nil
+		}
+		m.lgr.Warn("RPC endpoint request failed, failing over to next endpoint", "endpoint", e.Name, "method", method, "err", err)
+		result = multierror.Append(result, fmt.Errorf("endpoint %s: %w", e.Name, err))
+	}
+	return fmt.Errorf("all RPC endpoints failed: %w", result.ErrorOrNil())
+}
+
+func (m *MultiRPC) Close() {
+	m.mu.Lock()
+	cancel, closedCh := m.cancel, m.closedCh
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-closedCh
+	}
+	for _, e := range m.endpoints {
+		e.RPC.Close()
+	}
+}
+
+func (m *MultiRPC) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	return m.try(ctx, method, func(e *endpointState) error {
+		return e.RPC.CallContext(ctx, result, method, args...)
+	})
+}
+
+func (m *MultiRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return m.try(ctx, "batch", func(e *endpointState) error {
+		return e.RPC.BatchCallContext(ctx, b)
+	})
+}
+
+// EthSubscribe subscribes with the healthiest endpoint that accepts the subscription.
+// The subscription stays with that endpoint until it fails.
+func (m *MultiRPC) EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error) {
+	var sub ethereum.Subscription
+	err := m.try(ctx, "eth_subscribe", func(e *endpointState) error {
+		var err error
+		sub, err = e.RPC.EthSubscribe(ctx, channel, args...)
+		return err
+	})
+	return sub, err
+}
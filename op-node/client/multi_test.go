@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type testEndpoint struct {
+	mtx    sync.Mutex
+	head   uint64
+	err    error
+	calls  int
+	closed bool
+}
+
+func (e *testEndpoint) setErr(err error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.err = err
+}
+
+func (e *testEndpoint) callCount() int {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.calls
+}
+
+func (e *testEndpoint) Close() {
+	e.closed = true
+}
+
+func (e *testEndpoint) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	if method == "eth_blockNumber" {
+		*result.(*hexutil.Uint64) = hexutil.Uint64(e.head)
+		return nil
+	}
+	e.calls++
+	return nil
+}
+
+func (e *testEndpoint) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return e.CallContext(ctx, nil, "batch")
+}
+
+func (e *testEndpoint) EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error) {
+	return nil, errors.New("not supported")
+}
+
+type testRPCError struct{}
+
+func (testRPCError) Error() string  { return "method not found" }
+func (testRPCError) ErrorCode() int { return -32601 }
+
+type testMultiRPCMetrics struct {
+	mtx    sync.Mutex
+	health map[string]float64
+	lag    map[string]uint64
+}
+
+func (m *testMultiRPCMetrics) RecordEndpointHealth(endpoint string, score float64, headLag uint64) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.health[endpoint] = score
+	m.lag[endpoint] = headLag
+}
+
+func newTestMultiRPC(t *testing.T, endpoints []*testEndpoint, opts ...MultiRPCOption) *MultiRPC {
+	lgr := log.New()
+	lgr.SetHandler(log.DiscardHandler())
+	var eps []Endpoint
+	for i, e := range endpoints {
+		eps = append(eps, Endpoint{Name: string(rune('a' + i)), RPC: e})
+	}
+	m, err := NewMultiRPC(lgr, eps, opts...)
+	require.NoError(t, err)
+	return m
+}
+
+func TestMultiRPCFailover(t *testing.T) {
+	ctx := context.Background()
+	a, b := &testEndpoint{}, &testEndpoint{}
+	m := newTestMultiRPC(t, []*testEndpoint{a, b})
+
+	// requests go to the first endpoint while it is healthy
+	require.NoError(t, m.CallContext(ctx, nil, "eth_chainId"))
+	require.Equal(t, 1, a.callCount())
+	require.Equal(t, 0, b.callCount())
+
+	// and fail over to the next endpoint on errors
+	a.setErr(errors.New("connection refused"))
+	require.NoError(t, m.CallContext(ctx, nil, "eth_chainId"))
+	require.NoError(t, m.BatchCallContext(ctx, nil))
+	require.Equal(t, 2, b.callCount())
+
+	// the failed endpoint is not preferred anymore after it recovers
+	a.setErr(nil)
+	require.NoError(t, m.CallContext(ctx, nil, "eth_chainId"))
+	require.Equal(t, 1, a.callCount())
+	require.Equal(t, 3, b.callCount())
+
+	// JSON-RPC error responses are returned without failover
+	b.setErr(testRPCError{})
+	require.ErrorIs(t, m.CallContext(ctx, nil, "eth_chainId"), testRPCError{})
+	require.Equal(t, 1, a.callCount())
+
+	// all endpoints failing returns an error
+	a.setErr(errors.New("connection refused"))
+	b.setErr(errors.New("connection reset"))
+	require.ErrorContains(t, m.CallContext(ctx, nil, "eth_chainId"), "all RPC endpoints failed")
+
+	m.Close()
+	require.True(t, a.closed)
+	require.True(t, b.closed)
+}
+
+func TestMultiRPCHeadLag(t *testing.T) {
+	ctx := context.Background()
+	a, b := &testEndpoint{head: 100}, &testEndpoint{head: 110}
+	m := newTestMultiRPC(t, []*testEndpoint{a, b})
+
+	m.checkHealth(ctx)
+	require.NoError(t, m.CallContext(ctx, nil, "eth_chainId"))
+	require.Equal(t, 0, a.callCount(), "lagging endpoint should not be used")
+	require.Equal(t, 1, b.callCount())
+
+	// the lagging endpoint is still used if the others fail
+	b.setErr(errors.New("connection refused"))
+	require.NoError(t, m.CallContext(ctx, nil, "eth_chainId"))
+	require.Equal(t, 1, a.callCount())
+}
+
+func TestMultiRPCStart(t *testing.T) {
+	a, b := &testEndpoint{head: 100}, &testEndpoint{head: 105, err: errors.New("connection refused")}
+	m := newTestMultiRPC(t, []*testEndpoint{a, b}, WithHealthCheckInterval(10*time.Millisecond))
+	metrics := &testMultiRPCMetrics{health: make(map[string]float64), lag: make(map[string]uint64)}
+	m.Start(context.Background(), metrics)
+
+	require.Eventually(t, func() bool {
+		metrics.mtx.Lock()
+		defer metrics.mtx.Unlock()
+		return metrics.health["a"] > 0.99 && metrics.health["b"] < 0.5
+	}, time.Second, 10*time.Millisecond)
+	metrics.mtx.Lock()
+	require.Equal(t, uint64(0), metrics.lag["a"])
+	metrics.mtx.Unlock()
+	m.Close()
+}
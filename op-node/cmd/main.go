@@ -18,6 +18,7 @@ import (
 	opnode "github.com/ethereum-optimism/optimism/op-node"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/genesis"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/p2p"
+	"github.com/ethereum-optimism/optimism/op-node/cmd/replay"
 	"github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/heartbeat"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
@@ -72,6 +73,11 @@ func main() {
 			Name:        "doc",
 			Subcommands: doc.Subcommands,
 		},
+		{
+			Name:        "derive",
+			Usage:       "Replay the derivation of L2 blocks offline",
+			Subcommands: replay.Subcommands,
+		},
 	}
 
 	err := app.Run(os.Args)
@@ -0,0 +1,186 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-node/dastore"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/sources"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+)
+
+var Subcommands = cli.Commands{
+	{
+		Name:  "record",
+		Usage: "Record the L1 and L2 chain data to replay the derivation of a range of L2 blocks offline",
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:     "l1",
+				Usage:    "L1 RPC URL",
+				Required: true,
+			},
+			cli.StringFlag{
+				Name:  "l1.rpckind",
+				Usage: "The kind of L1 RPC provider, used to fetch receipts efficiently",
+				Value: string(sources.RPCKindBasic),
+			},
+			cli.StringFlag{
+				Name:     "l2",
+				Usage:    "L2 RPC URL, the eth namespace is required",
+				Required: true,
+			},
+			cli.StringFlag{
+				Name:  "rollup.config",
+				Usage: "Path to the rollup config",
+			},
+			cli.StringFlag{
+				Name:  "network",
+				Usage: fmt.Sprintf("Predefined network selection. Available networks: %s", strings.Join(chaincfg.AvailableNetworks(), ", ")),
+			},
+			cli.Uint64Flag{
+				Name:     "l2.start",
+				Usage:    "L2 safe head to start deriving from",
+				Required: true,
+			},
+			cli.Uint64Flag{
+				Name:     "l2.end",
+				Usage:    "Last L2 block to record",
+				Required: true,
+			},
+			cli.StringFlag{
+				Name:     "out",
+				Usage:    "Path to write the recording to",
+				Required: true,
+			},
+		}, oplog.CLIFlags("OP_NODE")...),
+		Action: func(ctx *cli.Context) error {
+			logger := oplog.NewLogger(oplog.ReadLocalCLIConfig(ctx))
+			cfg, err := loadRollupConfig(ctx.String("rollup.config"), ctx.String("network"))
+			if err != nil {
+				return err
+			}
+			l1RPC, err := client.NewRPC(context.Background(), logger, ctx.String("l1"))
+			if err != nil {
+				return fmt.Errorf("failed to dial L1 RPC: %w", err)
+			}
+			defer l1RPC.Close()
+			l1, err := sources.NewL1Client(l1RPC, logger, nil, sources.L1ClientDefaultConfig(cfg, true, sources.RPCProviderKind(strings.ToLower(ctx.String("l1.rpckind")))))
+			if err != nil {
+				return fmt.Errorf("failed to create L1 client: %w", err)
+			}
+			l2RPC, err := client.NewRPC(context.Background(), logger, ctx.String("l2"))
+			if err != nil {
+				return fmt.Errorf("failed to dial L2 RPC: %w", err)
+			}
+			defer l2RPC.Close()
+			l2, err := sources.NewL2Client(l2RPC, logger, nil, sources.L2ClientDefaultConfig(cfg, true))
+			if err != nil {
+				return fmt.Errorf("failed to create L2 client: %w", err)
+			}
+			rec, err := Record(context.Background(), logger, cfg, l1, l2, ctx.Uint64("l2.start"), ctx.Uint64("l2.end"))
+			if err != nil {
+				return err
+			}
+			return WriteRecording(ctx.String("out"), rec)
+		},
+	},
+	{
+		Name:  "replay",
+		Usage: "Run the derivation pipeline offline over a recording, and output the payload attributes of every derived L2 block",
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:     "in",
+				Usage:    "Path to the recording",
+				Required: true,
+			},
+			cli.StringFlag{
+				Name:  "out",
+				Usage: "Path to write the derived blocks to, stdout if not set",
+			},
+			cli.Uint64Flag{
+				Name:  "l2.end",
+				Usage: "Stop when the safe head reaches this L2 block, instead of at the end of the recorded L1 chain",
+			},
+			cli.StringFlag{
+				Name:  "rollup.config",
+				Usage: "Path to a rollup config to replay with, instead of the recorded rollup config",
+			},
+			cli.StringFlag{
+				Name:  "da.store",
+				Usage: "Location of the DA store, a directory or an http(s) URL. Required if DA commitments are enabled in the rollup config",
+			},
+		}, oplog.CLIFlags("OP_NODE")...),
+		Action: func(ctx *cli.Context) error {
+			logger := oplog.NewLogger(oplog.ReadLocalCLIConfig(ctx))
+			rec, err := LoadRecording(ctx.String("in"))
+			if err != nil {
+				return err
+			}
+			if path := ctx.String("rollup.config"); path != "" {
+				rec.Rollup, err = loadRollupConfig(path, "")
+				if err != nil {
+					return err
+				}
+			}
+			var daStore derive.DAStore
+			if location := ctx.String("da.store"); location != "" {
+				daStore, err = dastore.Open(location)
+				if err != nil {
+					return err
+				}
+			} else if rec.Rollup.DACommitmentsTime != nil {
+				return errors.New("DA commitments are scheduled in the rollup config, but no DA store is configured")
+			}
+			derived, err := Replay(context.Background(), logger, rec, daStore, ctx.Uint64("l2.end"))
+			if err != nil {
+				return err
+			}
+			var out io.Writer = os.Stdout
+			if path := ctx.String("out"); path != "" {
+				f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+				if err != nil {
+					return fmt.Errorf("failed to open output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(derived)
+		},
+	},
+}
+
+func loadRollupConfig(path string, network string) (*rollup.Config, error) {
+	if network != "" {
+		cfg, err := chaincfg.GetRollupConfig(network)
+		if err != nil {
+			return nil, err
+		}
+		return &cfg, nil
+	}
+	if path == "" {
+		return nil, errors.New("either a rollup config or a network must be specified")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollup config: %w", err)
+	}
+	defer f.Close()
+	var cfg rollup.Config
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode rollup config: %w", err)
+	}
+	return &cfg, nil
+}
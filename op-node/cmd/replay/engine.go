@@ -0,0 +1,223 @@
+package replay
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+)
+
+// DerivedBlock is an L2 block produced by the replay, with the payload attributes it was built from.
+type DerivedBlock struct {
+	Block eth.L2BlockRef `json:"block"`
+	// DerivedFrom is the L1 block the derivation pipeline was at when the block was derived.
+	DerivedFrom eth.BlockID `json:"derivedFrom"`
+	// Canonical is true if the block matches the parent hash, timestamp and L1 origin of the
+	// recorded canonical block at the same height. The transactions are not compared.
+	Canonical  bool                   `json:"canonical"`
+	Attributes *eth.PayloadAttributes `json:"attributes"`
+}
+
+type stubPayload struct {
+	payload *eth.ExecutionPayload
+	derived *DerivedBlock
+}
+
+// stubEngine is an L2 engine that does not execute anything. It builds payloads directly from the
+// payload attributes and records them. A built block takes the hash of the recorded canonical block
+// at the same height if it matches, so the batches of the canonical chain keep applying on top of it.
+type stubEngine struct {
+	log log.Logger
+	cfg *rollup.Config
+
+	canonical map[uint64]eth.L2BlockRef
+	refs      map[common.Hash]eth.L2BlockRef
+	sysCfgs   map[common.Hash]eth.SystemConfig
+	payloads  map[common.Hash]*eth.ExecutionPayload
+
+	building map[eth.PayloadID]*stubPayload
+	nextID   uint64
+
+	fc      eth.ForkchoiceState
+	derived []*DerivedBlock
+}
+
+var _ derive.Engine = (*stubEngine)(nil)
+
+func newStubEngine(log log.Logger, rec *Recording) *stubEngine {
+	eng := &stubEngine{
+		log:       log,
+		cfg:       rec.Rollup,
+		canonical: make(map[uint64]eth.L2BlockRef, len(rec.L2)),
+		refs:      make(map[common.Hash]eth.L2BlockRef),
+		sysCfgs:   make(map[common.Hash]eth.SystemConfig),
+		payloads:  make(map[common.Hash]*eth.ExecutionPayload),
+		building:  make(map[eth.PayloadID]*stubPayload),
+		fc: eth.ForkchoiceState{
+			HeadBlockHash:      rec.Start.Hash,
+			SafeBlockHash:      rec.Start.Hash,
+			FinalizedBlockHash: rec.Start.Hash,
+		},
+	}
+	for _, b := range rec.L2 {
+		eng.canonical[b.Ref.Number] = b.Ref
+		// Blocks after the start block are not known to the engine, until they are derived.
+		if b.Ref.Number <= rec.Start.Number {
+			eng.refs[b.Ref.Hash] = b.Ref
+			if b.SystemConfig != nil {
+				eng.sysCfgs[b.Ref.Hash] = *b.SystemConfig
+			}
+		}
+	}
+	return eng
+}
+
+func (s *stubEngine) GetPayload(ctx context.Context, payloadId eth.PayloadID) (*eth.ExecutionPayload, error) {
+	p, ok := s.building[payloadId]
+	if !ok {
+		return nil, eth.InputError{Inner: fmt.Errorf("unknown payload %s", payloadId), Code: eth.UnknownPayload}
+	}
+	delete(s.building, payloadId)
+	s.derived = append(s.derived, p.derived)
+	return p.payload, nil
+}
+
+func (s *stubEngine) ForkchoiceUpdate(ctx context.Context, state *eth.ForkchoiceState, attr *eth.PayloadAttributes) (*eth.ForkchoiceUpdatedResult, error) {
+	for _, h := range []common.Hash{state.HeadBlockHash, state.SafeBlockHash, state.FinalizedBlockHash} {
+		if _, ok := s.refs[h]; !ok {
+			return nil, eth.InputError{Inner: fmt.Errorf("unknown block %s", h), Code: eth.InvalidForkchoiceState}
+		}
+	}
+	s.fc = *state
+	res := &eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: eth.ExecutionValid}}
+	if attr == nil {
+		return res, nil
+	}
+	p, err := s.build(s.refs[state.HeadBlockHash], attr)
+	if err != nil {
+		return nil, eth.InputError{Inner: err, Code: eth.InvalidPayloadAttributes}
+	}
+	var id eth.PayloadID
+	binary.BigEndian.PutUint64(id[:], s.nextID)
+	s.nextID++
+	s.building[id] = p
+	res.PayloadID = &id
+	return res, nil
+}
+
+// build creates the payload of the attributes on top of the parent block.
+func (s *stubEngine) build(parent eth.L2BlockRef, attr *eth.PayloadAttributes) (*stubPayload, error) {
+	payload := &eth.ExecutionPayload{
+		ParentHash:   parent.Hash,
+		FeeRecipient: attr.SuggestedFeeRecipient,
+		PrevRandao:   attr.PrevRandao,
+		BlockNumber:  eth.Uint64Quantity(parent.Number + 1),
+		Timestamp:    attr.Timestamp,
+		Transactions: attr.Transactions,
+	}
+	if attr.GasLimit != nil {
+		payload.GasLimit = *attr.GasLimit
+	}
+	if len(payload.Transactions) == 0 {
+		return nil, fmt.Errorf("payload attributes on top of %s have no transactions", parent)
+	}
+	ref, err := derive.PayloadToBlockRef(payload, &s.cfg.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload attributes on top of %s: %w", parent, err)
+	}
+	canon, ok := s.canonical[ref.Number]
+	canonical := ok && canon.ParentHash == ref.ParentHash && canon.Time == ref.Time &&
+		canon.L1Origin == ref.L1Origin && canon.SequenceNumber == ref.SequenceNumber
+	if canonical {
+		payload.BlockHash = canon.Hash
+	} else {
+		payload.BlockHash, _ = payload.CheckBlockHash()
+		s.log.Warn("Derived block does not match recorded canonical block", "number", ref.Number, "canonical", canon, "derived", ref)
+	}
+	ref.Hash = payload.BlockHash
+	return &stubPayload{
+		payload: payload,
+		derived: &DerivedBlock{Block: ref, Canonical: canonical, Attributes: attr},
+	}, nil
+}
+
+func (s *stubEngine) NewPayload(ctx context.Context, payload *eth.ExecutionPayload) (*eth.PayloadStatusV1, error) {
+	if _, ok := s.refs[payload.ParentHash]; !ok {
+		return &eth.PayloadStatusV1{Status: eth.ExecutionSyncing}, nil
+	}
+	ref, err := derive.PayloadToBlockRef(payload, &s.cfg.Genesis)
+	if err != nil {
+		return invalidPayload(err), nil
+	}
+	sysCfg, err := derive.PayloadToSystemConfig(payload, s.cfg)
+	if err != nil {
+		return invalidPayload(err), nil
+	}
+	s.refs[ref.Hash] = ref
+	s.sysCfgs[ref.Hash] = sysCfg
+	s.payloads[ref.Hash] = payload
+	return &eth.PayloadStatusV1{Status: eth.ExecutionValid}, nil
+}
+
+func invalidPayload(err error) *eth.PayloadStatusV1 {
+	msg := err.Error()
+	return &eth.PayloadStatusV1{Status: eth.ExecutionInvalid, ValidationError: &msg}
+}
+
+// PayloadByHash only returns derived payloads, the recorded blocks are not available as payloads.
+func (s *stubEngine) PayloadByHash(ctx context.Context, hash common.Hash) (*eth.ExecutionPayload, error) {
+	p, ok := s.payloads[hash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return p, nil
+}
+
+// PayloadByNumber only returns derived payloads of the current chain, the recorded blocks are not available as payloads.
+func (s *stubEngine) PayloadByNumber(ctx context.Context, num uint64) (*eth.ExecutionPayload, error) {
+	ref := s.refs[s.fc.HeadBlockHash]
+	for ref.Number > num {
+		ref = s.refs[ref.ParentHash]
+	}
+	p, ok := s.payloads[ref.Hash]
+	if !ok || ref.Number != num {
+		return nil, ethereum.NotFound
+	}
+	return p, nil
+}
+
+func (s *stubEngine) L2BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L2BlockRef, error) {
+	switch label {
+	case eth.Unsafe:
+		return s.refs[s.fc.HeadBlockHash], nil
+	case eth.Safe:
+		return s.refs[s.fc.SafeBlockHash], nil
+	case eth.Finalized:
+		return s.refs[s.fc.FinalizedBlockHash], nil
+	default:
+		return eth.L2BlockRef{}, fmt.Errorf("unsupported block label %q", label)
+	}
+}
+
+func (s *stubEngine) L2BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L2BlockRef, error) {
+	ref, ok := s.refs[hash]
+	if !ok {
+		return eth.L2BlockRef{}, fmt.Errorf("L2 block %s is not recorded nor derived: %w", hash, ethereum.NotFound)
+	}
+	return ref, nil
+}
+
+func (s *stubEngine) SystemConfigByL2Hash(ctx context.Context, hash common.Hash) (eth.SystemConfig, error) {
+	sysCfg, ok := s.sysCfgs[hash]
+	if !ok {
+		return eth.SystemConfig{}, fmt.Errorf("system config of L2 block %s is not recorded nor derived: %w", hash, ethereum.NotFound)
+	}
+	return sysCfg, nil
+}
@@ -0,0 +1,137 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+)
+
+type L1Source interface {
+	L1BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L1BlockRef, error)
+	L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error)
+	InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error)
+	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
+}
+
+type L2Source interface {
+	L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+	L2BlockRefByHash(ctx context.Context, l2Hash common.Hash) (eth.L2BlockRef, error)
+	SystemConfigByL2Hash(ctx context.Context, hash common.Hash) (eth.SystemConfig, error)
+}
+
+// Record records the L1 and L2 chain data to derive the L2 blocks after the start block, up to and including the end block.
+// The L1 chain is recorded from the L1 origin that the derivation pipeline resets to, given the start block as safe head,
+// up to a full sequencing window after the L1 origin of the end block, or up to the L1 head, if that is earlier.
+func Record(ctx context.Context, logger log.Logger, cfg *rollup.Config, l1 L1Source, l2 L2Source, start uint64, end uint64) (*Recording, error) {
+	if end < start {
+		return nil, fmt.Errorf("end block %d is before start block %d", end, start)
+	}
+	startRef, err := l2.L2BlockRefByNumber(ctx, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch start block %d: %w", start, err)
+	}
+	endRef, err := l2.L2BlockRefByNumber(ctx, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch end block %d: %w", end, err)
+	}
+
+	// Walk back the L2 chain like the engine queue does on reset,
+	// to find the L2 block of the L1 origin that the derivation pipeline starts at.
+	pipelineL2 := startRef
+	for pipelineL2.Number > cfg.Genesis.L2.Number && pipelineL2.L1Origin.Number > cfg.Genesis.L1.Number &&
+		pipelineL2.L1Origin.Number+cfg.ChannelTimeout > startRef.L1Origin.Number {
+		pipelineL2, err = l2.L2BlockRefByHash(ctx, pipelineL2.ParentHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch L2 parent block %s: %w", pipelineL2.ParentID(), err)
+		}
+	}
+
+	rec := &Recording{Rollup: cfg, Start: startRef}
+	logger.Info("Recording L2 blocks", "from", pipelineL2.Number, "to", endRef.Number)
+	for num := pipelineL2.Number; num <= endRef.Number; num++ {
+		ref, err := l2.L2BlockRefByNumber(ctx, num)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch L2 block %d: %w", num, err)
+		}
+		b := &L2Block{Ref: ref}
+		if num <= startRef.Number {
+			sysCfg, err := l2.SystemConfigByL2Hash(ctx, ref.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch system config of L2 block %s: %w", ref, err)
+			}
+			b.SystemConfig = &sysCfg
+		}
+		rec.L2 = append(rec.L2, b)
+	}
+
+	l1Head, err := l1.L1BlockRefByLabel(ctx, eth.Unsafe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch L1 head: %w", err)
+	}
+	l1End := endRef.L1Origin.Number + cfg.SeqWindowSize
+	if l1End > l1Head.Number {
+		l1End = l1Head.Number
+	}
+	logger.Info("Recording L1 blocks", "from", pipelineL2.L1Origin.Number, "to", l1End)
+	for num := pipelineL2.L1Origin.Number; num <= l1End; num++ {
+		b, err := recordL1Block(ctx, cfg, l1, num)
+		if err != nil {
+			return nil, err
+		}
+		if len(rec.L1) > 0 && rec.L1[len(rec.L1)-1].Hash != b.ParentHash {
+			return nil, fmt.Errorf("L1 block %s does not build on previously recorded block, L1 chain reorged while recording", b.ref())
+		}
+		rec.L1 = append(rec.L1, b)
+	}
+	return rec, nil
+}
+
+func recordL1Block(ctx context.Context, cfg *rollup.Config, l1 L1Source, num uint64) (*L1Block, error) {
+	ref, err := l1.L1BlockRefByNumber(ctx, num)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch L1 block %d: %w", num, err)
+	}
+	info, txs, err := l1.InfoAndTxsByHash(ctx, ref.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions of L1 block %s: %w", ref, err)
+	}
+	_, receipts, err := l1.FetchReceipts(ctx, ref.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receipts of L1 block %s: %w", ref, err)
+	}
+	b := &L1Block{
+		Hash:         info.Hash(),
+		ParentHash:   info.ParentHash(),
+		Coinbase:     info.Coinbase(),
+		Root:         info.Root(),
+		Number:       info.NumberU64(),
+		Time:         info.Time(),
+		MixDigest:    info.MixDigest(),
+		BaseFee:      (*hexutil.Big)(info.BaseFee()),
+		ReceiptHash:  info.ReceiptHash(),
+		GasUsed:      info.GasUsed(),
+		Transactions: types.Transactions{},
+		Receipts:     types.Receipts{},
+	}
+	for _, tx := range txs {
+		if to := tx.To(); to != nil && *to == cfg.BatchInboxAddress {
+			b.Transactions = append(b.Transactions, tx)
+		}
+	}
+	for _, rec := range receipts {
+		for _, l := range rec.Logs {
+			if l.Address == cfg.DepositContractAddress || l.Address == cfg.L1SystemConfigAddress {
+				b.Receipts = append(b.Receipts, rec)
+				break
+			}
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,189 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+)
+
+// Recording is the L1 and L2 chain data that is needed to run the derivation pipeline offline.
+type Recording struct {
+	Rollup *rollup.Config `json:"rollup"`
+	// Start is the L2 safe head that derivation starts from.
+	Start eth.L2BlockRef `json:"start"`
+	// L2 is the canonical L2 chain, consecutive and in ascending order.
+	// It starts early enough for the derivation pipeline to reset to Start,
+	// and is used to check if the derived blocks are canonical.
+	L2 []*L2Block `json:"l2"`
+	// L1 is the L1 chain, consecutive and in ascending order, from the L1 origin that derivation starts at.
+	L1 []*L1Block `json:"l1"`
+}
+
+// L2Block is a recorded canonical L2 block.
+type L2Block struct {
+	Ref eth.L2BlockRef `json:"ref"`
+	// SystemConfig is only recorded up to and including the start block, the derivation pipeline derives the rest.
+	SystemConfig *eth.SystemConfig `json:"systemConfig,omitempty"`
+}
+
+// L1Block is a recorded L1 block. Only the transactions and receipts that
+// the derivation pipeline reads are recorded: batcher transactions, and receipts with
+// deposit or system config logs.
+type L1Block struct {
+	Hash        common.Hash    `json:"hash"`
+	ParentHash  common.Hash    `json:"parentHash"`
+	Coinbase    common.Address `json:"coinbase"`
+	Root        common.Hash    `json:"root"`
+	Number      uint64         `json:"number"`
+	Time        uint64         `json:"time"`
+	MixDigest   common.Hash    `json:"mixDigest"`
+	BaseFee     *hexutil.Big   `json:"baseFee"`
+	ReceiptHash common.Hash    `json:"receiptHash"`
+	GasUsed     uint64         `json:"gasUsed"`
+
+	Transactions types.Transactions `json:"transactions"`
+	Receipts     types.Receipts     `json:"receipts"`
+}
+
+func (b *L1Block) ref() eth.L1BlockRef {
+	return eth.L1BlockRef{
+		Hash:       b.Hash,
+		Number:     b.Number,
+		ParentHash: b.ParentHash,
+		Time:       b.Time,
+	}
+}
+
+// l1BlockInfo wraps a recorded L1 block as eth.BlockInfo
+type l1BlockInfo struct {
+	b *L1Block
+}
+
+var _ eth.BlockInfo = l1BlockInfo{}
+
+func (info l1BlockInfo) Hash() common.Hash        { return info.b.Hash }
+func (info l1BlockInfo) ParentHash() common.Hash  { return info.b.ParentHash }
+func (info l1BlockInfo) Coinbase() common.Address { return info.b.Coinbase }
+func (info l1BlockInfo) Root() common.Hash        { return info.b.Root }
+func (info l1BlockInfo) NumberU64() uint64        { return info.b.Number }
+func (info l1BlockInfo) Time() uint64             { return info.b.Time }
+func (info l1BlockInfo) MixDigest() common.Hash   { return info.b.MixDigest }
+func (info l1BlockInfo) BaseFee() *big.Int        { return (*big.Int)(info.b.BaseFee) }
+func (info l1BlockInfo) ReceiptHash() common.Hash { return info.b.ReceiptHash }
+func (info l1BlockInfo) GasUsed() uint64          { return info.b.GasUsed }
+
+// LoadRecording reads a recording from a JSON file.
+func LoadRecording(path string) (*Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+	var rec Recording
+	if err := json.NewDecoder(f).Decode(&rec); err != nil {
+		return nil, fmt.Errorf("failed to decode recording: %w", err)
+	}
+	return &rec, nil
+}
+
+// WriteRecording writes the recording to a JSON file.
+func WriteRecording(path string, rec *Recording) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open recording file: %w", err)
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(rec); err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+	return nil
+}
+
+// recordedL1 serves the recorded L1 chain to the derivation pipeline.
+type recordedL1 struct {
+	blocks   []*L1Block
+	byHash   map[common.Hash]*L1Block
+	byNumber map[uint64]*L1Block
+}
+
+var _ derive.L1Fetcher = (*recordedL1)(nil)
+
+func newRecordedL1(blocks []*L1Block) *recordedL1 {
+	l1 := &recordedL1{
+		blocks:   blocks,
+		byHash:   make(map[common.Hash]*L1Block, len(blocks)),
+		byNumber: make(map[uint64]*L1Block, len(blocks)),
+	}
+	for _, b := range blocks {
+		l1.byHash[b.Hash] = b
+		l1.byNumber[b.Number] = b
+	}
+	return l1
+}
+
+func (l1 *recordedL1) blockByHash(hash common.Hash) (*L1Block, error) {
+	b, ok := l1.byHash[hash]
+	if !ok {
+		return nil, fmt.Errorf("L1 block %s is not recorded: %w", hash, ethereum.NotFound)
+	}
+	return b, nil
+}
+
+// L1BlockRefByLabel returns the last recorded block for any label, the recorded L1 chain is final.
+func (l1 *recordedL1) L1BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L1BlockRef, error) {
+	if len(l1.blocks) == 0 {
+		return eth.L1BlockRef{}, ethereum.NotFound
+	}
+	return l1.blocks[len(l1.blocks)-1].ref(), nil
+}
+
+func (l1 *recordedL1) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	b, ok := l1.byNumber[num]
+	if !ok {
+		return eth.L1BlockRef{}, ethereum.NotFound
+	}
+	return b.ref(), nil
+}
+
+func (l1 *recordedL1) L1BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L1BlockRef, error) {
+	b, err := l1.blockByHash(hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return b.ref(), nil
+}
+
+func (l1 *recordedL1) InfoByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, error) {
+	b, err := l1.blockByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return l1BlockInfo{b}, nil
+}
+
+func (l1 *recordedL1) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	b, err := l1.blockByHash(blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l1BlockInfo{b}, b.Receipts, nil
+}
+
+func (l1 *recordedL1) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	b, err := l1.blockByHash(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l1BlockInfo{b}, b.Transactions, nil
+}
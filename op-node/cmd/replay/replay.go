@@ -0,0 +1,72 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+)
+
+const (
+	// maxResets is the number of pipeline resets tolerated during a replay.
+	// The recorded data does not change, so a pipeline that keeps resetting will not recover.
+	maxResets = 3
+	// maxTemporaryErrors is the number of consecutive temporary errors tolerated during a replay.
+	maxTemporaryErrors = 10
+)
+
+// Replay runs the derivation pipeline over the recorded L1 chain, starting from the recorded start block,
+// and returns the derived L2 blocks in the order they were derived.
+// The DA store is only used if DA commitments are enabled in the rollup config.
+// The replay ends when the recorded L1 chain is exhausted, or when the safe head reaches the end block, if non-zero.
+func Replay(ctx context.Context, logger log.Logger, rec *Recording, daStore derive.DAStore, end uint64) ([]*DerivedBlock, error) {
+	if rec.Rollup == nil {
+		return nil, errors.New("recording has no rollup config")
+	}
+	l1 := newRecordedL1(rec.L1)
+	engine := newStubEngine(logger, rec)
+	pipeline := derive.NewDerivationPipeline(logger, rec.Rollup, l1, daStore, engine, metrics.NoopMetrics, derive.NoopSafeHeadListener)
+	pipeline.Reset()
+
+	resets := 0
+	temporaryErrors := 0
+	for end == 0 || pipeline.SafeL2Head().Number < end {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		derived := len(engine.derived)
+		err := pipeline.Step(ctx)
+		for _, b := range engine.derived[derived:] {
+			b.DerivedFrom = pipeline.Origin().ID()
+		}
+		if err == io.EOF {
+			logger.Info("Replay reached end of recorded L1 chain", "origin", pipeline.Origin(), "safe", pipeline.SafeL2Head())
+			break
+		} else if err != nil && errors.Is(err, derive.ErrReset) {
+			resets++
+			if resets > maxResets {
+				return nil, fmt.Errorf("derivation pipeline keeps resetting: %w", err)
+			}
+			logger.Warn("Derivation pipeline is reset", "err", err)
+			pipeline.Reset()
+		} else if err != nil && errors.Is(err, derive.ErrTemporary) {
+			temporaryErrors++
+			if temporaryErrors > maxTemporaryErrors {
+				return nil, fmt.Errorf("derivation pipeline keeps failing: %w", err)
+			}
+			logger.Warn("Derivation process temporary error", "attempts", temporaryErrors, "err", err)
+		} else if err != nil && errors.Is(err, derive.NotEnoughData) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("derivation failed: %w", err)
+		} else {
+			temporaryErrors = 0
+		}
+	}
+	return engine.derived, nil
+}
@@ -0,0 +1,65 @@
+package dastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FileStore stores the data of every commitment in a file in a local directory,
+// which may be shared by the batcher and the rollup nodes of a devnet.
+type FileStore struct {
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore creates a file store in the given directory, the directory is created if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create DA store directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(commitment common.Hash) string {
+	return filepath.Join(s.dir, commitment.Hex())
+}
+
+func (s *FileStore) Get(ctx context.Context, commitment common.Hash) ([]byte, error) {
+	data, err := os.ReadFile(s.path(commitment))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read data of %s: %w", commitment, err)
+	}
+	return data, nil
+}
+
+// Put writes the data to a temporary file first, so readers never see partially written data.
+func (s *FileStore) Put(ctx context.Context, commitment common.Hash, data []byte) error {
+	if err := checkCommitment(commitment, data); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.dir, commitment.Hex()+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write data of %s: %w", commitment, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write data of %s: %w", commitment, err)
+	}
+	if err := os.Rename(f.Name(), s.path(commitment)); err != nil {
+		return fmt.Errorf("failed to store data of %s: %w", commitment, err)
+	}
+	return nil
+}
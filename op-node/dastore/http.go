@@ -0,0 +1,127 @@
+package dastore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MaxDataSize is the maximum size of the data that the HTTP handler accepts, and that the HTTP store reads.
+const MaxDataSize = 100_000_000
+
+// HTTPStore is a client of a DA server, which serves the data of a commitment at GET {url}/get/{commitment},
+// and stores data at PUT {url}/put/{commitment}. The commitment is 0x-prefixed hex.
+type HTTPStore struct {
+	url    string
+	client *http.Client
+}
+
+var _ Store = (*HTTPStore)(nil)
+
+func NewHTTPStore(url string) *HTTPStore {
+	return &HTTPStore{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *HTTPStore) Get(ctx context.Context, commitment common.Hash) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"/get/"+commitment.Hex(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data of %s: %w", commitment, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	} else if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get data of %s: DA server returned status %d", commitment, res.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, MaxDataSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data of %s: %w", commitment, err)
+	}
+	return data, nil
+}
+
+func (s *HTTPStore) Put(ctx context.Context, commitment common.Hash, data []byte) error {
+	if err := checkCommitment(commitment, data); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.url+"/put/"+commitment.Hex(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to put data of %s: %w", commitment, err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to put data of %s: DA server returned status %d", commitment, res.StatusCode)
+	}
+	return nil
+}
+
+// Handler serves a store to HTTPStore clients.
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, key, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	var commitment common.Hash
+	if err := commitment.UnmarshalText([]byte(key)); err != nil {
+		http.Error(w, "invalid commitment", http.StatusBadRequest)
+		return
+	}
+	switch {
+	case route == "get" && r.Method == http.MethodGet:
+		data, err := h.store.Get(r.Context(), commitment)
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(data)
+	case route == "put" && r.Method == http.MethodPut:
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxDataSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := checkCommitment(commitment, data); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.store.Put(r.Context(), commitment, data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported request %s %s", r.Method, r.URL.Path), http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,40 @@
+// Package dastore provides the data-availability stores that the batcher writes batch data to,
+// and that the rollup node reads batch data from, when DA commitments are enabled in the rollup config.
+// Data is addressed by its keccak256 commitment, which the batcher posts to L1 instead of the data itself.
+package dastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNotFound is returned when the store does not have the data of a commitment.
+var ErrNotFound = errors.New("not found")
+
+type Store interface {
+	// Get returns the data with the given commitment, or ErrNotFound.
+	Get(ctx context.Context, commitment common.Hash) ([]byte, error)
+	// Put stores the data under the given commitment. The commitment must be the keccak256 hash of the data.
+	Put(ctx context.Context, commitment common.Hash, data []byte) error
+}
+
+// Open opens the store at the given location: an HTTP store if it is an http(s) URL, a file store otherwise.
+func Open(location string) (Store, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return NewHTTPStore(location), nil
+	}
+	return NewFileStore(location)
+}
+
+// checkCommitment verifies that the commitment is the keccak256 hash of the data.
+func checkCommitment(commitment common.Hash, data []byte) error {
+	if got := crypto.Keccak256Hash(data); got != commitment {
+		return fmt.Errorf("data has commitment %s, expected %s", got, commitment)
+	}
+	return nil
+}
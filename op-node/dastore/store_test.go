@@ -0,0 +1,61 @@
+package dastore
+
+import (
+	"context"
+	"math/rand"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1234))
+	data := testutils.RandomData(rng, 1000)
+	commitment := crypto.Keccak256Hash(data)
+
+	_, err := store.Get(ctx, commitment)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Put(ctx, commitment, data))
+	got, err := store.Get(ctx, commitment)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+
+	// storing the same data again is fine
+	require.NoError(t, store.Put(ctx, commitment, data))
+
+	// data that does not match the commitment is rejected
+	other := testutils.RandomData(rng, 1000)
+	require.Error(t, store.Put(ctx, commitment, other))
+	got, err = store.Get(ctx, commitment)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	testStore(t, store)
+}
+
+func TestHTTPStore(t *testing.T) {
+	backend, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewHandler(backend))
+	t.Cleanup(srv.Close)
+	testStore(t, NewHTTPStore(srv.URL))
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open(t.TempDir())
+	require.NoError(t, err)
+	require.IsType(t, &FileStore{}, store)
+	store, err = Open("http://localhost:3100/")
+	require.NoError(t, err)
+	require.IsType(t, &HTTPStore{}, store)
+}
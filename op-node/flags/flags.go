@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
 
 	"github.com/urfave/cli"
 )
@@ -24,7 +25,7 @@ var (
 	/* Required Flags */
 	L1NodeAddr = cli.StringFlag{
 		Name:   "l1",
-		Usage:  "Address of L1 User JSON-RPC endpoint to use (eth namespace required)",
+		Usage:  "Address of L1 User JSON-RPC endpoint to use (eth namespace required). Multiple comma-separated addresses route requests to the healthiest endpoint, and fail over on errors",
 		Value:  "http://127.0.0.1:8545",
 		EnvVar: prefixEnvVar("L1_ETH_RPC"),
 	}
@@ -58,6 +59,11 @@ var (
 		Usage:  "Enable the admin API (experimental)",
 		EnvVar: prefixEnvVar("RPC_ENABLE_ADMIN"),
 	}
+	RPCEnableDebug = cli.BoolFlag{
+		Name:   "rpc.enable-debug",
+		Usage:  "Enable the debug API, which exposes the state of the derivation pipeline",
+		EnvVar: prefixEnvVar("RPC_ENABLE_DEBUG"),
+	}
 
 	/* Optional Flags */
 	L1TrustRPC = cli.BoolFlag{
@@ -114,6 +120,12 @@ var (
 		Required: false,
 		Value:    time.Second * 12 * 32,
 	}
+	L1QuorumFlag = cli.UintFlag{
+		Name:     "l1.quorum",
+		Usage:    "Number of L1 endpoints that must agree on the L1 head, safe and finalized block before they are used. Disabled if 0.",
+		EnvVar:   prefixEnvVar("L1_QUORUM"),
+		Required: false,
+	}
 	MetricsEnabledFlag = cli.BoolFlag{
 		Name:   "metrics.enabled",
 		Usage:  "Enable the metrics server",
@@ -175,6 +187,44 @@ var (
 		EnvVar:   prefixEnvVar("L2_BACKUP_UNSAFE_SYNC_RPC"),
 		Required: false,
 	}
+	SafeDBPath = cli.StringFlag{
+		Name:   "safedb.path",
+		Usage:  "File path used to persist the safe head of every L1 block. Disabled if not set.",
+		EnvVar: prefixEnvVar("SAFEDB_PATH"),
+	}
+	DAStoreFlag = cli.StringFlag{
+		Name:   "da.store",
+		Usage:  "Location of the DA store to retrieve batch data from, if DA commitments are enabled in the rollup config: a directory, or the http(s) URL of a DA server.",
+		EnvVar: prefixEnvVar("DA_STORE"),
+	}
+	ConductorEnabledFlag = cli.BoolFlag{
+		Name: "conductor.enabled",
+		Usage: "Enable sequencer failover: the sequencer is started only while the node holds the sequencer lease, shared with the standby sequencer nodes. " +
+			"The sequencer should not be started or stopped with the admin RPC while this is enabled.",
+		EnvVar: prefixEnvVar("CONDUCTOR_ENABLED"),
+	}
+	ConductorIDFlag = cli.StringFlag{
+		Name:   "conductor.id",
+		Usage:  "Unique ID of the node in the sequencer lease",
+		EnvVar: prefixEnvVar("CONDUCTOR_ID"),
+	}
+	ConductorLeasePathFlag = cli.StringFlag{
+		Name:   "conductor.lease.path",
+		Usage:  "Path of the sequencer lease file, shared with the standby sequencer nodes",
+		EnvVar: prefixEnvVar("CONDUCTOR_LEASE_PATH"),
+	}
+	ConductorLeaseDurationFlag = cli.DurationFlag{
+		Name:   "conductor.lease.duration",
+		Usage:  "Duration of the sequencer lease, after which a standby node may take over if the leader did not renew it",
+		EnvVar: prefixEnvVar("CONDUCTOR_LEASE_DURATION"),
+		Value:  time.Second * 10,
+	}
+	ConductorRenewIntervalFlag = cli.DurationFlag{
+		Name:   "conductor.renew.interval",
+		Usage:  "Interval to renew the sequencer lease as leader, or to try to acquire it as standby",
+		EnvVar: prefixEnvVar("CONDUCTOR_RENEW_INTERVAL"),
+		Value:  time.Second * 2,
+	}
 )
 
 var requiredFlags = []cli.Flag{
@@ -195,7 +245,9 @@ var optionalFlags = []cli.Flag{
 	SequencerStoppedFlag,
 	SequencerL1Confs,
 	L1EpochPollIntervalFlag,
+	L1QuorumFlag,
 	RPCEnableAdmin,
+	RPCEnableDebug,
 	MetricsEnabledFlag,
 	MetricsAddrFlag,
 	MetricsPortFlag,
@@ -207,6 +259,13 @@ var optionalFlags = []cli.Flag{
 	HeartbeatMonikerFlag,
 	HeartbeatURLFlag,
 	BackupL2UnsafeSyncRPC,
+	SafeDBPath,
+	DAStoreFlag,
+	ConductorEnabledFlag,
+	ConductorIDFlag,
+	ConductorLeasePathFlag,
+	ConductorLeaseDurationFlag,
+	ConductorRenewIntervalFlag,
 }
 
 // Flags contains the list of configuration options available to the binary.
@@ -214,6 +273,7 @@ var Flags []cli.Flag
 
 func init() {
 	optionalFlags = append(optionalFlags, p2pFlags...)
+	optionalFlags = append(optionalFlags, optls.CLIFlagsWithFlagPrefix(p2pEnv("SIGNER"), SequencerP2PSignerTLSFlagPrefix)...)
 	optionalFlags = append(optionalFlags, oplog.CLIFlags(envVarPrefix)...)
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -8,6 +8,9 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 )
 
+// SequencerP2PSignerTLSFlagPrefix is the flag prefix of the TLS config of the op-signer client, e.g. p2p.signer.tls.ca
+const SequencerP2PSignerTLSFlagPrefix = "p2p.signer"
+
 func p2pEnv(v string) string {
 	return prefixEnvVar("P2P_" + v)
 }
@@ -228,6 +231,41 @@ var (
 		Value:    "",
 		EnvVar:   p2pEnv("SEQUENCER_KEY"),
 	}
+	SequencerP2PSignerEndpointFlag = cli.StringFlag{
+		Name:     "p2p.signer.endpoint",
+		Usage:    "Endpoint of the op-signer service to sign gossiped blocks with, instead of the p2p.sequencer.key.",
+		Required: false,
+		Value:    "",
+		EnvVar:   p2pEnv("SIGNER_ENDPOINT"),
+	}
+	SequencerP2PSignerAddressFlag = cli.StringFlag{
+		Name:     "p2p.signer.address",
+		Usage:    "Address of the key of the op-signer service that gossiped blocks are signed with.",
+		Required: false,
+		Value:    "",
+		EnvVar:   p2pEnv("SIGNER_ADDRESS"),
+	}
+	SequencerP2PSignerTimeoutFlag = cli.DurationFlag{
+		Name:     "p2p.signer.timeout",
+		Usage:    "Timeout of a block signing request to the op-signer service.",
+		Required: false,
+		Value:    p2p.DefaultRemoteSignerTimeout,
+		EnvVar:   p2pEnv("SIGNER_TIMEOUT"),
+	}
+	SequencerP2PSignerFallbackFlag = cli.StringFlag{
+		Name:     "p2p.signer.fallback",
+		Usage:    "Policy to sign gossiped blocks with the p2p.sequencer.key if the op-signer service is unreachable or times out: 'none' or 'local'.",
+		Required: false,
+		Value:    string(p2p.SignerFallbackNone),
+		EnvVar:   p2pEnv("SIGNER_FALLBACK"),
+	}
+	SequencerP2PGraceBlocksFlag = cli.Uint64Flag{
+		Name:     "p2p.sequencer.grace-blocks",
+		Usage:    "Number of L1 blocks for which blocks signed by the previous p2p sequencer address are still accepted after the address changed in the SystemConfig. Recovering the previous address on startup requires the L1 state of this many blocks. Disabled if 0.",
+		Required: false,
+		Value:    64,
+		EnvVar:   p2pEnv("SEQUENCER_GRACE_BLOCKS"),
+	}
 	GossipMeshDFlag = cli.UintFlag{
 		Name:     "p2p.gossip.mesh.d",
 		Usage:    "Configure GossipSub topic stable mesh target count, a.k.a. desired outbound degree, number of peers to gossip to",
@@ -267,6 +305,12 @@ var (
 		Hidden:   true,
 		EnvVar:   p2pEnv("GOSSIP_FLOOD_PUBLISH"),
 	}
+	SyncReqRespFlag = cli.BoolFlag{
+		Name:     "p2p.sync.req-resp",
+		Usage:    "Enables serving unsafe L2 payloads to peers by block number, and requesting missing unsafe L2 payloads from peers.",
+		Required: false,
+		EnvVar:   p2pEnv("SYNC_REQ_RESP"),
+	}
 )
 
 // None of these flags are strictly required.
@@ -297,9 +341,15 @@ var p2pFlags = []cli.Flag{
 	PeerstorePath,
 	DiscoveryPath,
 	SequencerP2PKeyFlag,
+	SequencerP2PSignerEndpointFlag,
+	SequencerP2PSignerAddressFlag,
+	SequencerP2PSignerTimeoutFlag,
+	SequencerP2PSignerFallbackFlag,
+	SequencerP2PGraceBlocksFlag,
 	GossipMeshDFlag,
 	GossipMeshDloFlag,
 	GossipMeshDhiFlag,
 	GossipMeshDlazyFlag,
 	GossipFloodPublishFlag,
+	SyncReqRespFlag,
 }
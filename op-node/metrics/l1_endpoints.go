@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+)
+
+// L1EndpointMetrics implements the metrics of the L1 RPC endpoints:
+// the health of every endpoint, and the L1 blocks that the endpoints did not agree on.
+type L1EndpointMetrics struct {
+	Health              *prometheus.GaugeVec
+	HeadLag             *prometheus.GaugeVec
+	QuorumFailuresTotal *prometheus.CounterVec
+}
+
+// RecordEndpointHealth meters the health score and the head lag in blocks of an L1 endpoint.
+func (m *L1EndpointMetrics) RecordEndpointHealth(endpoint string, score float64, headLag uint64) {
+	m.Health.WithLabelValues(endpoint).Set(score)
+	m.HeadLag.WithLabelValues(endpoint).Set(float64(headLag))
+}
+
+// RecordQuorumFailure meters an L1 block of the given label that was ignored, for lack of quorum of L1 endpoints.
+func (m *L1EndpointMetrics) RecordQuorumFailure(label string) {
+	m.QuorumFailuresTotal.WithLabelValues(label).Inc()
+}
+
+func NewL1EndpointMetrics(factory metrics.Factory, ns string) *L1EndpointMetrics {
+	return &L1EndpointMetrics{
+		Health: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "l1_endpoint_health",
+			Help:      "Health score of the L1 RPC endpoint, between 0 (failing) and 1 (healthy)",
+		}, []string{
+			"endpoint",
+		}),
+		HeadLag: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "l1_endpoint_head_lag",
+			Help:      "Number of blocks the head of the L1 RPC endpoint is behind the best L1 RPC endpoint",
+		}, []string{
+			"endpoint",
+		}),
+		QuorumFailuresTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "l1_quorum_failures_total",
+			Help:      "L1 blocks that were ignored, because not enough L1 RPC endpoints agreed on them",
+		}, []string{
+			"label",
+		}),
+	}
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+)
+
+// L1SourceMetrics implements the metrics of the L1 client in the sources package:
+// the cache metrics, and the L1 data of the RPC that failed verification against the block header.
+type L1SourceMetrics struct {
+	*CacheMetrics
+	VerificationFailuresTotal *prometheus.CounterVec
+}
+
+// RecordVerificationFailure meters RPC data that failed verification,
+// by RPC provider kind, RPC method and data type.
+func (m *L1SourceMetrics) RecordVerificationFailure(provider string, method string, data string) {
+	m.VerificationFailuresTotal.WithLabelValues(provider, method, data).Inc()
+}
+
+func NewL1SourceMetrics(factory metrics.Factory, ns string) *L1SourceMetrics {
+	return &L1SourceMetrics{
+		CacheMetrics: NewCacheMetrics(factory, ns, "l1_source_cache", "L1 Source cache"),
+		VerificationFailuresTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "l1_source_verification_failures_total",
+			Help:      "L1 RPC data that failed verification against the block header",
+		}, []string{
+			"provider",
+			"method",
+			"data",
+		}),
+	}
+}
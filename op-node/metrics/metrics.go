@@ -47,6 +47,7 @@ type Metricer interface {
 	RecordSequencingError()
 	RecordPublishingError()
 	RecordDerivationError()
+	RecordDACommitmentMismatch()
 	RecordReceivedUnsafePayload(payload *eth.ExecutionPayload)
 	recordRef(layer string, name string, num uint64, timestamp uint64, h common.Hash)
 	RecordL1Ref(name string, ref eth.L1BlockRef)
@@ -80,7 +81,8 @@ type Metrics struct {
 	RPCClientRequestDurationSeconds *prometheus.HistogramVec
 	RPCClientResponsesTotal         *prometheus.CounterVec
 
-	L1SourceCache *CacheMetrics
+	L1Source      *L1SourceMetrics
+	L1Endpoints   *L1EndpointMetrics
 	L2SourceCache *CacheMetrics
 
 	DerivationIdle prometheus.Gauge
@@ -91,6 +93,8 @@ type Metrics struct {
 	SequencingErrors *EventMetrics
 	PublishingErrors *EventMetrics
 
+	DACommitmentMismatches *EventMetrics
+
 	SequencerInconsistentL1Origin *EventMetrics
 	SequencerResets               *EventMetrics
 
@@ -198,7 +202,8 @@ func NewMetrics(procName string) *Metrics {
 			"error",
 		}),
 
-		L1SourceCache: NewCacheMetrics(factory, ns, "l1_source_cache", "L1 Source cache"),
+		L1Source:      NewL1SourceMetrics(factory, ns),
+		L1Endpoints:   NewL1EndpointMetrics(factory, ns),
 		L2SourceCache: NewCacheMetrics(factory, ns, "l2_source_cache", "L2 Source cache"),
 
 		DerivationIdle: factory.NewGauge(prometheus.GaugeOpts{
@@ -213,6 +218,8 @@ func NewMetrics(procName string) *Metrics {
 		SequencingErrors: NewEventMetrics(factory, ns, "sequencing_errors", "sequencing errors"),
 		PublishingErrors: NewEventMetrics(factory, ns, "publishing_errors", "p2p publishing errors"),
 
+		DACommitmentMismatches: NewEventMetrics(factory, ns, "da_commitment_mismatches", "data from the DA store that did not match its DA commitment"),
+
 		SequencerInconsistentL1Origin: NewEventMetrics(factory, ns, "sequencer_inconsistent_l1_origin", "events when the sequencer selects an inconsistent L1 origin"),
 		SequencerResets:               NewEventMetrics(factory, ns, "sequencer_resets", "sequencer resets"),
 
@@ -429,6 +436,10 @@ func (m *Metrics) RecordPublishingError() {
 	m.PublishingErrors.RecordEvent()
 }
 
+func (m *Metrics) RecordDACommitmentMismatch() {
+	m.DACommitmentMismatches.RecordEvent()
+}
+
 func (m *Metrics) RecordDerivationError() {
 	m.DerivationErrors.RecordEvent()
 }
@@ -597,6 +608,9 @@ func (n *noopMetricer) RecordPublishingError() {
 func (n *noopMetricer) RecordDerivationError() {
 }
 
+func (n *noopMetricer) RecordDACommitmentMismatch() {
+}
+
 func (n *noopMetricer) RecordReceivedUnsafePayload(payload *eth.ExecutionPayload) {
 }
 
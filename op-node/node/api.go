@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum"
@@ -12,7 +13,11 @@ import (
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
+	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/version"
 )
 
@@ -29,6 +34,18 @@ type driverClient interface {
 	ResetDerivationPipeline(context.Context) error
 	StartSequencer(ctx context.Context, blockHash common.Hash) error
 	StopSequencer(context.Context) (common.Hash, error)
+	SequencerPolicy(ctx context.Context) (*driver.SequencerPolicyConfig, error)
+	SetSequencerPolicy(ctx context.Context, cfg driver.SequencerPolicyConfig) error
+	DerivationState(ctx context.Context) (*derive.DerivationState, error)
+}
+
+type p2pSignerClient interface {
+	SetP2PSigner(cfg *p2p.SignerConfig) error
+}
+
+type SafeDBReader interface {
+	SafeHeadAtL1(l1BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error)
+	L1OriginOfSafeBlock(l2BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error)
 }
 
 type rpcMetrics interface {
@@ -37,14 +54,17 @@ type rpcMetrics interface {
 }
 
 type adminAPI struct {
-	dr driverClient
-	m  rpcMetrics
+	dr     driverClient
+	signer p2pSignerClient
+	m      rpcMetrics
 }
 
-func NewAdminAPI(dr driverClient, m rpcMetrics) *adminAPI {
+// NewAdminAPI creates the admin API. The signer may be nil if the p2p signer cannot be replaced.
+func NewAdminAPI(dr driverClient, signer p2pSignerClient, m rpcMetrics) *adminAPI {
 	return &adminAPI{
-		dr: dr,
-		m:  m,
+		dr:     dr,
+		signer: signer,
+		m:      m,
 	}
 }
 
@@ -66,19 +86,64 @@ func (n *adminAPI) StopSequencer(ctx context.Context) (common.Hash, error) {
 	return n.dr.StopSequencer(ctx)
 }
 
+func (n *adminAPI) SequencerPolicy(ctx context.Context) (*driver.SequencerPolicyConfig, error) {
+	recordDur := n.m.RecordRPCServerRequest("admin_sequencerPolicy")
+	defer recordDur()
+	return n.dr.SequencerPolicy(ctx)
+}
+
+func (n *adminAPI) SetSequencerPolicy(ctx context.Context, cfg driver.SequencerPolicyConfig) error {
+	recordDur := n.m.RecordRPCServerRequest("admin_setSequencerPolicy")
+	defer recordDur()
+	return n.dr.SetSequencerPolicy(ctx, cfg)
+}
+
+// SetP2PSigner replaces the signer of gossiped blocks.
+// The new key is referenced by the path of a key file on the node, or the op-signer service that holds it,
+// keys are not accepted over RPC. The p2p sequencer address in the SystemConfig should be changed first,
+// and the signer replaced before the grace period of the previous address ends.
+func (n *adminAPI) SetP2PSigner(ctx context.Context, cfg p2p.SignerConfig) error {
+	recordDur := n.m.RecordRPCServerRequest("admin_setP2PSigner")
+	defer recordDur()
+	if n.signer == nil {
+		return errors.New("p2p signer cannot be replaced")
+	}
+	return n.signer.SetP2PSigner(&cfg)
+}
+
+type debugAPI struct {
+	dr driverClient
+	m  rpcMetrics
+}
+
+func NewDebugAPI(dr driverClient, m rpcMetrics) *debugAPI {
+	return &debugAPI{
+		dr: dr,
+		m:  m,
+	}
+}
+
+func (n *debugAPI) DerivationState(ctx context.Context) (*derive.DerivationState, error) {
+	recordDur := n.m.RecordRPCServerRequest("debug_derivationState")
+	defer recordDur()
+	return n.dr.DerivationState(ctx)
+}
+
 type nodeAPI struct {
 	config *rollup.Config
 	client l2EthClient
 	dr     driverClient
+	safeDB SafeDBReader
 	log    log.Logger
 	m      rpcMetrics
 }
 
-func NewNodeAPI(config *rollup.Config, l2Client l2EthClient, dr driverClient, log log.Logger, m rpcMetrics) *nodeAPI {
+func NewNodeAPI(config *rollup.Config, l2Client l2EthClient, dr driverClient, safeDB SafeDBReader, log log.Logger, m rpcMetrics) *nodeAPI {
 	return &nodeAPI{
 		config: config,
 		client: l2Client,
 		dr:     dr,
+		safeDB: safeDB,
 		log:    log,
 		m:      m,
 	}
@@ -136,6 +201,36 @@ func (n *nodeAPI) OutputAtBlock(ctx context.Context, number hexutil.Uint64) (*et
 	}, nil
 }
 
+func (n *nodeAPI) SafeHeadAtL1Block(ctx context.Context, number hexutil.Uint64) (*eth.SafeHeadResponse, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_safeHeadAtL1Block")
+	defer recordDur()
+	l1Block, safeHead, err := n.safeDB.SafeHeadAtL1(uint64(number))
+	if errors.Is(err, safedb.ErrNotFound) {
+		return nil, ethereum.NotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get safe head at L1 block %d: %w", number, err)
+	}
+	return &eth.SafeHeadResponse{
+		L1Block:  l1Block,
+		SafeHead: safeHead,
+	}, nil
+}
+
+func (n *nodeAPI) L1OriginOfSafeBlock(ctx context.Context, number hexutil.Uint64) (*eth.SafeHeadResponse, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_l1OriginOfSafeBlock")
+	defer recordDur()
+	l1Block, safeHead, err := n.safeDB.L1OriginOfSafeBlock(uint64(number))
+	if errors.Is(err, safedb.ErrNotFound) {
+		return nil, ethereum.NotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get L1 block of safe L2 block %d: %w", number, err)
+	}
+	return &eth.SafeHeadResponse{
+		L1Block:  l1Block,
+		SafeHead: safeHead,
+	}, nil
+}
+
 func (n *nodeAPI) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	recordDur := n.m.RecordRPCServerRequest("optimism_syncStatus")
 	defer recordDur()
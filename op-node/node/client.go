@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
@@ -125,7 +128,9 @@ func (cfg *L2SyncRPCConfig) Check() error {
 }
 
 type L1EndpointConfig struct {
-	L1NodeAddr string // Address of L1 User JSON-RPC endpoint to use (eth namespace required)
+	// L1NodeAddr is the address of the L1 User JSON-RPC endpoint to use (eth namespace required).
+	// Multiple comma-separated addresses route requests to the healthiest endpoint, and fail over on errors.
+	L1NodeAddr string
 
 	// L1TrustRPC: if we trust the L1 RPC we do not have to validate L1 response contents like headers
 	// against block hashes, or cached transaction sender addresses.
@@ -140,11 +145,46 @@ type L1EndpointConfig struct {
 var _ L1EndpointSetup = (*L1EndpointConfig)(nil)
 
 func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger) (cl client.RPC, trust bool, kind sources.RPCProviderKind, err error) {
-	l1Node, err := client.NewRPC(ctx, log, cfg.L1NodeAddr)
+	addrs := strings.Split(cfg.L1NodeAddr, ",")
+	if len(addrs) == 1 {
+		l1Node, err := client.NewRPC(ctx, log, cfg.L1NodeAddr)
+		if err != nil {
+			return nil, false, sources.RPCKindBasic, fmt.Errorf("failed to dial L1 address (%s): %w", cfg.L1NodeAddr, err)
+		}
+		return l1Node, cfg.L1TrustRPC, cfg.L1RPCKind, nil
+	}
+
+	endpoints := make([]client.Endpoint, 0, len(addrs))
+	closeAll := func() {
+		for _, e := range endpoints {
+			e.RPC.Close()
+		}
+	}
+	for i, addr := range addrs {
+		addr = strings.TrimSpace(addr)
+		l1Node, err := client.NewRPC(ctx, log, addr)
+		if err != nil {
+			closeAll()
+			return nil, false, sources.RPCKindBasic, fmt.Errorf("failed to dial L1 address %d (%s): %w", i, addr, err)
+		}
+		endpoints = append(endpoints, client.Endpoint{Name: l1EndpointName(i, addr), RPC: l1Node})
+	}
+	multi, err := client.NewMultiRPC(log, endpoints)
 	if err != nil {
-		return nil, false, sources.RPCKindBasic, fmt.Errorf("failed to dial L1 address (%s): %w", cfg.L1NodeAddr, err)
+		closeAll()
+		return nil, false, sources.RPCKindBasic, err
+	}
+	return multi, cfg.L1TrustRPC, cfg.L1RPCKind, nil
+}
+
+// l1EndpointName names an L1 endpoint by its index and host,
+// since the rest of the address may contain an API key.
+func l1EndpointName(i int, addr string) string {
+	u, err := url.Parse(addr)
+	if err != nil || u.Host == "" {
+		return strconv.Itoa(i)
 	}
-	return l1Node, cfg.L1TrustRPC, cfg.L1RPCKind, nil
+	return fmt.Sprintf("%d-%s", i, u.Host)
 }
 
 // PreparedL1Endpoint enables testing with an in-process pre-setup RPC connection to L1
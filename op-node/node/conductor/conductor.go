@@ -0,0 +1,259 @@
+package conductor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+type Config struct {
+	// ID identifies the node in the lease, it must be unique among the nodes that share the lease.
+	ID string
+	// LeaseDuration is how long the lease is held without renewal, before another node may take it over.
+	LeaseDuration time.Duration
+	// RenewInterval is how often the leader renews the lease, and how often a standby tries to acquire it.
+	RenewInterval time.Duration
+}
+
+func (c *Config) Check() error {
+	if c.ID == "" {
+		return errors.New("conductor ID must be set")
+	}
+	if c.RenewInterval <= 0 {
+		return errors.New("conductor renew interval must be positive")
+	}
+	if c.LeaseDuration <= c.RenewInterval {
+		return fmt.Errorf("conductor lease duration %s must be larger than the renew interval %s", c.LeaseDuration, c.RenewInterval)
+	}
+	return nil
+}
+
+// Sequencer is the sequencer control of the driver.
+type Sequencer interface {
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+	BlockRefWithStatus(ctx context.Context, num uint64) (eth.L2BlockRef, *eth.SyncStatus, error)
+	StartSequencer(ctx context.Context, blockHash common.Hash) error
+	StopSequencer(ctx context.Context) (common.Hash, error)
+}
+
+// Conductor runs the sequencer of the node if, and only if, the node holds the sequencer lease.
+//
+// The leader renews the lease with its latest unsafe head. When the leader stops renewing,
+// a standby takes over once the lease expired, but only if its own unsafe chain, synced over gossip,
+// includes the last unsafe head of the previous leader: a standby that is behind refuses to sequence,
+// to not reorg the unsafe blocks of the previous leader.
+// The leader stops sequencing as soon as it cannot renew the lease before it expires.
+type Conductor struct {
+	log     log.Logger
+	cfg     *Config
+	backend Backend
+	seq     Sequencer
+
+	// leader is true when this node holds the lease, until expiry.
+	leader bool
+	expiry time.Time
+	// sequencing is true when the sequencer was started by the conductor.
+	sequencing bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewConductor(log log.Logger, cfg *Config, backend Backend, seq Sequencer) *Conductor {
+	return &Conductor{
+		log:     log,
+		cfg:     cfg,
+		backend: backend,
+		seq:     seq,
+		done:    make(chan struct{}),
+	}
+}
+
+func (c *Conductor) Start() {
+	c.wg.Add(1)
+	go c.loop()
+}
+
+// Close stops the conductor and resigns from the lease if this node is the leader,
+// so a standby does not have to wait for the lease to expire.
+func (c *Conductor) Close() error {
+	close(c.done)
+	c.wg.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.RenewInterval)
+	defer cancel()
+	return c.resign(ctx)
+}
+
+func (c *Conductor) loop() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.cfg.RenewInterval)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), c.cfg.RenewInterval)
+		c.tick(ctx, time.Now())
+		cancel()
+		select {
+		case <-ticker.C:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *Conductor) tick(ctx context.Context, now time.Time) {
+	if c.leader {
+		c.renew(ctx, now)
+	} else {
+		c.campaign(ctx, now)
+	}
+}
+
+// renew extends the lease held by this node, and starts the sequencer if it is not running yet.
+func (c *Conductor) renew(ctx context.Context, now time.Time) {
+	status, err := c.seq.SyncStatus(ctx)
+	if err != nil {
+		c.log.Warn("Failed to get sync status to renew sequencer lease", "err", err)
+		c.checkExpiry(ctx, now)
+		return
+	}
+	lease, err := c.backend.Update(ctx, func(current Lease) (Lease, error) {
+		if current.Leader != c.cfg.ID {
+			return Lease{}, fmt.Errorf("%w: lease is held by %q", ErrLeaseLost, current.Leader)
+		}
+		return Lease{Leader: c.cfg.ID, Expiry: now.Add(c.cfg.LeaseDuration), UnsafeHead: status.UnsafeL2.ID()}, nil
+	})
+	if errors.Is(err, ErrLeaseLost) {
+		c.log.Error("Lost sequencer lease", "err", err)
+		c.stepDown(ctx)
+		return
+	} else if err != nil {
+		c.log.Warn("Failed to renew sequencer lease", "err", err)
+		c.checkExpiry(ctx, now)
+		return
+	}
+	c.expiry = lease.Expiry
+	if !c.sequencing {
+		c.startSequencing(ctx, status.UnsafeL2)
+	}
+}
+
+// checkExpiry steps down if the lease cannot be renewed anymore before it expires.
+func (c *Conductor) checkExpiry(ctx context.Context, now time.Time) {
+	if !now.Add(c.cfg.RenewInterval).Before(c.expiry) {
+		c.log.Error("Sequencer lease expires before it can be renewed", "expiry", c.expiry)
+		c.stepDown(ctx)
+	}
+}
+
+// campaign acquires the lease, if no other node holds it, and if this node is not behind the previous leader.
+func (c *Conductor) campaign(ctx context.Context, now time.Time) {
+	prev, err := c.backend.Lease(ctx)
+	if err != nil {
+		c.log.Warn("Failed to read sequencer lease", "err", err)
+		return
+	}
+	if prev.Active(now) && prev.Leader != c.cfg.ID {
+		c.log.Debug("Standing by, sequencer lease is held by another node", "leader", prev.Leader, "expiry", prev.Expiry)
+		return
+	}
+	status, err := c.checkUnsafeHead(ctx, prev.UnsafeHead)
+	if err != nil {
+		c.log.Warn("Refusing to sequence, not in sync with the previous leader", "leader", prev.Leader, "head", prev.UnsafeHead, "err", err)
+		return
+	}
+	lease, err := c.backend.Update(ctx, func(current Lease) (Lease, error) {
+		if current.Active(now) && current.Leader != c.cfg.ID {
+			return Lease{}, fmt.Errorf("%w: lease is held by %q", ErrLeaseHeld, current.Leader)
+		}
+		// The previous leader may have resigned, or renewed one last time, with a newer unsafe head.
+		if current.UnsafeHead != prev.UnsafeHead {
+			return Lease{}, fmt.Errorf("%w: unsafe head changed from %s to %s", ErrLeaseChanged, prev.UnsafeHead, current.UnsafeHead)
+		}
+		return Lease{Leader: c.cfg.ID, Expiry: now.Add(c.cfg.LeaseDuration), UnsafeHead: status.UnsafeL2.ID()}, nil
+	})
+	if err != nil {
+		c.log.Warn("Failed to acquire sequencer lease", "err", err)
+		return
+	}
+	c.log.Info("Acquired sequencer lease", "previous", prev.Leader, "head", status.UnsafeL2, "expiry", lease.Expiry)
+	c.leader = true
+	c.expiry = lease.Expiry
+	c.startSequencing(ctx, status.UnsafeL2)
+}
+
+// checkUnsafeHead checks that the unsafe chain of this node includes the given block, and returns the sync status.
+func (c *Conductor) checkUnsafeHead(ctx context.Context, head eth.BlockID) (*eth.SyncStatus, error) {
+	if head == (eth.BlockID{}) {
+		// no previous leader
+		return c.seq.SyncStatus(ctx)
+	}
+	ref, status, err := c.seq.BlockRefWithStatus(ctx, head.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", head.Number, err)
+	}
+	if status.UnsafeL2.Number < head.Number {
+		return nil, fmt.Errorf("unsafe head %s is behind %s", status.UnsafeL2, head)
+	}
+	if ref.Hash != head.Hash {
+		return nil, fmt.Errorf("block %s conflicts with %s", ref, head)
+	}
+	return status, nil
+}
+
+func (c *Conductor) startSequencing(ctx context.Context, head eth.L2BlockRef) {
+	// The unsafe head may change in the meantime, e.g. with a late gossip block, the start is then retried at the next renewal.
+	if err := c.seq.StartSequencer(ctx, head.Hash); err != nil {
+		c.log.Warn("Failed to start sequencer", "head", head, "err", err)
+		return
+	}
+	c.log.Info("Started sequencer", "head", head)
+	c.sequencing = true
+}
+
+// stepDown stops the sequencer, the lease is left to expire.
+// If the sequencer fails to stop, the node remains the leader, to retry at the next renewal.
+func (c *Conductor) stepDown(ctx context.Context) {
+	if c.sequencing {
+		hash, err := c.seq.StopSequencer(ctx)
+		if err != nil {
+			c.log.Error("Failed to stop sequencer", "err", err)
+			return
+		}
+		c.log.Warn("Stopped sequencer", "head", hash)
+		c.sequencing = false
+	}
+	c.leader = false
+}
+
+// resign stops the sequencer and releases the lease, with the final unsafe head of this node.
+func (c *Conductor) resign(ctx context.Context) error {
+	if !c.leader {
+		return nil
+	}
+	c.stepDown(ctx)
+	if c.leader {
+		return errors.New("failed to stop sequencer, not releasing the sequencer lease")
+	}
+	status, err := c.seq.SyncStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get final unsafe head: %w", err)
+	}
+	_, err = c.backend.Update(ctx, func(current Lease) (Lease, error) {
+		if current.Leader != c.cfg.ID {
+			return Lease{}, fmt.Errorf("%w: lease is held by %q", ErrLeaseLost, current.Leader)
+		}
+		return Lease{UnsafeHead: status.UnsafeL2.ID()}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release sequencer lease: %w", err)
+	}
+	c.log.Info("Released sequencer lease", "head", status.UnsafeL2)
+	return nil
+}
@@ -0,0 +1,234 @@
+package conductor
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+)
+
+// testSequencer is the sequencer control of a node, with an unsafe chain that can be extended by the test.
+type testSequencer struct {
+	chain      []eth.L2BlockRef
+	sequencing bool
+	stopErr    error
+}
+
+func (s *testSequencer) head() eth.L2BlockRef {
+	return s.chain[len(s.chain)-1]
+}
+
+func (s *testSequencer) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	return &eth.SyncStatus{UnsafeL2: s.head()}, nil
+}
+
+func (s *testSequencer) BlockRefWithStatus(ctx context.Context, num uint64) (eth.L2BlockRef, *eth.SyncStatus, error) {
+	status, _ := s.SyncStatus(ctx)
+	if num >= uint64(len(s.chain)) {
+		return eth.L2BlockRef{}, status, errors.New("not found")
+	}
+	return s.chain[num], status, nil
+}
+
+func (s *testSequencer) StartSequencer(ctx context.Context, blockHash common.Hash) error {
+	if s.sequencing {
+		return errors.New("sequencer already running")
+	}
+	if blockHash != s.head().Hash {
+		return errors.New("block hash does not match")
+	}
+	s.sequencing = true
+	return nil
+}
+
+func (s *testSequencer) StopSequencer(ctx context.Context) (common.Hash, error) {
+	if s.stopErr != nil {
+		return common.Hash{}, s.stopErr
+	}
+	if !s.sequencing {
+		return common.Hash{}, errors.New("sequencer not running")
+	}
+	s.sequencing = false
+	return s.head().Hash, nil
+}
+
+// testChain is a shared L2 chain, the nodes copy a prefix of it as their unsafe chain.
+type testChain []eth.L2BlockRef
+
+func newTestChain(seed int64, n int) testChain {
+	rng := rand.New(rand.NewSource(seed))
+	chain := testChain{testutils.RandomL2BlockRef(rng)}
+	chain[0].Number = 0
+	for i := 1; i < n; i++ {
+		chain = append(chain, testutils.NextRandomL2Ref(rng, 2, chain[i-1], testutils.RandomBlockID(rng)))
+	}
+	return chain
+}
+
+func testConfig(id string) *Config {
+	return &Config{ID: id, LeaseDuration: 10 * time.Second, RenewInterval: 2 * time.Second}
+}
+
+func TestConductorFailover(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+	backend := NewMemoryBackend()
+	chain := newTestChain(1, 10)
+	seqA := &testSequencer{chain: chain[:3]}
+	seqB := &testSequencer{chain: chain[:3]}
+	a := NewConductor(logger.New("node", "a"), testConfig("a"), backend, seqA)
+	b := NewConductor(logger.New("node", "b"), testConfig("b"), backend, seqB)
+	now := time.Unix(1000, 0)
+
+	// A acquires the lease and sequences, B stands by
+	a.tick(ctx, now)
+	b.tick(ctx, now)
+	require.True(t, seqA.sequencing)
+	require.False(t, seqB.sequencing)
+
+	// A sequences and renews the lease with its new unsafe head
+	seqA.chain = chain[:6]
+	now = now.Add(2 * time.Second)
+	a.tick(ctx, now)
+	b.tick(ctx, now)
+	lease, err := backend.Lease(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Lease{Leader: "a", Expiry: now.Add(10 * time.Second), UnsafeHead: chain[5].ID()}, lease)
+
+	// A stops renewing. B only received part of the blocks of A, it refuses to sequence after the lease expired.
+	seqB.chain = chain[:5]
+	now = now.Add(11 * time.Second)
+	b.tick(ctx, now)
+	require.False(t, seqB.sequencing)
+	lease, err = backend.Lease(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "a", lease.Leader)
+
+	// Once B received the unsafe head of A, B takes over
+	seqB.chain = chain[:6]
+	b.tick(ctx, now)
+	require.True(t, seqB.sequencing)
+	lease, err = backend.Lease(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "b", lease.Leader)
+
+	// A comes back, and steps down as it lost the lease
+	a.tick(ctx, now)
+	require.False(t, seqA.sequencing)
+	require.False(t, a.leader)
+	a.tick(ctx, now)
+	require.False(t, seqA.sequencing, "A does not take over the active lease of B")
+}
+
+func TestConductorRefuseConflictingHead(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+	backend := NewMemoryBackend()
+	chain := newTestChain(1, 5)
+	other := newTestChain(2, 5)
+	seqA := &testSequencer{chain: chain}
+	seqB := &testSequencer{chain: append(testChain{}, chain[:3]...)}
+	seqB.chain = append(seqB.chain, other[3:]...)
+	a := NewConductor(logger, testConfig("a"), backend, seqA)
+	b := NewConductor(logger, testConfig("b"), backend, seqB)
+	now := time.Unix(1000, 0)
+
+	a.tick(ctx, now)
+	require.True(t, seqA.sequencing)
+
+	// B has a different block at the height of the head of A
+	now = now.Add(time.Minute)
+	b.tick(ctx, now)
+	require.False(t, seqB.sequencing)
+}
+
+func TestConductorRenewFailure(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+	chain := newTestChain(1, 3)
+	seq := &testSequencer{chain: chain}
+	backend := &failingBackend{Backend: NewMemoryBackend()}
+	c := NewConductor(logger, testConfig("a"), backend, seq)
+	now := time.Unix(1000, 0)
+
+	c.tick(ctx, now)
+	require.True(t, seq.sequencing)
+
+	// a failed renewal is tolerated while the lease is valid for longer than the renew interval
+	backend.err = errors.New("backend unavailable")
+	now = now.Add(2 * time.Second)
+	c.tick(ctx, now)
+	require.True(t, seq.sequencing)
+
+	// the sequencer stops before the lease expires
+	now = now.Add(6 * time.Second)
+	c.tick(ctx, now)
+	require.False(t, seq.sequencing)
+	require.False(t, c.leader)
+}
+
+func TestConductorResign(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+	backend := NewMemoryBackend()
+	chain := newTestChain(1, 5)
+	seqA := &testSequencer{chain: chain[:3]}
+	seqB := &testSequencer{chain: chain[:3]}
+	a := NewConductor(logger, testConfig("a"), backend, seqA)
+	b := NewConductor(logger, testConfig("b"), backend, seqB)
+	now := time.Unix(1000, 0)
+
+	a.tick(ctx, now)
+	require.True(t, seqA.sequencing)
+
+	// the sequencer of A fails to stop, the lease is kept
+	seqA.stopErr = errors.New("driver busy")
+	require.Error(t, a.resign(ctx))
+	require.True(t, seqA.sequencing)
+	b.tick(ctx, now)
+	require.False(t, seqB.sequencing)
+
+	// A builds a last block, and releases the lease on shutdown
+	seqA.stopErr = nil
+	seqA.chain = chain[:4]
+	require.NoError(t, a.resign(ctx))
+	require.False(t, seqA.sequencing)
+	lease, err := backend.Lease(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Lease{UnsafeHead: chain[3].ID()}, lease)
+
+	// B does not wait for the lease to expire, but it does wait for the last block of A
+	b.tick(ctx, now)
+	require.False(t, seqB.sequencing)
+	seqB.chain = chain[:4]
+	b.tick(ctx, now)
+	require.True(t, seqB.sequencing)
+}
+
+func TestConfigCheck(t *testing.T) {
+	require.NoError(t, testConfig("a").Check())
+	require.Error(t, testConfig("").Check())
+	require.Error(t, (&Config{ID: "a", LeaseDuration: time.Second, RenewInterval: time.Second}).Check())
+	require.Error(t, (&Config{ID: "a", LeaseDuration: time.Second}).Check())
+}
+
+type failingBackend struct {
+	Backend
+	err error
+}
+
+func (f *failingBackend) Update(ctx context.Context, fn func(current Lease) (Lease, error)) (Lease, error) {
+	if f.err != nil {
+		return Lease{}, f.err
+	}
+	return f.Backend.Update(ctx, fn)
+}
@@ -0,0 +1,88 @@
+package conductor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/tsdb/fileutil"
+)
+
+// FileBackend stores the lease in a JSON file, shared by nodes on the same host or on a shared file system.
+// Updates are serialized with an exclusive lock on a separate lock file next to the lease file.
+type FileBackend struct {
+	path string
+}
+
+var _ Backend = (*FileBackend)(nil)
+
+func NewFileBackend(path string) *FileBackend {
+	return &FileBackend{path: path}
+}
+
+func (f *FileBackend) Lease(ctx context.Context) (Lease, error) {
+	return f.read()
+}
+
+func (f *FileBackend) Update(ctx context.Context, fn func(current Lease) (Lease, error)) (Lease, error) {
+	// The lock is not blocking: if another node is updating the lease, the update fails and is retried by the caller.
+	lock, _, err := fileutil.Flock(f.path + ".lock")
+	if err != nil {
+		return Lease{}, fmt.Errorf("failed to lock lease file: %w", err)
+	}
+	defer lock.Release()
+
+	current, err := f.read()
+	if err != nil {
+		return Lease{}, err
+	}
+	next, err := fn(current)
+	if err != nil {
+		return current, err
+	}
+	if err := f.write(next); err != nil {
+		return Lease{}, err
+	}
+	return next, nil
+}
+
+func (f *FileBackend) read() (Lease, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Lease{}, nil
+	} else if err != nil {
+		return Lease{}, fmt.Errorf("failed to read lease file: %w", err)
+	}
+	var lease Lease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return Lease{}, fmt.Errorf("failed to decode lease file: %w", err)
+	}
+	return lease, nil
+}
+
+// write replaces the lease file atomically, so a reader that does not hold the lock never sees a partial lease.
+func (f *FileBackend) write(lease Lease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return fmt.Errorf("failed to encode lease: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create lease file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write lease file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write lease file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to replace lease file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package conductor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+func TestFileBackend(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "lease.json")
+	a := NewFileBackend(path)
+	b := NewFileBackend(path)
+
+	lease, err := a.Lease(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Lease{}, lease, "no lease before the first update")
+
+	expected := Lease{Leader: "a", Expiry: time.Unix(1000, 0).UTC(), UnsafeHead: eth.BlockID{Number: 42}}
+	lease, err = a.Update(ctx, func(current Lease) (Lease, error) {
+		require.Equal(t, Lease{}, current)
+		return expected, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, lease)
+
+	lease, err = b.Lease(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, lease, "lease is shared through the file")
+
+	// a failed update leaves the lease unchanged
+	_, err = b.Update(ctx, func(current Lease) (Lease, error) {
+		return Lease{Leader: "b"}, ErrLeaseHeld
+	})
+	require.ErrorIs(t, err, ErrLeaseHeld)
+	lease, err = a.Lease(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, lease)
+
+	// the lease file is locked during an update
+	_, err = a.Update(ctx, func(current Lease) (Lease, error) {
+		_, err := b.Update(ctx, func(current Lease) (Lease, error) {
+			return Lease{Leader: "b"}, nil
+		})
+		return current, err
+	})
+	require.Error(t, err)
+	lease, err = a.Lease(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, lease)
+}
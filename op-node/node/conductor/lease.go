@@ -0,0 +1,45 @@
+package conductor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+var (
+	// ErrLeaseHeld is returned when the lease cannot be acquired because another node holds it.
+	ErrLeaseHeld = errors.New("lease is held by another node")
+	// ErrLeaseLost is returned when the lease cannot be renewed because this node does not hold it anymore.
+	ErrLeaseLost = errors.New("lease was lost")
+	// ErrLeaseChanged is returned when the lease changed between reading and updating it.
+	ErrLeaseChanged = errors.New("lease changed")
+)
+
+// Lease is the right to sequence, held by at most one node at a time.
+// Expiry is compared against the wall clock of every node,
+// the clock drift between the nodes must be small compared to the lease duration.
+type Lease struct {
+	// Leader is the ID of the node that holds the lease, empty if no node holds it.
+	Leader string `json:"leader"`
+	// Expiry is the time after which the lease may be taken over by another node, unless it is renewed.
+	Expiry time.Time `json:"expiry"`
+	// UnsafeHead is the unsafe L2 head of the leader at the last renewal, or when it resigned.
+	// A node only takes over the lease if its own unsafe chain includes this block.
+	UnsafeHead eth.BlockID `json:"unsafeHead"`
+}
+
+// Active returns true if a node holds the lease at the given time.
+func (l Lease) Active(now time.Time) bool {
+	return l.Leader != "" && now.Before(l.Expiry)
+}
+
+// Backend stores the lease shared by the nodes that take part in the leader election.
+type Backend interface {
+	// Lease returns the current lease. The zero Lease is returned if no node held the lease before.
+	Lease(ctx context.Context) (Lease, error)
+	// Update atomically replaces the current lease with the lease returned by fn.
+	// The lease is left unchanged if fn returns an error, and the error is returned.
+	Update(ctx context.Context, fn func(current Lease) (Lease, error)) (Lease, error)
+}
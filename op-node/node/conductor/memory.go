@@ -0,0 +1,35 @@
+package conductor
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBackend stores the lease in memory, to run the leader election between nodes within the same process.
+type MemoryBackend struct {
+	mu    sync.Mutex
+	lease Lease
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{}
+}
+
+func (m *MemoryBackend) Lease(ctx context.Context) (Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lease, nil
+}
+
+func (m *MemoryBackend) Update(ctx context.Context, fn func(current Lease) (Lease, error)) (Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next, err := fn(m.lease)
+	if err != nil {
+		return m.lease, err
+	}
+	m.lease = next
+	return next, nil
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/op-node/node/conductor"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
@@ -25,6 +26,10 @@ type Config struct {
 	// if the node is sequencing and if the p2p stack is enabled
 	P2PSigner p2p.SignerSetup
 
+	// P2PSignerGraceBlocks is the number of L1 blocks for which the previous p2p sequencer address
+	// is still accepted after the address changed in the SystemConfig. Disabled if 0.
+	P2PSignerGraceBlocks uint64
+
 	RPC RPCConfig
 
 	P2P p2p.SetupP2P
@@ -36,15 +41,29 @@ type Config struct {
 	// Used to poll the L1 for new finalized or safe blocks
 	L1EpochPollInterval time.Duration
 
+	// L1Quorum is the number of L1 endpoints that must agree on the L1 head, safe and finalized block,
+	// before the node uses them. Disabled if 0.
+	L1Quorum int
+
 	// Optional
 	Tracer    Tracer
 	Heartbeat HeartbeatConfig
+
+	// SafeDBPath is the path of the database of safe heads by L1 block. Disabled if empty.
+	SafeDBPath string
+
+	// DAStore is the location of the DA store, a directory or an http(s) URL. Required if DA commitments are enabled.
+	DAStore string
+
+	// Conductor starts and stops the sequencer for sequencer failover. Disabled if nil.
+	Conductor *ConductorConfig
 }
 
 type RPCConfig struct {
 	ListenAddr  string
 	ListenPort  int
 	EnableAdmin bool
+	EnableDebug bool
 }
 
 func (cfg *RPCConfig) HttpEndpoint() string {
@@ -69,6 +88,21 @@ func (m MetricsConfig) Check() error {
 	return nil
 }
 
+type ConductorConfig struct {
+	conductor.Config
+	// LeasePath is the path of the lease file shared with the other sequencer nodes, used if Backend is nil.
+	LeasePath string
+	// Backend overrides the lease backend, e.g. to share an in-memory lease between nodes in tests.
+	Backend conductor.Backend
+}
+
+func (c *ConductorConfig) Check() error {
+	if c.LeasePath == "" && c.Backend == nil {
+		return errors.New("lease path must be set")
+	}
+	return c.Config.Check()
+}
+
 type HeartbeatConfig struct {
 	Enabled bool
 	Moniker string
@@ -94,5 +128,19 @@ func (cfg *Config) Check() error {
 			return fmt.Errorf("p2p config error: %w", err)
 		}
 	}
+	if cfg.Rollup.DACommitmentsTime != nil && cfg.DAStore == "" {
+		return errors.New("DA commitments are scheduled in the rollup config, but no DA store is configured")
+	}
+	if cfg.Conductor != nil {
+		if err := cfg.Conductor.Check(); err != nil {
+			return fmt.Errorf("conductor config error: %w", err)
+		}
+		if !cfg.Driver.SequencerEnabled {
+			return errors.New("conductor requires the sequencer to be enabled")
+		}
+		if !cfg.Driver.SequencerStopped {
+			return errors.New("conductor requires the sequencer to start stopped, the conductor starts it")
+		}
+	}
 	return nil
 }
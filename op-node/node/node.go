@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -14,11 +16,16 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-node/dastore"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/conductor"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-service/backoff"
 )
 
 type OpNode struct {
@@ -31,20 +38,38 @@ type OpNode struct {
 	l1FinalizedSub ethereum.Subscription // Subscription to get L1 safe blocks, a.k.a. justified data (polling)
 
 	l1Source  *sources.L1Client     // L1 Client to fetch data from
+	l1Quorum  *sources.L1Quorum     // Checks that L1 endpoints agree on L1 blocks, nil if disabled
 	l2Driver  *driver.Driver        // L2 Engine to Sync
 	l2Source  *sources.EngineClient // L2 Execution Engine RPC bindings
+	rpcSync   *sources.SyncClient   // Alt-sync RPC client, optional (may be nil)
+	safeDB    closableSafeDB        // Safe heads by L1 block, optional (safedb.Disabled if not enabled)
+	conductor *conductor.Conductor  // Sequencer failover, optional (may be nil)
 	server    *rpcServer            // RPC server hosting the rollup-node API
 	p2pNode   *p2p.NodeP2P          // P2P node functionality
 	p2pSigner p2p.Signer            // p2p gogssip application messages will be signed with this signer
 	tracer    Tracer                // tracer to get events for testing/debugging
 	runCfg    *RuntimeConfig        // runtime configurables
 
+	// runCfgHeads holds the latest L1 head to reload the runtime config at, older heads are dropped
+	runCfgHeads chan eth.L1BlockRef
+
+	// p2pSignerLock guards p2pSigner, which can be replaced through the admin RPC
+	p2pSignerLock sync.RWMutex
+	// p2pSignerClosed is set once the node closed the p2p signer, after which no new signer can be set
+	p2pSignerClosed bool
+
 	// some resources cannot be stopped directly, like the p2p gossipsub router (not our design),
 	// and depend on this ctx to be closed.
 	resourcesCtx   context.Context
 	resourcesClose context.CancelFunc
 }
 
+type closableSafeDB interface {
+	derive.SafeHeadListener
+	SafeDBReader
+	io.Closer
+}
+
 // The OpNode handles incoming gossip
 var _ p2p.GossipIn = (*OpNode)(nil)
 
@@ -117,8 +142,26 @@ func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
 		return fmt.Errorf("failed to get L1 RPC client: %w", err)
 	}
 
+	if multi, ok := l1Node.(*client.MultiRPC); ok {
+		multi.Start(n.resourcesCtx, n.metrics.L1Endpoints)
+	}
+	if cfg.L1Quorum > 0 {
+		var endpoints []client.RPC
+		if multi, ok := l1Node.(*client.MultiRPC); ok {
+			for _, e := range multi.Endpoints() {
+				endpoints = append(endpoints, e.RPC)
+			}
+		} else {
+			endpoints = []client.RPC{l1Node}
+		}
+		n.l1Quorum, err = sources.NewL1Quorum(endpoints, cfg.L1Quorum)
+		if err != nil {
+			return err
+		}
+	}
+
 	n.l1Source, err = sources.NewL1Client(
-		client.NewInstrumentedRPC(l1Node, n.metrics), n.log, n.metrics.L1SourceCache,
+		client.NewInstrumentedRPC(l1Node, n.metrics), n.log, n.metrics.L1Source,
 		sources.L1ClientDefaultConfig(&cfg.Rollup, trustRPC, rpcProvKind))
 	if err != nil {
 		return fmt.Errorf("failed to create L1 source: %w", err)
@@ -129,11 +172,12 @@ func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
 	}
 
 	// Keep subscribed to the L1 heads, which keeps the L1 maintainer pointing to the best headers to sync
+	onL1Head := n.withL1Quorum(eth.Unsafe, n.OnNewL1Head)
 	n.l1HeadsSub = event.ResubscribeErr(time.Second*10, func(ctx context.Context, err error) (event.Subscription, error) {
 		if err != nil {
 			n.log.Warn("resubscribing after failed L1 subscription", "err", err)
 		}
-		return eth.WatchHeadChanges(n.resourcesCtx, n.l1Source, n.OnNewL1Head)
+		return eth.WatchHeadChanges(n.resourcesCtx, n.l1Source, onL1Head)
 	})
 	go func() {
 		err, ok := <-n.l1HeadsSub.Err()
@@ -145,16 +189,18 @@ func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
 
 	// Poll for the safe L1 block and finalized block,
 	// which only change once per epoch at most and may be delayed.
-	n.l1SafeSub = eth.PollBlockChanges(n.resourcesCtx, n.log, n.l1Source, n.OnNewL1Safe, eth.Safe,
+	n.l1SafeSub = eth.PollBlockChanges(n.resourcesCtx, n.log, n.l1Source, n.withL1Quorum(eth.Safe, n.OnNewL1Safe), eth.Safe,
 		cfg.L1EpochPollInterval, time.Second*10)
-	n.l1FinalizedSub = eth.PollBlockChanges(n.resourcesCtx, n.log, n.l1Source, n.OnNewL1Finalized, eth.Finalized,
+	n.l1FinalizedSub = eth.PollBlockChanges(n.resourcesCtx, n.log, n.l1Source, n.withL1Quorum(eth.Finalized, n.OnNewL1Finalized), eth.Finalized,
 		cfg.L1EpochPollInterval, time.Second*10)
 	return nil
 }
 
 func (n *OpNode) initRuntimeConfig(ctx context.Context, cfg *Config) error {
 	// attempt to load runtime config, repeat N times
-	n.runCfg = NewRuntimeConfig(n.log, n.l1Source, &cfg.Rollup)
+	n.runCfg = NewRuntimeConfig(n.log, n.l1Source, &cfg.Rollup, cfg.P2PSignerGraceBlocks)
+	n.runCfgHeads = make(chan eth.L1BlockRef, 1)
+	go n.reloadRuntimeConfig()
 
 	for i := 0; i < 5; i++ {
 		fetchCtx, fetchCancel := context.WithTimeout(ctx, time.Second*10)
@@ -179,6 +225,24 @@ func (n *OpNode) initRuntimeConfig(ctx context.Context, cfg *Config) error {
 	return errors.New("failed to load runtime configuration repeatedly")
 }
 
+// reloadRuntimeConfig reloads the runtime config at the latest L1 head, until the node is closed.
+// Loading is not done in the L1 head subscription, a slow L1 endpoint must not delay the L1 head
+// signals to the driver, and must not queue up reloads at heads that are outdated by the time they run.
+func (n *OpNode) reloadRuntimeConfig() {
+	for {
+		select {
+		case <-n.resourcesCtx.Done():
+			return
+		case head := <-n.runCfgHeads:
+			loadCtx, loadCancel := context.WithTimeout(n.resourcesCtx, time.Second*10)
+			if err := n.runCfg.Load(loadCtx, head); err != nil {
+				n.log.Warn("failed to reload runtime config", "l1_head", head, "err", err)
+			}
+			loadCancel()
+		}
+	}
+}
+
 func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger) error {
 	rpcClient, err := cfg.L2.Setup(ctx, n.log)
 	if err != nil {
@@ -197,7 +261,6 @@ func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger
 		return err
 	}
 
-	var syncClient *sources.SyncClient
 	// If the L2 sync config is present, use it to create a sync client
 	if cfg.L2Sync != nil {
 		if err := cfg.L2Sync.Check(); err != nil {
@@ -211,20 +274,56 @@ func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger
 			// The sync client's RPC is always trusted
 			config := sources.SyncClientDefaultConfig(&cfg.Rollup, true)
 
-			syncClient, err = sources.NewSyncClient(n.OnUnsafeL2Payload, rpcSyncClient, n.log, n.metrics.L2SourceCache, config)
+			n.rpcSync, err = sources.NewSyncClient(n.OnUnsafeL2Payload, rpcSyncClient, n.log, n.metrics.L2SourceCache, config)
 			if err != nil {
 				return fmt.Errorf("failed to create sync client: %w", err)
 			}
 		}
 	}
 
-	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, syncClient, n, n.log, snapshotLog, n.metrics)
+	// The p2p node is set up after the driver, the node routes the sync requests to it when it's ready.
+	var altSync driver.AltSync
+	if n.rpcSync != nil || (cfg.P2P != nil && cfg.P2P.ReqRespSyncEnabled()) {
+		altSync = n
+	}
+
+	if cfg.SafeDBPath != "" {
+		n.log.Info("Safe head database enabled", "path", cfg.SafeDBPath)
+		safeDB, err := safedb.NewSafeDB(n.log, cfg.SafeDBPath)
+		if err != nil {
+			return fmt.Errorf("failed to create safe head database at %q: %w", cfg.SafeDBPath, err)
+		}
+		n.safeDB = safeDB
+	} else {
+		n.safeDB = safedb.Disabled
+	}
+
+	var daStore derive.DAStore
+	if cfg.DAStore != "" {
+		n.log.Info("DA store enabled", "location", cfg.DAStore, "commitments_time", cfg.Rollup.DACommitmentsTime)
+		store, err := dastore.Open(cfg.DAStore)
+		if err != nil {
+			return fmt.Errorf("failed to open DA store at %q: %w", cfg.DAStore, err)
+		}
+		daStore = store
+	}
+
+	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, daStore, altSync, n, n.safeDB, n.log, snapshotLog, n.metrics)
+
+	if cfg.Conductor != nil {
+		backend := cfg.Conductor.Backend
+		if backend == nil {
+			backend = conductor.NewFileBackend(cfg.Conductor.LeasePath)
+		}
+		n.log.Info("Sequencer conductor enabled", "id", cfg.Conductor.ID)
+		n.conductor = conductor.NewConductor(n.log.New("module", "conductor"), &cfg.Conductor.Config, backend, n.l2Driver)
+	}
 
 	return nil
 }
 
 func (n *OpNode) initRPCServer(ctx context.Context, cfg *Config) error {
-	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.log, n.appVersion, n.metrics)
+	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.safeDB, n.log, n.appVersion, n.metrics)
 	if err != nil {
 		return err
 	}
@@ -232,9 +331,13 @@ func (n *OpNode) initRPCServer(ctx context.Context, cfg *Config) error {
 		server.EnableP2P(p2p.NewP2PAPIBackend(n.p2pNode, n.log, n.metrics))
 	}
 	if cfg.RPC.EnableAdmin {
-		server.EnableAdminAPI(NewAdminAPI(n.l2Driver, n.metrics))
+		server.EnableAdminAPI(NewAdminAPI(n.l2Driver, n, n.metrics))
 		n.log.Info("Admin RPC enabled")
 	}
+	if cfg.RPC.EnableDebug {
+		server.EnableDebugAPI(NewDebugAPI(n.l2Driver, n.metrics))
+		n.log.Info("Debug RPC enabled")
+	}
 	n.log.Info("Starting JSON-RPC server")
 	if err := server.Start(); err != nil {
 		return fmt.Errorf("unable to start RPC server: %w", err)
@@ -282,6 +385,32 @@ func (n *OpNode) initP2PSigner(ctx context.Context, cfg *Config) error {
 	return err
 }
 
+// SetP2PSigner creates a new p2p signer, and replaces the current p2p signer with it.
+// The replaced signer is closed. To rotate the sequencer key without rejected blocks, the new signer
+// must be set within the grace period after the p2p sequencer address was changed in the SystemConfig.
+func (n *OpNode) SetP2PSigner(cfg *p2p.SignerConfig) error {
+	signer, err := cfg.NewSigner(n.log)
+	if err != nil {
+		return fmt.Errorf("failed to create p2p signer: %w", err)
+	}
+	n.p2pSignerLock.Lock()
+	if n.p2pSignerClosed {
+		n.p2pSignerLock.Unlock()
+		_ = signer.Close()
+		return errors.New("node is closed")
+	}
+	prev := n.p2pSigner
+	n.p2pSigner = signer
+	n.p2pSignerLock.Unlock()
+	n.log.Info("Replaced p2p signer")
+	if prev != nil {
+		if err := prev.Close(); err != nil {
+			n.log.Warn("failed to close replaced p2p signer", "err", err)
+		}
+	}
+	return nil
+}
+
 func (n *OpNode) Start(ctx context.Context) error {
 	n.log.Info("Starting execution engine driver")
 
@@ -292,19 +421,93 @@ func (n *OpNode) Start(ctx context.Context) error {
 	}
 
 	// If the backup unsafe sync client is enabled, start its event loop
-	if n.l2Driver.L2SyncCl != nil {
-		if err := n.l2Driver.L2SyncCl.Start(); err != nil {
+	if n.rpcSync != nil {
+		if err := n.rpcSync.Start(); err != nil {
 			n.log.Error("Could not start the backup sync client", "err", err)
 			return err
 		}
 	}
 
+	// The conductor starts the sequencer once the node holds the sequencer lease
+	if n.conductor != nil {
+		n.conductor.Start()
+	}
+
 	return nil
 }
 
+// withL1Quorum returns a signal handler that only passes on L1 blocks that a quorum of L1 endpoints agrees on,
+// if an L1 quorum is configured. Endpoints may lag behind shortly, so the quorum is checked a few times.
+// The quorum is checked in the background: a slow or disagreeing endpoint must not delay the subscription
+// that emits the signals. Only the latest signal is checked, older signals that were not checked yet are dropped.
+func (n *OpNode) withL1Quorum(label eth.BlockLabel, fn eth.HeadSignalFn) eth.HeadSignalFn {
+	if n.l1Quorum == nil {
+		return fn
+	}
+	latest := make(chan eth.L1BlockRef, 1)
+	go n.checkL1Quorum(label, latest, fn)
+	return func(ctx context.Context, sig eth.L1BlockRef) {
+		// replace the signal of a pending check that did not start yet
+		select {
+		case <-latest:
+		default:
+		}
+		select {
+		case latest <- sig:
+		default:
+		}
+	}
+}
+
+// checkL1Quorum checks the quorum of the latest signals of the given label, and passes them on to fn,
+// until the node is closed. The check of a signal is abandoned once a newer signal is received.
+func (n *OpNode) checkL1Quorum(label eth.BlockLabel, latest <-chan eth.L1BlockRef, fn eth.HeadSignalFn) {
+	ctx := n.resourcesCtx
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-latest:
+			superseded := false
+			err := backoff.DoCtx(ctx, 5, backoff.Fixed(time.Second), func() error {
+				if len(latest) > 0 {
+					superseded = true
+					return nil
+				}
+				checkCtx, cancel := context.WithTimeout(ctx, time.Second*10)
+				defer cancel()
+				return n.l1Quorum.Check(checkCtx, sig)
+			})
+			if superseded {
+				n.log.Debug("dropping L1 block quorum check, newer block received", "label", label, "block", sig)
+				continue
+			}
+			if err != nil {
+				n.log.Warn("ignoring L1 block without quorum of L1 endpoints", "label", label, "block", sig, "err", err)
+				n.metrics.L1Endpoints.RecordQuorumFailure(string(label))
+				continue
+			}
+			fn(ctx, sig)
+		}
+	}
+}
+
 func (n *OpNode) OnNewL1Head(ctx context.Context, sig eth.L1BlockRef) {
 	n.tracer.OnNewL1Head(ctx, sig)
 
+	if n.runCfgHeads != nil {
+		// reload the runtime config, to follow changes of the p2p sequencer address,
+		// replacing the head of a pending reload that did not start yet
+		select {
+		case <-n.runCfgHeads:
+		default:
+		}
+		select {
+		case n.runCfgHeads <- sig:
+		default:
+		}
+	}
+
 	if n.l2Driver == nil {
 		return
 	}
@@ -345,6 +548,8 @@ func (n *OpNode) PublishL2Payload(ctx context.Context, payload *eth.ExecutionPay
 
 	// publish to p2p, if we are running p2p at all
 	if n.p2pNode != nil {
+		n.p2pSignerLock.RLock()
+		defer n.p2pSignerLock.RUnlock()
 		if n.p2pSigner == nil {
 			return fmt.Errorf("node has no p2p signer, payload %s cannot be published", payload.ID())
 		}
@@ -375,6 +580,19 @@ func (n *OpNode) OnUnsafeL2Payload(ctx context.Context, from peer.ID, payload *e
 	return nil
 }
 
+// RequestL2Range requests the unsafe L2 payloads of the blocks in the range [start, end]
+// from the backup sync RPC if it's configured, or from p2p peers otherwise.
+func (n *OpNode) RequestL2Range(ctx context.Context, start, end uint64) error {
+	if n.rpcSync != nil {
+		return n.rpcSync.RequestL2Range(ctx, start, end)
+	}
+	if n.p2pNode != nil && n.p2pNode.AltSyncEnabled() {
+		return n.p2pNode.RequestL2Range(ctx, start, end)
+	}
+	n.log.Debug("ignoring request to sync L2 range, no sync method available", "start", start, "end", end)
+	return nil
+}
+
 func (n *OpNode) P2P() p2p.Node {
 	return n.p2pNode
 }
@@ -386,13 +604,25 @@ func (n *OpNode) Close() error {
 	if n.server != nil {
 		n.server.Stop()
 	}
+	// stop sequencing and release the sequencer lease first, while the standby nodes can still sync the last blocks
+	if n.conductor != nil {
+		if err := n.conductor.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close conductor: %w", err))
+		}
+	}
 	if n.p2pNode != nil {
 		if err := n.p2pNode.Close(); err != nil {
 			result = multierror.Append(result, fmt.Errorf("failed to close p2p node: %w", err))
 		}
 	}
-	if n.p2pSigner != nil {
-		if err := n.p2pSigner.Close(); err != nil {
+	// no signer can be set after this, so the last one is closed exactly once
+	n.p2pSignerLock.Lock()
+	signer := n.p2pSigner
+	n.p2pSigner = nil
+	n.p2pSignerClosed = true
+	n.p2pSignerLock.Unlock()
+	if signer != nil {
+		if err := signer.Close(); err != nil {
 			result = multierror.Append(result, fmt.Errorf("failed to close p2p signer: %w", err))
 		}
 	}
@@ -411,12 +641,19 @@ func (n *OpNode) Close() error {
 		if err := n.l2Driver.Close(); err != nil {
 			result = multierror.Append(result, fmt.Errorf("failed to close L2 engine driver cleanly: %w", err))
 		}
+	}
+
+	// If the L2 sync client is present & running, close it.
+	if n.rpcSync != nil {
+		if err := n.rpcSync.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close L2 engine backup sync client cleanly: %w", err))
+		}
+	}
 
-		// If the L2 sync client is present & running, close it.
-		if n.l2Driver.L2SyncCl != nil {
-			if err := n.l2Driver.L2SyncCl.Close(); err != nil {
-				result = multierror.Append(result, fmt.Errorf("failed to close L2 engine backup sync client cleanly: %w", err))
-			}
+	// close the safe head database, after the driver stopped updating it
+	if n.safeDB != nil {
+		if err := n.safeDB.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close safe head database: %w", err))
 		}
 	}
 
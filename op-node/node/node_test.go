@@ -0,0 +1,135 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/p2p"
+	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+// slowL1Endpoint serves the given headers, but blocks every request until it is released.
+type slowL1Endpoint struct {
+	headers map[hexutil.Uint64]*types.Header
+	started chan struct{}
+	release chan struct{}
+}
+
+func (e *slowL1Endpoint) Close() {}
+
+func (e *slowL1Endpoint) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	select {
+	case e.started <- struct{}{}:
+	default:
+	}
+	select {
+	case <-e.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	num, err := hexutil.DecodeUint64(args[0].(string))
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(e.headers[hexutil.Uint64(num)])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (e *slowL1Endpoint) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	panic("not implemented")
+}
+
+func (e *slowL1Endpoint) EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error) {
+	panic("not implemented")
+}
+
+// TestWithL1Quorum tests that the quorum of L1 blocks is checked without blocking the signals,
+// and that only the latest signal is passed on while a check is in progress.
+func TestWithL1Quorum(t *testing.T) {
+	endpoint := &slowL1Endpoint{
+		headers: make(map[hexutil.Uint64]*types.Header),
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	var refs []eth.L1BlockRef
+	for i := uint64(1); i <= 3; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), Difficulty: big.NewInt(0), Time: i}
+		endpoint.headers[hexutil.Uint64(i)] = header
+		refs = append(refs, eth.L1BlockRef{Hash: header.Hash(), Number: i, Time: i})
+	}
+	quorum, err := sources.NewL1Quorum([]client.RPC{endpoint}, 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := &OpNode{
+		log:          testlog.Logger(t, log.LvlError),
+		metrics:      metrics.NewMetrics(""),
+		l1Quorum:     quorum,
+		resourcesCtx: ctx,
+	}
+	passed := make(chan eth.L1BlockRef, 3)
+	onSignal := n.withL1Quorum(eth.Unsafe, func(ctx context.Context, sig eth.L1BlockRef) {
+		passed <- sig
+	})
+
+	// the signals don't wait for the slow endpoint
+	onSignal(ctx, refs[0])
+	<-endpoint.started
+	onSignal(ctx, refs[1])
+	onSignal(ctx, refs[2])
+	select {
+	case sig := <-passed:
+		t.Fatalf("unexpected signal passed on before the quorum check: %s", sig)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// the check of the first block was in progress, and the second block was replaced by the third
+	close(endpoint.release)
+	require.Equal(t, refs[0], <-passed)
+	require.Equal(t, refs[2], <-passed)
+	select {
+	case sig := <-passed:
+		t.Fatalf("unexpected signal passed on: %s", sig)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCloseP2PSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signerCfg := &p2p.SignerConfig{PrivateKey: hexutil.Encode(crypto.FromECDSA(key))}
+	n := &OpNode{log: testlog.Logger(t, log.LvlError)}
+	require.NoError(t, n.SetP2PSigner(signerCfg))
+
+	// the signer can be replaced while the node is closing
+	done := make(chan error)
+	go func() {
+		done <- n.SetP2PSigner(signerCfg)
+	}()
+	require.NoError(t, n.Close())
+	<-done
+	require.Nil(t, n.p2pSigner)
+
+	// no signer can be set once the node is closed
+	require.Error(t, n.SetP2PSigner(signerCfg))
+	require.Nil(t, n.p2pSigner)
+}
@@ -21,12 +21,15 @@ var (
 
 type RuntimeCfgL1Source interface {
 	ReadStorageAt(ctx context.Context, address common.Address, storageSlot common.Hash, blockHash common.Hash) (common.Hash, error)
+	L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error)
 }
 
 // RuntimeConfig maintains runtime-configurable options.
 // These options are loaded based on initial loading + updates for every subsequent L1 block.
 // Only the *latest* values are maintained however, the runtime config has no concept of chain history,
 // does not require any archive data, and may be out of sync with the rollup derivation process.
+// The previous p2p sequencer address is the exception: on the first load it is recovered from the state
+// of the last signerGraceBlocks L1 blocks, so a restart during a key rotation does not end the grace period.
 type RuntimeConfig struct {
 	mu sync.RWMutex
 
@@ -39,21 +42,33 @@ type RuntimeConfig struct {
 	// if this is invalidated with a reorg the data will have to be reloaded.
 	l1Ref eth.L1BlockRef
 
+	// signerGraceBlocks is the number of L1 blocks the previous p2p sequencer address
+	// is still accepted for after it changed. Zero disables the grace period.
+	signerGraceBlocks uint64
+
 	runtimeConfigData
 }
 
 // runtimeConfigData is a flat bundle of configurable data, easy and light to copy around.
 type runtimeConfigData struct {
 	p2pBlockSignerAddr common.Address
+
+	// p2pPrevBlockSignerAddr is the p2p sequencer address before the latest change,
+	// accepted until the L1 block number p2pPrevBlockSignerUntil (exclusive).
+	p2pPrevBlockSignerAddr  common.Address
+	p2pPrevBlockSignerUntil uint64
 }
 
 var _ p2p.GossipRuntimeConfig = (*RuntimeConfig)(nil)
 
-func NewRuntimeConfig(log log.Logger, l1Client RuntimeCfgL1Source, rollupCfg *rollup.Config) *RuntimeConfig {
+// NewRuntimeConfig creates a RuntimeConfig. After a change of the p2p sequencer address,
+// the previous address is accepted for signerGraceBlocks more L1 blocks, so blocks are not rejected during a key rotation.
+func NewRuntimeConfig(log log.Logger, l1Client RuntimeCfgL1Source, rollupCfg *rollup.Config, signerGraceBlocks uint64) *RuntimeConfig {
 	return &RuntimeConfig{
-		log:       log,
-		l1Client:  l1Client,
-		rollupCfg: rollupCfg,
+		log:               log,
+		l1Client:          l1Client,
+		rollupCfg:         rollupCfg,
+		signerGraceBlocks: signerGraceBlocks,
 	}
 }
 
@@ -63,6 +78,21 @@ func (r *RuntimeConfig) P2PSequencerAddress() common.Address {
 	return r.p2pBlockSignerAddr
 }
 
+// P2PSequencerAddresses returns the current p2p sequencer address,
+// and the previous address if the current one changed within the grace period.
+func (r *RuntimeConfig) P2PSequencerAddresses() []common.Address {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []common.Address
+	if r.p2pBlockSignerAddr != (common.Address{}) {
+		out = append(out, r.p2pBlockSignerAddr)
+	}
+	if r.p2pPrevBlockSignerAddr != (common.Address{}) && r.l1Ref.Number < r.p2pPrevBlockSignerUntil {
+		out = append(out, r.p2pPrevBlockSignerAddr)
+	}
+	return out
+}
+
 // Load resets the runtime configuration by fetching the latest config data from L1 at the given L1 block.
 // Load is safe to call concurrently, but will lock the runtime configuration modifications only,
 // and will thus not block other Load calls with possibly alternative L1 block views.
@@ -71,10 +101,81 @@ func (r *RuntimeConfig) Load(ctx context.Context, l1Ref eth.L1BlockRef) error {
 	if err != nil {
 		return fmt.Errorf("failed to fetch unsafe block signing address from system config: %w", err)
 	}
+	addr := common.BytesToAddress(val[:])
+
+	r.mu.RLock()
+	firstLoad := r.l1Ref == (eth.L1BlockRef{})
+	r.mu.RUnlock()
+	var prevAddr common.Address
+	var changedAt uint64
+	if firstLoad && r.signerGraceBlocks > 0 {
+		prevAddr, changedAt, err = r.lastSignerChange(ctx, l1Ref, addr)
+		if err != nil {
+			// not fatal, the state of older L1 blocks may not be available
+			r.log.Warn("failed to recover previous p2p sequencer address", "err", err)
+		}
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if prevAddr != (common.Address{}) && r.l1Ref == (eth.L1BlockRef{}) {
+		r.p2pPrevBlockSignerAddr = prevAddr
+		r.p2pPrevBlockSignerUntil = changedAt + r.signerGraceBlocks
+	}
+	if addr == r.p2pBlockSignerAddr {
+		r.l1Ref = l1Ref
+		return nil
+	}
+	if r.p2pBlockSignerAddr != (common.Address{}) && r.signerGraceBlocks > 0 {
+		r.p2pPrevBlockSignerAddr = r.p2pBlockSignerAddr
+		r.p2pPrevBlockSignerUntil = l1Ref.Number + r.signerGraceBlocks
+	}
 	r.l1Ref = l1Ref
-	r.p2pBlockSignerAddr = common.BytesToAddress(val[:])
-	r.log.Info("loaded new runtime config values!", "p2p_seq_address", r.p2pBlockSignerAddr)
+	r.p2pBlockSignerAddr = addr
+	r.log.Info("loaded new runtime config values!", "p2p_seq_address", r.p2pBlockSignerAddr,
+		"prev_p2p_seq_address", r.p2pPrevBlockSignerAddr, "prev_accepted_until", r.p2pPrevBlockSignerUntil)
 	return nil
 }
+
+// lastSignerChange returns the previous p2p sequencer address and the L1 block number it changed at,
+// if the address changed to addr within the grace period before l1Ref. It binary-searches the
+// state of the grace period, assuming the address changed at most once within it.
+func (r *RuntimeConfig) lastSignerChange(ctx context.Context, l1Ref eth.L1BlockRef, addr common.Address) (common.Address, uint64, error) {
+	if l1Ref.Number == 0 {
+		return common.Address{}, 0, nil
+	}
+	lo := uint64(0)
+	if l1Ref.Number > r.signerGraceBlocks {
+		lo = l1Ref.Number - r.signerGraceBlocks
+	}
+	signerAt := func(num uint64) (common.Address, error) {
+		ref, err := r.l1Client.L1BlockRefByNumber(ctx, num)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("failed to fetch L1 block %d: %w", num, err)
+		}
+		val, err := r.l1Client.ReadStorageAt(ctx, r.rollupCfg.L1SystemConfigAddress, UnsafeBlockSignerAddressSystemConfigStorageSlot, ref.Hash)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("failed to fetch unsafe block signing address at L1 block %d: %w", num, err)
+		}
+		return common.BytesToAddress(val[:]), nil
+	}
+	prevAddr, err := signerAt(lo)
+	if err != nil || prevAddr == addr {
+		return common.Address{}, 0, err
+	}
+	// the address at lo is the previous one, and the address at l1Ref the current one
+	hi := l1Ref.Number
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		midAddr, err := signerAt(mid)
+		if err != nil {
+			return common.Address{}, 0, err
+		}
+		if midAddr == addr {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return prevAddr, hi, nil
+}
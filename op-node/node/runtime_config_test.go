@@ -0,0 +1,123 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+// testRuntimeCfgL1Source serves the signer address of the latest change at or before an L1 block.
+// L1 block hashes are derived from the block number.
+type testRuntimeCfgL1Source struct {
+	changes []signerChange
+	// pruned is the number of the first L1 block with available state
+	pruned uint64
+}
+
+type signerChange struct {
+	num    uint64
+	signer common.Address
+}
+
+func testL1Ref(num uint64) eth.L1BlockRef {
+	return eth.L1BlockRef{Number: num, Hash: common.BigToHash(new(big.Int).SetUint64(num + 1))}
+}
+
+func (s *testRuntimeCfgL1Source) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	return testL1Ref(num), nil
+}
+
+func (s *testRuntimeCfgL1Source) ReadStorageAt(ctx context.Context, address common.Address, storageSlot common.Hash, blockHash common.Hash) (common.Hash, error) {
+	num := blockHash.Big().Uint64() - 1
+	if num < s.pruned {
+		return common.Hash{}, errors.New("missing trie node")
+	}
+	var signer common.Address
+	for _, c := range s.changes {
+		if c.num <= num {
+			signer = c.signer
+		}
+	}
+	return common.BytesToHash(signer[:]), nil
+}
+
+func (s *testRuntimeCfgL1Source) setSigner(num uint64, signer common.Address) {
+	s.changes = append(s.changes, signerChange{num: num, signer: signer})
+}
+
+func TestRuntimeConfigSignerGracePeriod(t *testing.T) {
+	oldSigner, newSigner := common.Address{0xaa}, common.Address{0xbb}
+	l1 := &testRuntimeCfgL1Source{}
+	l1.setSigner(0, oldSigner)
+	runCfg := NewRuntimeConfig(testlog.Logger(t, log.LvlError), l1, &rollup.Config{}, 3)
+	load := func(num uint64) {
+		require.NoError(t, runCfg.Load(context.Background(), testL1Ref(num)))
+	}
+
+	load(100)
+	require.Equal(t, []common.Address{oldSigner}, runCfg.P2PSequencerAddresses())
+
+	// the previous signer is accepted for 3 L1 blocks after the change
+	l1.setSigner(101, newSigner)
+	load(101)
+	require.Equal(t, newSigner, runCfg.P2PSequencerAddress())
+	require.Equal(t, []common.Address{newSigner, oldSigner}, runCfg.P2PSequencerAddresses())
+	load(103)
+	require.Equal(t, []common.Address{newSigner, oldSigner}, runCfg.P2PSequencerAddresses())
+	load(104)
+	require.Equal(t, []common.Address{newSigner}, runCfg.P2PSequencerAddresses())
+}
+
+func TestRuntimeConfigNoSignerGracePeriod(t *testing.T) {
+	l1 := &testRuntimeCfgL1Source{}
+	l1.setSigner(0, common.Address{0xaa})
+	runCfg := NewRuntimeConfig(testlog.Logger(t, log.LvlError), l1, &rollup.Config{}, 0)
+	require.NoError(t, runCfg.Load(context.Background(), testL1Ref(100)))
+
+	l1.setSigner(101, common.Address{0xbb})
+	require.NoError(t, runCfg.Load(context.Background(), testL1Ref(101)))
+	require.Equal(t, []common.Address{{0xbb}}, runCfg.P2PSequencerAddresses())
+}
+
+func TestRuntimeConfigRecoverSignerGracePeriod(t *testing.T) {
+	oldSigner, newSigner := common.Address{0xaa}, common.Address{0xbb}
+	for _, changedAt := range []uint64{37, 40, 50, 63, 64} {
+		l1 := &testRuntimeCfgL1Source{}
+		l1.setSigner(0, oldSigner)
+		l1.setSigner(changedAt, newSigner)
+		// a node that starts within the grace period recovers the change, and when the grace period ends
+		runCfg := NewRuntimeConfig(testlog.Logger(t, log.LvlError), l1, &rollup.Config{}, 64)
+		require.NoError(t, runCfg.Load(context.Background(), testL1Ref(changedAt+63)))
+		require.Equal(t, []common.Address{newSigner, oldSigner}, runCfg.P2PSequencerAddresses(), "changed at %d", changedAt)
+		require.NoError(t, runCfg.Load(context.Background(), testL1Ref(changedAt+64)))
+		require.Equal(t, []common.Address{newSigner}, runCfg.P2PSequencerAddresses(), "changed at %d", changedAt)
+	}
+}
+
+func TestRuntimeConfigRecoverSignerNoChange(t *testing.T) {
+	l1 := &testRuntimeCfgL1Source{}
+	l1.setSigner(0, common.Address{0xaa})
+	l1.setSigner(10, common.Address{0xbb})
+	runCfg := NewRuntimeConfig(testlog.Logger(t, log.LvlError), l1, &rollup.Config{}, 64)
+	require.NoError(t, runCfg.Load(context.Background(), testL1Ref(100)))
+	require.Equal(t, []common.Address{{0xbb}}, runCfg.P2PSequencerAddresses())
+}
+
+func TestRuntimeConfigRecoverSignerPrunedState(t *testing.T) {
+	// the previous signer cannot be recovered without the state of older L1 blocks, but loading succeeds
+	l1 := &testRuntimeCfgL1Source{pruned: 90}
+	l1.setSigner(0, common.Address{0xaa})
+	l1.setSigner(95, common.Address{0xbb})
+	runCfg := NewRuntimeConfig(testlog.Logger(t, log.LvlCrit), l1, &rollup.Config{}, 64)
+	require.NoError(t, runCfg.Load(context.Background(), testL1Ref(100)))
+	require.Equal(t, []common.Address{{0xbb}}, runCfg.P2PSequencerAddresses())
+}
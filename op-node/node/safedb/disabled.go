@@ -0,0 +1,31 @@
+package safedb
+
+import (
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+type disabled struct{}
+
+// Disabled is used when the safe head database is not enabled.
+// It ignores all safe head changes, and returns ErrNotEnabled for all reads.
+var Disabled = &disabled{}
+
+func (d *disabled) SafeHeadUpdated(eth.L2BlockRef, eth.BlockID) error {
+	return nil
+}
+
+func (d *disabled) SafeHeadReset(eth.L2BlockRef) error {
+	return nil
+}
+
+func (d *disabled) SafeHeadAtL1(uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	return eth.BlockID{}, eth.BlockID{}, ErrNotEnabled
+}
+
+func (d *disabled) L1OriginOfSafeBlock(uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	return eth.BlockID{}, eth.BlockID{}, ErrNotEnabled
+}
+
+func (d *disabled) Close() error {
+	return nil
+}
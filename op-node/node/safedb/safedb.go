@@ -0,0 +1,185 @@
+// Package safedb persists which L1 block made which L2 block safe.
+//
+// For every L1 block that the safe head advanced at, the last safe head derived while the derivation
+// pipeline was at that L1 block is recorded. The records are indexed by L1 block number, to look up
+// the safe head at an L1 block, and by L2 block number, to look up the L1 block that made an L2 block safe.
+package safedb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+)
+
+var (
+	ErrNotFound   = errors.New("safe head not found")
+	ErrNotEnabled = errors.New("safe head database not enabled")
+)
+
+const (
+	// l1Prefix is the key prefix of the records by L1 block number
+	l1Prefix byte = 0
+	// l2Prefix is the key prefix of the records by safe head L2 block number
+	l2Prefix byte = 1
+
+	recordSize = 2 * (common.HashLength + 8)
+)
+
+// record is a safe head and the L1 block that it was derived at.
+type record struct {
+	L1Block  eth.BlockID
+	SafeHead eth.BlockID
+}
+
+func (r record) encode() []byte {
+	out := make([]byte, recordSize)
+	copy(out[0:32], r.L1Block.Hash[:])
+	binary.BigEndian.PutUint64(out[32:40], r.L1Block.Number)
+	copy(out[40:72], r.SafeHead.Hash[:])
+	binary.BigEndian.PutUint64(out[72:80], r.SafeHead.Number)
+	return out
+}
+
+func decodeRecord(data []byte) (record, error) {
+	if len(data) != recordSize {
+		return record{}, fmt.Errorf("invalid safe head record size %d", len(data))
+	}
+	var r record
+	copy(r.L1Block.Hash[:], data[0:32])
+	r.L1Block.Number = binary.BigEndian.Uint64(data[32:40])
+	copy(r.SafeHead.Hash[:], data[40:72])
+	r.SafeHead.Number = binary.BigEndian.Uint64(data[72:80])
+	return r, nil
+}
+
+func key(prefix byte, number uint64) []byte {
+	out := make([]byte, 9)
+	out[0] = prefix
+	binary.BigEndian.PutUint64(out[1:], number)
+	return out
+}
+
+// SafeDB is a leveldb backed database of safe heads.
+// It is updated by the derivation pipeline, and read by the RPC server.
+type SafeDB struct {
+	log log.Logger
+	db  *leveldb.DB
+}
+
+var _ derive.SafeHeadListener = (*SafeDB)(nil)
+
+// NewSafeDB opens the safe head database at the given path, creating it if it doesn't exist.
+func NewSafeDB(log log.Logger, path string) (*SafeDB, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open safe head database at %s: %w", path, err)
+	}
+	return &SafeDB{log: log, db: db}, nil
+}
+
+// SafeHeadUpdated records the new safe head, and the L1 block it was derived at.
+func (d *SafeDB) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Block eth.BlockID) error {
+	r := record{L1Block: l1Block, SafeHead: safeHead.ID()}
+	d.log.Debug("Recording safe head", "l1", r.L1Block, "l2", r.SafeHead)
+	batch := new(leveldb.Batch)
+	batch.Put(key(l1Prefix, l1Block.Number), r.encode())
+	batch.Put(key(l2Prefix, safeHead.Number), r.encode())
+	if err := d.db.Write(batch, &opt.WriteOptions{Sync: true}); err != nil {
+		return fmt.Errorf("failed to record safe head %s at L1 block %s: %w", r.SafeHead, r.L1Block, err)
+	}
+	return nil
+}
+
+// SafeHeadReset drops the records of all safe heads after the reset safe head. If the L1 block of a
+// dropped record also made earlier safe heads safe, its record is restored to the last remaining one.
+func (d *SafeDB) SafeHeadReset(resetSafeHead eth.L2BlockRef) error {
+	batch := new(leveldb.Batch)
+	iter := d.db.NewIterator(util.BytesPrefix([]byte{l2Prefix}), nil)
+	defer iter.Release()
+	dropped := 0
+	for ok := iter.Seek(key(l2Prefix, resetSafeHead.Number+1)); ok; ok = iter.Next() {
+		r, err := decodeRecord(iter.Value())
+		if err != nil {
+			return err
+		}
+		batch.Delete(key(l2Prefix, r.SafeHead.Number))
+		batch.Delete(key(l1Prefix, r.L1Block.Number))
+		dropped++
+	}
+	if dropped == 0 {
+		return nil
+	}
+	// The L1 block of the last remaining safe head may have made dropped safe heads safe too,
+	// in which case its record by L1 block number got dropped, and has to be restored.
+	if iter.Seek(key(l2Prefix, resetSafeHead.Number+1)) && iter.Prev() {
+		r, err := decodeRecord(iter.Value())
+		if err != nil {
+			return err
+		}
+		batch.Put(key(l1Prefix, r.L1Block.Number), r.encode())
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("failed to iterate safe heads: %w", err)
+	}
+	d.log.Info("Dropping safe heads after reset", "reset_safe_head", resetSafeHead, "dropped", dropped)
+	if err := d.db.Write(batch, &opt.WriteOptions{Sync: true}); err != nil {
+		return fmt.Errorf("failed to drop safe heads after %s: %w", resetSafeHead, err)
+	}
+	return nil
+}
+
+// SafeHeadAtL1 returns the last safe head that was derived at or before the given L1 block,
+// and the L1 block it was derived at.
+func (d *SafeDB) SafeHeadAtL1(l1BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	iter := d.db.NewIterator(util.BytesPrefix([]byte{l1Prefix}), nil)
+	defer iter.Release()
+	var ok bool
+	if l1BlockNum < math.MaxUint64 && iter.Seek(key(l1Prefix, l1BlockNum+1)) {
+		ok = iter.Prev()
+	} else {
+		ok = iter.Last()
+	}
+	if !ok {
+		if err := iter.Error(); err != nil {
+			return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("failed to find safe head at L1 block %d: %w", l1BlockNum, err)
+		}
+		return eth.BlockID{}, eth.BlockID{}, ErrNotFound
+	}
+	r, err := decodeRecord(iter.Value())
+	if err != nil {
+		return eth.BlockID{}, eth.BlockID{}, err
+	}
+	return r.L1Block, r.SafeHead, nil
+}
+
+// L1OriginOfSafeBlock returns the L1 block that made the given L2 block safe, i.e. the first L1 block
+// at which the safe head was at or after the L2 block, and that first safe head.
+func (d *SafeDB) L1OriginOfSafeBlock(l2BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	iter := d.db.NewIterator(util.BytesPrefix([]byte{l2Prefix}), nil)
+	defer iter.Release()
+	if !iter.Seek(key(l2Prefix, l2BlockNum)) {
+		if err := iter.Error(); err != nil {
+			return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("failed to find L1 block of safe L2 block %d: %w", l2BlockNum, err)
+		}
+		return eth.BlockID{}, eth.BlockID{}, ErrNotFound
+	}
+	r, err := decodeRecord(iter.Value())
+	if err != nil {
+		return eth.BlockID{}, eth.BlockID{}, err
+	}
+	return r.L1Block, r.SafeHead, nil
+}
+
+func (d *SafeDB) Close() error {
+	return d.db.Close()
+}
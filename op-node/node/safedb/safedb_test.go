@@ -0,0 +1,124 @@
+package safedb
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+func l1Block(n uint64) eth.BlockID {
+	return eth.BlockID{Hash: common.Hash{0x01, byte(n)}, Number: n}
+}
+
+func safeHead(n uint64) eth.L2BlockRef {
+	return eth.L2BlockRef{Hash: common.Hash{0x02, byte(n)}, Number: n}
+}
+
+func newTestDB(t *testing.T) *SafeDB {
+	logger := testlog.Logger(t, log.LvlInfo)
+	db, err := NewSafeDB(logger, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+	return db
+}
+
+func requireSafeHeadAtL1(t *testing.T, db *SafeDB, l1Num uint64, expectedL1 uint64, expectedL2 uint64) {
+	l1, l2, err := db.SafeHeadAtL1(l1Num)
+	require.NoError(t, err)
+	require.Equal(t, l1Block(expectedL1), l1)
+	require.Equal(t, safeHead(expectedL2).ID(), l2)
+}
+
+func requireL1OriginOfSafeBlock(t *testing.T, db *SafeDB, l2Num uint64, expectedL1 uint64, expectedL2 uint64) {
+	l1, l2, err := db.L1OriginOfSafeBlock(l2Num)
+	require.NoError(t, err)
+	require.Equal(t, l1Block(expectedL1), l1)
+	require.Equal(t, safeHead(expectedL2).ID(), l2)
+}
+
+func TestSafeHeadLookups(t *testing.T) {
+	db := newTestDB(t)
+
+	_, _, err := db.SafeHeadAtL1(10)
+	require.ErrorIs(t, err, ErrNotFound)
+	_, _, err = db.L1OriginOfSafeBlock(100)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.SafeHeadUpdated(safeHead(100), l1Block(10)))
+	require.NoError(t, db.SafeHeadUpdated(safeHead(101), l1Block(10)))
+	require.NoError(t, db.SafeHeadUpdated(safeHead(104), l1Block(12)))
+
+	_, _, err = db.SafeHeadAtL1(9)
+	require.ErrorIs(t, err, ErrNotFound)
+	requireSafeHeadAtL1(t, db, 10, 10, 101)
+	requireSafeHeadAtL1(t, db, 11, 10, 101)
+	requireSafeHeadAtL1(t, db, 12, 12, 104)
+	requireSafeHeadAtL1(t, db, 1000, 12, 104)
+
+	requireL1OriginOfSafeBlock(t, db, 99, 10, 100)
+	requireL1OriginOfSafeBlock(t, db, 100, 10, 100)
+	requireL1OriginOfSafeBlock(t, db, 101, 10, 101)
+	requireL1OriginOfSafeBlock(t, db, 102, 12, 104)
+	requireL1OriginOfSafeBlock(t, db, 104, 12, 104)
+	_, _, err = db.L1OriginOfSafeBlock(105)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSafeHeadReset(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.SafeHeadUpdated(safeHead(100), l1Block(10)))
+	require.NoError(t, db.SafeHeadUpdated(safeHead(101), l1Block(10)))
+	require.NoError(t, db.SafeHeadUpdated(safeHead(102), l1Block(10)))
+	require.NoError(t, db.SafeHeadUpdated(safeHead(104), l1Block(12)))
+
+	// Resetting to the current safe head drops nothing
+	require.NoError(t, db.SafeHeadReset(safeHead(104)))
+	requireSafeHeadAtL1(t, db, 12, 12, 104)
+
+	// Resetting into the safe heads of L1 block 10 keeps the remaining ones of that L1 block
+	require.NoError(t, db.SafeHeadReset(safeHead(101)))
+	requireSafeHeadAtL1(t, db, 10, 10, 101)
+	requireSafeHeadAtL1(t, db, 12, 10, 101)
+	requireL1OriginOfSafeBlock(t, db, 101, 10, 101)
+	_, _, err := db.L1OriginOfSafeBlock(102)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Safe heads can be derived again after the reset
+	require.NoError(t, db.SafeHeadUpdated(safeHead(102), l1Block(11)))
+	requireSafeHeadAtL1(t, db, 11, 11, 102)
+	requireL1OriginOfSafeBlock(t, db, 102, 11, 102)
+
+	// Resetting before all safe heads drops everything
+	require.NoError(t, db.SafeHeadReset(safeHead(50)))
+	_, _, err = db.SafeHeadAtL1(1000)
+	require.ErrorIs(t, err, ErrNotFound)
+	_, _, err = db.L1OriginOfSafeBlock(0)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSafeHeadPersisted(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	dir := t.TempDir()
+	db, err := NewSafeDB(logger, dir)
+	require.NoError(t, err)
+	require.NoError(t, db.SafeHeadUpdated(safeHead(100), l1Block(10)))
+	require.NoError(t, db.Close())
+
+	db, err = NewSafeDB(logger, dir)
+	require.NoError(t, err)
+	defer db.Close()
+	requireSafeHeadAtL1(t, db, 10, 10, 100)
+}
+
+func TestDisabled(t *testing.T) {
+	require.NoError(t, Disabled.SafeHeadUpdated(safeHead(100), l1Block(10)))
+	_, _, err := Disabled.SafeHeadAtL1(10)
+	require.ErrorIs(t, err, ErrNotEnabled)
+	_, _, err = Disabled.L1OriginOfSafeBlock(100)
+	require.ErrorIs(t, err, ErrNotEnabled)
+}
@@ -19,7 +19,10 @@ import (
 	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
 	"github.com/ethereum-optimism/optimism/op-node/testutils"
 	"github.com/ethereum-optimism/optimism/op-node/version"
@@ -110,7 +113,7 @@ func TestOutputAtBlock(t *testing.T) {
 	status := randomSyncStatus(rand.New(rand.NewSource(123)))
 	drClient.ExpectBlockRefWithStatus(0xdcdc89, ref, status, nil)
 
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	require.NoError(t, err)
 	require.NoError(t, server.Start())
 	defer server.Stop()
@@ -142,7 +145,7 @@ func TestVersion(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer server.Stop()
@@ -184,7 +187,7 @@ func TestSyncStatus(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer server.Stop()
@@ -198,6 +201,69 @@ func TestSyncStatus(t *testing.T) {
 	assert.Equal(t, status, out)
 }
 
+func TestDerivationState(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	rng := rand.New(rand.NewSource(1234))
+	state := &derive.DerivationState{
+		Stages: []derive.StageState{{Name: "l1_traversal", Origin: testutils.RandomBlockRef(rng)}},
+		Channels: []derive.ChannelState{{
+			ID:             derive.ChannelID{0xaa},
+			OpenBlock:      testutils.RandomBlockRef(rng),
+			FramesReceived: 2,
+			Size:           1234,
+			Age:            3,
+		}},
+		Batches: []derive.BatchState{{
+			L1InclusionBlock: testutils.RandomBlockRef(rng),
+			ParentHash:       testutils.RandomHash(rng),
+			Epoch:            testutils.RandomBlockID(rng),
+			Timestamp:        1000,
+			Transactions:     5,
+		}},
+		UnsafePayloads: []derive.UnsafePayloadState{{
+			Block:        testutils.RandomBlockID(rng),
+			ParentHash:   testutils.RandomHash(rng),
+			Timestamp:    1002,
+			Transactions: 1,
+		}},
+	}
+	drClient.On("DerivationState").Return(state)
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{
+		// ignore other rollup config info in this test
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	server.EnableDebugAPI(NewDebugAPI(drClient, metrics.NoopMetrics))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client, err := rpcclient.DialRPCClientWithBackoff(context.Background(), log, "http://"+server.Addr().String())
+	require.NoError(t, err)
+
+	var out *derive.DerivationState
+	err = client.CallContext(context.Background(), &out, "debug_derivationState")
+	require.NoError(t, err)
+	require.Equal(t, state, out)
+
+	// the debug API is not served unless it's enabled
+	noDebugServer, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	require.NoError(t, noDebugServer.Start())
+	defer noDebugServer.Stop()
+
+	client, err = rpcclient.DialRPCClientWithBackoff(context.Background(), log, "http://"+noDebugServer.Addr().String())
+	require.NoError(t, err)
+	err = client.CallContext(context.Background(), &out, "debug_derivationState")
+	require.ErrorContains(t, err, "the method debug_derivationState does not exist")
+}
+
 type mockDriverClient struct {
 	mock.Mock
 }
@@ -226,3 +292,15 @@ func (c *mockDriverClient) StartSequencer(ctx context.Context, blockHash common.
 func (c *mockDriverClient) StopSequencer(ctx context.Context) (common.Hash, error) {
 	return c.Mock.MethodCalled("StopSequencer").Get(0).(common.Hash), nil
 }
+
+func (c *mockDriverClient) SequencerPolicy(ctx context.Context) (*driver.SequencerPolicyConfig, error) {
+	return c.Mock.MethodCalled("SequencerPolicy").Get(0).(*driver.SequencerPolicyConfig), nil
+}
+
+func (c *mockDriverClient) SetSequencerPolicy(ctx context.Context, cfg driver.SequencerPolicyConfig) error {
+	return c.Mock.MethodCalled("SetSequencerPolicy", cfg).Get(0).(error)
+}
+
+func (c *mockDriverClient) DerivationState(ctx context.Context) (*derive.DerivationState, error) {
+	return c.Mock.MethodCalled("DerivationState").Get(0).(*derive.DerivationState), nil
+}
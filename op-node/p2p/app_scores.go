@@ -0,0 +1,84 @@
+package p2p
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// appScoresCacheSize is the number of peers that application penalties are remembered for.
+	appScoresCacheSize = 1000
+	// appScoresHalfLife is the duration after which half of an application penalty is forgiven.
+	appScoresHalfLife = 10 * time.Minute
+	// peerScoreRetention is the duration after which the persisted score of a peer that is not seen anymore is deleted.
+	peerScoreRetention = 24 * time.Hour
+)
+
+type appScore struct {
+	value   float64
+	updated time.Time
+}
+
+// AppScores tracks application-specific penalties of peers, e.g. for bad responses to sync requests.
+// The penalties decay exponentially over time, and are added to the gossipsub peer score,
+// so misbehaving peers are graylisted, and banned if banning is enabled.
+type AppScores struct {
+	mu       sync.Mutex
+	scores   *lru.Cache // peer.ID -> *appScore
+	halfLife time.Duration
+	now      func() time.Time
+}
+
+// NewAppScores creates a new application-specific peer score tracker.
+func NewAppScores() *AppScores {
+	scores, err := lru.New(appScoresCacheSize)
+	if err != nil {
+		panic(fmt.Errorf("failed to set up app scores LRU cache: %w", err))
+	}
+	return &AppScores{
+		scores:   scores,
+		halfLife: appScoresHalfLife,
+		now:      time.Now,
+	}
+}
+
+// decayed returns the score of the peer, decayed up to now.
+func (s *AppScores) decayed(id peer.ID, now time.Time) float64 {
+	v, ok := s.scores.Get(id)
+	if !ok {
+		return 0
+	}
+	score := v.(*appScore)
+	elapsed := now.Sub(score.updated)
+	return score.value * math.Pow(0.5, float64(elapsed)/float64(s.halfLife))
+}
+
+// Penalize lowers the score of the peer by the given penalty.
+func (s *AppScores) Penalize(id peer.ID, penalty float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.scores.Add(id, &appScore{value: s.decayed(id, now) - penalty, updated: now})
+}
+
+// Score returns the current application-specific score of the peer, which is 0 or negative.
+func (s *AppScores) Score(id peer.ID) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.decayed(id, s.now())
+}
+
+// Restore penalizes the peer with its persisted score, if negative, decayed from the time the score was last updated.
+func (s *AppScores) Restore(id peer.ID, score PeerScore) {
+	if score.Score >= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scores.Add(id, &appScore{value: score.Score, updated: score.Updated})
+}
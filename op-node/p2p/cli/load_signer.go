@@ -1,33 +1,35 @@
 package cli
 
 import (
-	"fmt"
-
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli"
 
 	"github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
 )
 
-// TODO: implement remote signer setup (config to authenticated endpoint)
-// and remote signer itself (e.g. a open http client to make signing requests)
-
-// LoadSignerSetup loads a configuration for a Signer to be set up later
-func LoadSignerSetup(ctx *cli.Context) (p2p.SignerSetup, error) {
-	key := ctx.GlobalString(flags.SequencerP2PKeyFlag.Name)
-	if key != "" {
-		// Mnemonics are bad because they leak *all* keys when they leak.
-		// Unencrypted keys from file are bad because they are easy to leak (and we are not checking file permissions).
-		priv, err := crypto.HexToECDSA(key)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read batch submitter key: %w", err)
-		}
-
-		return &p2p.PreparedSigner{Signer: p2p.NewLocalSigner(priv)}, nil
+// LoadSignerSetup loads a configuration for a Signer to be set up later.
+// A remote signer is connected to right away, to fail early if it is misconfigured.
+func LoadSignerSetup(ctx *cli.Context, log log.Logger) (p2p.SignerSetup, error) {
+	cfg := &p2p.SignerConfig{
+		PrivateKey: ctx.GlobalString(flags.SequencerP2PKeyFlag.Name),
+		Remote: opsigner.CLIConfig{
+			Endpoint:  ctx.GlobalString(flags.SequencerP2PSignerEndpointFlag.Name),
+			Address:   ctx.GlobalString(flags.SequencerP2PSignerAddressFlag.Name),
+			TLSConfig: optls.ReadCLIConfigWithPrefix(ctx, flags.SequencerP2PSignerTLSFlagPrefix),
+		},
+		RemoteTimeout: ctx.GlobalDuration(flags.SequencerP2PSignerTimeoutFlag.Name),
+		Fallback:      p2p.SignerFallbackPolicy(ctx.GlobalString(flags.SequencerP2PSignerFallbackFlag.Name)),
 	}
-
-	// TODO: create remote signer
-
-	return nil, nil
+	// the signer is optional, e.g. for nodes that are not sequencing
+	if cfg.PrivateKey == "" && cfg.Remote.Endpoint == "" && cfg.Remote.Address == "" {
+		return nil, nil
+	}
+	signer, err := cfg.NewSigner(log)
+	if err != nil {
+		return nil, err
+	}
+	return &p2p.PreparedSigner{Signer: signer}, nil
 }
@@ -18,7 +18,6 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/metrics"
 	"github.com/libp2p/go-libp2p/core/peer"
-	"github.com/libp2p/go-libp2p/p2p/net/conngater"
 	cmgr "github.com/libp2p/go-libp2p/p2p/net/connmgr"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
@@ -39,6 +38,8 @@ type SetupP2P interface {
 	// Discovery creates a disc-v5 service. Returns nil, nil, nil if discovery is disabled.
 	Discovery(log log.Logger, rollupCfg *rollup.Config, tcpPort uint16) (*enode.LocalNode, *discover.UDPv5, error)
 	TargetPeers() uint
+	// ReqRespSyncEnabled returns whether unsafe L2 payloads are served to and requested from peers.
+	ReqRespSyncEnabled() bool
 	GossipSetupConfigurables
 }
 
@@ -101,6 +102,10 @@ type Config struct {
 
 	ConnGater func(conf *Config) (connmgr.ConnectionGater, error)
 	ConnMngr  func(conf *Config) (connmgr.ConnManager, error)
+
+	// Whether to serve unsafe L2 payloads to peers by block number,
+	// and to request missing payloads from peers.
+	EnableReqRespSync bool
 }
 
 //go:generate mockery --name ConnectionGater
@@ -124,10 +129,24 @@ type ConnectionGater interface {
 	BlockSubnet(ipnet *net.IPNet) error
 	UnblockSubnet(ipnet *net.IPNet) error
 	ListBlockedSubnets() []*net.IPNet
+
+	// BanPeer blocks a peer, and records the source, reason and expiry of the ban.
+	// A zero expiry bans the peer until it is unblocked.
+	BanPeer(p peer.ID, source BanSource, reason string, expiry time.Time) error
+	// BanAddr blocks an IP address, and records the source, reason and expiry of the ban.
+	// A zero expiry bans the IP address until it is unblocked.
+	BanAddr(ip net.IP, source BanSource, reason string, expiry time.Time) error
+	// BanSubnet blocks an IP subnet, and records the source, reason and expiry of the ban.
+	// A zero expiry bans the IP subnet until it is unblocked.
+	BanSubnet(ipnet *net.IPNet, source BanSource, reason string, expiry time.Time) error
+	// PeerBan returns the active ban of a peer, if any.
+	PeerBan(p peer.ID) (*BanInfo, bool)
+	// ListBans returns the active bans of peers, IP addresses and subnets.
+	ListBans() []*BanInfo
 }
 
 func DefaultConnGater(conf *Config) (connmgr.ConnectionGater, error) {
-	return conngater.NewBasicConnectionGater(conf.Store)
+	return NewExtendedConnectionGater(conf.Store)
 }
 
 func DefaultConnManager(conf *Config) (connmgr.ConnManager, error) {
@@ -147,6 +166,10 @@ func (conf *Config) Disabled() bool {
 	return conf.DisableP2P
 }
 
+func (conf *Config) ReqRespSyncEnabled() bool {
+	return !conf.DisableP2P && conf.EnableReqRespSync
+}
+
 func (conf *Config) PeerScoringParams() *pubsub.PeerScoreParams {
 	return &conf.PeerScoring
 }
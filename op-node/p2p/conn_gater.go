@@ -0,0 +1,308 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// BanSource identifies what banned a peer, IP address or subnet.
+type BanSource string
+
+const (
+	// BanSourceManual bans are made through the RPC API, and do not expire unless an expiry is given.
+	BanSourceManual BanSource = "manual"
+	// BanSourceScore bans are made by the PeerGater for peers with a score below the PeerScoreThreshold.
+	BanSourceScore BanSource = "score"
+	// BanSourceUnknown bans were made before the reasons of bans were recorded.
+	BanSourceUnknown BanSource = "unknown"
+)
+
+// BanInfo describes a ban of a peer, IP address or subnet.
+type BanInfo struct {
+	Peer   peer.ID `json:"peer,omitempty"`
+	IP     net.IP  `json:"ip,omitempty"`
+	Subnet string  `json:"subnet,omitempty"`
+
+	Source BanSource `json:"source"`
+	Reason string    `json:"reason"`
+	// Expiry is the time at which the ban is lifted, zero if the ban does not expire.
+	Expiry time.Time `json:"expiry"`
+}
+
+// target returns the kind and the string representation of the banned peer, IP address or subnet.
+func (b *BanInfo) target() (kind string, target string) {
+	switch {
+	case b.Peer != "":
+		return "peer", b.Peer.String()
+	case b.IP != nil:
+		return "addr", b.IP.String()
+	default:
+		return "subnet", b.Subnet
+	}
+}
+
+func (b *BanInfo) expired(now time.Time) bool {
+	return !b.Expiry.IsZero() && !now.Before(b.Expiry)
+}
+
+// ExtendedConnectionGater is a libp2p BasicConnectionGater that records the source, reason and expiry of bans
+// in the PeerDB. Expired bans are lifted when the gater is used.
+type ExtendedConnectionGater struct {
+	*conngater.BasicConnectionGater
+	db  *PeerDB
+	now func() time.Time
+
+	mu         sync.Mutex
+	bans       map[string]*BanInfo // by kind and target
+	nextExpiry time.Time           // earliest expiry of the bans, zero if no ban expires
+}
+
+var _ ConnectionGater = (*ExtendedConnectionGater)(nil)
+
+// NewExtendedConnectionGater returns a connection gater that persists bans in the given datastore.
+func NewExtendedConnectionGater(store ds.Batching) (*ExtendedConnectionGater, error) {
+	basic, err := conngater.NewBasicConnectionGater(store)
+	if err != nil {
+		return nil, err
+	}
+	g := &ExtendedConnectionGater{
+		BasicConnectionGater: basic,
+		db:                   NewPeerDB(store),
+		now:                  time.Now,
+		bans:                 make(map[string]*BanInfo),
+	}
+	bans, err := g.db.Bans()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bans: %w", err)
+	}
+	for _, ban := range bans {
+		g.bans[banMapKey(ban)] = ban
+		g.updateNextExpiry(ban)
+	}
+	g.expireBans()
+	return g, nil
+}
+
+func banMapKey(ban *BanInfo) string {
+	kind, target := ban.target()
+	return kind + "/" + target
+}
+
+func (g *ExtendedConnectionGater) updateNextExpiry(ban *BanInfo) {
+	if !ban.Expiry.IsZero() && (g.nextExpiry.IsZero() || ban.Expiry.Before(g.nextExpiry)) {
+		g.nextExpiry = ban.Expiry
+	}
+}
+
+// expireBans lifts the bans that expired.
+func (g *ExtendedConnectionGater) expireBans() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	if g.nextExpiry.IsZero() || now.Before(g.nextExpiry) {
+		return
+	}
+	g.nextExpiry = time.Time{}
+	for key, ban := range g.bans {
+		if !ban.expired(now) {
+			g.updateNextExpiry(ban)
+			continue
+		}
+		// If lifting the ban fails, it is retried at the next expiry check.
+		if err := g.unblock(ban); err != nil {
+			g.updateNextExpiry(ban)
+			continue
+		}
+		delete(g.bans, key)
+	}
+}
+
+func (g *ExtendedConnectionGater) unblock(ban *BanInfo) error {
+	var err error
+	switch {
+	case ban.Peer != "":
+		err = g.BasicConnectionGater.UnblockPeer(ban.Peer)
+	case ban.IP != nil:
+		err = g.BasicConnectionGater.UnblockAddr(ban.IP)
+	default:
+		var ipnet *net.IPNet
+		if _, ipnet, err = net.ParseCIDR(ban.Subnet); err == nil {
+			err = g.BasicConnectionGater.UnblockSubnet(ipnet)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return g.db.DeleteBan(ban)
+}
+
+// ban records the ban, after the basic connection gater blocked the target with the block function.
+func (g *ExtendedConnectionGater) ban(ban *BanInfo, block func() error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := block(); err != nil {
+		return err
+	}
+	if err := g.db.PutBan(ban); err != nil {
+		return fmt.Errorf("failed to store ban: %w", err)
+	}
+	g.bans[banMapKey(ban)] = ban
+	g.updateNextExpiry(ban)
+	return nil
+}
+
+// lift lifts the ban of the target with the unblock function of the basic connection gater.
+func (g *ExtendedConnectionGater) lift(ban *BanInfo, unblock func() error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := unblock(); err != nil {
+		return err
+	}
+	delete(g.bans, banMapKey(ban))
+	return g.db.DeleteBan(ban)
+}
+
+// BanPeer blocks the peer, and records the source, reason and expiry of the ban.
+// A zero expiry bans the peer until it is unblocked.
+func (g *ExtendedConnectionGater) BanPeer(p peer.ID, source BanSource, reason string, expiry time.Time) error {
+	return g.ban(&BanInfo{Peer: p, Source: source, Reason: reason, Expiry: expiry}, func() error {
+		return g.BasicConnectionGater.BlockPeer(p)
+	})
+}
+
+func (g *ExtendedConnectionGater) BlockPeer(p peer.ID) error {
+	return g.BanPeer(p, BanSourceManual, "blocked through API", time.Time{})
+}
+
+func (g *ExtendedConnectionGater) UnblockPeer(p peer.ID) error {
+	return g.lift(&BanInfo{Peer: p}, func() error {
+		return g.BasicConnectionGater.UnblockPeer(p)
+	})
+}
+
+// BanAddr blocks the IP address, and records the source, reason and expiry of the ban.
+// A zero expiry bans the IP address until it is unblocked.
+func (g *ExtendedConnectionGater) BanAddr(ip net.IP, source BanSource, reason string, expiry time.Time) error {
+	return g.ban(&BanInfo{IP: ip, Source: source, Reason: reason, Expiry: expiry}, func() error {
+		return g.BasicConnectionGater.BlockAddr(ip)
+	})
+}
+
+func (g *ExtendedConnectionGater) BlockAddr(ip net.IP) error {
+	return g.BanAddr(ip, BanSourceManual, "blocked through API", time.Time{})
+}
+
+func (g *ExtendedConnectionGater) UnblockAddr(ip net.IP) error {
+	return g.lift(&BanInfo{IP: ip}, func() error {
+		return g.BasicConnectionGater.UnblockAddr(ip)
+	})
+}
+
+// BanSubnet blocks the IP subnet, and records the source, reason and expiry of the ban.
+// A zero expiry bans the IP subnet until it is unblocked.
+func (g *ExtendedConnectionGater) BanSubnet(ipnet *net.IPNet, source BanSource, reason string, expiry time.Time) error {
+	return g.ban(&BanInfo{Subnet: ipnet.String(), Source: source, Reason: reason, Expiry: expiry}, func() error {
+		return g.BasicConnectionGater.BlockSubnet(ipnet)
+	})
+}
+
+func (g *ExtendedConnectionGater) BlockSubnet(ipnet *net.IPNet) error {
+	return g.BanSubnet(ipnet, BanSourceManual, "blocked through API", time.Time{})
+}
+
+func (g *ExtendedConnectionGater) UnblockSubnet(ipnet *net.IPNet) error {
+	return g.lift(&BanInfo{Subnet: ipnet.String()}, func() error {
+		return g.BasicConnectionGater.UnblockSubnet(ipnet)
+	})
+}
+
+func (g *ExtendedConnectionGater) ListBlockedPeers() []peer.ID {
+	g.expireBans()
+	return g.BasicConnectionGater.ListBlockedPeers()
+}
+
+func (g *ExtendedConnectionGater) ListBlockedAddrs() []net.IP {
+	g.expireBans()
+	return g.BasicConnectionGater.ListBlockedAddrs()
+}
+
+func (g *ExtendedConnectionGater) ListBlockedSubnets() []*net.IPNet {
+	g.expireBans()
+	return g.BasicConnectionGater.ListBlockedSubnets()
+}
+
+// ListBans returns all active bans. Bans that were made before the reasons of bans were recorded
+// are listed with the BanSourceUnknown source.
+func (g *ExtendedConnectionGater) ListBans() []*BanInfo {
+	g.expireBans()
+	blocked := make([]*BanInfo, 0)
+	for _, p := range g.BasicConnectionGater.ListBlockedPeers() {
+		blocked = append(blocked, &BanInfo{Peer: p})
+	}
+	for _, ip := range g.BasicConnectionGater.ListBlockedAddrs() {
+		blocked = append(blocked, &BanInfo{IP: ip})
+	}
+	for _, ipnet := range g.BasicConnectionGater.ListBlockedSubnets() {
+		blocked = append(blocked, &BanInfo{Subnet: ipnet.String()})
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]*BanInfo, 0, len(blocked))
+	for _, b := range blocked {
+		if ban, ok := g.bans[banMapKey(b)]; ok {
+			b = ban
+		} else {
+			b.Source = BanSourceUnknown
+		}
+		banCopy := *b
+		out = append(out, &banCopy)
+	}
+	return out
+}
+
+// PeerBan returns the active ban of the peer, if any. A ban that was made before the reasons of bans
+// were recorded is returned with the BanSourceUnknown source.
+func (g *ExtendedConnectionGater) PeerBan(p peer.ID) (*BanInfo, bool) {
+	g.expireBans()
+	if g.BasicConnectionGater.InterceptPeerDial(p) {
+		return nil, false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ban := &BanInfo{Peer: p, Source: BanSourceUnknown}
+	if b, ok := g.bans[banMapKey(ban)]; ok {
+		ban = b
+	}
+	banCopy := *ban
+	return &banCopy, true
+}
+
+func (g *ExtendedConnectionGater) InterceptPeerDial(p peer.ID) (allow bool) {
+	g.expireBans()
+	return g.BasicConnectionGater.InterceptPeerDial(p)
+}
+
+func (g *ExtendedConnectionGater) InterceptAddrDial(p peer.ID, a ma.Multiaddr) (allow bool) {
+	g.expireBans()
+	return g.BasicConnectionGater.InterceptAddrDial(p, a)
+}
+
+func (g *ExtendedConnectionGater) InterceptAccept(cma network.ConnMultiaddrs) (allow bool) {
+	g.expireBans()
+	return g.BasicConnectionGater.InterceptAccept(cma)
+}
+
+func (g *ExtendedConnectionGater) InterceptSecured(dir network.Direction, p peer.ID, cma network.ConnMultiaddrs) (allow bool) {
+	g.expireBans()
+	return g.BasicConnectionGater.InterceptSecured(dir, p, cma)
+}
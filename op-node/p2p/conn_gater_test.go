@@ -0,0 +1,195 @@
+package p2p
+
+import (
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+func testPeerID(t *testing.T) peer.ID {
+	priv, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
+	require.NoError(t, err)
+	id, err := peer.IDFromPrivateKey(priv)
+	require.NoError(t, err)
+	return id
+}
+
+func TestExtendedConnectionGater(t *testing.T) {
+	alice, bob, carol := testPeerID(t), testPeerID(t), testPeerID(t)
+	store := sync.MutexWrap(ds.NewMapDatastore())
+	now := time.Now()
+	gater, err := NewExtendedConnectionGater(store)
+	require.NoError(t, err)
+	gater.now = func() time.Time { return now }
+
+	require.NoError(t, gater.BanPeer(alice, BanSourceScore, "bad score", now.Add(time.Hour)))
+	require.NoError(t, gater.BlockPeer(bob))
+	require.NoError(t, gater.BlockAddr(net.IP{1, 2, 3, 4}))
+	subnet := &net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.IPMask{0xff, 0, 0, 0}}
+	require.NoError(t, gater.BlockSubnet(subnet))
+	// a ban made by the basic connection gater, without reason
+	require.NoError(t, gater.BasicConnectionGater.BlockPeer(carol))
+	require.False(t, gater.InterceptPeerDial(alice))
+
+	bans := make(map[string]*BanInfo)
+	for _, ban := range gater.ListBans() {
+		bans[banMapKey(ban)] = ban
+	}
+	require.Len(t, bans, 5)
+	require.Equal(t, &BanInfo{Peer: alice, Source: BanSourceScore, Reason: "bad score", Expiry: now.Add(time.Hour)}, bans[banMapKey(&BanInfo{Peer: alice})])
+	require.Equal(t, BanSourceManual, bans[banMapKey(&BanInfo{Peer: bob})].Source)
+	require.Equal(t, BanSourceManual, bans["addr/1.2.3.4"].Source)
+	require.Equal(t, BanSourceManual, bans["subnet/10.0.0.0/8"].Source)
+	require.Equal(t, BanSourceUnknown, bans[banMapKey(&BanInfo{Peer: carol})].Source)
+
+	ban, ok := gater.PeerBan(alice)
+	require.True(t, ok)
+	require.Equal(t, bans[banMapKey(&BanInfo{Peer: alice})], ban)
+	ban, ok = gater.PeerBan(carol)
+	require.True(t, ok)
+	require.Equal(t, BanSourceUnknown, ban.Source)
+	_, ok = gater.PeerBan(testPeerID(t))
+	require.False(t, ok)
+
+	// bans are retained after a restart
+	gater, err = NewExtendedConnectionGater(store)
+	require.NoError(t, err)
+	gater.now = func() time.Time { return now }
+	require.Len(t, gater.ListBans(), 5)
+	require.False(t, gater.InterceptPeerDial(alice))
+
+	// expired bans are lifted
+	now = now.Add(time.Hour)
+	require.True(t, gater.InterceptPeerDial(alice))
+	require.NotContains(t, gater.ListBlockedPeers(), alice)
+	_, ok = gater.PeerBan(alice)
+	require.False(t, ok)
+	require.Len(t, gater.ListBans(), 4)
+
+	require.NoError(t, gater.UnblockPeer(bob))
+	require.NoError(t, gater.UnblockAddr(net.IP{1, 2, 3, 4}))
+	require.NoError(t, gater.UnblockSubnet(subnet))
+	gater, err = NewExtendedConnectionGater(store)
+	require.NoError(t, err)
+	require.Len(t, gater.ListBans(), 1)
+	stored, err := gater.db.Bans()
+	require.NoError(t, err)
+	require.Empty(t, stored)
+}
+
+func TestExtendedConnectionGaterBanExpiry(t *testing.T) {
+	now := time.Now()
+	gater, err := NewExtendedConnectionGater(sync.MutexWrap(ds.NewMapDatastore()))
+	require.NoError(t, err)
+	gater.now = func() time.Time { return now }
+
+	ip := net.IP{1, 2, 3, 4}
+	subnet := &net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.IPMask{0xff, 0, 0, 0}}
+	require.NoError(t, gater.BanAddr(ip, BanSourceManual, "spam", now.Add(time.Minute)))
+	require.NoError(t, gater.BanSubnet(subnet, BanSourceManual, "abuse", now.Add(time.Hour)))
+	bans := gater.ListBans()
+	require.Len(t, bans, 2)
+	for _, ban := range bans {
+		if ban.IP != nil {
+			require.Equal(t, "spam", ban.Reason)
+			require.Equal(t, now.Add(time.Minute), ban.Expiry)
+		} else {
+			require.Equal(t, "abuse", ban.Reason)
+			require.Equal(t, now.Add(time.Hour), ban.Expiry)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	require.Empty(t, gater.ListBlockedAddrs())
+	require.Len(t, gater.ListBlockedSubnets(), 1)
+	now = now.Add(time.Hour)
+	require.Empty(t, gater.ListBans())
+}
+
+func TestPeerDB(t *testing.T) {
+	db := NewPeerDB(sync.MutexWrap(ds.NewMapDatastore()))
+	alice := testPeerID(t)
+
+	score := PeerScore{Score: -42, Updated: time.Unix(1000, 0).UTC()}
+	require.NoError(t, db.PutScore(alice, score))
+	scores, err := db.Scores()
+	require.NoError(t, err)
+	require.Equal(t, map[peer.ID]PeerScore{alice: score}, scores)
+	require.NoError(t, db.DeleteScore(alice))
+	scores, err = db.Scores()
+	require.NoError(t, err)
+	require.Empty(t, scores)
+
+	require.NoError(t, db.AddProtected(alice, "api-protected"))
+	require.NoError(t, db.AddProtected(alice, "api-protected"))
+	require.NoError(t, db.AddProtected(alice, "other"))
+	protected, err := db.Protected()
+	require.NoError(t, err)
+	require.Equal(t, map[peer.ID][]string{alice: {"api-protected", "other"}}, protected)
+	require.NoError(t, db.RemoveProtected(alice, "api-protected"))
+	require.NoError(t, db.RemoveProtected(alice, "other"))
+	protected, err = db.Protected()
+	require.NoError(t, err)
+	require.Empty(t, protected)
+}
+
+type noopPeerGater struct{}
+
+func (noopPeerGater) Update(peer.ID, float64) {}
+
+type noopGossipMetricer struct{}
+
+func (noopGossipMetricer) RecordGossipEvent(int32) {}
+
+func (noopGossipMetricer) RecordPeerScoring(peer.ID, float64) {}
+
+func TestScorerPersistScores(t *testing.T) {
+	db := NewPeerDB(sync.MutexWrap(ds.NewMapDatastore()))
+	alice, bob := testPeerID(t), testPeerID(t)
+	now := time.Unix(1000, 0).UTC()
+	s := NewScorer(noopPeerGater{}, nil, noopGossipMetricer{}, db, testlog.Logger(t, log.LvlError)).(*scorer)
+	s.now = func() time.Time { return now }
+	snapshot := func(scores map[peer.ID]float64) map[peer.ID]PeerScore {
+		m := make(map[peer.ID]*pubsub.PeerScoreSnapshot)
+		for id, score := range scores {
+			m[id] = &pubsub.PeerScoreSnapshot{Score: score}
+		}
+		s.SnapshotHook()(m)
+		stored, err := db.Scores()
+		require.NoError(t, err)
+		return stored
+	}
+
+	// only negative scores are persisted
+	start := now
+	require.Equal(t, map[peer.ID]PeerScore{alice: {Score: -10, Updated: start}}, snapshot(map[peer.ID]float64{alice: -10, bob: 5}))
+
+	// small changes are not persisted
+	now = now.Add(15 * time.Second)
+	require.Equal(t, map[peer.ID]PeerScore{alice: {Score: -10, Updated: start}}, snapshot(map[peer.ID]float64{alice: -10.5, bob: 6}))
+
+	// meaningful changes are persisted
+	require.Equal(t, map[peer.ID]PeerScore{alice: {Score: -12, Updated: now}}, snapshot(map[peer.ID]float64{alice: -12, bob: 6}))
+
+	// unchanged scores are persisted again after the interval
+	now = now.Add(scorePersistInterval)
+	require.Equal(t, map[peer.ID]PeerScore{alice: {Score: -12, Updated: now}}, snapshot(map[peer.ID]float64{alice: -12, bob: 6}))
+
+	// recovered scores are deleted
+	require.Empty(t, snapshot(map[peer.ID]float64{alice: 1, bob: 6}))
+
+	// peers that are not scored anymore are forgotten
+	snapshot(map[peer.ID]float64{})
+	require.Empty(t, s.persisted)
+}
@@ -58,7 +58,9 @@ type GossipSetupConfigurables interface {
 }
 
 type GossipRuntimeConfig interface {
-	P2PSequencerAddress() common.Address
+	// P2PSequencerAddresses returns the addresses that may sign gossiped blocks.
+	// During a key rotation this includes both the new and the previous sequencer address.
+	P2PSequencerAddresses() []common.Address
 }
 
 //go:generate mockery --name GossipMetricer
@@ -150,7 +152,7 @@ func BuildGlobalGossipParams(cfg *rollup.Config) pubsub.GossipSubParams {
 
 // NewGossipSub configures a new pubsub instance with the specified parameters.
 // PubSub uses a GossipSubRouter as it's router under the hood.
-func NewGossipSub(p2pCtx context.Context, h host.Host, g ConnectionGater, cfg *rollup.Config, gossipConf GossipSetupConfigurables, m GossipMetricer, log log.Logger) (*pubsub.PubSub, error) {
+func NewGossipSub(p2pCtx context.Context, h host.Host, g ConnectionGater, cfg *rollup.Config, gossipConf GossipSetupConfigurables, appScores *AppScores, db *PeerDB, m GossipMetricer, log log.Logger) (*pubsub.PubSub, error) {
 	denyList, err := pubsub.NewTimeCachedBlacklist(30 * time.Second)
 	if err != nil {
 		return nil, err
@@ -171,7 +173,7 @@ func NewGossipSub(p2pCtx context.Context, h host.Host, g ConnectionGater, cfg *r
 		pubsub.WithGossipSubParams(params),
 		pubsub.WithEventTracer(&gossipTracer{m: m}),
 	}
-	gossipOpts = append(gossipOpts, ConfigurePeerScoring(h, g, gossipConf, m, log)...)
+	gossipOpts = append(gossipOpts, ConfigurePeerScoring(h, g, gossipConf, appScores, db, m, log)...)
 	gossipOpts = append(gossipOpts, gossipConf.ConfigureGossip(&params)...)
 	return pubsub.NewGossipSub(p2pCtx, h, gossipOpts...)
 }
@@ -363,21 +365,25 @@ func verifyBlockSignatureWithHasher(log log.Logger, cfg *rollup.Config, runCfg G
 
 	// In the future we may load & validate block metadata before checking the signature.
 	// And then check the signer based on the metadata, to support e.g. multiple p2p signers at the same time.
-	// For now we only have one signer at a time and thus check the address directly.
-	// This means we may drop old payloads upon key rotation,
+	// For now we accept the current signer, and the previous signer during the grace period of a key rotation.
+	// Payloads of older signers are dropped,
 	// but this can be recovered from like any other missed unsafe payload.
-	if expected := runCfg.P2PSequencerAddress(); expected == (common.Address{}) {
+	expected := runCfg.P2PSequencerAddresses()
+	if len(expected) == 0 {
 		if log != nil {
 			log.Warn("no configured p2p sequencer address, ignoring gossiped block", "peer", id, "addr", addr)
 		}
 		return pubsub.ValidationIgnore
-	} else if addr != expected {
-		if log != nil {
-			log.Warn("unexpected block author", "err", err, "peer", id, "addr", addr, "expected", expected)
+	}
+	for _, a := range expected {
+		if addr == a {
+			return pubsub.ValidationAccept
 		}
-		return pubsub.ValidationReject
 	}
-	return pubsub.ValidationAccept
+	if log != nil {
+		log.Warn("unexpected block author", "peer", id, "addr", addr, "expected", expected)
+	}
+	return pubsub.ValidationReject
 }
 
 type GossipIn interface {
@@ -399,6 +405,7 @@ type publisher struct {
 	cfg         *rollup.Config
 	blocksTopic *pubsub.Topic
 	runCfg      GossipRuntimeConfig
+	payloads    *signedPayloads // optional, to serve published payloads to peers
 }
 
 var _ GossipOut = (*publisher)(nil)
@@ -430,6 +437,9 @@ func (p *publisher) PublishL2Payload(ctx context.Context, payload *eth.Execution
 	// compress the full message
 	// This also copies the data, freeing up the original buffer to go back into the pool
 	out := snappy.Encode(nil, data)
+	if p.payloads != nil {
+		p.payloads.add(uint64(payload.BlockNumber), out)
+	}
 
 	return p.blocksTopic.Publish(ctx, out)
 }
@@ -438,8 +448,11 @@ func (p *publisher) Close() error {
 	return p.blocksTopic.Close()
 }
 
-func JoinGossip(p2pCtx context.Context, self peer.ID, topicScoreParams *pubsub.TopicScoreParams, ps *pubsub.PubSub, log log.Logger, cfg *rollup.Config, runCfg GossipRuntimeConfig, gossipIn GossipIn) (GossipOut, error) {
+func JoinGossip(p2pCtx context.Context, self peer.ID, topicScoreParams *pubsub.TopicScoreParams, ps *pubsub.PubSub, log log.Logger, cfg *rollup.Config, runCfg GossipRuntimeConfig, gossipIn GossipIn, payloads *signedPayloads) (GossipOut, error) {
 	val := guardGossipValidator(log, logValidationResult(self, "validated block", log, BuildBlocksValidator(log, cfg, runCfg)))
+	if payloads != nil {
+		val = cacheSignedPayloads(payloads, val)
+	}
 	blocksTopicName := blocksTopicV1(cfg)
 	err := ps.RegisterTopicValidator(blocksTopicName,
 		val,
@@ -475,7 +488,7 @@ func JoinGossip(p2pCtx context.Context, self peer.ID, topicScoreParams *pubsub.T
 	subscriber := MakeSubscriber(log, BlocksHandler(gossipIn.OnUnsafeL2Payload))
 	go subscriber(p2pCtx, subscription)
 
-	return &publisher{log: log, cfg: cfg, blocksTopic: blocksTopic, runCfg: runCfg}, nil
+	return &publisher{log: log, cfg: cfg, blocksTopic: blocksTopic, runCfg: runCfg, payloads: payloads}, nil
 }
 
 type TopicSubscriber func(ctx context.Context, sub *pubsub.Subscription)
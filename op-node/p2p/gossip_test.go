@@ -85,6 +85,18 @@ func TestVerifyBlockSignature(t *testing.T) {
 			require.Equal(t, pubsub.ValidationReject, result)
 		})
 
+		t.Run("PreviousSigner "+test.name, func(t *testing.T) {
+			runCfg := &testutils.MockRuntimeConfig{
+				P2PSeqAddress:     common.HexToAddress("0x1234"),
+				P2PPrevSeqAddress: crypto.PubkeyToAddress(secrets.SequencerP2P.PublicKey),
+			}
+			signer := &PreparedSigner{Signer: test.newSigner(secrets.SequencerP2P)}
+			sig, err := signer.Sign(context.Background(), SigningDomainBlocksV1, cfg.L2ChainID, msg)
+			require.NoError(t, err)
+			result := verifyBlockSignature(logger, cfg, runCfg, peerId, sig[:65], msg)
+			require.Equal(t, pubsub.ValidationAccept, result)
+		})
+
 		t.Run("InvalidSignature "+test.name, func(t *testing.T) {
 			runCfg := &testutils.MockRuntimeConfig{P2PSeqAddress: crypto.PubkeyToAddress(secrets.SequencerP2P.PublicKey)}
 			sig := make([]byte, 65)
@@ -28,16 +28,21 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// staticPeerTag is the connection manager tag of the protection of static peers.
+const staticPeerTag = "static"
+
 type ExtraHostFeatures interface {
 	host.Host
 	ConnectionGater() ConnectionGater
 	ConnectionManager() connmgr.ConnManager
+	PeerDB() *PeerDB
 }
 
 type extraHost struct {
 	host.Host
 	gater   ConnectionGater
 	connMgr connmgr.ConnManager
+	db      *PeerDB
 	log     log.Logger
 
 	staticPeers []*peer.AddrInfo
@@ -53,6 +58,50 @@ func (e *extraHost) ConnectionManager() connmgr.ConnManager {
 	return e.connMgr
 }
 
+func (e *extraHost) PeerDB() *PeerDB {
+	return e.db
+}
+
+// persistentConnManager is a connection manager that persists the protection of peers in the PeerDB,
+// except for the protection of static peers, which is configured again at startup.
+type persistentConnManager struct {
+	connmgr.ConnManager
+	db  *PeerDB
+	log log.Logger
+}
+
+func (m *persistentConnManager) Protect(id peer.ID, tag string) {
+	m.ConnManager.Protect(id, tag)
+	if tag == staticPeerTag {
+		return
+	}
+	if err := m.db.AddProtected(id, tag); err != nil {
+		m.log.Warn("failed to persist peer protection", "peer", id, "tag", tag, "err", err)
+	}
+}
+
+func (m *persistentConnManager) Unprotect(id peer.ID, tag string) (protected bool) {
+	protected = m.ConnManager.Unprotect(id, tag)
+	if err := m.db.RemoveProtected(id, tag); err != nil {
+		m.log.Warn("failed to persist removal of peer protection", "peer", id, "tag", tag, "err", err)
+	}
+	return protected
+}
+
+// restore protects the peers that were protected before the node restarted.
+func (m *persistentConnManager) restore() error {
+	protected, err := m.db.Protected()
+	if err != nil {
+		return err
+	}
+	for id, tags := range protected {
+		for _, tag := range tags {
+			m.ConnManager.Protect(id, tag)
+		}
+	}
+	return nil
+}
+
 func (e *extraHost) Close() error {
 	close(e.quitC)
 	return e.Host.Close()
@@ -63,7 +112,7 @@ func (e *extraHost) initStaticPeers() {
 		e.Peerstore().AddAddrs(addr.ID, addr.Addrs, time.Hour*24*7)
 		// We protect the peer, so the connection manager doesn't decide to prune it.
 		// We tag it with "static" so other protects/unprotects with different tags don't affect this protection.
-		e.connMgr.Protect(addr.ID, "static")
+		e.connMgr.Protect(addr.ID, staticPeerTag)
 		// Try to dial the node in the background
 		go func(addr *peer.AddrInfo) {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
@@ -153,6 +202,11 @@ func (conf *Config) Host(log log.Logger, reporter metrics.Reporter) (host.Host,
 	if err != nil {
 		return nil, fmt.Errorf("failed to open connection manager: %w", err)
 	}
+	db := NewPeerDB(conf.Store)
+	persistentConnMngr := &persistentConnManager{ConnManager: connMngr, db: db, log: log}
+	if err := persistentConnMngr.restore(); err != nil {
+		return nil, fmt.Errorf("failed to restore protected peers: %w", err)
+	}
 
 	listenAddr, err := addrFromIPAndPort(conf.ListenIP, conf.ListenTCPPort)
 	if err != nil {
@@ -182,7 +236,7 @@ func (conf *Config) Host(log log.Logger, reporter metrics.Reporter) (host.Host,
 		// host will start and listen to network directly after construction from config.
 		libp2p.ListenAddrs(listenAddr),
 		libp2p.ConnectionGater(connGtr),
-		libp2p.ConnectionManager(connMngr),
+		libp2p.ConnectionManager(persistentConnMngr),
 		//libp2p.ResourceManager(nil), // TODO use resource manager interface to manage resources per peer better.
 		libp2p.NATManager(nat),
 		libp2p.Peerstore(ps),
@@ -216,7 +270,8 @@ func (conf *Config) Host(log log.Logger, reporter metrics.Reporter) (host.Host,
 
 	out := &extraHost{
 		Host:        h,
-		connMgr:     connMngr,
+		connMgr:     persistentConnMngr,
+		db:          db,
 		log:         log,
 		staticPeers: staticPeers,
 		quitC:       make(chan struct{}),
@@ -174,6 +174,11 @@ func TestP2PFull(t *testing.T) {
 	blockedPeers, err := p2pClientA.ListBlockedPeers(ctx)
 	require.NoError(t, err)
 	require.Equal(t, []peer.ID{hostB.ID()}, blockedPeers)
+	bans, err := p2pClientA.ListBans(ctx)
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	require.Equal(t, hostB.ID(), bans[0].Peer)
+	require.Equal(t, BanSourceManual, bans[0].Source)
 	require.NoError(t, p2pClientA.UnblockPeer(ctx, hostB.ID()))
 
 	require.NoError(t, p2pClientA.BlockAddr(ctx, net.IP{123, 123, 123, 123}))
@@ -191,6 +196,16 @@ func TestP2PFull(t *testing.T) {
 	require.Equal(t, subnet, blockedSubnets[0])
 	require.NoError(t, p2pClientA.UnblockSubnet(ctx, subnet))
 
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	require.NoError(t, p2pClientA.BanAddr(ctx, net.IP{123, 123, 123, 123}, "spam", expiry))
+	bans, err = p2pClientA.ListBans(ctx)
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	require.Equal(t, BanSourceManual, bans[0].Source)
+	require.Equal(t, "spam", bans[0].Reason)
+	require.True(t, expiry.Equal(bans[0].Expiry))
+	require.NoError(t, p2pClientA.UnblockAddr(ctx, net.IP{123, 123, 123, 123}))
+
 	// Ask host A for all peer information they have
 	peerDump, err := p2pClientA.Peers(ctx, false)
 	require.Nil(t, err)
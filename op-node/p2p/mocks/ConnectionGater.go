@@ -6,6 +6,8 @@ import (
 	control "github.com/libp2p/go-libp2p/core/control"
 	mock "github.com/stretchr/testify/mock"
 
+	p2p "github.com/ethereum-optimism/optimism/op-node/p2p"
+
 	multiaddr "github.com/multiformats/go-multiaddr"
 
 	net "net"
@@ -13,6 +15,8 @@ import (
 	network "github.com/libp2p/go-libp2p/core/network"
 
 	peer "github.com/libp2p/go-libp2p/core/peer"
+
+	time "time"
 )
 
 // ConnectionGater is an autogenerated mock type for the ConnectionGater type
@@ -20,6 +24,48 @@ type ConnectionGater struct {
 	mock.Mock
 }
 
+// BanAddr provides a mock function with given fields: ip, source, reason, expiry
+func (_m *ConnectionGater) BanAddr(ip net.IP, source p2p.BanSource, reason string, expiry time.Time) error {
+	ret := _m.Called(ip, source, reason, expiry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(net.IP, p2p.BanSource, string, time.Time) error); ok {
+		r0 = rf(ip, source, reason, expiry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BanPeer provides a mock function with given fields: p, source, reason, expiry
+func (_m *ConnectionGater) BanPeer(p peer.ID, source p2p.BanSource, reason string, expiry time.Time) error {
+	ret := _m.Called(p, source, reason, expiry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(peer.ID, p2p.BanSource, string, time.Time) error); ok {
+		r0 = rf(p, source, reason, expiry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BanSubnet provides a mock function with given fields: ipnet, source, reason, expiry
+func (_m *ConnectionGater) BanSubnet(ipnet *net.IPNet, source p2p.BanSource, reason string, expiry time.Time) error {
+	ret := _m.Called(ipnet, source, reason, expiry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*net.IPNet, p2p.BanSource, string, time.Time) error); ok {
+		r0 = rf(ipnet, source, reason, expiry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BlockAddr provides a mock function with given fields: ip
 func (_m *ConnectionGater) BlockAddr(ip net.IP) error {
 	ret := _m.Called(ip)
@@ -139,6 +185,22 @@ func (_m *ConnectionGater) InterceptUpgraded(_a0 network.Conn) (bool, control.Di
 	return r0, r1
 }
 
+// ListBans provides a mock function with given fields:
+func (_m *ConnectionGater) ListBans() []*p2p.BanInfo {
+	ret := _m.Called()
+
+	var r0 []*p2p.BanInfo
+	if rf, ok := ret.Get(0).(func() []*p2p.BanInfo); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*p2p.BanInfo)
+		}
+	}
+
+	return r0
+}
+
 // ListBlockedAddrs provides a mock function with given fields:
 func (_m *ConnectionGater) ListBlockedAddrs() []net.IP {
 	ret := _m.Called()
@@ -187,6 +249,29 @@ func (_m *ConnectionGater) ListBlockedSubnets() []*net.IPNet {
 	return r0
 }
 
+// PeerBan provides a mock function with given fields: p
+func (_m *ConnectionGater) PeerBan(p peer.ID) (*p2p.BanInfo, bool) {
+	ret := _m.Called(p)
+
+	var r0 *p2p.BanInfo
+	if rf, ok := ret.Get(0).(func(peer.ID) *p2p.BanInfo); ok {
+		r0 = rf(p)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*p2p.BanInfo)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(peer.ID) bool); ok {
+		r1 = rf(p)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // UnblockAddr provides a mock function with given fields: ip
 func (_m *ConnectionGater) UnblockAddr(ip net.IP) error {
 	ret := _m.Called(ip)
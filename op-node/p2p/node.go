@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/connmgr"
 	"github.com/libp2p/go-libp2p/core/host"
 	p2pmetrics "github.com/libp2p/go-libp2p/core/metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
 	ma "github.com/multiformats/go-multiaddr"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
@@ -27,11 +30,16 @@ type NodeP2P struct {
 	host    host.Host           // p2p host (optional, may be nil)
 	gater   ConnectionGater     // p2p gater, to ban/unban peers with, may be nil even with p2p enabled
 	connMgr connmgr.ConnManager // p2p conn manager, to keep a reliable number of peers, may be nil even with p2p enabled
+	db      *PeerDB             // p2p persisted peer scores, bans and protected peers, may be nil even with p2p enabled
 	// the below components are all optional, and may be nil. They require the host to not be nil.
-	dv5Local *enode.LocalNode // p2p discovery identity
-	dv5Udp   *discover.UDPv5  // p2p discovery service
-	gs       *pubsub.PubSub   // p2p gossip router
-	gsOut    GossipOut        // p2p gossip application interface for publishing
+	dv5Local  *enode.LocalNode // p2p discovery identity
+	dv5Udp    *discover.UDPv5  // p2p discovery service
+	gs        *pubsub.PubSub   // p2p gossip router
+	gsOut     GossipOut        // p2p gossip application interface for publishing
+	appScores *AppScores       // penalties of peers for bad sync responses, and for their score before a restart
+	// the below components are nil unless req-resp sync is enabled.
+	syncSrv *ReqRespServer // p2p server of unsafe payloads by block number
+	syncCl  *SyncClient    // p2p client to request missing unsafe payloads
 }
 
 // NewNodeP2P creates a new p2p node, and returns a reference to it. If the p2p is disabled, it returns nil.
@@ -72,18 +80,34 @@ func (n *NodeP2P) init(resourcesCtx context.Context, rollupCfg *rollup.Config, l
 		if extra, ok := n.host.(ExtraHostFeatures); ok {
 			n.gater = extra.ConnectionGater()
 			n.connMgr = extra.ConnectionManager()
+			n.db = extra.PeerDB()
 		}
 		// notify of any new connections/streams/etc.
 		n.host.Network().Notify(NewNetworkNotifier(log, metrics))
 		// note: the IDDelta functionality was removed from libP2P, and no longer needs to be explicitly disabled.
-		n.gs, err = NewGossipSub(resourcesCtx, n.host, n.gater, rollupCfg, setup, metrics, log)
+		n.appScores = NewAppScores()
+		if n.db != nil {
+			n.restoreScores(log)
+		}
+		var payloads *signedPayloads
+		if setup.ReqRespSyncEnabled() {
+			payloads = newSignedPayloads()
+		}
+		n.gs, err = NewGossipSub(resourcesCtx, n.host, n.gater, rollupCfg, setup, n.appScores, n.db, metrics, log)
 		if err != nil {
 			return fmt.Errorf("failed to start gossipsub router: %w", err)
 		}
-		n.gsOut, err = JoinGossip(resourcesCtx, n.host.ID(), setup.TopicScoringParams(), n.gs, log, rollupCfg, runCfg, gossipIn)
+		n.gsOut, err = JoinGossip(resourcesCtx, n.host.ID(), setup.TopicScoringParams(), n.gs, log, rollupCfg, runCfg, gossipIn, payloads)
 		if err != nil {
 			return fmt.Errorf("failed to join blocks gossip topic: %w", err)
 		}
+		if setup.ReqRespSyncEnabled() {
+			protocolID := PayloadByNumberProtocolID(rollupCfg.L2ChainID)
+			n.syncSrv = NewReqRespServer(payloads)
+			n.host.SetStreamHandler(protocolID, MakeStreamHandler(resourcesCtx, log.New("serve", "payloads_by_number"), n.syncSrv.HandleSyncRequest))
+			n.syncCl = NewSyncClient(log.New("sync", "payloads_by_number"), rollupCfg, runCfg, n.host.NewStream, n.syncPeers(protocolID), gossipIn.OnUnsafeL2Payload, n.appScores)
+			n.syncCl.Start()
+		}
 		log.Info("started p2p host", "addrs", n.host.Addrs(), "peerID", n.host.ID().Pretty())
 
 		tcpPort, err := FindActiveTCPPort(n.host)
@@ -104,6 +128,53 @@ func (n *NodeP2P) init(resourcesCtx context.Context, rollupCfg *rollup.Config, l
 	return nil
 }
 
+// restoreScores penalizes peers with the negative scores they had before the node restarted,
+// so misbehaving peers do not start with a clean slate. The penalties decay like other application penalties.
+// Scores of peers that were not seen for the peerScoreRetention are deleted.
+func (n *NodeP2P) restoreScores(log log.Logger) {
+	scores, err := n.db.Scores()
+	if err != nil {
+		log.Warn("failed to load persisted peer scores", "err", err)
+		return
+	}
+	now := time.Now()
+	for id, score := range scores {
+		if now.Sub(score.Updated) > peerScoreRetention {
+			if err := n.db.DeleteScore(id); err != nil {
+				log.Warn("failed to delete persisted peer score", "peer", id, "err", err)
+			}
+			continue
+		}
+		n.appScores.Restore(id, score)
+	}
+}
+
+// syncPeers returns a function that lists the connected peers that support the sync protocol.
+func (n *NodeP2P) syncPeers(protocolID protocol.ID) func() []peer.ID {
+	return func() []peer.ID {
+		var peers []peer.ID
+		for _, id := range n.host.Network().Peers() {
+			if protocols, err := n.host.Peerstore().SupportsProtocols(id, protocolID); err == nil && len(protocols) > 0 {
+				peers = append(peers, id)
+			}
+		}
+		return peers
+	}
+}
+
+// AltSyncEnabled returns whether missing unsafe L2 payloads can be requested from peers.
+func (n *NodeP2P) AltSyncEnabled() bool {
+	return n.syncCl != nil
+}
+
+// RequestL2Range requests the unsafe L2 payloads of the blocks in the range [start, end] from peers.
+func (n *NodeP2P) RequestL2Range(ctx context.Context, start, end uint64) error {
+	if !n.AltSyncEnabled() {
+		return errors.New("cannot request L2 range, req-resp sync is disabled")
+	}
+	return n.syncCl.RequestL2Range(ctx, start, end)
+}
+
 func (n *NodeP2P) Host() host.Host {
 	return n.host
 }
@@ -137,6 +208,11 @@ func (n *NodeP2P) Close() error {
 	if n.dv5Udp != nil {
 		n.dv5Udp.Close()
 	}
+	if n.syncCl != nil {
+		if err := n.syncCl.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close p2p sync client cleanly: %w", err))
+		}
+	}
 	if n.gsOut != nil {
 		if err := n.gsOut.Close(); err != nil {
 			result = multierror.Append(result, fmt.Errorf("failed to close gossip cleanly: %w", err))
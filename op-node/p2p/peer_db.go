@@ -0,0 +1,204 @@
+package p2p
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	peerDBScoresPrefix    = "/scores"
+	peerDBBansPrefix      = "/bans"
+	peerDBProtectedPrefix = "/protected"
+)
+
+// keyEncoding encodes ban targets in datastore keys, since subnets contain slashes.
+var keyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// PeerScore is the last known score of a peer.
+type PeerScore struct {
+	Score   float64   `json:"score"`
+	Updated time.Time `json:"updated"`
+}
+
+// PeerDB persists peer scores, bans and protected peers in the peerstore datastore,
+// so they are retained across restarts of the node.
+type PeerDB struct {
+	store ds.Batching
+
+	// protectedLock serializes the read-modify-write of the tags of protected peers.
+	protectedLock sync.Mutex
+}
+
+// NewPeerDB returns a PeerDB that stores its data in a separate namespace of the given datastore.
+func NewPeerDB(store ds.Batching) *PeerDB {
+	return &PeerDB{store: namespace.Wrap(store, ds.NewKey("/optimism/p2p"))}
+}
+
+func (db *PeerDB) put(key ds.Key, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return db.store.Put(context.Background(), key, data)
+}
+
+// list decodes all entries with the given key prefix, with the decode function.
+func (db *PeerDB) list(prefix string, decode func(key ds.Key, value []byte) error) error {
+	res, err := db.store.Query(context.Background(), query.Query{Prefix: prefix})
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+	for r := range res.Next() {
+		if r.Error != nil {
+			return r.Error
+		}
+		if err := decode(ds.RawKey(r.Key), r.Value); err != nil {
+			return fmt.Errorf("failed to decode peer DB entry %s: %w", r.Key, err)
+		}
+	}
+	return nil
+}
+
+func scoreKey(id peer.ID) ds.Key {
+	return ds.NewKey(peerDBScoresPrefix).ChildString(id.String())
+}
+
+// PutScore stores the last known score of the peer.
+func (db *PeerDB) PutScore(id peer.ID, score PeerScore) error {
+	return db.put(scoreKey(id), &score)
+}
+
+// DeleteScore deletes the score of the peer.
+func (db *PeerDB) DeleteScore(id peer.ID) error {
+	return db.store.Delete(context.Background(), scoreKey(id))
+}
+
+// Scores returns the last known scores of all peers.
+func (db *PeerDB) Scores() (map[peer.ID]PeerScore, error) {
+	out := make(map[peer.ID]PeerScore)
+	err := db.list(peerDBScoresPrefix, func(key ds.Key, value []byte) error {
+		id, err := peer.Decode(key.BaseNamespace())
+		if err != nil {
+			return err
+		}
+		var score PeerScore
+		if err := json.Unmarshal(value, &score); err != nil {
+			return err
+		}
+		out[id] = score
+		return nil
+	})
+	return out, err
+}
+
+func banKey(ban *BanInfo) ds.Key {
+	kind, target := ban.target()
+	return ds.NewKey(peerDBBansPrefix).ChildString(kind).ChildString(keyEncoding.EncodeToString([]byte(target)))
+}
+
+// PutBan stores the ban.
+func (db *PeerDB) PutBan(ban *BanInfo) error {
+	return db.put(banKey(ban), ban)
+}
+
+// DeleteBan deletes the ban of the same peer, IP address or subnet as the given ban.
+func (db *PeerDB) DeleteBan(ban *BanInfo) error {
+	return db.store.Delete(context.Background(), banKey(ban))
+}
+
+// Bans returns all stored bans, including expired bans that were not deleted yet.
+func (db *PeerDB) Bans() ([]*BanInfo, error) {
+	var out []*BanInfo
+	err := db.list(peerDBBansPrefix, func(key ds.Key, value []byte) error {
+		var ban BanInfo
+		if err := json.Unmarshal(value, &ban); err != nil {
+			return err
+		}
+		out = append(out, &ban)
+		return nil
+	})
+	return out, err
+}
+
+func protectedKey(id peer.ID) ds.Key {
+	return ds.NewKey(peerDBProtectedPrefix).ChildString(id.String())
+}
+
+// AddProtected records the protection of the peer with the given connection manager tag.
+func (db *PeerDB) AddProtected(id peer.ID, tag string) error {
+	db.protectedLock.Lock()
+	defer db.protectedLock.Unlock()
+	tags, err := db.protectedTags(id)
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		if t == tag {
+			return nil
+		}
+	}
+	return db.put(protectedKey(id), append(tags, tag))
+}
+
+// RemoveProtected removes the protection of the peer with the given connection manager tag.
+func (db *PeerDB) RemoveProtected(id peer.ID, tag string) error {
+	db.protectedLock.Lock()
+	defer db.protectedLock.Unlock()
+	tags, err := db.protectedTags(id)
+	if err != nil {
+		return err
+	}
+	remaining := tags[:0]
+	for _, t := range tags {
+		if t != tag {
+			remaining = append(remaining, t)
+		}
+	}
+	if len(remaining) == 0 {
+		return db.store.Delete(context.Background(), protectedKey(id))
+	}
+	return db.put(protectedKey(id), remaining)
+}
+
+func (db *PeerDB) protectedTags(id peer.ID) ([]string, error) {
+	data, err := db.store.Get(context.Background(), protectedKey(id))
+	if errors.Is(err, ds.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var tags []string
+	if err := json.Unmarshal(data, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Protected returns the connection manager tags of all protected peers.
+func (db *PeerDB) Protected() (map[peer.ID][]string, error) {
+	out := make(map[peer.ID][]string)
+	err := db.list(peerDBProtectedPrefix, func(key ds.Key, value []byte) error {
+		id, err := peer.Decode(key.BaseNamespace())
+		if err != nil {
+			return err
+		}
+		var tags []string
+		if err := json.Unmarshal(value, &tags); err != nil {
+			return err
+		}
+		out[id] = tags
+		return nil
+	})
+	return out, err
+}
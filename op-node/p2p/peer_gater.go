@@ -1,9 +1,11 @@
 package p2p
 
 import (
+	"fmt"
+	"time"
+
 	log "github.com/ethereum/go-ethereum/log"
 	peer "github.com/libp2p/go-libp2p/core/peer"
-	slices "golang.org/x/exp/slices"
 )
 
 // ConnectionFactor is the factor by which we multiply the connection score.
@@ -12,11 +14,15 @@ const ConnectionFactor = -10
 // PeerScoreThreshold is the threshold at which we block a peer.
 const PeerScoreThreshold = -100
 
+// PeerScoreBanDuration is the duration of the ban of a peer with a score below the PeerScoreThreshold.
+const PeerScoreBanDuration = time.Hour
+
 // gater is an internal implementation of the [PeerGater] interface.
 type gater struct {
 	connGater  ConnectionGater
 	log        log.Logger
 	banEnabled bool
+	now        func() time.Time
 }
 
 // PeerGater manages the connection gating of peers.
@@ -33,21 +39,31 @@ func NewPeerGater(connGater ConnectionGater, log log.Logger, banEnabled bool) Pe
 		connGater:  connGater,
 		log:        log,
 		banEnabled: banEnabled,
+		now:        time.Now,
 	}
 }
 
 // Update handles a peer score update and blocks/unblocks the peer if necessary.
+// Peers are banned for the PeerScoreBanDuration, or until their score recovers.
+// Bans of the peer for other reasons are left as-is.
 func (s *gater) Update(id peer.ID, score float64) {
 	// Check if the peer score is below the threshold
 	// If so, we need to block the peer
 	if score < PeerScoreThreshold && s.banEnabled {
-		s.log.Warn("peer blocking enabled, blocking peer", "id", id.String(), "score", score)
-		err := s.connGater.BlockPeer(id)
-		s.log.Warn("connection gater failed to block peer", id.String(), "err", err)
+		if _, banned := s.connGater.PeerBan(id); !banned {
+			s.log.Warn("peer blocking enabled, blocking peer", "id", id.String(), "score", score)
+			reason := fmt.Sprintf("peer score %.2f below threshold %d", score, PeerScoreThreshold)
+			if err := s.connGater.BanPeer(id, BanSourceScore, reason, s.now().Add(PeerScoreBanDuration)); err != nil {
+				s.log.Warn("connection gater failed to block peer", "id", id.String(), "err", err)
+			}
+		}
 	}
 	// Unblock peers whose score has recovered to an acceptable level
-	if (score > PeerScoreThreshold) && slices.Contains(s.connGater.ListBlockedPeers(), id) {
-		err := s.connGater.UnblockPeer(id)
-		s.log.Warn("connection gater failed to unblock peer", id.String(), "err", err)
+	if score > PeerScoreThreshold {
+		if ban, banned := s.connGater.PeerBan(id); banned && ban.Source == BanSourceScore {
+			if err := s.connGater.UnblockPeer(id); err != nil {
+				s.log.Warn("connection gater failed to unblock peer", "id", id.String(), "err", err)
+			}
+		}
 	}
 }
@@ -8,6 +8,7 @@ import (
 	testlog "github.com/ethereum-optimism/optimism/op-node/testlog"
 	log "github.com/ethereum/go-ethereum/log"
 	peer "github.com/libp2p/go-libp2p/core/peer"
+	mock "github.com/stretchr/testify/mock"
 	suite "github.com/stretchr/testify/suite"
 )
 
@@ -44,13 +45,33 @@ func (testSuite *PeerGaterTestSuite) TestPeerGaterUpdate() {
 		true,
 	)
 
-	// Mock a connection gater peer block call
+	// Mock a connection gater peer ban call
 	// Since the peer score is below the [PeerScoreThreshold] of -100,
-	// the [BlockPeer] method should be called
-	testSuite.mockGater.On("BlockPeer", peer.ID("peer1")).Return(nil)
+	// the [BanPeer] method should be called, with the score as reason
+	testSuite.mockGater.On("PeerBan", peer.ID("peer1")).Return(nil, false)
+	testSuite.mockGater.On("BanPeer", peer.ID("peer1"), p2p.BanSourceScore, "peer score -101.00 below threshold -100", mock.Anything).Return(nil)
 
 	// Apply the peer gater update
-	gater.Update(peer.ID("peer1"), float64(-100))
+	gater.Update(peer.ID("peer1"), float64(-101))
+	testSuite.mockGater.AssertExpectations(testSuite.T())
+}
+
+// TestPeerGaterUpdateRecovered tests that only score bans are lifted when the peer score recovers.
+func (testSuite *PeerGaterTestSuite) TestPeerGaterUpdateRecovered() {
+	gater := p2p.NewPeerGater(
+		testSuite.mockGater,
+		testSuite.logger,
+		true,
+	)
+
+	testSuite.mockGater.On("PeerBan", peer.ID("peer1")).Return(&p2p.BanInfo{Peer: peer.ID("peer1"), Source: p2p.BanSourceScore}, true)
+	testSuite.mockGater.On("PeerBan", peer.ID("peer2")).Return(&p2p.BanInfo{Peer: peer.ID("peer2"), Source: p2p.BanSourceManual}, true)
+	testSuite.mockGater.On("UnblockPeer", peer.ID("peer1")).Return(nil)
+
+	gater.Update(peer.ID("peer1"), float64(0))
+	gater.Update(peer.ID("peer2"), float64(0))
+	testSuite.mockGater.AssertExpectations(testSuite.T())
+	testSuite.mockGater.AssertNotCalled(testSuite.T(), "UnblockPeer", peer.ID("peer2"))
 }
 
 // TestPeerGaterUpdateNoBanning tests the peer gater update hook without banning set
@@ -1,16 +1,33 @@
 package p2p
 
 import (
+	"math"
+	"time"
+
 	log "github.com/ethereum/go-ethereum/log"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	peer "github.com/libp2p/go-libp2p/core/peer"
 )
 
+const (
+	// scorePersistDelta is the change of a negative peer score after which the score is persisted again.
+	scorePersistDelta = 1.0
+	// scorePersistInterval is the interval after which an unchanged negative peer score is persisted again,
+	// so it is not deleted after the peerScoreRetention while the peer is still seen.
+	scorePersistInterval = time.Hour
+)
+
 type scorer struct {
 	peerStore Peerstore
 	metricer  GossipMetricer
 	log       log.Logger
 	gater     PeerGater
+	db        *PeerDB
+	now       func() time.Time
+
+	// persisted are the last persisted scores of the peers in the latest snapshot.
+	// Only used by the snapshot hook, which the pubsub library calls from a single goroutine.
+	persisted map[peer.ID]PeerScore
 }
 
 // Peerstore is a subset of the libp2p peerstore.Peerstore interface.
@@ -34,12 +51,16 @@ type Scorer interface {
 }
 
 // NewScorer returns a new peer scorer.
-func NewScorer(peerGater PeerGater, peerStore Peerstore, metricer GossipMetricer, log log.Logger) Scorer {
+// The scores are persisted in the optional PeerDB, to be restored after a restart.
+func NewScorer(peerGater PeerGater, peerStore Peerstore, metricer GossipMetricer, db *PeerDB, log log.Logger) Scorer {
 	return &scorer{
 		peerStore: peerStore,
 		metricer:  metricer,
 		log:       log,
 		gater:     peerGater,
+		db:        db,
+		now:       time.Now,
+		persisted: make(map[peer.ID]PeerScore),
 	}
 }
 
@@ -48,14 +69,50 @@ func NewScorer(peerGater PeerGater, peerStore Peerstore, metricer GossipMetricer
 // The returned [pubsub.ExtendedPeerScoreInspectFn] is called with a mapping of peer IDs to peer score snapshots.
 func (s *scorer) SnapshotHook() pubsub.ExtendedPeerScoreInspectFn {
 	return func(m map[peer.ID]*pubsub.PeerScoreSnapshot) {
+		now := s.now()
 		for id, snap := range m {
 			// Record peer score in the metricer
 			s.metricer.RecordPeerScoring(id, snap.Score)
 
+			// Persist the peer score
+			if s.db != nil {
+				s.persistScore(id, snap.Score, now)
+			}
+
 			// Update with the peer gater
 			s.gater.Update(id, snap.Score)
 		}
+		// Forget the peers that are not scored anymore
+		for id := range s.persisted {
+			if _, ok := m[id]; !ok {
+				delete(s.persisted, id)
+			}
+		}
+	}
+}
+
+// persistScore persists the score of the peer if it changed meaningfully since it was last persisted.
+// Only negative scores are restored after a restart, so a score that recovered is deleted once,
+// and a negative score is written again after it changed by the scorePersistDelta, or after the scorePersistInterval.
+func (s *scorer) persistScore(id peer.ID, score float64, now time.Time) {
+	last, ok := s.persisted[id]
+	var err error
+	if score >= 0 {
+		if ok && last.Score >= 0 {
+			return
+		}
+		err = s.db.DeleteScore(id)
+	} else {
+		if ok && last.Score < 0 && math.Abs(score-last.Score) < scorePersistDelta && now.Sub(last.Updated) < scorePersistInterval {
+			return
+		}
+		err = s.db.PutScore(id, PeerScore{Score: score, Updated: now})
+	}
+	if err != nil {
+		s.log.Warn("failed to persist peer score", "id", id.String(), "err", err)
+		return
 	}
+	s.persisted[id] = PeerScore{Score: score, Updated: now}
 }
 
 // OnConnect is called when a peer connects.
@@ -43,6 +43,7 @@ func (testSuite *PeerScorerTestSuite) TestPeerScorerOnConnect() {
 		testSuite.mockGater,
 		testSuite.mockStore,
 		testSuite.mockMetricer,
+		nil,
 		testSuite.logger,
 	)
 	scorer.OnConnect()
@@ -54,6 +55,7 @@ func (testSuite *PeerScorerTestSuite) TestPeerScorerOnDisconnect() {
 		testSuite.mockGater,
 		testSuite.mockStore,
 		testSuite.mockMetricer,
+		nil,
 		testSuite.logger,
 	)
 	scorer.OnDisconnect()
@@ -65,6 +67,7 @@ func (testSuite *PeerScorerTestSuite) TestSnapshotHook() {
 		testSuite.mockGater,
 		testSuite.mockStore,
 		testSuite.mockMetricer,
+		nil,
 		testSuite.logger,
 	)
 	inspectFn := scorer.SnapshotHook()
@@ -92,6 +95,7 @@ func (testSuite *PeerScorerTestSuite) TestSnapshotHookBlockPeer() {
 		testSuite.mockGater,
 		testSuite.mockStore,
 		testSuite.mockMetricer,
+		nil,
 		testSuite.logger,
 	)
 	inspectFn := scorer.SnapshotHook()
@@ -4,17 +4,28 @@ import (
 	log "github.com/ethereum/go-ethereum/log"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	host "github.com/libp2p/go-libp2p/core/host"
+	peer "github.com/libp2p/go-libp2p/core/peer"
 )
 
-// ConfigurePeerScoring configures the peer scoring parameters for the pubsub
-func ConfigurePeerScoring(h host.Host, g ConnectionGater, gossipConf GossipSetupConfigurables, m GossipMetricer, log log.Logger) []pubsub.Option {
+// ConfigurePeerScoring configures the peer scoring parameters for the pubsub.
+// The optional appScores are added to the app-specific score of the peers.
+// The scores are persisted in the optional PeerDB.
+func ConfigurePeerScoring(h host.Host, g ConnectionGater, gossipConf GossipSetupConfigurables, appScores *AppScores, db *PeerDB, m GossipMetricer, log log.Logger) []pubsub.Option {
 	// If we want to completely disable scoring config here, we can use the [peerScoringParams]
 	// to return early without returning any [pubsub.Option].
 	peerScoreParams := gossipConf.PeerScoringParams()
+	if peerScoreParams != nil && peerScoreParams.AppSpecificScore != nil && appScores != nil {
+		params := *peerScoreParams
+		baseScore := params.AppSpecificScore
+		params.AppSpecificScore = func(p peer.ID) float64 {
+			return baseScore(p) + appScores.Score(p)
+		}
+		peerScoreParams = &params
+	}
 	peerScoreThresholds := NewPeerScoreThresholds()
 	banEnabled := gossipConf.BanPeers()
 	peerGater := NewPeerGater(g, log, banEnabled)
-	scorer := NewScorer(peerGater, h.Peerstore(), m, log)
+	scorer := NewScorer(peerGater, h.Peerstore(), m, db, log)
 	opts := []pubsub.Option{}
 	// Check the app specific score since libp2p doesn't export it's [validate] function :/
 	if peerScoreParams != nil && peerScoreParams.AppSpecificScore != nil {
@@ -80,7 +80,7 @@ func newGossipSubs(testSuite *PeerScoresTestSuite, ctx context.Context, hosts []
 				DecayInterval:     time.Second,
 				DecayToZero:       0.01,
 			},
-		}, testSuite.mockMetricer, logger)...)
+		}, nil, nil, testSuite.mockMetricer, logger)...)
 		ps, err := pubsub.NewGossipSubWithRouter(ctx, h, rt, opts...)
 		if err != nil {
 			panic(err)
@@ -121,7 +121,7 @@ func (testSuite *PeerScoresTestSuite) TestNegativeScores() {
 	testSuite.mockMetricer.On("RecordPeerScoring", mock.Anything, float64(0)).Return(nil)
 	testSuite.mockMetricer.On("RecordPeerScoring", mock.Anything, float64(-1000)).Return(nil)
 
-	testSuite.mockGater.On("ListBlockedPeers").Return([]peer.ID{})
+	testSuite.mockGater.On("PeerBan", mock.Anything).Return(nil, false)
 
 	// Construct 20 hosts using the [getNetHosts] function.
 	hosts := getNetHosts(testSuite, ctx, 20)
@@ -0,0 +1,136 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/hashicorp/go-multierror"
+
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+// DefaultRemoteSignerTimeout is the default timeout of a signing request to the remote signer.
+const DefaultRemoteSignerTimeout = time.Second
+
+// SignerFallbackPolicy determines if blocks are signed with the local key when the remote signer fails.
+type SignerFallbackPolicy string
+
+const (
+	// SignerFallbackNone returns the errors of the remote signer, blocks are never signed with the local key.
+	SignerFallbackNone SignerFallbackPolicy = "none"
+	// SignerFallbackLocal signs blocks with the local key if the remote signer is unreachable or times out.
+	// Blocks that the remote signer refuses to sign are not signed with the local key.
+	SignerFallbackLocal SignerFallbackPolicy = "local"
+)
+
+// RemoteSignerClient is a client of the op-signer service.
+type RemoteSignerClient interface {
+	SignBlockPayload(ctx context.Context, args *opsigner.BlockPayloadArgs) ([65]byte, error)
+	Close()
+}
+
+var _ RemoteSignerClient = (*opsigner.SignerClient)(nil)
+
+// RemoteSigner signs blocks with a key that is held by the op-signer service.
+type RemoteSigner struct {
+	client  RemoteSignerClient
+	address common.Address
+	timeout time.Duration
+}
+
+var _ Signer = (*RemoteSigner)(nil)
+
+// NewRemoteSigner returns a signer that signs with the key of the address through the client,
+// and fails signing requests that take longer than the timeout.
+func NewRemoteSigner(client RemoteSignerClient, address common.Address, timeout time.Duration) *RemoteSigner {
+	return &RemoteSigner{client: client, address: address, timeout: timeout}
+}
+
+func (s *RemoteSigner) Sign(ctx context.Context, domain [32]byte, chainID *big.Int, encodedMsg []byte) (sig *[65]byte, err error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	args := &opsigner.BlockPayloadArgs{
+		Domain:        domain,
+		ChainID:       (*hexutil.Big)(chainID),
+		PayloadHash:   crypto.Keccak256Hash(encodedMsg),
+		SenderAddress: &s.address,
+	}
+	signature, err := s.client.SignBlockPayload(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer failed to sign block: %w", err)
+	}
+	// verify the signature, a misconfigured signer must not get blocks rejected by the network
+	signingHash, err := args.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.SigToPub(signingHash[:], signature[:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature of remote signer: %w", err)
+	}
+	if addr := crypto.PubkeyToAddress(*pub); addr != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s instead of %s", addr, s.address)
+	}
+	return &signature, nil
+}
+
+func (s *RemoteSigner) Close() error {
+	s.client.Close()
+	return nil
+}
+
+// FallbackSigner signs with the remote signer, and falls back to the local signer
+// if the remote signer is unreachable or times out.
+type FallbackSigner struct {
+	log    log.Logger
+	remote Signer
+	local  Signer
+}
+
+var _ Signer = (*FallbackSigner)(nil)
+
+func NewFallbackSigner(log log.Logger, remote Signer, local Signer) *FallbackSigner {
+	return &FallbackSigner{log: log, remote: remote, local: local}
+}
+
+func (s *FallbackSigner) Sign(ctx context.Context, domain [32]byte, chainID *big.Int, encodedMsg []byte) (sig *[65]byte, err error) {
+	sig, err = s.remote.Sign(ctx, domain, chainID, encodedMsg)
+	if err == nil {
+		return sig, nil
+	}
+	// only sign locally if the request did not reach the remote signer. A remote signer that responds
+	// with an error, e.g. when the request is not authorized, or that signs with another key, is reachable
+	// but refuses to sign or is misconfigured. And the caller gave up if the context is done.
+	if !isTransportError(err) || ctx.Err() != nil {
+		return nil, err
+	}
+	s.log.Warn("Remote p2p signer failed, signing block with local key", "err", err)
+	return s.local.Sign(ctx, domain, chainID, encodedMsg)
+}
+
+// isTransportError returns true if the error is a failure to connect to the remote signer, e.g. a dial
+// or TLS handshake failure, or a timeout. Failed HTTP requests are returned as *url.Error, a net.Error,
+// while HTTP error responses (rpc.HTTPError) and JSON-RPC errors (rpc.Error) are not transport errors.
+func isTransportError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (s *FallbackSigner) Close() error {
+	var result *multierror.Error
+	if err := s.remote.Close(); err != nil {
+		result = multierror.Append(result, fmt.Errorf("failed to close remote signer: %w", err))
+	}
+	if err := s.local.Close(); err != nil {
+		result = multierror.Append(result, fmt.Errorf("failed to close local signer: %w", err))
+	}
+	return result.ErrorOrNil()
+}
@@ -0,0 +1,221 @@
+package p2p
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+type testRemoteSignerClient struct {
+	key    *ecdsa.PrivateKey
+	err    error
+	delay  time.Duration
+	closed bool
+}
+
+func (c *testRemoteSignerClient) SignBlockPayload(ctx context.Context, args *opsigner.BlockPayloadArgs) ([65]byte, error) {
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return [65]byte{}, ctx.Err()
+	}
+	if c.err != nil {
+		return [65]byte{}, c.err
+	}
+	hash, err := args.SigningHash()
+	if err != nil {
+		return [65]byte{}, err
+	}
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return [65]byte{}, err
+	}
+	return *(*[65]byte)(sig), nil
+}
+
+func (c *testRemoteSignerClient) Close() {
+	c.closed = true
+}
+
+type testRPCError struct{}
+
+func (testRPCError) Error() string  { return "unauthorized" }
+func (testRPCError) ErrorCode() int { return -32000 }
+
+func recoverSigner(t *testing.T, chainID *big.Int, msg []byte, sig *[65]byte) common.Address {
+	hash, err := SigningHash(SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(hash[:], sig[:])
+	require.NoError(t, err)
+	return crypto.PubkeyToAddress(*pub)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(100)
+	msg := []byte("arbitraryData")
+
+	client := &testRemoteSignerClient{key: key}
+	signer := NewRemoteSigner(client, addr, time.Second)
+	sig, err := signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	require.Equal(t, addr, recoverSigner(t, chainID, msg, sig), "signature must verify like a local signature")
+
+	// signatures of other keys are not returned
+	other := NewRemoteSigner(client, common.Address{0xaa}, time.Second)
+	_, err = other.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.ErrorContains(t, err, "instead of")
+
+	// slow requests time out
+	client.delay = time.Minute
+	_, err = signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, signer.Close())
+	require.True(t, client.closed)
+}
+
+func TestFallbackSigner(t *testing.T) {
+	remoteKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	localKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(100)
+	msg := []byte("arbitraryData")
+
+	client := &testRemoteSignerClient{key: remoteKey}
+	signer := NewFallbackSigner(testlog.Logger(t, log.LvlError),
+		NewRemoteSigner(client, crypto.PubkeyToAddress(remoteKey.PublicKey), 10*time.Millisecond),
+		NewLocalSigner(localKey))
+
+	sig, err := signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(remoteKey.PublicKey), recoverSigner(t, chainID, msg, sig))
+
+	// the block is only signed locally if the remote signer cannot be reached
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tests := []struct {
+		name     string
+		err      error
+		delay    time.Duration
+		key      *ecdsa.PrivateKey
+		fallback bool
+	}{
+		{name: "connection refused", err: &url.Error{Op: "Post", URL: "http://localhost:8080",
+			Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}, fallback: true},
+		{name: "tls failure", err: &url.Error{Op: "Post", URL: "https://localhost:8080",
+			Err: x509.UnknownAuthorityError{}}, fallback: true},
+		{name: "timeout", delay: time.Minute, fallback: true},
+		{name: "unauthorized", err: rpc.HTTPError{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"}},
+		{name: "forbidden", err: rpc.HTTPError{StatusCode: http.StatusForbidden, Status: "403 Forbidden"}},
+		{name: "rpc error", err: testRPCError{}},
+		{name: "wrong key", key: otherKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client.err, client.delay, client.key = tt.err, tt.delay, remoteKey
+			if tt.key != nil {
+				client.key = tt.key
+			}
+			sig, err := signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+			if tt.fallback {
+				require.NoError(t, err)
+				require.Equal(t, crypto.PubkeyToAddress(localKey.PublicKey), recoverSigner(t, chainID, msg, sig))
+			} else {
+				require.Error(t, err)
+				require.Nil(t, sig)
+			}
+		})
+	}
+
+	require.NoError(t, signer.Close())
+	require.True(t, client.closed)
+}
+
+type testBlockSignerService struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *testBlockSignerService) SignBlockPayload(args opsigner.BlockPayloadArgs) (hexutil.Bytes, error) {
+	hash, err := args.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash[:], s.key)
+}
+
+type testHealthService struct{}
+
+func (testHealthService) Status() string { return "v0.0.0" }
+
+func TestFallbackSignerHTTP(t *testing.T) {
+	remoteKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	localKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(100)
+	msg := []byte("arbitraryData")
+	logger := testlog.Logger(t, log.LvlError)
+
+	rpcSrv := rpc.NewServer()
+	require.NoError(t, rpcSrv.RegisterName("health", testHealthService{}))
+	require.NoError(t, rpcSrv.RegisterName("opsigner", &testBlockSignerService{key: remoteKey}))
+	var status atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code := status.Load(); code != 0 {
+			http.Error(w, http.StatusText(int(code)), int(code))
+			return
+		}
+		rpcSrv.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	client, err := opsigner.NewSignerClient(logger, srv.URL, optls.CLIConfig{})
+	require.NoError(t, err)
+	signer := NewFallbackSigner(logger,
+		NewRemoteSigner(client, crypto.PubkeyToAddress(remoteKey.PublicKey), time.Second),
+		NewLocalSigner(localKey))
+	defer signer.Close()
+
+	sig, err := signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(remoteKey.PublicKey), recoverSigner(t, chainID, msg, sig))
+
+	// requests that the remote signer does not authorize are not signed locally
+	for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		status.Store(int32(code))
+		_, err = signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+		var httpErr rpc.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, code, httpErr.StatusCode)
+	}
+
+	// an unreachable remote signer falls back to the local key
+	srv.Close()
+	sig, err = signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(localKey.PublicKey), recoverSigner(t, chainID, msg, sig))
+}
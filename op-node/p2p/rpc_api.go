@@ -52,6 +52,10 @@ type API interface {
 	BlockSubnet(ctx context.Context, ipnet *net.IPNet) error
 	UnblockSubnet(ctx context.Context, ipnet *net.IPNet) error
 	ListBlockedSubnets(ctx context.Context) ([]*net.IPNet, error)
+	BanPeer(ctx context.Context, p peer.ID, reason string, expiry time.Time) error
+	BanAddr(ctx context.Context, ip net.IP, reason string, expiry time.Time) error
+	BanSubnet(ctx context.Context, ipnet *net.IPNet, reason string, expiry time.Time) error
+	ListBans(ctx context.Context) ([]*BanInfo, error)
 	ProtectPeer(ctx context.Context, p peer.ID) error
 	UnprotectPeer(ctx context.Context, p peer.ID) error
 	ConnectPeer(ctx context.Context, addr string) error
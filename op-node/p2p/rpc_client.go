@@ -3,6 +3,7 @@ package p2p
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
@@ -92,6 +93,24 @@ func (c *Client) ListBlockedSubnets(ctx context.Context) ([]*net.IPNet, error) {
 	return out, err
 }
 
+func (c *Client) BanPeer(ctx context.Context, p peer.ID, reason string, expiry time.Time) error {
+	return c.c.CallContext(ctx, nil, prefixRPC("banPeer"), p, reason, expiry)
+}
+
+func (c *Client) BanAddr(ctx context.Context, ip net.IP, reason string, expiry time.Time) error {
+	return c.c.CallContext(ctx, nil, prefixRPC("banAddr"), ip, reason, expiry)
+}
+
+func (c *Client) BanSubnet(ctx context.Context, ipnet *net.IPNet, reason string, expiry time.Time) error {
+	return c.c.CallContext(ctx, nil, prefixRPC("banSubnet"), ipnet, reason, expiry)
+}
+
+func (c *Client) ListBans(ctx context.Context) ([]*BanInfo, error) {
+	var out []*BanInfo
+	err := c.c.CallContext(ctx, &out, prefixRPC("listBans"))
+	return out, err
+}
+
 func (c *Client) ProtectPeer(ctx context.Context, p peer.ID) error {
 	return c.c.CallContext(ctx, nil, prefixRPC("protectPeer"), p)
 }
@@ -334,6 +334,53 @@ func (s *APIBackend) ListBlockedSubnets(_ context.Context) ([]*net.IPNet, error)
 	}
 }
 
+// BanPeer blocks a peer, and records the reason and expiry of the manual ban.
+// A zero expiry bans the peer until it is unblocked.
+func (s *APIBackend) BanPeer(_ context.Context, p peer.ID, reason string, expiry time.Time) error {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_banPeer")
+	defer recordDur()
+	if gater := s.node.ConnectionGater(); gater == nil {
+		return ErrNoConnectionGater
+	} else {
+		return gater.BanPeer(p, BanSourceManual, reason, expiry)
+	}
+}
+
+// BanAddr blocks an IP address, and records the reason and expiry of the manual ban.
+// A zero expiry bans the IP address until it is unblocked.
+func (s *APIBackend) BanAddr(_ context.Context, ip net.IP, reason string, expiry time.Time) error {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_banAddr")
+	defer recordDur()
+	if gater := s.node.ConnectionGater(); gater == nil {
+		return ErrNoConnectionGater
+	} else {
+		return gater.BanAddr(ip, BanSourceManual, reason, expiry)
+	}
+}
+
+// BanSubnet blocks an IP subnet, and records the reason and expiry of the manual ban.
+// A zero expiry bans the IP subnet until it is unblocked.
+func (s *APIBackend) BanSubnet(_ context.Context, ipnet *net.IPNet, reason string, expiry time.Time) error {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_banSubnet")
+	defer recordDur()
+	if gater := s.node.ConnectionGater(); gater == nil {
+		return ErrNoConnectionGater
+	} else {
+		return gater.BanSubnet(ipnet, BanSourceManual, reason, expiry)
+	}
+}
+
+// ListBans lists the active bans of peers, IP addresses and subnets, with their sources, reasons and expiries.
+func (s *APIBackend) ListBans(_ context.Context) ([]*BanInfo, error) {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_listBans")
+	defer recordDur()
+	if gater := s.node.ConnectionGater(); gater == nil {
+		return nil, ErrNoConnectionGater
+	} else {
+		return gater.ListBans(), nil
+	}
+}
+
 func (s *APIBackend) ProtectPeer(_ context.Context, p peer.ID) error {
 	recordDur := s.m.RecordRPCServerRequest("opp2p_protectPeer")
 	defer recordDur()
@@ -4,13 +4,19 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"io"
 	"math/big"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
 )
 
 var SigningDomainBlocksV1 = [32]byte{}
@@ -99,3 +105,97 @@ func (p *PreparedSigner) SetupSigner(ctx context.Context) (Signer, error) {
 type SignerSetup interface {
 	SetupSigner(ctx context.Context) (Signer, error)
 }
+
+// SignerConfig configures a Signer, e.g. to replace the signer of a running sequencer through the admin RPC.
+// Keys are only referenced over RPC, by the path of a key file or the op-signer service that holds the key.
+type SignerConfig struct {
+	// PrivateKey is the hex-encoded private key of a LocalSigner, it is never read from or written to JSON.
+	// With a remote signer, it is only used as fallback if the fallback policy is local.
+	PrivateKey string `json:"-"`
+	// PrivateKeyFile is the path of a file with the hex-encoded private key, an alternative to PrivateKey.
+	PrivateKeyFile string `json:"privateKeyFile,omitempty"`
+	// Remote configures signing with a key held by the op-signer service.
+	Remote opsigner.CLIConfig `json:"remote"`
+	// RemoteTimeout is the timeout of a signing request to the remote signer, DefaultRemoteSignerTimeout if zero.
+	RemoteTimeout time.Duration `json:"remoteTimeout,omitempty"`
+	// Fallback is the policy to sign with the PrivateKey if the remote signer fails, SignerFallbackNone if empty.
+	Fallback SignerFallbackPolicy `json:"fallback,omitempty"`
+}
+
+// Check ensures that the SignerConfig is valid.
+func (c *SignerConfig) Check() error {
+	if err := c.Remote.Check(); err != nil {
+		return err
+	}
+	if c.PrivateKey != "" && c.PrivateKeyFile != "" {
+		return errors.New("both a p2p signer key and key file configured")
+	}
+	hasKey := c.PrivateKey != "" || c.PrivateKeyFile != ""
+	if !hasKey && !c.Remote.Enabled() {
+		return errors.New("no p2p signer key or remote signer configured")
+	}
+	if c.Remote.Enabled() && !common.IsHexAddress(c.Remote.Address) {
+		return fmt.Errorf("invalid remote signer address %q", c.Remote.Address)
+	}
+	switch c.Fallback {
+	case "", SignerFallbackNone:
+		if c.Remote.Enabled() && hasKey {
+			return errors.New("p2p signer key is only used with a remote signer if the fallback policy is local")
+		}
+	case SignerFallbackLocal:
+		if !c.Remote.Enabled() || !hasKey {
+			return errors.New("local fallback policy requires a remote signer and a p2p signer key")
+		}
+	default:
+		return fmt.Errorf("unknown p2p signer fallback policy %q", c.Fallback)
+	}
+	return nil
+}
+
+// NewSigner creates the configured signer: a LocalSigner, a RemoteSigner,
+// or a FallbackSigner of both if the fallback policy is local.
+func (c *SignerConfig) NewSigner(log log.Logger) (Signer, error) {
+	if err := c.Check(); err != nil {
+		return nil, err
+	}
+	var priv *ecdsa.PrivateKey
+	if c.PrivateKey != "" || c.PrivateKeyFile != "" {
+		key := c.PrivateKey
+		if c.PrivateKeyFile != "" {
+			data, err := os.ReadFile(c.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read p2p signer key file: %w", err)
+			}
+			key = strings.TrimSpace(string(data))
+		}
+		var err error
+		// Mnemonics are bad because they leak *all* keys when they leak.
+		// Unencrypted keys from file are bad because they are easy to leak (and we are not checking file permissions).
+		priv, err = crypto.HexToECDSA(strings.TrimPrefix(key, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read p2p signer key: %w", err)
+		}
+		if !c.Remote.Enabled() {
+			return NewLocalSigner(priv), nil
+		}
+	}
+
+	client, err := opsigner.NewSignerClientFromConfig(log, c.Remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+	timeout := c.RemoteTimeout
+	if timeout == 0 {
+		timeout = DefaultRemoteSignerTimeout
+	}
+	address := common.HexToAddress(c.Remote.Address)
+	remote := NewRemoteSigner(client, address, timeout)
+	if priv == nil {
+		return remote, nil
+	}
+	if localAddr := crypto.PubkeyToAddress(priv.PublicKey); localAddr != address {
+		log.Warn("Local fallback key differs from the remote p2p signer, blocks signed with it are only accepted during a key rotation",
+			"local", localAddr, "remote", address)
+	}
+	return NewFallbackSigner(log, remote, NewLocalSigner(priv)), nil
+}
@@ -1,10 +1,19 @@
 package p2p
 
 import (
+	"context"
+	"encoding/json"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/stretchr/testify/require"
 )
 
@@ -65,3 +74,93 @@ func TestSigningHash_LimitChainID(t *testing.T) {
 	_, err := SigningHash(SigningDomainBlocksV1, cfg.L2ChainID, []byte("arbitraryData"))
 	require.ErrorContains(t, err, "chain_id is too large")
 }
+
+func TestSignerConfig(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlError)
+	_, err := (&SignerConfig{}).NewSigner(logger)
+	require.Error(t, err, "no key configured")
+
+	_, err = (&SignerConfig{PrivateKey: "not a key"}).NewSigner(logger)
+	require.Error(t, err, "invalid key")
+
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer, err := (&SignerConfig{PrivateKey: hexutil.Encode(crypto.FromECDSA(priv))}).NewSigner(logger)
+	require.NoError(t, err)
+
+	chainID := big.NewInt(100)
+	msg := []byte("arbitraryData")
+	sig, err := signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	hash, err := SigningHash(SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(hash[:], sig[:])
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(priv.PublicKey), crypto.PubkeyToAddress(*pub))
+	require.NoError(t, signer.Close())
+}
+
+func TestSignerConfigKeyFile(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlError)
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "p2p.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(hexutil.Encode(crypto.FromECDSA(priv))+"\n"), 0600))
+
+	_, err = (&SignerConfig{PrivateKeyFile: filepath.Join(t.TempDir(), "missing.key")}).NewSigner(logger)
+	require.ErrorContains(t, err, "failed to read p2p signer key file")
+
+	signer, err := (&SignerConfig{PrivateKeyFile: keyFile}).NewSigner(logger)
+	require.NoError(t, err)
+	sig, err := signer.Sign(context.Background(), SigningDomainBlocksV1, big.NewInt(100), []byte("arbitraryData"))
+	require.NoError(t, err)
+	hash, err := SigningHash(SigningDomainBlocksV1, big.NewInt(100), []byte("arbitraryData"))
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(hash[:], sig[:])
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(priv.PublicKey), crypto.PubkeyToAddress(*pub))
+	require.NoError(t, signer.Close())
+}
+
+func TestSignerConfigJSON(t *testing.T) {
+	// keys must not be sent over RPC, only the path of a key file
+	var cfg SignerConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"privateKey":"0x01","privateKeyFile":"/keys/p2p.key"}`), &cfg))
+	require.Empty(t, cfg.PrivateKey)
+	require.Equal(t, "/keys/p2p.key", cfg.PrivateKeyFile)
+
+	data, err := json.Marshal(&SignerConfig{PrivateKey: "0x01"})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "0x01")
+}
+
+func TestSignerConfigCheck(t *testing.T) {
+	remote := opsigner.CLIConfig{Endpoint: "http://localhost:8080", Address: "0x9965507D1a55bcC2695C58ba16FB37d819B0A4dc"}
+	tests := []struct {
+		name string
+		cfg  SignerConfig
+		err  string
+	}{
+		{"local", SignerConfig{PrivateKey: "0x01"}, ""},
+		{"local key file", SignerConfig{PrivateKeyFile: "/keys/p2p.key"}, ""},
+		{"key and key file", SignerConfig{PrivateKey: "0x01", PrivateKeyFile: "/keys/p2p.key"}, "both a p2p signer key and key file"},
+		{"remote", SignerConfig{Remote: remote}, ""},
+		{"remote with fallback", SignerConfig{PrivateKey: "0x01", Remote: remote, Fallback: SignerFallbackLocal}, ""},
+		{"remote with key file fallback", SignerConfig{PrivateKeyFile: "/keys/p2p.key", Remote: remote, Fallback: SignerFallbackLocal}, ""},
+		{"remote without address", SignerConfig{Remote: opsigner.CLIConfig{Endpoint: remote.Endpoint}}, "must both be set"},
+		{"invalid remote address", SignerConfig{Remote: opsigner.CLIConfig{Endpoint: remote.Endpoint, Address: "0x01"}}, "invalid remote signer address"},
+		{"unused key", SignerConfig{PrivateKey: "0x01", Remote: remote, Fallback: SignerFallbackNone}, "only used with a remote signer"},
+		{"fallback without key", SignerConfig{Remote: remote, Fallback: SignerFallbackLocal}, "requires a remote signer and a p2p signer key"},
+		{"unknown fallback", SignerConfig{Remote: remote, Fallback: "always"}, "unknown p2p signer fallback policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Check()
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
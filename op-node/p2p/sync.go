@@ -0,0 +1,417 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	lru "github.com/hashicorp/golang-lru"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"golang.org/x/time/rate"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+)
+
+// The payload_by_number protocol serves the signed gossip messages of recent unsafe L2 blocks by block number,
+// so verifiers can fill gaps in their unsafe chain without trusting the peers they request them from.
+//
+// The request is the block number, as 8 byte big-endian uint64.
+// The response is a result byte, followed by the snappy-compressed gossip message of the block
+// (the signature followed by the SSZ-encoded execution payload) if the result is ResultSuccess.
+const (
+	ResultSuccess     byte = 0
+	ResultNotFound    byte = 1
+	ResultRateLimited byte = 2
+)
+
+const (
+	// signedPayloadsCacheSize is the number of recent signed payloads that are kept to serve to peers.
+	signedPayloadsCacheSize = 3600
+
+	serverReadRequestTimeout   = 5 * time.Second
+	serverWriteResponseTimeout = 10 * time.Second
+	// globalServerRateLimit is the maximum number of payloads served per second to all peers combined.
+	globalServerRateLimit rate.Limit = 100
+	globalServerBurst                = 200
+	// peerServerRateLimit is the maximum number of payloads served per second to a single peer.
+	peerServerRateLimit rate.Limit = 10
+	peerServerBurst                = 20
+	// peerLimitersCacheSize is the number of peers that a server rate limiter is kept for.
+	peerLimitersCacheSize = 1000
+
+	clientRequestTimeout = 10 * time.Second
+	// maxRequestAttempts is the maximum number of peers that a single payload is requested from.
+	maxRequestAttempts = 3
+	// syncClientWorkers is the number of payloads that are requested concurrently.
+	syncClientWorkers = 4
+	// maxPendingRequests is the number of payload requests that can be queued.
+	maxPendingRequests = 128
+	// invalidResponsePenalty is subtracted from the app-specific score of a peer for every invalid response.
+	invalidResponsePenalty = 20
+)
+
+var errInvalidResponse = errors.New("invalid response")
+
+func PayloadByNumberProtocolID(l2ChainID *big.Int) protocol.ID {
+	return protocol.ID(fmt.Sprintf("/opstack/req/payload_by_number/%d/0", l2ChainID))
+}
+
+// signedPayloads caches the compressed gossip messages of recent unsafe L2 blocks by block number.
+type signedPayloads struct {
+	cache *lru.Cache // uint64 -> []byte
+}
+
+func newSignedPayloads() *signedPayloads {
+	cache, err := lru.New(signedPayloadsCacheSize)
+	if err != nil {
+		panic(fmt.Errorf("failed to set up signed payloads LRU cache: %w", err))
+	}
+	return &signedPayloads{cache: cache}
+}
+
+// add remembers the gossip message of the block. A later message for the same block number replaces it.
+func (s *signedPayloads) add(number uint64, msg []byte) {
+	s.cache.Add(number, msg)
+}
+
+func (s *signedPayloads) get(number uint64) ([]byte, bool) {
+	v, ok := s.cache.Get(number)
+	if !ok {
+		return nil, false
+	}
+	return v.([]byte), true
+}
+
+// cacheSignedPayloads wraps a blocks validator to add the gossip messages of accepted blocks to the cache.
+func cacheSignedPayloads(payloads *signedPayloads, fn pubsub.ValidatorEx) pubsub.ValidatorEx {
+	return func(ctx context.Context, id peer.ID, message *pubsub.Message) pubsub.ValidationResult {
+		res := fn(ctx, id, message)
+		if payload, ok := message.ValidatorData.(*eth.ExecutionPayload); ok && res == pubsub.ValidationAccept {
+			payloads.add(uint64(payload.BlockNumber), message.Data)
+		}
+		return res
+	}
+}
+
+type StreamHandlerFn func(ctx context.Context, log log.Logger, stream network.Stream)
+
+// MakeStreamHandler wraps a stream handler with the resources context and a logger.
+func MakeStreamHandler(resourcesCtx context.Context, log log.Logger, fn StreamHandlerFn) network.StreamHandler {
+	return func(stream network.Stream) {
+		log := log.New("peer", stream.Conn().RemotePeer(), "remote", stream.Conn().RemoteMultiaddr())
+		defer func() {
+			if err := recover(); err != nil {
+				log.Error("p2p server request handling panic", "err", err, "protocol", stream.Protocol())
+			}
+		}()
+		defer stream.Close()
+		fn(resourcesCtx, log, stream)
+	}
+}
+
+// ReqRespServer serves the payload_by_number protocol from the cache of signed payloads.
+type ReqRespServer struct {
+	payloads *signedPayloads
+
+	globalLimiter *rate.Limiter
+	peerLimiters  *lru.Cache // peer.ID -> *rate.Limiter
+	peerLimitersM sync.Mutex
+}
+
+func NewReqRespServer(payloads *signedPayloads) *ReqRespServer {
+	peerLimiters, err := lru.New(peerLimitersCacheSize)
+	if err != nil {
+		panic(fmt.Errorf("failed to set up peer rate limiters LRU cache: %w", err))
+	}
+	return &ReqRespServer{
+		payloads:      payloads,
+		globalLimiter: rate.NewLimiter(globalServerRateLimit, globalServerBurst),
+		peerLimiters:  peerLimiters,
+	}
+}
+
+func (srv *ReqRespServer) peerLimiter(id peer.ID) *rate.Limiter {
+	srv.peerLimitersM.Lock()
+	defer srv.peerLimitersM.Unlock()
+	if v, ok := srv.peerLimiters.Get(id); ok {
+		return v.(*rate.Limiter)
+	}
+	limiter := rate.NewLimiter(peerServerRateLimit, peerServerBurst)
+	srv.peerLimiters.Add(id, limiter)
+	return limiter
+}
+
+// HandleSyncRequest reads a payload request from the stream, and writes the response.
+// Peers that exceed their rate limit are told so. The global rate limit is
+// shared by all peers, and delays responses instead.
+func (srv *ReqRespServer) HandleSyncRequest(ctx context.Context, log log.Logger, stream network.Stream) {
+	// deadlines are best-effort, not all transports support them
+	if err := stream.SetReadDeadline(time.Now().Add(serverReadRequestTimeout)); err != nil {
+		log.Debug("failed to set read deadline", "err", err)
+	}
+	var req [8]byte
+	if _, err := io.ReadFull(stream, req[:]); err != nil {
+		log.Debug("failed to read payload request", "err", err)
+		return
+	}
+	number := binary.BigEndian.Uint64(req[:])
+
+	if err := stream.SetWriteDeadline(time.Now().Add(serverWriteResponseTimeout)); err != nil {
+		log.Debug("failed to set write deadline", "err", err)
+	}
+	if !srv.peerLimiter(stream.Conn().RemotePeer()).Allow() {
+		log.Debug("peer exceeded payload request rate limit", "number", number)
+		_, _ = stream.Write([]byte{ResultRateLimited})
+		return
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, serverWriteResponseTimeout)
+	err := srv.globalLimiter.Wait(waitCtx)
+	cancel()
+	if err != nil {
+		log.Debug("global payload request rate limit exceeded", "number", number, "err", err)
+		_, _ = stream.Write([]byte{ResultRateLimited})
+		return
+	}
+
+	msg, ok := srv.payloads.get(number)
+	if !ok {
+		log.Debug("requested payload not found", "number", number)
+		_, _ = stream.Write([]byte{ResultNotFound})
+		return
+	}
+	if _, err := stream.Write(append([]byte{ResultSuccess}, msg...)); err != nil {
+		log.Debug("failed to write payload response", "number", number, "err", err)
+		return
+	}
+	log.Debug("served payload", "number", number)
+}
+
+type newStreamFn func(ctx context.Context, p peer.ID, pids ...protocol.ID) (network.Stream, error)
+
+type receivePayload = func(ctx context.Context, from peer.ID, payload *eth.ExecutionPayload) error
+
+// SyncClient requests missing unsafe L2 payloads from peers with the payload_by_number protocol.
+// Responses are verified like gossip, by the signature of the sequencer, and peers that respond
+// with invalid payloads are penalized.
+type SyncClient struct {
+	log    log.Logger
+	cfg    *rollup.Config
+	runCfg GossipRuntimeConfig
+
+	protocolID protocol.ID
+	newStream  newStreamFn
+	// peers returns the peers that support the payload_by_number protocol
+	peers          func() []peer.ID
+	receivePayload receivePayload
+	scores         *AppScores
+
+	requests   chan uint64
+	inFlight   map[uint64]struct{}
+	inFlightMu sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewSyncClient(log log.Logger, cfg *rollup.Config, runCfg GossipRuntimeConfig, newStream newStreamFn, peers func() []peer.ID, rcv receivePayload, scores *AppScores) *SyncClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SyncClient{
+		log:            log,
+		cfg:            cfg,
+		runCfg:         runCfg,
+		protocolID:     PayloadByNumberProtocolID(cfg.L2ChainID),
+		newStream:      newStream,
+		peers:          peers,
+		receivePayload: rcv,
+		scores:         scores,
+		requests:       make(chan uint64, maxPendingRequests),
+		inFlight:       make(map[uint64]struct{}),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+func (s *SyncClient) Start() {
+	for i := 0; i < syncClientWorkers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+}
+
+func (s *SyncClient) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// RequestL2Range queues requests for the payloads of the blocks in the range [start, end].
+// Blocks that are already requested are skipped. If the queue is full, the remaining
+// blocks are not requested, and should be requested again later.
+func (s *SyncClient) RequestL2Range(ctx context.Context, start, end uint64) error {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+	for number := start; number <= end; number++ {
+		if _, ok := s.inFlight[number]; ok {
+			continue
+		}
+		select {
+		case s.requests <- number:
+			s.inFlight[number] = struct{}{}
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *SyncClient) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case number := <-s.requests:
+			s.fetch(number)
+			s.inFlightMu.Lock()
+			delete(s.inFlight, number)
+			s.inFlightMu.Unlock()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// fetch requests the payload from random peers, until a valid payload is received or
+// maxRequestAttempts peers were tried. Received payloads are passed on like gossip.
+func (s *SyncClient) fetch(number uint64) {
+	peers := s.peers()
+	if len(peers) == 0 {
+		s.log.Debug("no peers to request payload from", "number", number)
+		return
+	}
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if len(peers) > maxRequestAttempts {
+		peers = peers[:maxRequestAttempts]
+	}
+
+	for _, id := range peers {
+		payload, err := s.request(s.ctx, id, number)
+		if err != nil {
+			if errors.Is(err, errInvalidResponse) {
+				s.log.Warn("peer responded with invalid payload", "peer", id, "number", number, "err", err)
+				s.scores.Penalize(id, invalidResponsePenalty)
+			} else {
+				s.log.Debug("failed to request payload from peer", "peer", id, "number", number, "err", err)
+			}
+			continue
+		}
+		s.log.Info("Received unsafe payload from peer", "peer", id, "payload", payload.ID())
+		if err := s.receivePayload(s.ctx, id, payload); err != nil {
+			s.log.Warn("failed to process payload from peer", "peer", id, "payload", payload.ID(), "err", err)
+		}
+		return
+	}
+}
+
+// request requests the payload of the block from the peer, and verifies the response.
+// An error wrapping errInvalidResponse is returned if the peer misbehaved.
+func (s *SyncClient) request(ctx context.Context, id peer.ID, number uint64) (*eth.ExecutionPayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, clientRequestTimeout)
+	defer cancel()
+	stream, err := s.newStream(ctx, id, s.protocolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stream: %w", err)
+	}
+	defer stream.Close()
+	// deadlines are best-effort, not all transports support them
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetDeadline(deadline); err != nil {
+			s.log.Debug("failed to set stream deadline", "peer", id, "err", err)
+		}
+	}
+
+	var req [8]byte
+	binary.BigEndian.PutUint64(req[:], number)
+	if _, err := stream.Write(req[:]); err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+	if err := stream.CloseWrite(); err != nil {
+		return nil, fmt.Errorf("failed to close stream for writing: %w", err)
+	}
+
+	var result [1]byte
+	if _, err := io.ReadFull(stream, result[:]); err != nil {
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+	switch result[0] {
+	case ResultSuccess:
+	case ResultNotFound:
+		return nil, errors.New("payload not found")
+	case ResultRateLimited:
+		return nil, errors.New("rate limited")
+	default:
+		return nil, fmt.Errorf("%w: unknown result %d", errInvalidResponse, result[0])
+	}
+
+	// a valid compressed message is never larger than the max gossip message
+	compressed, err := io.ReadAll(io.LimitReader(stream, maxGossipSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload: %w", err)
+	}
+	if len(compressed) > maxGossipSize {
+		return nil, fmt.Errorf("%w: compressed payload too large", errInvalidResponse)
+	}
+	return s.verifyPayload(id, number, compressed)
+}
+
+// verifyPayload decodes the compressed gossip message, and checks that it is signed by the
+// sequencer and contains the valid payload of the requested block.
+func (s *SyncClient) verifyPayload(id peer.ID, number uint64, compressed []byte) (*eth.ExecutionPayload, error) {
+	outLen, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid snappy compression length: %v", errInvalidResponse, err)
+	}
+	if outLen > maxGossipSize || outLen < minGossipSize {
+		return nil, fmt.Errorf("%w: invalid payload size %d", errInvalidResponse, outLen)
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid snappy compression: %v", errInvalidResponse, err)
+	}
+	signatureBytes, payloadBytes := data[:65], data[65:]
+
+	switch verifyBlockSignature(s.log, s.cfg, s.runCfg, id, signatureBytes, payloadBytes) {
+	case pubsub.ValidationAccept:
+	case pubsub.ValidationIgnore:
+		return nil, errors.New("cannot verify payload signature")
+	default:
+		return nil, fmt.Errorf("%w: invalid signature", errInvalidResponse)
+	}
+
+	var payload eth.ExecutionPayload
+	if err := payload.UnmarshalSSZ(uint32(len(payloadBytes)), bytes.NewReader(payloadBytes)); err != nil {
+		return nil, fmt.Errorf("%w: invalid payload encoding: %v", errInvalidResponse, err)
+	}
+	if uint64(payload.BlockNumber) != number {
+		return nil, fmt.Errorf("%w: requested block %d, got %s", errInvalidResponse, number, payload.ID())
+	}
+	if actual, ok := payload.CheckBlockHash(); !ok {
+		return nil, fmt.Errorf("%w: bad block hash %s, actual %s", errInvalidResponse, payload.BlockHash, actual)
+	}
+	return &payload, nil
+}
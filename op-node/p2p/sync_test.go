@@ -0,0 +1,183 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+)
+
+// signedPayload returns the compressed gossip message of a valid payload of the block, signed by the key.
+func signedPayload(t *testing.T, cfg *rollup.Config, key *ecdsa.PrivateKey, number uint64) (*eth.ExecutionPayload, []byte) {
+	payload := &eth.ExecutionPayload{
+		ParentHash:  common.Hash{0xaa},
+		BlockNumber: eth.Uint64Quantity(number),
+		GasLimit:    30_000_000,
+		Timestamp:   eth.Uint64Quantity(1000 + 2*number),
+	}
+	payload.BlockHash, _ = payload.CheckBlockHash()
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 65))
+	_, err := payload.MarshalSSZ(&buf)
+	require.NoError(t, err)
+	data := buf.Bytes()
+	sig, err := NewLocalSigner(key).Sign(context.Background(), SigningDomainBlocksV1, cfg.L2ChainID, data[65:])
+	require.NoError(t, err)
+	copy(data[:65], sig[:])
+	return payload, snappy.Encode(nil, data)
+}
+
+type syncTestSetup struct {
+	cfg      *rollup.Config
+	key      *ecdsa.PrivateKey
+	payloads *signedPayloads
+	scores   *AppScores
+	client   *SyncClient
+	received chan *eth.ExecutionPayload
+	server   host.Host
+}
+
+func setupSync(t *testing.T) *syncTestSetup {
+	mnet, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err, "failed to setup mocknet")
+	t.Cleanup(func() { _ = mnet.Close() })
+	hostA, hostB := mnet.Hosts()[0], mnet.Hosts()[1]
+
+	logger := testlog.Logger(t, log.LvlError)
+	cfg := &rollup.Config{L2ChainID: big.NewInt(901)}
+	key := testutils.RandomKey()
+	runCfg := &testutils.MockRuntimeConfig{P2PSeqAddress: crypto.PubkeyToAddress(key.PublicKey)}
+
+	payloads := newSignedPayloads()
+	srv := NewReqRespServer(payloads)
+	hostB.SetStreamHandler(PayloadByNumberProtocolID(cfg.L2ChainID), MakeStreamHandler(context.Background(), logger, srv.HandleSyncRequest))
+
+	received := make(chan *eth.ExecutionPayload, 10)
+	rcv := func(ctx context.Context, from peer.ID, payload *eth.ExecutionPayload) error {
+		require.Equal(t, hostB.ID(), from)
+		received <- payload
+		return nil
+	}
+	scores := NewAppScores()
+	cl := NewSyncClient(logger, cfg, runCfg, hostA.NewStream, func() []peer.ID { return []peer.ID{hostB.ID()} }, rcv, scores)
+	cl.Start()
+	t.Cleanup(func() { _ = cl.Close() })
+
+	return &syncTestSetup{
+		cfg:      cfg,
+		key:      key,
+		payloads: payloads,
+		scores:   scores,
+		client:   cl,
+		received: received,
+		server:   hostB,
+	}
+}
+
+func TestSyncRequestL2Range(t *testing.T) {
+	s := setupSync(t)
+	expected := make(map[common.Hash]bool)
+	for i := uint64(10); i <= 12; i++ {
+		payload, msg := signedPayload(t, s.cfg, s.key, i)
+		s.payloads.add(i, msg)
+		expected[payload.BlockHash] = true
+	}
+
+	require.NoError(t, s.client.RequestL2Range(context.Background(), 10, 12))
+	for i := 0; i < 3; i++ {
+		select {
+		case payload := <-s.received:
+			require.True(t, expected[payload.BlockHash], "unexpected payload %s", payload.ID())
+			delete(expected, payload.BlockHash)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for payload")
+		}
+	}
+	require.Zero(t, s.scores.Score(s.server.ID()))
+}
+
+func TestSyncPayloadNotFound(t *testing.T) {
+	s := setupSync(t)
+	_, err := s.client.request(context.Background(), s.server.ID(), 10)
+	require.ErrorContains(t, err, "not found")
+	require.NotErrorIs(t, err, errInvalidResponse)
+}
+
+func TestSyncInvalidResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  func(s *syncTestSetup) []byte
+	}{
+		{"wrong signer", func(s *syncTestSetup) []byte {
+			_, msg := signedPayload(t, s.cfg, testutils.RandomKey(), 10)
+			return msg
+		}},
+		{"wrong block", func(s *syncTestSetup) []byte {
+			_, msg := signedPayload(t, s.cfg, s.key, 11)
+			return msg
+		}},
+		{"invalid compression", func(s *syncTestSetup) []byte {
+			return []byte("not snappy")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := setupSync(t)
+			s.payloads.add(10, tt.msg(s))
+
+			_, err := s.client.request(context.Background(), s.server.ID(), 10)
+			require.ErrorIs(t, err, errInvalidResponse)
+
+			s.client.fetch(10)
+			require.Empty(t, s.received)
+			require.Less(t, s.scores.Score(s.server.ID()), float64(0))
+		})
+	}
+}
+
+func TestSyncServerRateLimit(t *testing.T) {
+	s := setupSync(t)
+	_, msg := signedPayload(t, s.cfg, s.key, 10)
+	s.payloads.add(10, msg)
+
+	for i := 0; i < peerServerBurst; i++ {
+		_, err := s.client.request(context.Background(), s.server.ID(), 10)
+		require.NoError(t, err)
+	}
+	_, err := s.client.request(context.Background(), s.server.ID(), 10)
+	require.ErrorContains(t, err, "rate limited")
+	require.NotErrorIs(t, err, errInvalidResponse)
+}
+
+func TestAppScoresDecay(t *testing.T) {
+	scores := NewAppScores()
+	now := time.Unix(1000, 0)
+	scores.now = func() time.Time { return now }
+
+	require.Zero(t, scores.Score("alice"))
+	scores.Penalize("alice", 20)
+	scores.Penalize("alice", 20)
+	require.Equal(t, float64(-40), scores.Score("alice"))
+	require.Zero(t, scores.Score("bob"))
+
+	now = now.Add(appScoresHalfLife)
+	require.InDelta(t, -20, scores.Score("alice"), 1e-9)
+}
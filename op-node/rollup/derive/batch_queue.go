@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/log"
 
@@ -143,6 +144,31 @@ func (bq *BatchQueue) AddBatch(batch *BatchData, l2SafeHead eth.L2BlockRef) {
 	bq.batches[batch.Timestamp] = append(bq.batches[batch.Timestamp], &data)
 }
 
+// BufferedBatches returns the state of the buffered batches, ordered by timestamp, for debugging.
+// Batches with the same timestamp are ordered by when they were first seen.
+func (bq *BatchQueue) BufferedBatches() []BatchState {
+	timestamps := make([]uint64, 0, len(bq.batches))
+	for ts := range bq.batches {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+	out := make([]BatchState, 0)
+	for _, ts := range timestamps {
+		for _, b := range bq.batches[ts] {
+			out = append(out, BatchState{
+				L1InclusionBlock: b.L1InclusionBlock,
+				ParentHash:       b.Batch.ParentHash,
+				Epoch:            b.Batch.Epoch(),
+				Timestamp:        b.Batch.Timestamp,
+				Transactions:     len(b.Batch.Transactions),
+			})
+		}
+	}
+	return out
+}
+
 // deriveNextBatch derives the next batch to apply on top of the current L2 safe head,
 // following the validity rules imposed on consecutive batches,
 // based on currently available buffered batch and L1 origin information.
@@ -30,15 +30,25 @@ type DataSourceFactory struct {
 	log     log.Logger
 	cfg     *rollup.Config
 	fetcher L1TransactionFetcher
+	daStore DAStore
+	metrics Metrics
 }
 
-func NewDataSourceFactory(log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher) *DataSourceFactory {
-	return &DataSourceFactory{log: log, cfg: cfg, fetcher: fetcher}
+// NewDataSourceFactory creates a DataSourceFactory. The DA store is only used,
+// and must be non-nil, if DA commitments are enabled in the rollup config.
+func NewDataSourceFactory(log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher, daStore DAStore, metrics Metrics) *DataSourceFactory {
+	return &DataSourceFactory{log: log, cfg: cfg, fetcher: fetcher, daStore: daStore, metrics: metrics}
 }
 
 // OpenData returns a CalldataSourceImpl. This struct implements the `Next` function.
-func (ds *DataSourceFactory) OpenData(ctx context.Context, id eth.BlockID, batcherAddr common.Address) DataIter {
-	return NewDataSource(ctx, ds.log, ds.cfg, ds.fetcher, id, batcherAddr)
+// If DA commitments are active at the time of the L1 block, the calldata source is wrapped
+// to retrieve the committed data from the DA store.
+func (ds *DataSourceFactory) OpenData(ctx context.Context, ref eth.L1BlockRef, batcherAddr common.Address) DataIter {
+	src := NewDataSource(ctx, ds.log, ds.cfg, ds.fetcher, ref.ID(), batcherAddr)
+	if ds.cfg.IsDACommitments(ref.Time) {
+		return NewDACommitmentSource(ds.log.New("origin", ref), src, ds.daStore, ds.metrics)
+	}
+	return src
 }
 
 // DataSource is a fault tolerant approach to fetching data.
@@ -57,6 +57,28 @@ func (cb *ChannelBank) Origin() eth.L1BlockRef {
 	return cb.prev.Origin()
 }
 
+// OpenChannels returns the state of the buffered channels, in FIFO order, for debugging.
+func (cb *ChannelBank) OpenChannels() []ChannelState {
+	origin := cb.Origin()
+	out := make([]ChannelState, 0, len(cb.channelQueue))
+	for _, id := range cb.channelQueue {
+		ch := cb.channels[id]
+		var age uint64
+		if origin.Number > ch.openBlock.Number {
+			age = origin.Number - ch.openBlock.Number
+		}
+		out = append(out, ChannelState{
+			ID:             id,
+			OpenBlock:      ch.openBlock,
+			FramesReceived: len(ch.inputs),
+			Size:           ch.size,
+			Closed:         ch.closed,
+			Age:            age,
+		})
+	}
+	return out
+}
+
 func (cb *ChannelBank) prune() {
 	// check total size
 	totalSize := uint64(0)
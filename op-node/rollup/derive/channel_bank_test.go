@@ -176,3 +176,36 @@ func TestChannelBankDuplicates(t *testing.T) {
 	require.Nil(t, out)
 	require.Equal(t, io.EOF, err)
 }
+
+func TestChannelBankOpenChannels(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	a := testutils.RandomBlockRef(rng)
+
+	input := &fakeChannelBankInput{origin: a}
+	input.AddFrames("a:0:first", "b:1:second")
+	input.AddFrame(Frame{}, io.EOF)
+
+	cfg := &rollup.Config{ChannelTimeout: 10}
+
+	cb := NewChannelBank(testlog.Logger(t, log.LvlCrit), cfg, input, nil)
+	require.Empty(t, cb.OpenChannels())
+
+	// Load both frames
+	_, err := cb.NextData(context.Background())
+	require.ErrorIs(t, err, NotEnoughData)
+	_, err = cb.NextData(context.Background())
+	require.ErrorIs(t, err, NotEnoughData)
+
+	input.origin.Number += 3
+	channels := cb.OpenChannels()
+	require.Len(t, channels, 2)
+	require.Equal(t, testFrame("a:0:first").ChannelID(), channels[0].ID)
+	require.Equal(t, testFrame("b:1:second").ChannelID(), channels[1].ID)
+	for _, ch := range channels {
+		require.Equal(t, a, ch.OpenBlock)
+		require.Equal(t, 1, ch.FramesReceived)
+		require.NotZero(t, ch.Size)
+		require.False(t, ch.Closed)
+		require.Equal(t, uint64(3), ch.Age)
+	}
+}
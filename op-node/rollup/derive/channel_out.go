@@ -2,7 +2,6 @@ package derive
 
 import (
 	"bytes"
-	"compress/zlib"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -24,8 +23,12 @@ type ChannelOut struct {
 	// rlpLength is the uncompressed size of the channel. Must be less than MAX_RLP_BYTES_PER_CHANNEL
 	rlpLength int
 
+	// Compression algorithm, and whether the channel data is prefixed with its
+	// version byte. Legacy channels are unversioned zlib streams.
+	algo      CompressionAlgo
+	versioned bool
 	// Compressor stage. Write input data to it
-	compress *zlib.Writer
+	compress Compressor
 	// post compression buffer
 	buf bytes.Buffer
 
@@ -36,18 +39,35 @@ func (co *ChannelOut) ID() ChannelID {
 	return co.id
 }
 
+// NewChannelOut creates a legacy channel out, which compresses the channel
+// data with zlib and doesn't prefix it with a version byte.
 func NewChannelOut() (*ChannelOut, error) {
+	return newChannelOut(Zlib, false)
+}
+
+// NewVersionedChannelOut creates a channel out, which compresses the channel
+// data with the given algorithm and prefixes it with its version byte.
+// Versioned channels are only valid after the channel compression upgrade
+// activated, see rollup.Config.IsChannelCompression.
+func NewVersionedChannelOut(algo CompressionAlgo) (*ChannelOut, error) {
+	return newChannelOut(algo, true)
+}
+
+func newChannelOut(algo CompressionAlgo, versioned bool) (*ChannelOut, error) {
 	c := &ChannelOut{
 		id:        ChannelID{}, // TODO: use GUID here instead of fully random data
 		frame:     0,
 		rlpLength: 0,
+		algo:      algo,
+		versioned: versioned,
 	}
 	_, err := rand.Read(c.id[:])
 	if err != nil {
 		return nil, err
 	}
 
-	compress, err := zlib.NewWriterLevel(&c.buf, zlib.BestCompression)
+	c.writeVersion()
+	compress, err := NewCompressor(algo, &c.buf)
 	if err != nil {
 		return nil, err
 	}
@@ -56,11 +76,30 @@ func NewChannelOut() (*ChannelOut, error) {
 	return c, nil
 }
 
+// Algo returns the compression algorithm of the channel.
+func (co *ChannelOut) Algo() CompressionAlgo {
+	return co.algo
+}
+
+// Versioned returns whether the channel data is prefixed with a version byte.
+func (co *ChannelOut) Versioned() bool {
+	return co.versioned
+}
+
+// writeVersion writes the version byte to the empty output buffer, if the
+// channel is versioned.
+func (co *ChannelOut) writeVersion() {
+	if co.versioned {
+		co.buf.WriteByte(byte(co.algo))
+	}
+}
+
 // TODO: reuse ChannelOut for performance
 func (co *ChannelOut) Reset() error {
 	co.frame = 0
 	co.rlpLength = 0
 	co.buf.Reset()
+	co.writeVersion()
 	co.compress.Reset(&co.buf)
 	co.closed = false
 	_, err := rand.Read(co.id[:])
@@ -152,8 +191,7 @@ func (co *ChannelOut) OutputFrame(w *bytes.Buffer, maxSize uint64) (uint16, erro
 
 	// Copy data from the local buffer into the frame data buffer
 	// Don't go past the maxSize with the fixed frame overhead.
-	// Fixed overhead: 16 + 2 + 4 + 1 = 23 bytes.
-	maxDataSize := maxSize - 23
+	maxDataSize := maxSize - FrameV0OverHeadSize
 	if maxDataSize > uint64(co.buf.Len()) {
 		maxDataSize = uint64(co.buf.Len())
 		// If we are closed & will not spill past the current frame
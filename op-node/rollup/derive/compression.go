@@ -0,0 +1,122 @@
+package derive
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionAlgo is the compression algorithm of channel data. Its value is
+// the version byte that prefixes the data of versioned channels.
+//
+// Legacy channels, which are the only valid channels before the channel
+// compression upgrade, don't have a version byte and are always zlib
+// compressed.
+type CompressionAlgo byte
+
+const (
+	Zlib CompressionAlgo = 0x00
+	Zstd CompressionAlgo = 0x01
+)
+
+var ErrUnknownCompressionAlgo = errors.New("unknown compression algorithm")
+
+// zlibCMDeflate is the compression method in the lower 4 bits of the first
+// byte (CMF) of every zlib stream. It can never be a valid version byte, which
+// allows legacy channels to be distinguished from versioned ones.
+const zlibCMDeflate = 8
+
+var compressionAlgoNames = map[CompressionAlgo]string{
+	Zlib: "zlib",
+	Zstd: "zstd",
+}
+
+func (a CompressionAlgo) String() string {
+	if name, ok := compressionAlgoNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", byte(a))
+}
+
+// ParseCompressionAlgo parses the name of a compression algorithm.
+func ParseCompressionAlgo(name string) (CompressionAlgo, error) {
+	for algo, n := range compressionAlgoNames {
+		if n == name {
+			return algo, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownCompressionAlgo, name)
+}
+
+// Compressor is a streaming compressor of channel data.
+type Compressor interface {
+	io.WriteCloser
+	// Flush writes any buffered data to the underlying writer, without closing
+	// the compression stream.
+	Flush() error
+	// Reset discards the compressor state and makes it write to w, as if it
+	// was newly created.
+	Reset(w io.Writer)
+}
+
+// NewCompressor creates a new compressor of the given algorithm, which writes
+// its output to w. The compressors are configured for the best compression,
+// since channel data is size- and not speed-critical.
+func NewCompressor(algo CompressionAlgo, w io.Writer) (Compressor, error) {
+	switch algo {
+	case Zlib:
+		zw, err := zlib.NewWriterLevel(w, zlib.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		return zw, nil
+	case Zstd:
+		zw, err := zstd.NewWriter(w,
+			zstd.WithEncoderLevel(zstd.SpeedBestCompression),
+			// single-threaded streaming, the compressor is flushed regularly anyway
+			zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return zw, nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnknownCompressionAlgo, algo)
+	}
+}
+
+// NewDecompressor returns a reader of the decompressed channel data read from
+// r. If versioned is false, the data must be a legacy zlib stream. Otherwise,
+// the data may also be a versioned channel, starting with the version byte
+// of its compression algorithm.
+func NewDecompressor(r io.Reader, versioned bool) (io.Reader, error) {
+	var first [1]byte
+	if _, err := io.ReadFull(r, first[:]); err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+	if !versioned || first[0]&0x0f == zlibCMDeflate {
+		return zlib.NewReader(io.MultiReader(bytes.NewReader(first[:]), r))
+	}
+
+	switch algo := CompressionAlgo(first[0]); algo {
+	case Zlib:
+		return zlib.NewReader(r)
+	case Zstd:
+		dec, err := zstd.NewReader(r,
+			// decode synchronously, so that no goroutines are left behind
+			zstd.WithDecoderConcurrency(1),
+			// limit the window size, the total output is limited by the RLP reader
+			zstd.WithDecoderMaxMemory(MaxRLPBytesPerChannel))
+		if err != nil {
+			return nil, err
+		}
+		return dec, nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnknownCompressionAlgo, algo)
+	}
+}
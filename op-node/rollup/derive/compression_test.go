@@ -0,0 +1,110 @@
+package derive
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+)
+
+func randomBatch(rng *rand.Rand) *BatchData {
+	txs := make([]hexutil.Bytes, 1+rng.Intn(10))
+	for i := range txs {
+		txs[i] = testutils.RandomData(rng, 100)
+	}
+	return &BatchData{BatchV1{
+		ParentHash:   testutils.RandomHash(rng),
+		EpochNum:     rollup.Epoch(rng.Uint64()),
+		EpochHash:    testutils.RandomHash(rng),
+		Timestamp:    rng.Uint64(),
+		Transactions: txs,
+	}}
+}
+
+// channelData returns the full data of a channel containing the given batches.
+func channelData(t *testing.T, co *ChannelOut, batches []*BatchData) []byte {
+	for _, b := range batches {
+		_, err := co.AddBatch(b)
+		require.NoError(t, err)
+	}
+	require.NoError(t, co.Close())
+
+	var buf bytes.Buffer
+	_, err := co.OutputFrame(&buf, 1_000_000)
+	require.Equal(t, io.EOF, err, "all data must fit into a single frame")
+	var f Frame
+	require.NoError(t, f.UnmarshalBinary(&buf))
+	return f.Data
+}
+
+func readBatches(t *testing.T, data []byte, versioned bool) []*BatchData {
+	br, err := BatchReader(bytes.NewReader(data), eth.L1BlockRef{}, versioned)
+	require.NoError(t, err)
+	var batches []*BatchData
+	for {
+		b, err := br()
+		if err == io.EOF {
+			return batches
+		}
+		require.NoError(t, err)
+		batches = append(batches, b.Batch)
+	}
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	batches := []*BatchData{randomBatch(rng), randomBatch(rng), randomBatch(rng)}
+
+	t.Run("legacy", func(t *testing.T) {
+		co, err := NewChannelOut()
+		require.NoError(t, err)
+		require.False(t, co.Versioned())
+		data := channelData(t, co, batches)
+
+		// legacy channels are valid before and after the upgrade
+		require.Equal(t, batches, readBatches(t, data, false))
+		require.Equal(t, batches, readBatches(t, data, true))
+	})
+
+	for _, algo := range []CompressionAlgo{Zlib, Zstd} {
+		algo := algo
+		t.Run("versioned "+algo.String(), func(t *testing.T) {
+			co, err := NewVersionedChannelOut(algo)
+			require.NoError(t, err)
+			require.True(t, co.Versioned())
+			data := channelData(t, co, batches)
+			require.Equal(t, byte(algo), data[0])
+
+			require.Equal(t, batches, readBatches(t, data, true))
+
+			// versioned channels are invalid before the upgrade
+			_, err = BatchReader(bytes.NewReader(data), eth.L1BlockRef{}, false)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDecompressorUnknownAlgo(t *testing.T) {
+	_, err := NewDecompressor(bytes.NewReader([]byte{0x07, 0x01, 0x02}), true)
+	require.ErrorIs(t, err, ErrUnknownCompressionAlgo)
+
+	_, err = NewDecompressor(bytes.NewReader(nil), true)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestParseCompressionAlgo(t *testing.T) {
+	for _, algo := range []CompressionAlgo{Zlib, Zstd} {
+		parsed, err := ParseCompressionAlgo(algo.String())
+		require.NoError(t, err)
+		require.Equal(t, algo, parsed)
+	}
+	_, err := ParseCompressionAlgo("brotli")
+	require.ErrorIs(t, err, ErrUnknownCompressionAlgo)
+}
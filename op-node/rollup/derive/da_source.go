@@ -0,0 +1,80 @@
+package derive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+// DAStore retrieves the data committed to by batcher transactions, when DA commitments are enabled.
+type DAStore interface {
+	// Get returns the data with the given keccak256 commitment.
+	Get(ctx context.Context, commitment common.Hash) ([]byte, error)
+}
+
+// DACommitment returns the keccak256 commitment to the data, and the batcher transaction data that carries it.
+func DACommitment(data []byte) (common.Hash, []byte) {
+	commitment := crypto.Keccak256Hash(data)
+	return commitment, append([]byte{DerivationVersionCommitment}, commitment[:]...)
+}
+
+// DACommitmentSource wraps the data of the batcher transactions,
+// and replaces the data that carries a commitment with the committed data from the DA store.
+// Data without commitment is passed through unchanged.
+type DACommitmentSource struct {
+	log     log.Logger
+	src     DataIter
+	store   DAStore
+	metrics Metrics
+
+	// pending is the commitment that is not retrieved yet, to retry on the next call to Next.
+	pending *common.Hash
+}
+
+func NewDACommitmentSource(log log.Logger, src DataIter, store DAStore, metrics Metrics) *DACommitmentSource {
+	return &DACommitmentSource{log: log, src: src, store: store, metrics: metrics}
+}
+
+// Next returns the next piece of data. If the committed data cannot be retrieved from the DA store,
+// it returns a temporary error, and retries the same commitment on the next call.
+// If the DA store returns data that does not match the commitment, the commitment is dropped,
+// like the data of an invalid batcher transaction, because retrying cannot fix a wrong store.
+func (s *DACommitmentSource) Next(ctx context.Context) (eth.Data, error) {
+	for {
+		for s.pending == nil {
+			data, err := s.src.Next(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if len(data) == 0 || data[0] != DerivationVersionCommitment {
+				return data, nil
+			}
+			if len(data) != 1+common.HashLength {
+				s.log.Warn("ignoring malformed DA commitment", "length", len(data))
+				continue
+			}
+			commitment := common.BytesToHash(data[1:])
+			s.pending = &commitment
+		}
+		commitment := *s.pending
+		if s.store == nil {
+			return nil, NewCriticalError(fmt.Errorf("no DA store to get data of DA commitment %s", commitment))
+		}
+		data, err := s.store.Get(ctx, commitment)
+		if err != nil {
+			return nil, NewTemporaryError(fmt.Errorf("failed to get data of DA commitment %s: %w", commitment, err))
+		}
+		s.pending = nil
+		if got := crypto.Keccak256Hash(data); got != commitment {
+			s.log.Error("dropping DA commitment, DA store returned data that does not match it", "commitment", commitment, "got", got)
+			s.metrics.RecordDACommitmentMismatch()
+			continue
+		}
+		return data, nil
+	}
+}
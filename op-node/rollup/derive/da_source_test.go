@@ -0,0 +1,108 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+)
+
+type testDataIter []eth.Data
+
+func (it *testDataIter) Next(ctx context.Context) (eth.Data, error) {
+	if len(*it) == 0 {
+		return nil, io.EOF
+	}
+	data := (*it)[0]
+	*it = (*it)[1:]
+	return data, nil
+}
+
+type testDAStore map[common.Hash][]byte
+
+func (s testDAStore) Get(ctx context.Context, commitment common.Hash) ([]byte, error) {
+	data, ok := s[commitment]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func TestDACommitmentSource(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	ctx := context.Background()
+	store := make(testDAStore)
+
+	plain := append([]byte{DerivationVersion0}, testutils.RandomData(rng, 100)...)
+	committed := append([]byte{DerivationVersion0}, testutils.RandomData(rng, 100)...)
+	commitment, calldata := DACommitment(committed)
+	store[commitment] = committed
+	missing := append([]byte{DerivationVersion0}, testutils.RandomData(rng, 100)...)
+	missingCommitment, missingCalldata := DACommitment(missing)
+	malformed := []byte{DerivationVersionCommitment, 0x01, 0x02}
+	bad := append([]byte{DerivationVersion0}, testutils.RandomData(rng, 100)...)
+	badCommitment, badCalldata := DACommitment(bad)
+	store[badCommitment] = plain
+
+	mismatches := 0
+	metrics := &testutils.TestDerivationMetrics{FnRecordDAMismatch: func() { mismatches++ }}
+	src := NewDACommitmentSource(testlog.Logger(t, log.LvlCrit), &testDataIter{plain, calldata, malformed, missingCalldata, badCalldata, plain}, store, metrics)
+
+	// data without commitment is passed through
+	data, err := src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(plain), data)
+
+	// committed data is retrieved from the store
+	data, err = src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(committed), data)
+
+	// malformed commitments are skipped, data that is not available is retried
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, ErrTemporary)
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, ErrTemporary)
+
+	store[missingCommitment] = missing
+	data, err = src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(missing), data)
+
+	// data that does not match the commitment is dropped, and the next data is returned
+	data, err = src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(plain), data)
+	require.Equal(t, 1, mismatches)
+
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+type testTxFetcher struct{}
+
+func (testTxFetcher) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	return nil, nil, errors.New("not available")
+}
+
+func TestDataSourceFactoryDACommitmentsTime(t *testing.T) {
+	activation := uint64(100)
+	cfg := &rollup.Config{DACommitmentsTime: &activation}
+	factory := NewDataSourceFactory(testlog.Logger(t, log.LvlCrit), cfg, testTxFetcher{}, make(testDAStore), &testutils.TestDerivationMetrics{})
+
+	src := factory.OpenData(context.Background(), eth.L1BlockRef{Time: activation - 1}, common.Address{})
+	require.IsType(t, &DataSource{}, src, "DA commitments are not active before the activation time")
+	src = factory.OpenData(context.Background(), eth.L1BlockRef{Time: activation}, common.Address{})
+	require.IsType(t, &DACommitmentSource{}, src, "DA commitments are active at the activation time")
+}
@@ -0,0 +1,52 @@
+package derive
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+// DerivationState is a view of the data buffered in the stages of the derivation pipeline, for debugging.
+type DerivationState struct {
+	// Stages lists the origin of every stage, in pipeline order, from L1 traversal to the engine queue.
+	Stages []StageState `json:"stages"`
+	// Channels lists the open channels of the channel bank, in FIFO order.
+	Channels []ChannelState `json:"channels"`
+	// Batches lists the batches buffered in the batch queue, ordered by timestamp.
+	Batches []BatchState `json:"batches"`
+	// UnsafePayloads lists the unsafe payloads queued by the engine queue, ordered by block number.
+	UnsafePayloads []UnsafePayloadState `json:"unsafePayloads"`
+}
+
+type StageState struct {
+	Name   string         `json:"name"`
+	Origin eth.L1BlockRef `json:"origin"`
+}
+
+type ChannelState struct {
+	ID        ChannelID      `json:"id"`
+	OpenBlock eth.L1BlockRef `json:"openBlock"`
+	// FramesReceived is the number of frames buffered in the channel
+	FramesReceived int `json:"framesReceived"`
+	// Size is the estimated memory size of the channel, as counted towards the channel bank limit
+	Size uint64 `json:"size"`
+	// Closed is true if the last frame of the channel has been received
+	Closed bool `json:"closed"`
+	// Age is the number of L1 blocks between the channel bank origin and the L1 block the channel was opened in
+	Age uint64 `json:"age"`
+}
+
+type BatchState struct {
+	L1InclusionBlock eth.L1BlockRef `json:"l1InclusionBlock"`
+	ParentHash       common.Hash    `json:"parentHash"`
+	Epoch            eth.BlockID    `json:"epoch"`
+	Timestamp        uint64         `json:"timestamp"`
+	Transactions     int            `json:"transactions"`
+}
+
+type UnsafePayloadState struct {
+	Block        eth.BlockID `json:"block"`
+	ParentHash   common.Hash `json:"parentHash"`
+	Timestamp    uint64      `json:"timestamp"`
+	Transactions int         `json:"transactions"`
+}
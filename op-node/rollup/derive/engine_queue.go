@@ -120,12 +120,14 @@ type EngineQueue struct {
 
 	metrics   Metrics
 	l1Fetcher L1Fetcher
+
+	safeHeadNotifs SafeHeadListener // notified of safe head updates and resets
 }
 
 var _ EngineControl = (*EngineQueue)(nil)
 
 // NewEngineQueue creates a new EngineQueue, which should be Reset(origin) before use.
-func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher) *EngineQueue {
+func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, safeHeadListener SafeHeadListener) *EngineQueue {
 	return &EngineQueue{
 		log:          log,
 		cfg:          cfg,
@@ -137,9 +139,25 @@ func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics M
 			SizeFn:   payloadMemSize,
 			blockNos: make(map[uint64]bool),
 		},
-		prev:      prev,
-		l1Fetcher: l1Fetcher,
+		prev:           prev,
+		l1Fetcher:      l1Fetcher,
+		safeHeadNotifs: safeHeadListener,
+	}
+}
+
+// UnsafePayloads returns the state of the queued unsafe payloads, ordered by block number, for debugging.
+func (eq *EngineQueue) UnsafePayloads() []UnsafePayloadState {
+	payloads := eq.unsafePayloads.Payloads()
+	out := make([]UnsafePayloadState, 0, len(payloads))
+	for _, p := range payloads {
+		out = append(out, UnsafePayloadState{
+			Block:        p.ID(),
+			ParentHash:   p.ParentHash,
+			Timestamp:    uint64(p.Timestamp),
+			Transactions: len(p.Transactions),
+		})
 	}
+	return out
 }
 
 // Origin identifies the L1 chain (incl.) that included and/or produced all the safe L2 blocks.
@@ -297,7 +315,13 @@ func (eq *EngineQueue) tryFinalizeL2() {
 
 // postProcessSafeL2 buffers the L1 block the safe head was fully derived from,
 // to finalize it once the L1 block, or later, finalizes.
-func (eq *EngineQueue) postProcessSafeL2() {
+// It also notifies the safe head listener of the new safe head.
+func (eq *EngineQueue) postProcessSafeL2() error {
+	if err := eq.safeHeadNotifs.SafeHeadUpdated(eq.safeHead, eq.origin.ID()); err != nil {
+		// The safe head is already updated in the engine, but not recorded by the listener.
+		// A reset rolls back the safe head, so the listener is notified of it again.
+		return NewResetError(fmt.Errorf("failed to notify safe head listener: %w", err))
+	}
 	// prune finality data if necessary
 	if len(eq.finalityData) >= finalityLookback {
 		eq.finalityData = append(eq.finalityData[:0], eq.finalityData[1:finalityLookback]...)
@@ -319,6 +343,7 @@ func (eq *EngineQueue) postProcessSafeL2() {
 			eq.log.Debug("updated finality-data", "last_l1", last.L1Block, "last_l2", last.L2Block)
 		}
 	}
+	return nil
 }
 
 func (eq *EngineQueue) logSyncProgress(reason string) {
@@ -486,7 +511,9 @@ func (eq *EngineQueue) consolidateNextSafeAttributes(ctx context.Context) error
 	eq.metrics.RecordL2Ref("l2_safe", ref)
 	// unsafe head stays the same, we did not reorg the chain.
 	eq.safeAttributes = nil
-	eq.postProcessSafeL2()
+	if err := eq.postProcessSafeL2(); err != nil {
+		return err
+	}
 	eq.logSyncProgress("reconciled with L1")
 
 	return nil
@@ -590,8 +617,11 @@ func (eq *EngineQueue) ConfirmPayload(ctx context.Context) (out *eth.ExecutionPa
 
 	if eq.buildingSafe {
 		eq.safeHead = ref
-		eq.postProcessSafeL2()
 		eq.metrics.RecordL2Ref("l2_safe", ref)
+		if err := eq.postProcessSafeL2(); err != nil {
+			eq.resetBuildingState()
+			return nil, BlockInsertPrestateErr, err
+		}
 	}
 	eq.resetBuildingState()
 	return payload, BlockInsertOK, nil
@@ -665,6 +695,9 @@ func (eq *EngineQueue) Reset(ctx context.Context, _ eth.L1BlockRef, _ eth.System
 	if err != nil {
 		return NewTemporaryError(fmt.Errorf("failed to fetch L1 config of L2 block %s: %w", pipelineL2.ID(), err))
 	}
+	if err := eq.safeHeadNotifs.SafeHeadReset(safe); err != nil {
+		return NewTemporaryError(fmt.Errorf("failed to reset safe head listener to %s: %w", safe, err))
+	}
 	eq.log.Debug("Reset engine queue", "safeHead", safe, "unsafe", unsafe, "safe_timestamp", safe.Time, "unsafe_timestamp", unsafe.Time, "l1Origin", l1Origin)
 	eq.unsafeHead = unsafe
 	eq.safeHead = safe
@@ -13,6 +13,10 @@ import (
 // but we leave space to grow larger anyway (gas limit allows for more data).
 const MaxFrameLen = 1_000_000
 
+// FrameV0OverHeadSize is the fixed size of a frame without its data:
+// 16 bytes channel id + 2 bytes frame number + 4 bytes data length + 1 byte is_last.
+const FrameV0OverHeadSize = 23
+
 // Data Format
 //
 // frame = channel_id ++ frame_number ++ frame_data_length ++ frame_data ++ is_last
@@ -19,6 +19,10 @@ func frameSize(frame Frame) uint64 {
 
 const DerivationVersion0 = 0
 
+// DerivationVersionCommitment prefixes batcher transaction data that carries a keccak256 commitment
+// to the data, instead of the data itself. The data is retrieved from a DA store, see rollup.Config.DACommitmentsTime.
+const DerivationVersionCommitment = 1
+
 // MaxChannelBankSize is the amount of memory space, in number of bytes,
 // till the bank is pruned by removing channels,
 // starting with the oldest channel.
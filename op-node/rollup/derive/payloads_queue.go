@@ -4,6 +4,7 @@ import (
 	"container/heap"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 )
@@ -141,3 +142,15 @@ func (upq *PayloadsQueue) Pop() *eth.ExecutionPayload {
 	delete(upq.blockNos, ps.payload.ID().Number)
 	return ps.payload
 }
+
+// Payloads returns a copy of the queued payloads, ordered by ascending block number, in O(N log(N)).
+func (upq *PayloadsQueue) Payloads() []*eth.ExecutionPayload {
+	out := make([]*eth.ExecutionPayload, 0, len(upq.pq))
+	for _, ps := range upq.pq {
+		out = append(out, ps.payload)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].BlockNumber < out[j].BlockNumber
+	})
+	return out
+}
@@ -137,3 +137,23 @@ func TestPayloadsQueue(t *testing.T) {
 	require.Equal(t, pq.Peek(), b, "expecting b, c, d")
 	require.NotContainsf(t, pq.pq[:], a, "a should be dropped after 3 items already exist under max size constraint")
 }
+
+func TestPayloadsQueuePayloads(t *testing.T) {
+	pq := PayloadsQueue{
+		MaxSize:  payloadMemFixedCost * 10,
+		SizeFn:   payloadMemSize,
+		blockNos: make(map[uint64]bool),
+	}
+	require.Empty(t, pq.Payloads())
+
+	for _, n := range []uint64{7, 3, 5, 4, 6} {
+		require.NoError(t, pq.Push(&eth.ExecutionPayload{BlockNumber: eth.Uint64Quantity(n)}))
+	}
+	payloads := pq.Payloads()
+	require.Len(t, payloads, 5)
+	for i, p := range payloads {
+		require.Equal(t, eth.Uint64Quantity(3+i), p.BlockNumber)
+	}
+	require.Equal(t, 5, pq.Len(), "listing the payloads does not modify the queue")
+	require.Equal(t, eth.Uint64Quantity(3), pq.Peek().BlockNumber)
+}
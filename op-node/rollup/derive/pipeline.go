@@ -15,6 +15,7 @@ type Metrics interface {
 	RecordL1Ref(name string, ref eth.L1BlockRef)
 	RecordL2Ref(name string, ref eth.L2BlockRef)
 	RecordUnsafePayloadsBuffer(length uint64, memSize uint64, next eth.BlockID)
+	RecordDACommitmentMismatch()
 }
 
 type L1Fetcher interface {
@@ -52,9 +53,20 @@ type EngineQueueStage interface {
 	Finalize(l1Origin eth.L1BlockRef)
 	AddUnsafePayload(payload *eth.ExecutionPayload)
 	GetUnsafeQueueGap(expectedNumber uint64) (uint64, uint64)
+	UnsafePayloads() []UnsafePayloadState
 	Step(context.Context) error
 }
 
+type originStage interface {
+	Origin() eth.L1BlockRef
+}
+
+// namedStage is a stage of the pipeline, named to inspect its origin for debugging.
+type namedStage struct {
+	name  string
+	stage originStage
+}
+
 // DerivationPipeline is updated with new L1 data, and the Step() function can be iterated on to keep the L2 Engine in sync.
 type DerivationPipeline struct {
 	log       log.Logger
@@ -67,43 +79,63 @@ type DerivationPipeline struct {
 	stages    []ResetableStage
 
 	// Special stages to keep track of
-	traversal *L1Traversal
-	eng       EngineQueueStage
+	traversal  *L1Traversal
+	bank       *ChannelBank
+	batchQueue *BatchQueue
+	eng        EngineQueueStage
+
+	// All stages in pipeline order, to inspect for debugging
+	namedStages []namedStage
 
 	metrics Metrics
 }
 
 // NewDerivationPipeline creates a derivation pipeline, which should be reset before use.
-func NewDerivationPipeline(log log.Logger, cfg *rollup.Config, l1Fetcher L1Fetcher, engine Engine, metrics Metrics) *DerivationPipeline {
+// The DA store is only used if DA commitments are enabled in the rollup config.
+func NewDerivationPipeline(log log.Logger, cfg *rollup.Config, l1Fetcher L1Fetcher, daStore DAStore, engine Engine, metrics Metrics, safeHeadListener SafeHeadListener) *DerivationPipeline {
 
 	// Pull stages
 	l1Traversal := NewL1Traversal(log, cfg, l1Fetcher)
-	dataSrc := NewDataSourceFactory(log, cfg, l1Fetcher) // auxiliary stage for L1Retrieval
+	dataSrc := NewDataSourceFactory(log, cfg, l1Fetcher, daStore, metrics) // auxiliary stage for L1Retrieval
 	l1Src := NewL1Retrieval(log, dataSrc, l1Traversal)
 	frameQueue := NewFrameQueue(log, l1Src)
 	bank := NewChannelBank(log, cfg, frameQueue, l1Fetcher)
-	chInReader := NewChannelInReader(log, bank)
+	chInReader := NewChannelInReader(log, cfg, bank)
 	batchQueue := NewBatchQueue(log, cfg, chInReader)
 	attrBuilder := NewFetchingAttributesBuilder(cfg, l1Fetcher, engine)
 	attributesQueue := NewAttributesQueue(log, cfg, attrBuilder, batchQueue)
 
 	// Step stages
-	eng := NewEngineQueue(log, cfg, engine, metrics, attributesQueue, l1Fetcher)
+	eng := NewEngineQueue(log, cfg, engine, metrics, attributesQueue, l1Fetcher, safeHeadListener)
 
 	// Reset from engine queue then up from L1 Traversal. The stages do not talk to each other during
 	// the reset, but after the engine queue, this is the order in which the stages could talk to each other.
 	// Note: The engine queue stage is the only reset that can fail.
 	stages := []ResetableStage{eng, l1Traversal, l1Src, frameQueue, bank, chInReader, batchQueue, attributesQueue}
 
+	namedStages := []namedStage{
+		{"l1_traversal", l1Traversal},
+		{"l1_retrieval", l1Src},
+		{"frame_queue", frameQueue},
+		{"channel_bank", bank},
+		{"channel_in_reader", chInReader},
+		{"batch_queue", batchQueue},
+		{"attributes_queue", attributesQueue},
+		{"engine_queue", eng},
+	}
+
 	return &DerivationPipeline{
-		log:       log,
-		cfg:       cfg,
-		l1Fetcher: l1Fetcher,
-		resetting: 0,
-		stages:    stages,
-		eng:       eng,
-		metrics:   metrics,
-		traversal: l1Traversal,
+		log:         log,
+		cfg:         cfg,
+		l1Fetcher:   l1Fetcher,
+		resetting:   0,
+		stages:      stages,
+		eng:         eng,
+		metrics:     metrics,
+		traversal:   l1Traversal,
+		bank:        bank,
+		batchQueue:  batchQueue,
+		namedStages: namedStages,
 	}
 }
 
@@ -173,6 +205,21 @@ func (dp *DerivationPipeline) GetUnsafeQueueGap(expectedNumber uint64) (uint64,
 	return dp.eng.GetUnsafeQueueGap(expectedNumber)
 }
 
+// DerivationState returns a view of the data buffered in the stages of the pipeline, for debugging.
+// It is not safe to call concurrently with Step or Reset.
+func (dp *DerivationPipeline) DerivationState() *DerivationState {
+	stages := make([]StageState, 0, len(dp.namedStages))
+	for _, s := range dp.namedStages {
+		stages = append(stages, StageState{Name: s.name, Origin: s.stage.Origin()})
+	}
+	return &DerivationState{
+		Stages:         stages,
+		Channels:       dp.bank.OpenChannels(),
+		Batches:        dp.batchQueue.BufferedBatches(),
+		UnsafePayloads: dp.eng.UnsafePayloads(),
+	}
+}
+
 // Step tries to progress the buffer.
 // An EOF is returned if there pipeline is blocked by waiting for new L1 data.
 // If ctx errors no error is returned, but the step may exit early in a state that can still be continued.
@@ -0,0 +1,30 @@
+package derive
+
+import (
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+// SafeHeadListener is notified of changes to the safe head,
+// e.g. to persist which L1 block made which L2 block safe.
+type SafeHeadListener interface {
+	// SafeHeadUpdated is called when the safe head advances. The L1 block is the L1 block
+	// that the derivation pipeline was at when the safe head got derived.
+	SafeHeadUpdated(safeHead eth.L2BlockRef, l1Block eth.BlockID) error
+
+	// SafeHeadReset is called when the derivation pipeline resets. Any safe heads after the
+	// reset safe head are not guaranteed to be derived again the same way, and must be dropped.
+	SafeHeadReset(resetSafeHead eth.L2BlockRef) error
+}
+
+type noopSafeHeadListener struct{}
+
+func (noopSafeHeadListener) SafeHeadUpdated(eth.L2BlockRef, eth.BlockID) error {
+	return nil
+}
+
+func (noopSafeHeadListener) SafeHeadReset(eth.L2BlockRef) error {
+	return nil
+}
+
+// NoopSafeHeadListener ignores all safe head changes.
+var NoopSafeHeadListener SafeHeadListener = noopSafeHeadListener{}
@@ -38,6 +38,7 @@ type Sequencer struct {
 
 	attrBuilder      derive.AttributesBuilder
 	l1OriginSelector L1OriginSelectorIface
+	policy           SequencerPolicy
 
 	metrics SequencerMetrics
 
@@ -47,7 +48,7 @@ type Sequencer struct {
 	nextAction time.Time
 }
 
-func NewSequencer(log log.Logger, cfg *rollup.Config, engine derive.ResettableEngineControl, attributesBuilder derive.AttributesBuilder, l1OriginSelector L1OriginSelectorIface, metrics SequencerMetrics) *Sequencer {
+func NewSequencer(log log.Logger, cfg *rollup.Config, engine derive.ResettableEngineControl, attributesBuilder derive.AttributesBuilder, l1OriginSelector L1OriginSelectorIface, policy SequencerPolicy, metrics SequencerMetrics) *Sequencer {
 	return &Sequencer{
 		log:              log,
 		config:           cfg,
@@ -55,6 +56,7 @@ func NewSequencer(log log.Logger, cfg *rollup.Config, engine derive.ResettableEn
 		timeNow:          time.Now,
 		attrBuilder:      attributesBuilder,
 		l1OriginSelector: l1OriginSelector,
+		policy:           policy,
 		metrics:          metrics,
 	}
 }
@@ -91,18 +93,60 @@ func (d *Sequencer) StartBuildingBlock(ctx context.Context) error {
 	// from the transaction pool.
 	attrs.NoTxPool = uint64(attrs.Timestamp) > l1Origin.Time+d.config.MaxSequencerDrift
 
+	policyTxs := d.applyPolicy(ctx, l2Head, attrs)
+
 	d.log.Debug("prepared attributes for new block",
 		"num", l2Head.Number+1, "time", uint64(attrs.Timestamp),
-		"origin", l1Origin, "origin_time", l1Origin.Time, "noTxPool", attrs.NoTxPool)
+		"origin", l1Origin, "origin_time", l1Origin.Time, "noTxPool", attrs.NoTxPool, "txs", len(attrs.Transactions))
 
 	// Start a payload building process.
-	errTyp, err := d.engine.StartPayload(ctx, l2Head, attrs, false)
+	errTyp, err := d.startPayload(ctx, l2Head, attrs, policyTxs)
 	if err != nil {
 		return fmt.Errorf("failed to start building on top of L2 chain %s, error (%d): %w", l2Head, errTyp, err)
 	}
 	return nil
 }
 
+// applyPolicy lets the sequencer policy modify the payload attributes, and returns the transactions that the policy added.
+// The original attributes are used if the policy fails or makes changes that are not allowed,
+// a broken policy does not halt the sequencer.
+func (d *Sequencer) applyPolicy(ctx context.Context, l2Head eth.L2BlockRef, attrs *eth.PayloadAttributes) []eth.Data {
+	orig := *attrs
+	orig.Transactions = append([]eth.Data(nil), attrs.Transactions...)
+	if attrs.GasLimit != nil {
+		gasLimit := *attrs.GasLimit
+		orig.GasLimit = &gasLimit
+	}
+	if err := d.policy.Apply(ctx, l2Head, attrs); err != nil {
+		d.log.Error("Sequencer policy failed, building block without policy", "parent", l2Head, "err", err)
+		*attrs = orig
+	} else if err := checkPolicyAttributes(&orig, attrs); err != nil {
+		d.log.Error("Sequencer policy made invalid changes, building block without policy", "parent", l2Head, "err", err)
+		*attrs = orig
+	}
+	return attrs.Transactions[len(orig.Transactions):]
+}
+
+// startPayload starts building a block with the given attributes, which include the given transactions of the policy.
+// The engine may reject transactions of the policy that are valid to decode, e.g. if their nonce is too low.
+// The block building is then retried without them, and if that succeeds, they are rejected from the policy,
+// so that they don't halt the sequencer.
+func (d *Sequencer) startPayload(ctx context.Context, l2Head eth.L2BlockRef, attrs *eth.PayloadAttributes, policyTxs []eth.Data) (derive.BlockInsertionErrType, error) {
+	errTyp, err := d.engine.StartPayload(ctx, l2Head, attrs, false)
+	if err == nil || errTyp != derive.BlockInsertPayloadErr || len(policyTxs) == 0 {
+		return errTyp, err
+	}
+	d.log.Warn("Engine rejected block with sequencer policy transactions, retrying without them", "parent", l2Head, "policy_txs", len(policyTxs), "err", err)
+	withoutPolicyTxs := *attrs
+	withoutPolicyTxs.Transactions = attrs.Transactions[:len(attrs.Transactions)-len(policyTxs)]
+	if errTyp, err := d.engine.StartPayload(ctx, l2Head, &withoutPolicyTxs, false); err != nil {
+		return errTyp, err
+	}
+	d.log.Error("Dropping sequencer policy transactions rejected by the engine", "parent", l2Head, "policy_txs", len(policyTxs), "err", err)
+	d.policy.TxsRejected(policyTxs)
+	return derive.BlockInsertOK, nil
+}
+
 // CompleteBuildingBlock takes the current block that is being built, and asks the engine to complete the building, seal the block, and persist it as canonical.
 // Warning: the safe and finalized L2 blocks as viewed during the initiation of the block building are reused for completion of the block building.
 // The Execution engine should not change the safe and finalized blocks between start and completion of block building.
@@ -111,6 +155,7 @@ func (d *Sequencer) CompleteBuildingBlock(ctx context.Context) (*eth.ExecutionPa
 	if err != nil {
 		return nil, fmt.Errorf("failed to complete building block: error (%d): %w", errTyp, err)
 	}
+	d.policy.BlockSealed(payload)
 	return payload, nil
 }
 
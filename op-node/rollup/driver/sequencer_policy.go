@@ -0,0 +1,221 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+// SequencerPolicy is consulted by the sequencer for every new block, before the block building starts.
+type SequencerPolicy interface {
+	// Apply modifies the payload attributes of the new block on top of the given L2 head.
+	// The attributes contain the deposits, the gas limit of the system config, and NoTxPool is set if the sequencer drift is exceeded.
+	// A policy may append transactions, set NoTxPool, and lower the gas limit, but not below the gas of the
+	// transactions it added. Other changes are rejected by the sequencer.
+	Apply(ctx context.Context, l2Head eth.L2BlockRef, attrs *eth.PayloadAttributes) error
+	// BlockSealed is called with every block that the sequencer sealed, after the policy was applied to its attributes.
+	BlockSealed(payload *eth.ExecutionPayload)
+	// TxsRejected is called with the transactions that the policy added, if the engine rejected the block with them
+	// but accepted it without them. The block is built without the transactions.
+	TxsRejected(txs []eth.Data)
+}
+
+// NoopSequencerPolicy leaves the payload attributes unchanged.
+var NoopSequencerPolicy SequencerPolicy = noopSequencerPolicy{}
+
+type noopSequencerPolicy struct{}
+
+func (noopSequencerPolicy) Apply(ctx context.Context, l2Head eth.L2BlockRef, attrs *eth.PayloadAttributes) error {
+	return nil
+}
+
+func (noopSequencerPolicy) BlockSealed(payload *eth.ExecutionPayload) {}
+
+func (noopSequencerPolicy) TxsRejected(txs []eth.Data) {}
+
+// SequencerPolicyConfig is the sequencer policy that can be changed at runtime, e.g. for planned upgrades and incident response.
+type SequencerPolicyConfig struct {
+	// NoTxPool pauses the inclusion of transactions from the tx pool.
+	NoTxPool bool `json:"noTxPool"`
+	// GasLimit lowers the gas limit of new blocks, if non-zero. The gas limit of the system config is used if it is lower,
+	// and the gas of the transactions of the policy if it is higher.
+	// The gas limit is not part of the batch data: verifiers derive the blocks with the gas limit of the system config,
+	// so blocks with a lowered gas limit are replaced once they are derived from L1.
+	GasLimit hexutil.Uint64 `json:"gasLimit"`
+	// Transactions are included in the next block that the sequencer builds, after the deposits.
+	// They are added to every block that the sequencer starts building, until a block that includes them is sealed.
+	// They are dropped if the engine rejects them, e.g. because their nonce is too low.
+	Transactions []hexutil.Bytes `json:"transactions"`
+	// RejectedTransactions are the transactions that got dropped because the engine rejected them.
+	// They are informational, and ignored when the policy is set.
+	RejectedTransactions []hexutil.Bytes `json:"rejectedTransactions,omitempty"`
+}
+
+// Check verifies that the transactions are valid non-deposit transactions.
+func (c *SequencerPolicyConfig) Check() error {
+	for i, data := range c.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("invalid transaction %d: %w", i, err)
+		}
+		if tx.IsDepositTx() {
+			return fmt.Errorf("transaction %d is a deposit, deposits can only be included from L1", i)
+		}
+	}
+	return nil
+}
+
+// RuntimeSequencerPolicy is a SequencerPolicy that is changed at runtime through the admin RPC.
+type RuntimeSequencerPolicy struct {
+	mu  sync.Mutex
+	cfg SequencerPolicyConfig
+}
+
+var _ SequencerPolicy = (*RuntimeSequencerPolicy)(nil)
+
+func NewRuntimeSequencerPolicy() *RuntimeSequencerPolicy {
+	return &RuntimeSequencerPolicy{}
+}
+
+// Config returns the current policy, including the transactions that are not yet included.
+func (p *RuntimeSequencerPolicy) Config() SequencerPolicyConfig {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cfg := p.cfg
+	cfg.Transactions = append([]hexutil.Bytes(nil), p.cfg.Transactions...)
+	cfg.RejectedTransactions = append([]hexutil.Bytes(nil), p.cfg.RejectedTransactions...)
+	return cfg
+}
+
+// SetConfig replaces the policy, including the transactions that are not yet included.
+func (p *RuntimeSequencerPolicy) SetConfig(cfg SequencerPolicyConfig) error {
+	if err := cfg.Check(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = cfg
+	p.cfg.Transactions = append([]hexutil.Bytes(nil), cfg.Transactions...)
+	p.cfg.RejectedTransactions = nil
+	return nil
+}
+
+// Apply applies the policy. The transactions remain in the policy until a block that includes them is sealed,
+// or the engine rejects them, so they are retried if the block building fails or is cancelled.
+func (p *RuntimeSequencerPolicy) Apply(ctx context.Context, l2Head eth.L2BlockRef, attrs *eth.PayloadAttributes) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg.NoTxPool {
+		attrs.NoTxPool = true
+	}
+	var gas uint64
+	for _, data := range p.cfg.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("invalid policy transaction: %w", err)
+		}
+		gas += tx.Gas()
+		attrs.Transactions = append(attrs.Transactions, eth.Data(data))
+	}
+	if p.cfg.GasLimit != 0 && attrs.GasLimit != nil && uint64(p.cfg.GasLimit) < uint64(*attrs.GasLimit) {
+		gasLimit := eth.Uint64Quantity(p.cfg.GasLimit)
+		if uint64(gasLimit) < gas {
+			gasLimit = eth.Uint64Quantity(gas)
+		}
+		if gasLimit < *attrs.GasLimit {
+			attrs.GasLimit = &gasLimit
+		}
+	}
+	return nil
+}
+
+// BlockSealed removes the transactions that are included in the sealed block from the policy.
+func (p *RuntimeSequencerPolicy) BlockSealed(payload *eth.ExecutionPayload) {
+	included := make(map[string]struct{}, len(payload.Transactions))
+	for _, tx := range payload.Transactions {
+		included[string(tx)] = struct{}{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var remaining []hexutil.Bytes
+	for _, tx := range p.cfg.Transactions {
+		if _, ok := included[string(tx)]; !ok {
+			remaining = append(remaining, tx)
+		}
+	}
+	p.cfg.Transactions = remaining
+}
+
+// TxsRejected moves the rejected transactions from the policy to the rejected transactions.
+// The engine rejects the block as a whole, so all transactions that were added to it are rejected.
+func (p *RuntimeSequencerPolicy) TxsRejected(txs []eth.Data) {
+	rejected := make(map[string]struct{}, len(txs))
+	for _, tx := range txs {
+		rejected[string(tx)] = struct{}{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var remaining []hexutil.Bytes
+	for _, tx := range p.cfg.Transactions {
+		if _, ok := rejected[string(tx)]; ok {
+			p.cfg.RejectedTransactions = append(p.cfg.RejectedTransactions, tx)
+		} else {
+			remaining = append(remaining, tx)
+		}
+	}
+	p.cfg.Transactions = remaining
+}
+
+// checkPolicyAttributes checks that the policy only made the allowed changes to the original payload attributes.
+func checkPolicyAttributes(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) error {
+	if attrs.Timestamp != orig.Timestamp || attrs.PrevRandao != orig.PrevRandao || attrs.SuggestedFeeRecipient != orig.SuggestedFeeRecipient {
+		return errors.New("timestamp, prev-randao and fee recipient cannot be changed")
+	}
+	if orig.NoTxPool && !attrs.NoTxPool {
+		return errors.New("tx pool cannot be enabled when the sequencer drift is exceeded")
+	}
+	if len(attrs.Transactions) < len(orig.Transactions) {
+		return fmt.Errorf("transactions cannot be removed, got %d, expected at least %d", len(attrs.Transactions), len(orig.Transactions))
+	}
+	// Only the added transactions must fit in the gas limit. The gas of deposits is bought on L1,
+	// and the pre-Regolith L1-info deposit has a gas of 150M, more than the block gas limit.
+	var gas uint64
+	for i, data := range attrs.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("invalid transaction %d: %w", i, err)
+		}
+		if i < len(orig.Transactions) {
+			if string(data) != string(orig.Transactions[i]) {
+				return fmt.Errorf("transaction %d cannot be changed", i)
+			}
+			continue
+		}
+		if tx.IsDepositTx() {
+			return fmt.Errorf("transaction %d is a deposit, deposits can only be included from L1", i)
+		}
+		gas += tx.Gas()
+	}
+	if orig.GasLimit == nil {
+		if attrs.GasLimit != nil {
+			return errors.New("gas limit cannot be set before the system config defines it")
+		}
+		return nil
+	}
+	if attrs.GasLimit == nil {
+		return errors.New("gas limit cannot be removed")
+	}
+	if *attrs.GasLimit > *orig.GasLimit {
+		return fmt.Errorf("gas limit %d exceeds the system config gas limit %d", *attrs.GasLimit, *orig.GasLimit)
+	}
+	if uint64(*attrs.GasLimit) < gas {
+		return fmt.Errorf("gas limit %d is lower than the %d gas of the included transactions", *attrs.GasLimit, gas)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+)
+
+func policyTestAttributes(t *testing.T, rng *rand.Rand, gasLimit uint64) *eth.PayloadAttributes {
+	deposit, err := types.NewTx(&types.DepositTx{
+		SourceHash: testutils.RandomHash(rng),
+		From:       testutils.RandomAddress(rng),
+		Gas:        1_000_000,
+		Data:       testutils.RandomData(rng, 100),
+	}).MarshalBinary()
+	require.NoError(t, err)
+	gl := eth.Uint64Quantity(gasLimit)
+	return &eth.PayloadAttributes{
+		Timestamp:             eth.Uint64Quantity(rng.Uint64()),
+		PrevRandao:            eth.Bytes32(testutils.RandomHash(rng)),
+		SuggestedFeeRecipient: testutils.RandomAddress(rng),
+		Transactions:          []eth.Data{deposit},
+		GasLimit:              &gl,
+	}
+}
+
+func policyTestTx(t *testing.T, rng *rand.Rand) hexutil.Bytes {
+	signer := types.NewLondonSigner(big.NewInt(901))
+	data, err := testutils.RandomTx(rng, big.NewInt(1), signer).MarshalBinary()
+	require.NoError(t, err)
+	return data
+}
+
+func TestRuntimeSequencerPolicy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	ctx := context.Background()
+	policy := NewRuntimeSequencerPolicy()
+	txA, txB := policyTestTx(t, rng), policyTestTx(t, rng)
+
+	// the zero policy leaves the attributes unchanged
+	attrs := policyTestAttributes(t, rng, 30_000_000)
+	orig := *attrs
+	require.NoError(t, policy.Apply(ctx, eth.L2BlockRef{}, attrs))
+	require.Equal(t, orig, *attrs)
+
+	require.NoError(t, policy.SetConfig(SequencerPolicyConfig{
+		NoTxPool:     true,
+		Transactions: []hexutil.Bytes{txA, txB},
+	}))
+	require.Len(t, policy.Config().Transactions, 2)
+
+	attrs = policyTestAttributes(t, rng, 30_000_000)
+	orig = *attrs
+	require.NoError(t, policy.Apply(ctx, eth.L2BlockRef{}, attrs))
+	require.NoError(t, checkPolicyAttributes(&orig, attrs))
+	require.True(t, attrs.NoTxPool)
+	require.Equal(t, eth.Uint64Quantity(30_000_000), *attrs.GasLimit)
+	require.Equal(t, []eth.Data{orig.Transactions[0], eth.Data(txA), eth.Data(txB)}, attrs.Transactions)
+
+	// the transactions are retried until a block that includes them is sealed
+	require.Len(t, policy.Config().Transactions, 2)
+	policy.BlockSealed(&eth.ExecutionPayload{Transactions: []eth.Data{orig.Transactions[0]}})
+	require.Len(t, policy.Config().Transactions, 2)
+	attrs = policyTestAttributes(t, rng, 30_000_000)
+	require.NoError(t, policy.Apply(ctx, eth.L2BlockRef{}, attrs))
+	require.Len(t, attrs.Transactions, 3)
+	policy.BlockSealed(&eth.ExecutionPayload{Transactions: []eth.Data{attrs.Transactions[0], eth.Data(txA)}})
+	require.Equal(t, []hexutil.Bytes{txB}, policy.Config().Transactions)
+	policy.BlockSealed(&eth.ExecutionPayload{Transactions: []eth.Data{attrs.Transactions[0], eth.Data(txB)}})
+
+	// the transactions are only included once, the rest of the policy remains
+	require.Empty(t, policy.Config().Transactions)
+	attrs = policyTestAttributes(t, rng, 30_000_000)
+	require.NoError(t, policy.Apply(ctx, eth.L2BlockRef{}, attrs))
+	require.Len(t, attrs.Transactions, 1)
+	require.True(t, attrs.NoTxPool)
+
+	// the gas limit is lowered, but not above the system config gas limit, nor below the gas of the transactions
+	require.NoError(t, policy.SetConfig(SequencerPolicyConfig{GasLimit: 20_000_000}))
+	attrs = policyTestAttributes(t, rng, 30_000_000)
+	orig = *attrs
+	require.NoError(t, policy.Apply(ctx, eth.L2BlockRef{}, attrs))
+	require.NoError(t, checkPolicyAttributes(&orig, attrs))
+	require.Equal(t, eth.Uint64Quantity(20_000_000), *attrs.GasLimit)
+	attrs = policyTestAttributes(t, rng, 15_000_000)
+	require.NoError(t, policy.Apply(ctx, eth.L2BlockRef{}, attrs))
+	require.Equal(t, eth.Uint64Quantity(15_000_000), *attrs.GasLimit)
+	require.NoError(t, policy.SetConfig(SequencerPolicyConfig{GasLimit: 1, Transactions: []hexutil.Bytes{txA}}))
+	var decodedA types.Transaction
+	require.NoError(t, decodedA.UnmarshalBinary(txA))
+	attrs = policyTestAttributes(t, rng, 30_000_000)
+	orig = *attrs
+	require.NoError(t, policy.Apply(ctx, eth.L2BlockRef{}, attrs))
+	require.NoError(t, checkPolicyAttributes(&orig, attrs))
+	require.Equal(t, eth.Uint64Quantity(decodedA.Gas()), *attrs.GasLimit)
+	require.NoError(t, policy.SetConfig(SequencerPolicyConfig{NoTxPool: true}))
+
+	// deposits and invalid transactions are rejected
+	deposit := policyTestAttributes(t, rng, 30_000_000).Transactions[0]
+	require.Error(t, policy.SetConfig(SequencerPolicyConfig{Transactions: []hexutil.Bytes{hexutil.Bytes(deposit)}}))
+	require.Error(t, policy.SetConfig(SequencerPolicyConfig{Transactions: []hexutil.Bytes{{0x01, 0x02}}}))
+	require.True(t, policy.Config().NoTxPool, "policy unchanged after invalid config")
+}
+
+func TestCheckPolicyAttributes(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	tx := policyTestTx(t, rng)
+	testCases := []struct {
+		name   string
+		modify func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes)
+		valid  bool
+	}{
+		{"unchanged", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {}, true},
+		{"append tx", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			attrs.Transactions = append(attrs.Transactions, eth.Data(tx))
+		}, true},
+		{"disable tx pool", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			attrs.NoTxPool = true
+		}, true},
+		{"enable tx pool past drift", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			orig.NoTxPool = true
+		}, false},
+		{"lower gas limit", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			gl := *orig.GasLimit / 2
+			attrs.GasLimit = &gl
+		}, true},
+		{"lower gas limit below tx gas", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			gl := eth.Uint64Quantity(1)
+			attrs.GasLimit = &gl
+			attrs.Transactions = append(attrs.Transactions, eth.Data(tx))
+		}, false},
+		{"raise gas limit", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			gl := *orig.GasLimit + 1
+			attrs.GasLimit = &gl
+		}, false},
+		{"gas limit below deposit gas", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			gl := eth.Uint64Quantity(100_000)
+			orig.GasLimit, attrs.GasLimit = &gl, &gl
+		}, true},
+		{"gas limit below tx gas", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			gl := eth.Uint64Quantity(1)
+			orig.GasLimit, attrs.GasLimit = &gl, &gl
+			attrs.Transactions = append(attrs.Transactions, eth.Data(tx))
+		}, false},
+		{"remove gas limit", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			attrs.GasLimit = nil
+		}, false},
+		{"remove deposit", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			attrs.Transactions = nil
+		}, false},
+		{"replace deposit", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			attrs.Transactions = []eth.Data{eth.Data(tx)}
+		}, false},
+		{"append deposit", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			attrs.Transactions = append(attrs.Transactions, attrs.Transactions[0])
+		}, false},
+		{"change timestamp", func(orig *eth.PayloadAttributes, attrs *eth.PayloadAttributes) {
+			attrs.Timestamp++
+		}, false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			orig := policyTestAttributes(t, rng, 30_000_000)
+			attrs := *orig
+			attrs.Transactions = append([]eth.Data(nil), orig.Transactions...)
+			tc.modify(orig, &attrs)
+			err := checkPolicyAttributes(orig, &attrs)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckPolicyAttributesL1InfoDeposit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	tx := policyTestTx(t, rng)
+	l1Info := testutils.RandomBlockInfo(rng)
+	for _, regolith := range []bool{false, true} {
+		deposit, err := derive.L1InfoDepositBytes(1, l1Info, eth.SystemConfig{}, regolith)
+		require.NoError(t, err)
+		// the pre-Regolith L1-info deposit has more gas than the gas limit
+		orig := policyTestAttributes(t, rng, 30_000_000)
+		orig.Transactions = []eth.Data{deposit}
+		attrs := *orig
+		attrs.Transactions = []eth.Data{deposit, eth.Data(tx)}
+		require.NoError(t, checkPolicyAttributes(orig, &attrs), "regolith: %v", regolith)
+	}
+}
+
+type testSequencerPolicy func(attrs *eth.PayloadAttributes) error
+
+func (fn testSequencerPolicy) Apply(ctx context.Context, l2Head eth.L2BlockRef, attrs *eth.PayloadAttributes) error {
+	return fn(attrs)
+}
+
+func (fn testSequencerPolicy) BlockSealed(payload *eth.ExecutionPayload) {}
+
+func (fn testSequencerPolicy) TxsRejected(txs []eth.Data) {}
+
+func TestSequencerApplyPolicy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	tx := policyTestTx(t, rng)
+	seq := &Sequencer{log: testlog.Logger(t, log.LvlError)}
+
+	seq.policy = testSequencerPolicy(func(attrs *eth.PayloadAttributes) error {
+		attrs.Transactions = append(attrs.Transactions, eth.Data(tx))
+		return nil
+	})
+	attrs := policyTestAttributes(t, rng, 30_000_000)
+	seq.applyPolicy(context.Background(), eth.L2BlockRef{}, attrs)
+	require.Len(t, attrs.Transactions, 2)
+
+	// the original attributes are used if the policy fails
+	seq.policy = testSequencerPolicy(func(attrs *eth.PayloadAttributes) error {
+		attrs.Transactions = append(attrs.Transactions, eth.Data(tx))
+		return errors.New("policy failure")
+	})
+	attrs = policyTestAttributes(t, rng, 30_000_000)
+	orig := *attrs
+	seq.applyPolicy(context.Background(), eth.L2BlockRef{}, attrs)
+	require.Equal(t, orig, *attrs)
+
+	// the original attributes are used if the policy breaks the rules
+	seq.policy = testSequencerPolicy(func(attrs *eth.PayloadAttributes) error {
+		*attrs.GasLimit *= 2
+		attrs.Transactions = nil
+		return nil
+	})
+	attrs = policyTestAttributes(t, rng, 30_000_000)
+	orig = *attrs
+	origGasLimit := *attrs.GasLimit
+	seq.applyPolicy(context.Background(), eth.L2BlockRef{}, attrs)
+	require.Equal(t, orig.Transactions, attrs.Transactions)
+	require.Equal(t, origGasLimit, *attrs.GasLimit)
+}
+
+// rejectingEngine is an engine that rejects all blocks that include any of the rejected transactions.
+type rejectingEngine struct {
+	derive.ResettableEngineControl
+	rejected map[string]struct{}
+	started  []*eth.PayloadAttributes
+}
+
+func (e *rejectingEngine) StartPayload(ctx context.Context, parent eth.L2BlockRef, attrs *eth.PayloadAttributes, updateSafe bool) (derive.BlockInsertionErrType, error) {
+	for _, tx := range attrs.Transactions {
+		if _, ok := e.rejected[string(tx)]; ok {
+			return derive.BlockInsertPayloadErr, errors.New("failed to force-include tx: nonce too low")
+		}
+	}
+	e.started = append(e.started, attrs)
+	return derive.BlockInsertOK, nil
+}
+
+// TestSequencerPolicyEngineRejection tests that policy transactions that the engine rejects don't halt the sequencer:
+// the block is built without them, and they are dropped from the policy.
+func TestSequencerPolicyEngineRejection(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	ctx := context.Background()
+	txA, txB := policyTestTx(t, rng), policyTestTx(t, rng)
+	policy := NewRuntimeSequencerPolicy()
+	require.NoError(t, policy.SetConfig(SequencerPolicyConfig{Transactions: []hexutil.Bytes{txA}}))
+	engine := &rejectingEngine{rejected: map[string]struct{}{string(txA): {}}}
+	seq := &Sequencer{log: testlog.Logger(t, log.LvlCrit), engine: engine, policy: policy}
+
+	attrs := policyTestAttributes(t, rng, 30_000_000)
+	deposit := attrs.Transactions[0]
+	policyTxs := seq.applyPolicy(ctx, eth.L2BlockRef{}, attrs)
+	require.Equal(t, []eth.Data{eth.Data(txA)}, policyTxs)
+	errTyp, err := seq.startPayload(ctx, eth.L2BlockRef{}, attrs, policyTxs)
+	require.NoError(t, err)
+	require.Equal(t, derive.BlockInsertOK, errTyp)
+	require.Len(t, engine.started, 1)
+	require.Equal(t, []eth.Data{deposit}, engine.started[0].Transactions)
+	require.Empty(t, policy.Config().Transactions)
+	require.Equal(t, []hexutil.Bytes{txA}, policy.Config().RejectedTransactions)
+
+	// valid transactions are kept in the policy if the engine rejects the block for other reasons
+	require.NoError(t, policy.SetConfig(SequencerPolicyConfig{Transactions: []hexutil.Bytes{txB}}))
+	require.Empty(t, policy.Config().RejectedTransactions)
+	attrs = policyTestAttributes(t, rng, 30_000_000)
+	engine.rejected = map[string]struct{}{string(attrs.Transactions[0]): {}}
+	policyTxs = seq.applyPolicy(ctx, eth.L2BlockRef{}, attrs)
+	errTyp, err = seq.startPayload(ctx, eth.L2BlockRef{}, attrs, policyTxs)
+	require.Error(t, err)
+	require.Equal(t, derive.BlockInsertPayloadErr, errTyp)
+	require.Equal(t, []hexutil.Bytes{txB}, policy.Config().Transactions)
+	require.Empty(t, policy.Config().RejectedTransactions)
+}
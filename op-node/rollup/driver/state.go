@@ -16,7 +16,6 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
-	"github.com/ethereum-optimism/optimism/op-node/sources"
 	"github.com/ethereum-optimism/optimism/op-service/backoff"
 )
 
@@ -64,8 +63,8 @@ type Driver struct {
 	l1SafeSig      chan eth.L1BlockRef
 	l1FinalizedSig chan eth.L1BlockRef
 
-	// Backup unsafe sync client
-	L2SyncCl *sources.SyncClient
+	// Alternative sync method for unsafe L2 payloads that were missed, may be nil
+	altSync AltSync
 
 	// L2 Signals:
 
@@ -76,6 +75,9 @@ type Driver struct {
 	sequencer SequencerIface
 	network   Network // may be nil, network for is optional
 
+	// sequencerPolicy is consulted by the sequencer for every new block, and changed through the admin RPC.
+	sequencerPolicy *RuntimeSequencerPolicy
+
 	metrics     Metrics
 	log         log.Logger
 	snapshotLog log.Logger
@@ -201,8 +203,8 @@ func (s *Driver) eventLoop() {
 	}
 
 	// Create a ticker to check if there is a gap in the engine queue every 15 seconds
-	// If there is, we send requests to the backup RPC to retrieve the missing payloads
-	// and add them to the unsafe queue.
+	// If there is, we request the missing payloads from the alternative sync method,
+	// which adds them to the unsafe queue.
 	altSyncTicker := time.NewTicker(15 * time.Second)
 	defer altSyncTicker.Stop()
 
@@ -238,8 +240,8 @@ func (s *Driver) eventLoop() {
 			planSequencerAction() // schedule the next sequencer action to keep the sequencing looping
 		case <-altSyncTicker.C:
 			// Check if there is a gap in the current unsafe payload queue. If there is, attempt to fetch
-			// missing payloads from the backup RPC (if it is configured).
-			if s.L2SyncCl != nil {
+			// missing payloads from the alternative sync method (if it is configured).
+			if s.altSync != nil {
 				s.checkForGapInUnsafeQueue(ctx)
 			}
 		case payload := <-s.unsafeL2Payloads:
@@ -387,6 +389,23 @@ func (s *Driver) StopSequencer(ctx context.Context) (common.Hash, error) {
 	}
 }
 
+// SequencerPolicy returns the current runtime policy of the sequencer.
+func (s *Driver) SequencerPolicy(ctx context.Context) (*SequencerPolicyConfig, error) {
+	if !s.driverConfig.SequencerEnabled {
+		return nil, errors.New("sequencer is not enabled")
+	}
+	cfg := s.sequencerPolicy.Config()
+	return &cfg, nil
+}
+
+// SetSequencerPolicy replaces the runtime policy of the sequencer, it applies from the next block that the sequencer starts building.
+func (s *Driver) SetSequencerPolicy(ctx context.Context, cfg SequencerPolicyConfig) error {
+	if !s.driverConfig.SequencerEnabled {
+		return errors.New("sequencer is not enabled")
+	}
+	return s.sequencerPolicy.SetConfig(cfg)
+}
+
 // syncStatus returns the current sync status, and should only be called synchronously with
 // the driver event loop to avoid retrieval of an inconsistent status.
 func (s *Driver) syncStatus() *eth.SyncStatus {
@@ -432,6 +451,20 @@ func (s *Driver) BlockRefWithStatus(ctx context.Context, num uint64) (eth.L2Bloc
 	}
 }
 
+// DerivationState blocks the driver event loop and captures the data buffered in the derivation pipeline.
+// If the event loop is too busy and the context expires, a context error is returned.
+func (s *Driver) DerivationState(ctx context.Context) (*derive.DerivationState, error) {
+	wait := make(chan struct{})
+	select {
+	case s.stateReq <- wait:
+		resp := s.derivation.DerivationState()
+		<-wait
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // deferJSONString helps avoid a JSON-encoding performance hit if the snapshot logger does not run
 type deferJSONString struct {
 	x any
@@ -462,8 +495,8 @@ type hashAndErrorChannel struct {
 	err  chan error
 }
 
-// checkForGapInUnsafeQueue checks if there is a gap in the unsafe queue and attempts to retrieve the missing payloads from the backup RPC.
-// WARNING: The sync client's attempt to retrieve the missing payloads is not guaranteed to succeed, and it will fail silently (besides
+// checkForGapInUnsafeQueue checks if there is a gap in the unsafe queue and attempts to retrieve the missing payloads from the alternative sync method.
+// WARNING: The alternative sync method is not guaranteed to retrieve the missing payloads, and it will fail silently (besides
 // emitting warning logs) if the requests fail.
 func (s *Driver) checkForGapInUnsafeQueue(ctx context.Context) {
 	// subtract genesis time from wall clock to get the time elapsed since genesis, and then divide that
@@ -480,17 +513,11 @@ func (s *Driver) checkForGapInUnsafeQueue(ctx context.Context) {
 	// Check if there is a gap between the unsafe head and the expected L2 block number at the current time.
 	if size > 0 {
 		s.log.Warn("Gap in payload queue tip and expected unsafe chain detected", "start", start, "end", end, "size", size)
-		s.log.Info("Attempting to fetch missing payloads from backup RPC", "start", start, "end", end, "size", size)
+		s.log.Info("Attempting to fetch missing payloads with alternative sync", "start", start, "end", end, "size", size)
 
-		// Attempt to fetch the missing payloads from the backup unsafe sync RPC concurrently.
 		// Concurrent requests are safe here due to the engine queue being a priority queue.
-		for blockNumber := start; blockNumber <= end; blockNumber++ {
-			select {
-			case s.L2SyncCl.FetchUnsafeBlock <- blockNumber:
-				// Do nothing- the block number was successfully sent into the channel
-			default:
-				return // If the channel is full, return and wait for the next iteration of the event loop
-			}
+		if err := s.altSync.RequestL2Range(ctx, start, end); err != nil {
+			s.log.Warn("Failed to request missing payloads", "start", start, "end", end, "err", err)
 		}
 	}
 }
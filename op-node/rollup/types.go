@@ -75,6 +75,20 @@ type Config struct {
 	// Active if RegolithTime != nil && L2 block timestamp >= *RegolithTime, inactive otherwise.
 	RegolithTime *uint64 `json:"regolith_time,omitempty"`
 
+	// ChannelCompressionTime sets the activation time of versioned channels:
+	// channel data may then be prefixed with a version byte that selects its compression algorithm,
+	// which allows stronger algorithms than zlib to be used by the batcher.
+	// Legacy channels, without version byte, remain valid.
+	// Active if ChannelCompressionTime != nil && L1 origin timestamp >= *ChannelCompressionTime, inactive otherwise.
+	ChannelCompressionTime *uint64 `json:"channel_compression_time,omitempty"`
+
+	// DACommitmentsTime sets the activation time of the retrieval of batch data from an alternative data-availability store:
+	// batcher transactions may then carry a keccak256 commitment to the data instead of the data itself,
+	// and the data is fetched from the DA store and verified against the commitment.
+	// Batcher transactions with the data itself remain valid.
+	// Active if DACommitmentsTime != nil && L1 block timestamp >= *DACommitmentsTime, inactive otherwise.
+	DACommitmentsTime *uint64 `json:"da_commitments_time,omitempty"`
+
 	// Note: below addresses are part of the block-derivation process,
 	// and required to be the same network-wide to stay in consensus.
 
@@ -242,6 +256,16 @@ func (c *Config) IsRegolith(timestamp uint64) bool {
 	return c.RegolithTime != nil && timestamp >= *c.RegolithTime
 }
 
+// IsChannelCompression returns true if versioned channels are valid at or past the given L1 timestamp.
+func (c *Config) IsChannelCompression(timestamp uint64) bool {
+	return c.ChannelCompressionTime != nil && timestamp >= *c.ChannelCompressionTime
+}
+
+// IsDACommitments returns true if DA commitments are valid at or past the given L1 timestamp.
+func (c *Config) IsDACommitments(timestamp uint64) bool {
+	return c.DACommitmentsTime != nil && timestamp >= *c.DACommitmentsTime
+}
+
 // Description outputs a banner describing the important parts of rollup configuration in a human-readable form.
 // Optionally provide a mapping of L2 chain IDs to network names to label the L2 chain with if not unknown.
 // The config should be config.Check()-ed before creating a description.
@@ -269,6 +293,8 @@ func (c *Config) Description(l2Chains map[string]string) string {
 	// Report the upgrade configuration
 	banner += "Post-Bedrock Network Upgrades (timestamp based):\n"
 	banner += fmt.Sprintf("  - Regolith: %s\n", fmtForkTimeOrUnset(c.RegolithTime))
+	banner += fmt.Sprintf("  - Channel compression: %s\n", fmtForkTimeOrUnset(c.ChannelCompressionTime))
+	banner += fmt.Sprintf("  - DA commitments: %s\n", fmtForkTimeOrUnset(c.DACommitmentsTime))
 	return banner
 }
 
@@ -291,7 +317,9 @@ func (c *Config) LogDescription(log log.Logger, l2Chains map[string]string) {
 	log.Info("Rollup Config", "l2_chain_id", c.L2ChainID, "l2_network", networkL2, "l1_chain_id", c.L1ChainID,
 		"l1_network", networkL1, "l2_start_time", c.Genesis.L2Time, "l2_block_hash", c.Genesis.L2.Hash.String(),
 		"l2_block_number", c.Genesis.L2.Number, "l1_block_hash", c.Genesis.L1.Hash.String(),
-		"l1_block_number", c.Genesis.L1.Number, "regolith_time", fmtForkTimeOrUnset(c.RegolithTime))
+		"l1_block_number", c.Genesis.L1.Number, "regolith_time", fmtForkTimeOrUnset(c.RegolithTime),
+		"channel_compression_time", fmtForkTimeOrUnset(c.ChannelCompressionTime),
+		"da_commitments_time", fmtForkTimeOrUnset(c.DACommitmentsTime))
 }
 
 func fmtForkTimeOrUnset(v *uint64) string {
@@ -20,6 +20,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/node"
+	"github.com/ethereum-optimism/optimism/op-node/node/conductor"
 	p2pcli "github.com/ethereum-optimism/optimism/op-node/p2p/cli"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
@@ -38,7 +39,7 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 
 	driverConfig := NewDriverConfig(ctx)
 
-	p2pSignerSetup, err := p2pcli.LoadSignerSetup(ctx)
+	p2pSignerSetup, err := p2pcli.LoadSignerSetup(ctx, log)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load p2p signer: %w", err)
 	}
@@ -67,6 +68,7 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 			ListenAddr:  ctx.GlobalString(flags.RPCListenAddr.Name),
 			ListenPort:  ctx.GlobalInt(flags.RPCListenPort.Name),
 			EnableAdmin: ctx.GlobalBool(flags.RPCEnableAdmin.Name),
+			EnableDebug: ctx.GlobalBool(flags.RPCEnableDebug.Name),
 		},
 		Metrics: node.MetricsConfig{
 			Enabled:    ctx.GlobalBool(flags.MetricsEnabledFlag.Name),
@@ -78,14 +80,23 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 			ListenAddr: ctx.GlobalString(flags.PprofAddrFlag.Name),
 			ListenPort: ctx.GlobalInt(flags.PprofPortFlag.Name),
 		},
-		P2P:                 p2pConfig,
-		P2PSigner:           p2pSignerSetup,
-		L1EpochPollInterval: ctx.GlobalDuration(flags.L1EpochPollIntervalFlag.Name),
+		P2P:                  p2pConfig,
+		P2PSigner:            p2pSignerSetup,
+		P2PSignerGraceBlocks: ctx.GlobalUint64(flags.SequencerP2PGraceBlocksFlag.Name),
+		L1EpochPollInterval:  ctx.GlobalDuration(flags.L1EpochPollIntervalFlag.Name),
+		L1Quorum:             int(ctx.GlobalUint(flags.L1QuorumFlag.Name)),
 		Heartbeat: node.HeartbeatConfig{
 			Enabled: ctx.GlobalBool(flags.HeartbeatEnabledFlag.Name),
 			Moniker: ctx.GlobalString(flags.HeartbeatMonikerFlag.Name),
 			URL:     ctx.GlobalString(flags.HeartbeatURLFlag.Name),
 		},
+		SafeDBPath: ctx.GlobalString(flags.SafeDBPath.Name),
+		DAStore:    ctx.GlobalString(flags.DAStoreFlag.Name),
+		Conductor:  NewConductorConfig(ctx),
+	}
+	if cfg.Conductor != nil {
+		// the conductor starts the sequencer once the node holds the sequencer lease
+		cfg.Driver.SequencerStopped = true
 	}
 	if err := cfg.Check(); err != nil {
 		return nil, err
@@ -148,6 +159,21 @@ func NewDriverConfig(ctx *cli.Context) *driver.Config {
 	}
 }
 
+// NewConductorConfig returns a ConductorConfig if sequencer failover is enabled, otherwise nil.
+func NewConductorConfig(ctx *cli.Context) *node.ConductorConfig {
+	if !ctx.GlobalBool(flags.ConductorEnabledFlag.Name) {
+		return nil
+	}
+	return &node.ConductorConfig{
+		Config: conductor.Config{
+			ID:            ctx.GlobalString(flags.ConductorIDFlag.Name),
+			LeaseDuration: ctx.GlobalDuration(flags.ConductorLeaseDurationFlag.Name),
+			RenewInterval: ctx.GlobalDuration(flags.ConductorRenewIntervalFlag.Name),
+		},
+		LeasePath: ctx.GlobalString(flags.ConductorLeasePathFlag.Name),
+	}
+}
+
 func NewRollupConfig(ctx *cli.Context) (*rollup.Config, error) {
 	network := ctx.GlobalString(flags.Network.Name)
 	if network != "" {
@@ -11,8 +11,11 @@ package sources
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -47,6 +50,8 @@ type EthClientConfig struct {
 	// and instead verify against the block-hash.
 	// Of real L1 blocks no deposits can be missed/faked, no batches can be missed/faked,
 	// only the wrong L1 blocks can be retrieved.
+	// Transactions, receipts and storage proofs are verified against the block header regardless,
+	// the header itself is only verified against the block-hash if the RPC is untrusted.
 	TrustRPC bool
 
 	// If the RPC must ensure that the results fit the ExecutionPayload(Header) format.
@@ -97,6 +102,8 @@ type EthClient struct {
 
 	log log.Logger
 
+	verifMetrics VerificationMetrics
+
 	// cache receipts in bundles per block hash
 	// We cache the receipts fetching job to not lose progress when we have to retry the `Fetch` call
 	// common.Hash -> *receiptsFetchingJob
@@ -118,14 +125,51 @@ type EthClient struct {
 	// This may be modified concurrently, but we don't lock since it's a single
 	// uint64 that's not critical (fine to miss or mix up a modification)
 	availableReceiptMethods ReceiptsFetchingMethod
+
+	// mismatchedReceiptMethods tracks the receipt methods that were disabled because their receipts failed
+	// verification. They are available again after receiptsMismatchResetDuration, since the RPC may have served
+	// the bad receipts temporarily, e.g. during a reorg.
+	mismatchedReceiptMethods ReceiptsFetchingMethod
+	mismatchResetTime        time.Time
+	mismatchLock             sync.Mutex
+
+	// now is the clock used to re-enable receipt methods, mocked in tests
+	now func() time.Time
 }
 
+// receiptsMismatchResetDuration is how long a receipts fetching method is not used after its receipts failed verification.
+const receiptsMismatchResetDuration = 10 * time.Minute
+
 func (s *EthClient) PickReceiptsMethod(txCount uint64) ReceiptsFetchingMethod {
+	s.mismatchLock.Lock()
+	if s.mismatchedReceiptMethods != 0 && !s.now().Before(s.mismatchResetTime) {
+		s.availableReceiptMethods |= s.mismatchedReceiptMethods
+		s.log.Info("receipt methods that failed verification are available again",
+			"provider_kind", s.provKind, "methods", s.mismatchedReceiptMethods)
+		s.mismatchedReceiptMethods = 0
+	}
+	s.mismatchLock.Unlock()
 	return PickBestReceiptsFetchingMethod(s.provKind, s.availableReceiptMethods, txCount)
 }
 
 func (s *EthClient) OnReceiptsMethodErr(m ReceiptsFetchingMethod, err error) {
-	if unusableMethod(err) {
+	s.recordVerificationErr(m.String(), err)
+	if errors.Is(err, ErrReceiptsMismatch) {
+		if m == EthGetTransactionReceiptBatch {
+			// the standard method is the last resort, there is nothing to fall back to
+			s.log.Error("receipts of standard RPC method failed verification", "provider_kind", s.provKind, "err", err)
+			return
+		}
+		// the RPC serves inconsistent receipts with this method, do not use it for a while
+		s.mismatchLock.Lock()
+		s.mismatchedReceiptMethods |= m
+		s.mismatchResetTime = s.now().Add(receiptsMismatchResetDuration)
+		s.availableReceiptMethods &^= m
+		s.mismatchLock.Unlock()
+		s.log.Warn("receipts of selected RPC method failed verification, falling back to alternatives",
+			"provider_kind", s.provKind, "failed_method", m, "fallback", s.availableReceiptMethods,
+			"retry_in", receiptsMismatchResetDuration, "err", err)
+	} else if unusableMethod(err) {
 		// clear the bit of the method that errored
 		s.availableReceiptMethods &^= m
 		s.log.Warn("failed to use selected RPC method for receipt fetching, falling back to alternatives",
@@ -138,11 +182,16 @@ func (s *EthClient) OnReceiptsMethodErr(m ReceiptsFetchingMethod, err error) {
 
 // NewEthClient returns an [EthClient], wrapping an RPC with bindings to fetch ethereum data with added error logging,
 // metric tracking, and caching. The [EthClient] uses a [LimitRPC] wrapper to limit the number of concurrent RPC requests.
+// Verification failures are tracked if the metrics implement [VerificationMetrics].
 func NewEthClient(client client.RPC, log log.Logger, metrics caching.Metrics, config *EthClientConfig) (*EthClient, error) {
 	if err := config.Check(); err != nil {
 		return nil, fmt.Errorf("bad config, cannot create L1 source: %w", err)
 	}
 	client = LimitRPC(client, config.MaxConcurrentRequests)
+	var verifMetrics VerificationMetrics = noopVerificationMetrics{}
+	if m, ok := metrics.(VerificationMetrics); ok {
+		verifMetrics = m
+	}
 	return &EthClient{
 		client:                  client,
 		maxBatchSize:            config.MaxRequestsPerBatch,
@@ -150,14 +199,23 @@ func NewEthClient(client client.RPC, log log.Logger, metrics caching.Metrics, co
 		mustBePostMerge:         config.MustBePostMerge,
 		provKind:                config.RPCProviderKind,
 		log:                     log,
+		verifMetrics:            verifMetrics,
 		receiptsCache:           caching.NewLRUCache(metrics, "receipts", config.ReceiptsCacheSize),
 		transactionsCache:       caching.NewLRUCache(metrics, "txs", config.TransactionsCacheSize),
 		headersCache:            caching.NewLRUCache(metrics, "headers", config.HeadersCacheSize),
 		payloadsCache:           caching.NewLRUCache(metrics, "payloads", config.PayloadsCacheSize),
 		availableReceiptMethods: AvailableReceiptsFetchingMethods(config.RPCProviderKind),
+		now:                     time.Now,
 	}, nil
 }
 
+// recordVerificationErr records the error in the metrics if it is a verification error.
+func (s *EthClient) recordVerificationErr(method string, err error) {
+	if data, ok := verificationData(err); ok {
+		s.verifMetrics.RecordVerificationFailure(s.provKind.String(), method, data)
+	}
+}
+
 // SubscribeNewHead subscribes to notifications about the current blockchain head on the given channel.
 func (s *EthClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
 	// Note that *types.Header does not cache the block hash unlike *HeaderInfo, it always recomputes.
@@ -176,6 +234,7 @@ func (s *EthClient) headerCall(ctx context.Context, method string, id any) (*Hea
 	}
 	info, err := header.Info(s.trustRPC, s.mustBePostMerge)
 	if err != nil {
+		s.recordVerificationErr(method, err)
 		return nil, err
 	}
 	s.headersCache.Add(info.Hash(), info)
@@ -193,6 +252,7 @@ func (s *EthClient) blockCall(ctx context.Context, method string, id any) (*Head
 	}
 	info, txs, err := block.Info(s.trustRPC, s.mustBePostMerge)
 	if err != nil {
+		s.recordVerificationErr(method, err)
 		return nil, nil, err
 	}
 	s.headersCache.Add(info.Hash(), info)
@@ -211,6 +271,7 @@ func (s *EthClient) payloadCall(ctx context.Context, method string, id any) (*et
 	}
 	payload, err := block.ExecutionPayload(s.trustRPC)
 	if err != nil {
+		s.recordVerificationErr(method, err)
 		return nil, err
 	}
 	s.payloadsCache.Add(payload.BlockHash, payload)
@@ -341,23 +402,25 @@ func (s *EthClient) GetStorageAt(ctx context.Context, address common.Address, st
 }
 
 // ReadStorageAt is a convenience method to read a single storage value at the given slot in the given account.
-// The storage slot value is verified against the state-root of the given block if we do not trust the RPC provider, or directly retrieved without proof if we do trust the RPC.
+// The storage slot value is retrieved with eth_getProof, and verified against the state-root of the given block.
+// Only if we do trust the RPC provider, and it does not support eth_getProof, the value is directly retrieved without proof.
 func (s *EthClient) ReadStorageAt(ctx context.Context, address common.Address, storageSlot common.Hash, blockHash common.Hash) (common.Hash, error) {
-	if s.trustRPC {
-		return s.GetStorageAt(ctx, address, storageSlot, blockHash.String())
-	}
 	block, err := s.InfoByHash(ctx, blockHash)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to retrieve state root of block %s: %w", blockHash, err)
 	}
 
 	result, err := s.GetProof(ctx, address, []common.Hash{storageSlot}, blockHash.String())
-	if err != nil {
+	if err != nil && s.trustRPC && unusableMethod(err) {
+		return s.GetStorageAt(ctx, address, storageSlot, blockHash.String())
+	} else if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to fetch proof of storage slot %s at block %s: %w", storageSlot, blockHash, err)
 	}
 
 	if err := result.Verify(block.Root()); err != nil {
-		return common.Hash{}, fmt.Errorf("failed to verify retrieved proof against state root: %w", err)
+		err = fmt.Errorf("%w: %v", ErrStorageProofMismatch, err)
+		s.recordVerificationErr("eth_getProof", err)
+		return common.Hash{}, err
 	}
 	value := result.StorageProof[0].Value.ToInt()
 	return common.BytesToHash(value.Bytes()), nil
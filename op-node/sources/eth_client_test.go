@@ -2,9 +2,11 @@ package sources
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/stretchr/testify/mock"
@@ -13,11 +15,13 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
 )
 
 type mockRPC struct {
@@ -140,3 +144,147 @@ func TestEthClient_InfoByNumber(t *testing.T) {
 	require.Equal(t, info, expectedInfo)
 	m.Mock.AssertExpectations(t)
 }
+
+type testVerificationMetrics struct {
+	failures []string
+}
+
+func (m *testVerificationMetrics) CacheAdd(label string, cacheSize int, evicted bool) {}
+
+func (m *testVerificationMetrics) CacheGet(label string, hit bool) {}
+
+func (m *testVerificationMetrics) RecordVerificationFailure(provider string, method string, data string) {
+	m.failures = append(m.failures, provider+"/"+method+"/"+data)
+}
+
+func TestEthClient_ReadStorageAt(t *testing.T) {
+	ctx := context.Background()
+	addr := common.Address{0x42}
+	slot := randHash()
+	expectHeader := func(m *mockRPC, rhdr *rpcHeader) {
+		m.On("CallContext", ctx, new(*rpcHeader),
+			"eth_getBlockByHash", []any{rhdr.Hash, false}).Run(func(args mock.Arguments) {
+			*args[1].(**rpcHeader) = rhdr
+		}).Return([]error{nil})
+	}
+	expectProof := func(m *mockRPC, rhdr *rpcHeader, result *eth.AccountResult, err error) {
+		m.On("CallContext", ctx, new(*eth.AccountResult),
+			"eth_getProof", []any{addr, []common.Hash{slot}, rhdr.Hash.String()}).Run(func(args mock.Arguments) {
+			*args[1].(**eth.AccountResult) = result
+		}).Return([]error{err})
+	}
+
+	t.Run("invalid proof", func(t *testing.T) {
+		m := new(mockRPC)
+		_, rhdr := randHeader()
+		expectHeader(m, rhdr)
+		expectProof(m, rhdr, &eth.AccountResult{
+			Address:      addr,
+			Balance:      new(hexutil.Big),
+			StorageProof: []eth.StorageProofEntry{{Key: slot, Value: *(*hexutil.Big)(big.NewInt(1)), Proof: []hexutil.Bytes{{0x01, 0x02}}}},
+		}, nil)
+		metrics := new(testVerificationMetrics)
+		s, err := NewEthClient(m, nil, metrics, testEthClientConfig)
+		require.NoError(t, err)
+		_, err = s.ReadStorageAt(ctx, addr, slot, rhdr.Hash)
+		require.ErrorIs(t, err, ErrStorageProofMismatch)
+		require.Equal(t, []string{"basic/eth_getProof/storage"}, metrics.failures)
+		m.Mock.AssertExpectations(t)
+	})
+
+	t.Run("unsupported proof with trusted RPC", func(t *testing.T) {
+		m := new(mockRPC)
+		_, rhdr := randHeader()
+		expectHeader(m, rhdr)
+		expectProof(m, rhdr, nil, &methodNotFoundError{method: "eth_getProof"})
+		value := randHash()
+		m.On("CallContext", ctx, new(common.Hash),
+			"eth_getStorageAt", []any{addr, slot, rhdr.Hash.String()}).Run(func(args mock.Arguments) {
+			*args[1].(*common.Hash) = value
+		}).Return([]error{nil})
+		cfg := *testEthClientConfig
+		cfg.TrustRPC = true
+		s, err := NewEthClient(m, nil, nil, &cfg)
+		require.NoError(t, err)
+		got, err := s.ReadStorageAt(ctx, addr, slot, rhdr.Hash)
+		require.NoError(t, err)
+		require.Equal(t, value, got)
+		m.Mock.AssertExpectations(t)
+	})
+
+	t.Run("unsupported proof with untrusted RPC", func(t *testing.T) {
+		m := new(mockRPC)
+		_, rhdr := randHeader()
+		expectHeader(m, rhdr)
+		expectProof(m, rhdr, nil, &methodNotFoundError{method: "eth_getProof"})
+		s, err := NewEthClient(m, nil, nil, testEthClientConfig)
+		require.NoError(t, err)
+		_, err = s.ReadStorageAt(ctx, addr, slot, rhdr.Hash)
+		require.Error(t, err)
+		m.Mock.AssertExpectations(t)
+	})
+}
+
+func TestEthClient_InfoByHashMismatch(t *testing.T) {
+	m := new(mockRPC)
+	_, rhdr := randHeader()
+	ctx := context.Background()
+	hash := rhdr.Hash
+	rhdr.Root = randHash() // the header does not match the block hash anymore
+	m.On("CallContext", ctx, new(*rpcHeader),
+		"eth_getBlockByHash", []any{hash, false}).Run(func(args mock.Arguments) {
+		*args[1].(**rpcHeader) = rhdr
+	}).Return([]error{nil})
+	metrics := new(testVerificationMetrics)
+	s, err := NewEthClient(m, nil, metrics, testEthClientConfig)
+	require.NoError(t, err)
+	_, err = s.InfoByHash(ctx, hash)
+	require.ErrorIs(t, err, ErrBlockHashMismatch)
+	require.Equal(t, []string{"basic/eth_getBlockByHash/block_hash"}, metrics.failures)
+	m.Mock.AssertExpectations(t)
+}
+
+func TestEthClient_InfoAndTxsTrustedRPCMismatch(t *testing.T) {
+	m := new(mockRPC)
+	_, rhdr := randHeader()
+	ctx := context.Background()
+	// the transactions do not match the tx-root, which is checked even if the block hash is not
+	block := &rpcBlock{rpcHeader: *rhdr}
+	m.On("CallContext", ctx, new(*rpcBlock),
+		"eth_getBlockByHash", []any{rhdr.Hash, true}).Run(func(args mock.Arguments) {
+		*args[1].(**rpcBlock) = block
+	}).Return([]error{nil})
+	cfg := *testEthClientConfig
+	cfg.TrustRPC = true
+	metrics := new(testVerificationMetrics)
+	s, err := NewEthClient(m, nil, metrics, &cfg)
+	require.NoError(t, err)
+	_, _, err = s.InfoAndTxsByHash(ctx, rhdr.Hash)
+	require.ErrorIs(t, err, ErrTransactionsMismatch)
+	require.Equal(t, []string{"basic/eth_getBlockByHash/transactions"}, metrics.failures)
+	m.Mock.AssertExpectations(t)
+}
+
+func TestEthClient_ReceiptsMethodMismatchReset(t *testing.T) {
+	cfg := *testEthClientConfig
+	cfg.RPCProviderKind = RPCKindDebugGeth
+	s, err := NewEthClient(new(mockRPC), testlog.Logger(t, log.LvlError), nil, &cfg)
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	require.Equal(t, DebugGetRawReceipts, s.PickReceiptsMethod(10))
+	s.OnReceiptsMethodErr(DebugGetRawReceipts, fmt.Errorf("%w: bad receipts", ErrReceiptsMismatch))
+	require.Equal(t, EthGetTransactionReceiptBatch, s.PickReceiptsMethod(10))
+
+	// the method is not used until the reset duration passed
+	now = now.Add(receiptsMismatchResetDuration - time.Second)
+	require.Equal(t, EthGetTransactionReceiptBatch, s.PickReceiptsMethod(10))
+	now = now.Add(time.Second)
+	require.Equal(t, DebugGetRawReceipts, s.PickReceiptsMethod(10))
+
+	// methods that are not supported by the RPC remain disabled
+	s.OnReceiptsMethodErr(DebugGetRawReceipts, &methodNotFoundError{method: "debug_getRawReceipts"})
+	now = now.Add(receiptsMismatchResetDuration)
+	require.Equal(t, EthGetTransactionReceiptBatch, s.PickReceiptsMethod(10))
+}
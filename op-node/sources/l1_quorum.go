@@ -0,0 +1,64 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+var ErrNoQuorum = errors.New("no quorum of L1 endpoints")
+
+// L1Quorum checks that a quorum of L1 endpoints agrees on a block,
+// i.e. that the endpoints have the same block hash at the height of the block.
+type L1Quorum struct {
+	endpoints []client.RPC
+	threshold int
+}
+
+// NewL1Quorum returns an L1Quorum that requires threshold of the endpoints to agree.
+func NewL1Quorum(endpoints []client.RPC, threshold int) (*L1Quorum, error) {
+	if threshold < 1 || threshold > len(endpoints) {
+		return nil, fmt.Errorf("quorum of %d is not possible with %d L1 endpoints", threshold, len(endpoints))
+	}
+	return &L1Quorum{endpoints: endpoints, threshold: threshold}, nil
+}
+
+// Check returns ErrNoQuorum if less than the threshold of endpoints agree on the block.
+// Endpoints that do not have a block at the height yet, or that fail to respond, do not agree.
+func (q *L1Quorum) Check(ctx context.Context, ref eth.L1BlockRef) error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		agree int
+	)
+	for _, endpoint := range q.endpoints {
+		wg.Add(1)
+		go func(endpoint client.RPC) {
+			defer wg.Done()
+			var header *rpcHeader
+			err := endpoint.CallContext(ctx, &header, "eth_getBlockByNumber", hexutil.EncodeUint64(ref.Number), false)
+			if err != nil || header == nil || uint64(header.Number) != ref.Number {
+				return
+			}
+			// verify the block hash, the endpoint should not be able to agree on a block it does not have
+			info, err := header.Info(false, false)
+			if err != nil || info.Hash() != ref.Hash {
+				return
+			}
+			mu.Lock()
+			agree++
+			mu.Unlock()
+		}(endpoint)
+	}
+	wg.Wait()
+	if agree < q.threshold {
+		return fmt.Errorf("%w: %d of %d endpoints agree on block %s, need %d", ErrNoQuorum, agree, len(q.endpoints), ref, q.threshold)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+func quorumEndpoint(rhdr *rpcHeader, err error) *mockRPC {
+	m := new(mockRPC)
+	m.On("CallContext", mock.Anything, new(*rpcHeader),
+		"eth_getBlockByNumber", []any{hexutil.EncodeUint64(1234), false}).Run(func(args mock.Arguments) {
+		*args[1].(**rpcHeader) = rhdr
+	}).Return([]error{err})
+	return m
+}
+
+func TestL1Quorum(t *testing.T) {
+	ctx := context.Background()
+	_, rhdr := randHeader()
+	_, otherHdr := randHeader()
+	ref := eth.L1BlockRef{Hash: rhdr.Hash, Number: uint64(rhdr.Number), ParentHash: rhdr.ParentHash, Time: uint64(rhdr.Time)}
+	// an endpoint that reports the hash of the block, but not its contents, does not agree
+	badHdr := *otherHdr
+	badHdr.Hash = rhdr.Hash
+
+	endpoints := []client.RPC{
+		quorumEndpoint(rhdr, nil),
+		quorumEndpoint(rhdr, nil),
+		quorumEndpoint(otherHdr, nil),
+		quorumEndpoint(&badHdr, nil),
+		quorumEndpoint(nil, nil),
+		quorumEndpoint(nil, errors.New("connection refused")),
+	}
+
+	q, err := NewL1Quorum(endpoints, 2)
+	require.NoError(t, err)
+	require.NoError(t, q.Check(ctx, ref))
+
+	q, err = NewL1Quorum(endpoints, 3)
+	require.NoError(t, err)
+	require.ErrorIs(t, q.Check(ctx, ref), ErrNoQuorum)
+
+	_, err = NewL1Quorum(endpoints, 7)
+	require.Error(t, err)
+	_, err = NewL1Quorum(endpoints, 0)
+	require.Error(t, err)
+}
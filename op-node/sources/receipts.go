@@ -170,9 +170,9 @@ func (r ReceiptsFetchingMethod) String() string {
 		if x&m != 0 {
 			out += v
 			x ^= x & m
-		}
-		if x != 0 { // add separator if there are entries left
-			out += ", "
+			if x != 0 { // add separator if there are entries left
+				out += ", "
+			}
 		}
 	}
 	addMaybe(EthGetTransactionReceiptBatch, "eth_getTransactionReceipt (batched)")
@@ -358,7 +358,8 @@ func NewReceiptsFetchingJob(requester ReceiptsRequester, client rpcClient, maxBa
 }
 
 // ReceiptsRequester helps determine which receipts fetching method can be used,
-// and is given feedback upon receipt fetching errors to adapt the choice of method.
+// and is given feedback upon receipt fetching errors, including receipts that fail verification
+// (ErrReceiptsMismatch), to adapt the choice of method.
 type ReceiptsRequester interface {
 	PickReceiptsMethod(txCount uint64) ReceiptsFetchingMethod
 	OnReceiptsMethodErr(m ReceiptsFetchingMethod, err error)
@@ -390,6 +391,8 @@ func (job *receiptsFetchingJob) runFetcher(ctx context.Context) error {
 	}
 	if err := validateReceipts(job.block, job.receiptHash, job.txHashes, result); err != nil {
 		job.fetcher.Reset() // if results are fetched but invalid, try restart all the fetching to try and get valid data.
+		err = fmt.Errorf("%w: %v", ErrReceiptsMismatch, err)
+		job.requester.OnReceiptsMethodErr(EthGetTransactionReceiptBatch, err)
 		return err
 	}
 	// Remember the result, and don't keep the fetcher and tx hashes around for longer than needed
@@ -458,6 +461,8 @@ func (job *receiptsFetchingJob) runAltMethod(ctx context.Context, m ReceiptsFetc
 		return err
 	} else {
 		if err := validateReceipts(job.block, job.receiptHash, job.txHashes, result); err != nil {
+			err = fmt.Errorf("%w: %v", ErrReceiptsMismatch, err)
+			job.requester.OnReceiptsMethodErr(m, err)
 			return err
 		}
 		job.result = result
@@ -70,6 +70,8 @@ type ReceiptsRequest struct {
 	method ReceiptsFetchingMethod
 	result []*types.Receipt
 	err    error
+	// mismatch is set if the result does not match the block, and is expected to fail verification
+	mismatch bool
 }
 
 type methodNotFoundError struct{ method string }
@@ -150,7 +152,9 @@ func (tc *ReceiptsTestCase) Run(t *testing.T) {
 
 	for i, req := range requests {
 		info, result, err := ethCl.FetchReceipts(context.Background(), block.Hash)
-		if err == nil {
+		if req.mismatch {
+			require.ErrorIs(t, err, ErrReceiptsMismatch, fmt.Sprintf("req %d err", i))
+		} else if err == nil {
 			require.Nil(t, req.err, "error")
 			require.Equal(t, block.Hash, info.Hash(), fmt.Sprintf("req %d blockhash", i))
 			expectedJson, err := json.MarshalIndent(req.result, "", "  ")
@@ -303,3 +307,57 @@ func TestEthClient_FetchReceipts(t *testing.T) {
 		t.Run(tc.name, tc.Run)
 	}
 }
+
+func TestEthClient_FetchReceiptsMismatch(t *testing.T) {
+	// Each method serves receipts that fail verification, except the last:
+	// the client falls back to the next method, instead of retrying the inconsistent one.
+	mismatchCase := func(txCount uint64, methods ...ReceiptsFetchingMethod) func(t *testing.T) (*rpcBlock, []ReceiptsRequest) {
+		return func(t *testing.T) (*rpcBlock, []ReceiptsRequest) {
+			block, receipts := randomRpcBlockAndReceipts(rand.New(rand.NewSource(123)), txCount)
+			for _, r := range receipts {
+				r.ContractAddress = common.Address{}
+			}
+			var out []ReceiptsRequest
+			for _, m := range methods {
+				invalid := make([]*types.Receipt, len(receipts))
+				for i, r := range receipts {
+					cpy := *r
+					invalid[i] = &cpy
+				}
+				// a different status changes the receipts root, but passes the other sanity checks
+				invalid[len(invalid)-1].Status ^= 1
+				out = append(out, ReceiptsRequest{method: m, result: invalid, mismatch: true})
+			}
+			out[len(out)-1] = ReceiptsRequest{method: methods[len(methods)-1], result: receipts}
+			return block, out
+		}
+	}
+
+	testCases := []ReceiptsTestCase{
+		{
+			name:         "alchemy fallback",
+			providerKind: RPCKindAlchemy,
+			setup:        mismatchCase(40, AlchemyGetTransactionReceipts, EthGetBlockReceipts, EthGetTransactionReceiptBatch),
+		},
+		{
+			name:         "debug geth fallback",
+			providerKind: RPCKindDebugGeth,
+			setup:        mismatchCase(4, DebugGetRawReceipts, EthGetTransactionReceiptBatch),
+		},
+		{
+			name:         "any fallback",
+			providerKind: RPCKindAny,
+			setup: mismatchCase(4,
+				AlchemyGetTransactionReceipts,
+				DebugGetRawReceipts,
+				EthGetBlockReceipts,
+				ParityGetBlockReceipts,
+				EthGetTransactionReceiptBatch,
+			),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.Run)
+	}
+}
@@ -23,6 +23,7 @@ type receivePayload = func(ctx context.Context, from peer.ID, payload *eth.Execu
 type SyncClientInterface interface {
 	Start() error
 	Close() error
+	RequestL2Range(ctx context.Context, start, end uint64) error
 	fetchUnsafeBlockFromRpc(ctx context.Context, blockNumber uint64)
 }
 
@@ -75,6 +76,22 @@ func (s *SyncClient) Close() error {
 	return nil
 }
 
+// RequestL2Range queues the blocks in the range [start, end] to be fetched from the backup RPC.
+// If the queue is full, the remaining blocks are not requested, and should be requested again later.
+func (s *SyncClient) RequestL2Range(ctx context.Context, start, end uint64) error {
+	for blockNumber := start; blockNumber <= end; blockNumber++ {
+		select {
+		case s.FetchUnsafeBlock <- blockNumber:
+			// Do nothing- the block number was successfully sent into the channel
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			return nil // If the channel is full, return and wait for the next iteration of the event loop
+		}
+	}
+	return nil
+}
+
 // eventLoop is the main event loop for the sync client.
 func (s *SyncClient) eventLoop() {
 	defer s.wg.Done()
@@ -25,7 +25,7 @@ type CallContextFn func(ctx context.Context, result any, method string, args ...
 // - batched calls of many block requests (standard bindings do extra uncle-header fetches, cannot be batched nicely)
 // - ignore uncle data (does not even exist anymore post-Merge)
 // - use cached block hash, if we trust the RPC.
-// - verify transactions list matches tx-root, to ensure consistency with block-hash, if we do not trust the RPC
+// - verify transactions list matches tx-root, and the header matches the block-hash if we do not trust the RPC
 // - verify block contents are compatible with Post-Merge ExecutionPayload format
 //
 // Transaction-sender data from the RPC is not cached, since ethclient.setSenderFromServer is private,
@@ -179,7 +179,7 @@ func (hdr *rpcHeader) Info(trustCache bool, mustBePostMerge bool) (*HeaderInfo,
 	}
 	if !trustCache {
 		if computed := hdr.computeBlockHash(); computed != hdr.Hash {
-			return nil, fmt.Errorf("failed to verify block hash: computed %s but RPC said %s", computed, hdr.Hash)
+			return nil, fmt.Errorf("%w: computed %s but RPC said %s", ErrBlockHashMismatch, computed, hdr.Hash)
 		}
 	}
 
@@ -205,12 +205,16 @@ type rpcBlock struct {
 	Transactions []*types.Transaction `json:"transactions"`
 }
 
-func (block *rpcBlock) verify() error {
-	if computed := block.computeBlockHash(); computed != block.Hash {
-		return fmt.Errorf("failed to verify block hash: computed %s but RPC said %s", computed, block.Hash)
+// verify checks that the transactions match the tx-root of the header,
+// and that the header matches the block-hash if the RPC is not trusted.
+func (block *rpcBlock) verify(trustCache bool) error {
+	if !trustCache {
+		if computed := block.computeBlockHash(); computed != block.Hash {
+			return fmt.Errorf("%w: computed %s but RPC said %s", ErrBlockHashMismatch, computed, block.Hash)
+		}
 	}
 	if computed := types.DeriveSha(types.Transactions(block.Transactions), trie.NewStackTrie(nil)); block.TxHash != computed {
-		return fmt.Errorf("failed to verify transactions list: computed %s but RPC said %s", computed, block.TxHash)
+		return fmt.Errorf("%w: computed %s but RPC said %s", ErrTransactionsMismatch, computed, block.TxHash)
 	}
 	return nil
 }
@@ -221,10 +225,8 @@ func (block *rpcBlock) Info(trustCache bool, mustBePostMerge bool) (*HeaderInfo,
 			return nil, nil, err
 		}
 	}
-	if !trustCache {
-		if err := block.verify(); err != nil {
-			return nil, nil, err
-		}
+	if err := block.verify(trustCache); err != nil {
+		return nil, nil, err
 	}
 
 	// verify the header data
@@ -240,10 +242,8 @@ func (block *rpcBlock) ExecutionPayload(trustCache bool) (*eth.ExecutionPayload,
 	if err := block.checkPostMerge(); err != nil {
 		return nil, err
 	}
-	if !trustCache {
-		if err := block.verify(); err != nil {
-			return nil, err
-		}
+	if err := block.verify(trustCache); err != nil {
+		return nil, err
 	}
 	var baseFee uint256.Int
 	baseFee.SetFromBig((*big.Int)(block.BaseFee))
@@ -0,0 +1,43 @@
+package sources
+
+import (
+	"errors"
+)
+
+// Errors of RPC data that does not match the block header it belongs to.
+// A mismatch is not temporary: the RPC serves inconsistent data, and the request should not be retried as-is.
+var (
+	ErrBlockHashMismatch    = errors.New("failed to verify block hash")
+	ErrTransactionsMismatch = errors.New("failed to verify transactions list")
+	ErrReceiptsMismatch     = errors.New("failed to verify receipts")
+	ErrStorageProofMismatch = errors.New("failed to verify storage proof")
+)
+
+// VerificationMetrics tracks RPC data that failed verification against the block header.
+// The metrics passed to NewEthClient may implement this interface.
+type VerificationMetrics interface {
+	// RecordVerificationFailure records a verification failure of the given data type,
+	// retrieved with the given RPC method from the given RPC provider kind.
+	RecordVerificationFailure(provider string, method string, data string)
+}
+
+type noopVerificationMetrics struct{}
+
+func (noopVerificationMetrics) RecordVerificationFailure(provider string, method string, data string) {
+}
+
+// verificationData returns the data type of a verification error, for metrics, or false if it is not a verification error.
+func verificationData(err error) (string, bool) {
+	switch {
+	case errors.Is(err, ErrBlockHashMismatch):
+		return "block_hash", true
+	case errors.Is(err, ErrTransactionsMismatch):
+		return "transactions", true
+	case errors.Is(err, ErrReceiptsMismatch):
+		return "receipts", true
+	case errors.Is(err, ErrStorageProofMismatch):
+		return "storage", true
+	default:
+		return "", false
+	}
+}
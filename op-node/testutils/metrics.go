@@ -9,6 +9,7 @@ type TestDerivationMetrics struct {
 	FnRecordL1Ref          func(name string, ref eth.L1BlockRef)
 	FnRecordL2Ref          func(name string, ref eth.L2BlockRef)
 	FnRecordUnsafePayloads func(length uint64, memSize uint64, next eth.BlockID)
+	FnRecordDAMismatch     func()
 }
 
 func (t *TestDerivationMetrics) RecordL1ReorgDepth(d uint64) {
@@ -35,6 +36,12 @@ func (t *TestDerivationMetrics) RecordUnsafePayloadsBuffer(length uint64, memSiz
 	}
 }
 
+func (t *TestDerivationMetrics) RecordDACommitmentMismatch() {
+	if t.FnRecordDAMismatch != nil {
+		t.FnRecordDAMismatch()
+	}
+}
+
 type TestRPCMetrics struct{}
 
 func (n *TestRPCMetrics) RecordRPCServerRequest(method string) func() {
@@ -4,8 +4,16 @@ import "github.com/ethereum/go-ethereum/common"
 
 type MockRuntimeConfig struct {
 	P2PSeqAddress common.Address
+	// P2PPrevSeqAddress is the previous p2p sequencer address, still accepted during a key rotation
+	P2PPrevSeqAddress common.Address
 }
 
-func (m *MockRuntimeConfig) P2PSequencerAddress() common.Address {
-	return m.P2PSeqAddress
+func (m *MockRuntimeConfig) P2PSequencerAddresses() []common.Address {
+	var out []common.Address
+	for _, addr := range []common.Address{m.P2PSeqAddress, m.P2PPrevSeqAddress} {
+		if addr != (common.Address{}) {
+			out = append(out, addr)
+		}
+	}
+	return out
 }
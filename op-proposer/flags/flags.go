@@ -41,31 +41,28 @@ var (
 		Required: true,
 		EnvVar:   opservice.PrefixEnvVar(envVarPrefix, "POLL_INTERVAL"),
 	}
+
+	/* Optional flags */
+
 	NumConfirmationsFlag = cli.Uint64Flag{
 		Name: "num-confirmations",
 		Usage: "Number of confirmations which we will wait after " +
-			"appending a new batch",
-		Required: true,
-		EnvVar:   opservice.PrefixEnvVar(envVarPrefix, "NUM_CONFIRMATIONS"),
+			"appending a new batch. Required unless in validator mode.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "NUM_CONFIRMATIONS"),
 	}
 	SafeAbortNonceTooLowCountFlag = cli.Uint64Flag{
 		Name: "safe-abort-nonce-too-low-count",
 		Usage: "Number of ErrNonceTooLow observations required to " +
 			"give up on a tx at a particular nonce without receiving " +
-			"confirmation",
-		Required: true,
-		EnvVar:   opservice.PrefixEnvVar(envVarPrefix, "SAFE_ABORT_NONCE_TOO_LOW_COUNT"),
+			"confirmation. Required unless in validator mode.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "SAFE_ABORT_NONCE_TOO_LOW_COUNT"),
 	}
 	ResubmissionTimeoutFlag = cli.DurationFlag{
 		Name: "resubmission-timeout",
 		Usage: "Duration we will wait before resubmitting a " +
-			"transaction to L1",
-		Required: true,
-		EnvVar:   opservice.PrefixEnvVar(envVarPrefix, "RESUBMISSION_TIMEOUT"),
+			"transaction to L1. Required unless in validator mode.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "RESUBMISSION_TIMEOUT"),
 	}
-
-	/* Optional flags */
-
 	MnemonicFlag = cli.StringFlag{
 		Name: "mnemonic",
 		Usage: "The mnemonic used to derive the wallets for either the " +
@@ -83,11 +80,46 @@ var (
 		Usage:  "The private key to use with the l2output wallet. Must not be used with mnemonic.",
 		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "PRIVATE_KEY"),
 	}
+	TxCancelTimeoutFlag = cli.DurationFlag{
+		Name: "tx-cancel-timeout",
+		Usage: "Duration after which a transaction that isn't mined yet is cancelled, by replacing it with a " +
+			"self-transfer at the same nonce. 0 disables cancellation.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "TX_CANCEL_TIMEOUT"),
+	}
 	AllowNonFinalizedFlag = cli.BoolFlag{
 		Name:   "allow-non-finalized",
 		Usage:  "Allow the proposer to submit proposals for L2 blocks derived from non-finalized L1 blocks.",
 		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "ALLOW_NON_FINALIZED"),
 	}
+	StoppedFlag = cli.BoolFlag{
+		Name:   "stopped",
+		Usage:  "Initialize the proposer in a stopped state. The proposer can be started using the admin_startProposer RPC",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "STOPPED"),
+	}
+	MaxCatchUpOutputsFlag = cli.Uint64Flag{
+		Name: "max-catch-up-outputs",
+		Usage: "Maximum number of backlogged outputs that are proposed back-to-back, without waiting for each " +
+			"to confirm. Catch-up is disabled if less than 2.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "MAX_CATCH_UP_OUTPUTS"),
+	}
+	ValidatorFlag = cli.BoolFlag{
+		Name: "validator",
+		Usage: "Run in validator mode: instead of proposing outputs, validate the outputs proposed to the " +
+			"L2OutputOracle against the rollup node. No proposer key is required.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "VALIDATOR"),
+	}
+	ValidatorWebhookURLFlag = cli.StringFlag{
+		Name:   "validator.webhook-url",
+		Usage:  "URL that a JSON alert is posted to when the validator finds a mismatching output.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "VALIDATOR_WEBHOOK_URL"),
+	}
+	ValidatorConfirmationDepthFlag = cli.Uint64Flag{
+		Name: "validator.confirmation-depth",
+		Usage: "Number of L1 blocks that a proposal must be buried under before the validator checks it, " +
+			"so that proposals that get reorged out of L1 don't raise alerts.",
+		Value:  10,
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "VALIDATOR_CONFIRMATION_DEPTH"),
+	}
 )
 
 var requiredFlags = []cli.Flag{
@@ -95,21 +127,28 @@ var requiredFlags = []cli.Flag{
 	RollupRpcFlag,
 	L2OOAddressFlag,
 	PollIntervalFlag,
-	NumConfirmationsFlag,
-	SafeAbortNonceTooLowCountFlag,
-	ResubmissionTimeoutFlag,
 }
 
 var optionalFlags = []cli.Flag{
+	NumConfirmationsFlag,
+	SafeAbortNonceTooLowCountFlag,
+	ResubmissionTimeoutFlag,
 	MnemonicFlag,
 	L2OutputHDPathFlag,
 	PrivateKeyFlag,
+	TxCancelTimeoutFlag,
 	AllowNonFinalizedFlag,
+	StoppedFlag,
+	MaxCatchUpOutputsFlag,
+	ValidatorFlag,
+	ValidatorWebhookURLFlag,
+	ValidatorConfirmationDepthFlag,
 }
 
 func init() {
 	requiredFlags = append(requiredFlags, oprpc.CLIFlags(envVarPrefix)...)
 
+	optionalFlags = append(optionalFlags, oprpc.AdminCLIFlags(envVarPrefix)...)
 	optionalFlags = append(optionalFlags, oplog.CLIFlags(envVarPrefix)...)
 	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(envVarPrefix)...)
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(envVarPrefix)...)
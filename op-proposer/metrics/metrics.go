@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+const Namespace = "op_proposer"
+
+type Metricer interface {
+	RecordInfo(version string)
+	RecordUp()
+
+	// Records stuck tx cancellations of the tx manager
+	txmgr.Metricer
+
+	RecordProposalBacklog(numCheckpoints uint64)
+	RecordOutputValidated(l2BlockNumber uint64, valid bool)
+
+	Document() []opmetrics.DocumentedMetric
+}
+
+type Metrics struct {
+	registry *prometheus.Registry
+	factory  opmetrics.Factory
+
+	txmgr.TxMetrics
+
+	Info prometheus.GaugeVec
+	Up   prometheus.Gauge
+
+	ProposalBacklog prometheus.Gauge
+
+	// label by valid, mismatch
+	OutputValidationEvs opmetrics.EventVec
+	// label by valid, mismatch
+	ValidatedL2Block prometheus.GaugeVec
+}
+
+var _ Metricer = (*Metrics)(nil)
+
+func NewMetrics(procName string) *Metrics {
+	if procName == "" {
+		procName = "default"
+	}
+	ns := Namespace + "_" + procName
+
+	registry := opmetrics.NewRegistry()
+	factory := opmetrics.With(registry)
+
+	return &Metrics{
+		registry: registry,
+		factory:  factory,
+
+		TxMetrics: txmgr.MakeTxMetrics(ns, factory),
+
+		Info: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "info",
+			Help:      "Pseudo-metric tracking version and config info",
+		}, []string{
+			"version",
+		}),
+		Up: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "up",
+			Help:      "1 if the op-proposer has finished starting up",
+		}),
+
+		ProposalBacklog: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "proposal_backlog",
+			Help:      "Number of L2 checkpoints that are ready for proposal but not proposed yet.",
+		}),
+
+		OutputValidationEvs: opmetrics.NewEventVec(factory, ns, "output_validation", "Output validation", []string{"result"}),
+		ValidatedL2Block: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "validated_l2_block",
+			Help:      "L2 block number of the latest validated output proposal, by validation result.",
+		}, []string{"result"}),
+	}
+}
+
+func (m *Metrics) Serve(ctx context.Context, host string, port int) error {
+	return opmetrics.ListenAndServe(ctx, m.registry, host, port)
+}
+
+func (m *Metrics) Document() []opmetrics.DocumentedMetric {
+	return m.factory.Document()
+}
+
+// StartBalanceMetrics periodically records the balance of the account. The
+// balance metric is registered without namespace, as it was before the other
+// proposer metrics were added, so existing dashboards and alerts keep working.
+func (m *Metrics) StartBalanceMetrics(ctx context.Context,
+	l log.Logger, client *ethclient.Client, account common.Address) {
+	opmetrics.LaunchBalanceMetrics(ctx, l, m.registry, "", client, account)
+}
+
+// RecordInfo sets a pseudo-metric that contains versioning and
+// config info for the op-proposer.
+func (m *Metrics) RecordInfo(version string) {
+	m.Info.WithLabelValues(version).Set(1)
+}
+
+// RecordUp sets the up metric to 1.
+func (m *Metrics) RecordUp() {
+	m.Up.Set(1)
+}
+
+// RecordProposalBacklog records the number of checkpoints that are ready for
+// proposal but not proposed yet.
+func (m *Metrics) RecordProposalBacklog(numCheckpoints uint64) {
+	m.ProposalBacklog.Set(float64(numCheckpoints))
+}
+
+const (
+	ValidationValid    = "valid"
+	ValidationMismatch = "mismatch"
+)
+
+// RecordOutputValidated records the result of validating a proposed output
+// against the output computed by the rollup node.
+func (m *Metrics) RecordOutputValidated(l2BlockNumber uint64, valid bool) {
+	result := ValidationValid
+	if !valid {
+		result = ValidationMismatch
+	}
+	m.OutputValidationEvs.Record(result)
+	m.ValidatedL2Block.WithLabelValues(result).Set(float64(l2BlockNumber))
+}
@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+type noopMetrics struct {
+	txmgr.NoopTxMetrics
+}
+
+var NoopMetrics Metricer = new(noopMetrics)
+
+func (*noopMetrics) Document() []opmetrics.DocumentedMetric { return nil }
+
+func (*noopMetrics) RecordInfo(version string) {}
+func (*noopMetrics) RecordUp()                 {}
+
+func (*noopMetrics) RecordProposalBacklog(uint64)       {}
+func (*noopMetrics) RecordOutputValidated(uint64, bool) {}
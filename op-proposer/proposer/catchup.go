@@ -0,0 +1,216 @@
+package proposer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+var errL1Reorg = errors.New("L1 block of proposal got reorged")
+
+// FetchBacklog returns the outputs of all checkpoints that are ready for
+// proposal, starting at the next checkpoint of the L2OutputOracle. At most
+// maxCatchUpOutputs outputs are returned, or one if catch-up is disabled. The
+// total number of ready checkpoints is recorded as the backlog metric.
+func (l *L2OutputSubmitter) FetchBacklog(ctx context.Context) ([]*eth.OutputResponse, error) {
+	nextCheckpointBlock, currentBlockNumber, err := l.fetchProposalRange(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if currentBlockNumber.Cmp(nextCheckpointBlock) < 0 {
+		l.log.Info("proposer submission interval has not elapsed", "currentBlockNumber", currentBlockNumber, "nextBlockNumber", nextCheckpointBlock)
+		l.metr.RecordProposalBacklog(0)
+		return nil, nil
+	}
+
+	next := nextCheckpointBlock.Uint64()
+	backlog := (currentBlockNumber.Uint64()-next)/l.submissionInterval + 1
+	l.metr.RecordProposalBacklog(backlog)
+
+	limit := backlog
+	if l.maxCatchUpOutputs < 2 {
+		limit = 1
+	} else if limit > l.maxCatchUpOutputs {
+		limit = l.maxCatchUpOutputs
+	}
+	var outputs []*eth.OutputResponse
+	for i := uint64(0); i < limit; i++ {
+		output, shouldPropose, err := l.fetchOutput(ctx, next+i*l.submissionInterval)
+		if err != nil {
+			if len(outputs) > 0 {
+				// propose what we have so far, the rest is tried again next round
+				break
+			}
+			return nil, err
+		}
+		if !shouldPropose {
+			break
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
+
+// catchUpL1Client is the L1 client interface used to propose a backlog.
+type catchUpL1Client interface {
+	txmgr.QueueBackend
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// proposeBacklog submits proposals of all outputs back-to-back, without
+// waiting for each to confirm. Nonces are assigned locally, in the order of the
+// outputs, so the proposals are executed in order. It stops submitting if the
+// L1 block of a proposal got reorged, and blocks until all submitted proposals
+// are confirmed or failed, or until the L1 block of a pending proposal got
+// reorged. If a proposal ran out of gas, the remaining outputs are proposed
+// again with a new gas estimate.
+func (l *L2OutputSubmitter) proposeBacklog(ctx context.Context, l1 catchUpL1Client, outputs []*eth.OutputResponse) {
+	l.log.Info("Catching up on proposal backlog", "num_outputs", len(outputs),
+		"first_l2_block", outputs[0].BlockRef.Number, "last_l2_block", outputs[len(outputs)-1].BlockRef.Number)
+	for len(outputs) > 0 {
+		next := l.proposeOutputs(ctx, l1, outputs)
+		if next == 0 {
+			return
+		}
+		l.log.Warn("Proposal ran out of gas, re-estimating gas of remaining outputs",
+			"l2_block", outputs[next].BlockRef.Number, "remaining", len(outputs)-next)
+		outputs = outputs[next:]
+	}
+}
+
+// proposeOutputs submits proposals of the outputs back-to-back, and waits for
+// them to confirm. It returns the index of the first output whose proposal ran
+// out of gas, if the outputs should be proposed again from there, or 0.
+func (l *L2OutputSubmitter) proposeOutputs(ctx context.Context, l1 catchUpL1Client, outputs []*eth.OutputResponse) int {
+	// The proposals after the first can't be estimated before their predecessors
+	// are executed, so they all use the estimate of the first one. The first
+	// proposal that runs out of gas is estimated again in the next round.
+	data, err := l.proposalTxData(outputs[0])
+	if err != nil {
+		l.log.Error("Failed to create proposal tx data", "err", err)
+		return 0
+	}
+	cCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	gas, err := l1.EstimateGas(cCtx, ethereum.CallMsg{From: l.from, To: &l.l2ooAddr, Data: data})
+	cancel()
+	if err != nil {
+		l.log.Error("Failed to estimate proposal gas", "err", err)
+		return 0
+	}
+	gas = gas * 6 / 5
+
+	qCtx, qCancel := context.WithCancel(ctx)
+	defer qCancel()
+	queue := txmgr.NewQueue[uint64](qCtx, "proposer", l.log, l.metr, l.txMgrConfig, l1, l.chainID, 0)
+	receiptCh := make(chan txmgr.TxReceipt[uint64], len(outputs))
+	// pending are the L1 blocks of the proposals that are not confirmed yet, by L2 block
+	pending := make(map[uint64]eth.L1BlockRef, len(outputs))
+	index := make(map[uint64]int, len(outputs))
+	for i, output := range outputs {
+		if err := l.checkL1Block(ctx, l1, output.Status.CurrentL1); err != nil {
+			l.log.Warn("Stopping catch-up", "l2_block", output.BlockRef.Number, "err", err)
+			break
+		}
+		data, err := l.proposalTxData(output)
+		if err != nil {
+			l.log.Error("Failed to create proposal tx data", "err", err)
+			break
+		}
+		queue.Send(output.BlockRef.Number, txmgr.TxCandidate{
+			To:       l.l2ooAddr,
+			TxData:   data,
+			GasLimit: gas,
+		}, receiptCh)
+		pending[output.BlockRef.Number] = output.Status.CurrentL1
+		index[output.BlockRef.Number] = i
+	}
+
+	// the proposals are executed in order, so all proposals after the first
+	// failed one fail too
+	firstFailed := len(outputs)
+	outOfGas := false
+	for len(pending) > 0 {
+		// the proposals of a reorged L1 block revert, so don't wait for them
+		if err := l.checkPendingL1Blocks(ctx, l1, pending); errors.Is(err, errL1Reorg) {
+			l.log.Warn("Aborting catch-up, L1 block of pending proposal got reorged", "pending", len(pending), "err", err)
+			qCancel()
+			queue.Wait()
+			return 0
+		} else if err != nil {
+			l.log.Warn("Failed to check L1 blocks of pending proposals", "err", err)
+		}
+		r := <-receiptCh
+		delete(pending, r.ID)
+		switch {
+		case r.Err != nil:
+			l.log.Error("Failed to send proposal transaction", "l2_block", r.ID, "err", r.Err)
+		case r.Receipt.Status != types.ReceiptStatusSuccessful:
+			l.log.Error("Proposal transaction reverted", "l2_block", r.ID, "tx_hash", r.Receipt.TxHash)
+		default:
+			l.log.Info("proposer tx successfully published", "l2_block", r.ID, "tx_hash", r.Receipt.TxHash)
+			continue
+		}
+		if i := index[r.ID]; i < firstFailed {
+			firstFailed = i
+			outOfGas = r.Err == nil && r.Receipt.GasUsed >= gas
+		}
+	}
+	queue.Wait()
+
+	// The gas of the first proposal is estimated, so it doesn't run out of gas
+	// unless the estimate is off. Then the next round would fail the same way.
+	if outOfGas && firstFailed > 0 {
+		return firstFailed
+	}
+	return 0
+}
+
+// proposalTxData returns the calldata of the proposal of the output.
+func (l *L2OutputSubmitter) proposalTxData(output *eth.OutputResponse) ([]byte, error) {
+	return l.l2ooABI.Pack(
+		"proposeL2Output",
+		[32]byte(output.OutputRoot),
+		new(big.Int).SetUint64(output.BlockRef.Number),
+		[32]byte(output.Status.CurrentL1.Hash),
+		new(big.Int).SetUint64(output.Status.CurrentL1.Number))
+}
+
+// checkL1Block checks that the L1 block is still canonical. The L2OutputOracle
+// rejects proposals that commit to a non-canonical L1 block.
+func (l *L2OutputSubmitter) checkL1Block(ctx context.Context, l1 catchUpL1Client, ref eth.L1BlockRef) error {
+	cCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	header, err := l1.HeaderByNumber(cCtx, new(big.Int).SetUint64(ref.Number))
+	if err != nil {
+		return fmt.Errorf("failed to fetch L1 block %d: %w", ref.Number, err)
+	}
+	if header.Hash() != ref.Hash {
+		return fmt.Errorf("%w: expected %s, got %s", errL1Reorg, ref, header.Hash())
+	}
+	return nil
+}
+
+// checkPendingL1Blocks checks that the L1 blocks of the pending proposals are
+// still canonical.
+func (l *L2OutputSubmitter) checkPendingL1Blocks(ctx context.Context, l1 catchUpL1Client, pending map[uint64]eth.L1BlockRef) error {
+	checked := make(map[common.Hash]struct{}, len(pending))
+	for _, ref := range pending {
+		if _, ok := checked[ref.Hash]; ok {
+			continue
+		}
+		if err := l.checkL1Block(ctx, l1, ref); err != nil {
+			return err
+		}
+		checked[ref.Hash] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,430 @@
+package proposer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+type mockL2OOContract struct {
+	nextBlockNumber uint64
+}
+
+func (m *mockL2OOContract) NextBlockNumber(opts *bind.CallOpts) (*big.Int, error) {
+	return new(big.Int).SetUint64(m.nextBlockNumber), nil
+}
+
+func (m *mockL2OOContract) LatestBlockNumber(opts *bind.CallOpts) (*big.Int, error) {
+	panic("not implemented")
+}
+
+func (m *mockL2OOContract) ProposeL2Output(opts *bind.TransactOpts, outputRoot [32]byte, l2BlockNumber *big.Int, l1BlockHash [32]byte, l1BlockNumber *big.Int) (*types.Transaction, error) {
+	panic("not implemented")
+}
+
+// mockL2Chain is a rollup node with the given safe and finalized L2 heads.
+// Fetching the output at failBlock fails.
+type mockL2Chain struct {
+	safe      uint64
+	finalized uint64
+	failBlock uint64
+}
+
+func (m *mockL2Chain) status() *eth.SyncStatus {
+	return &eth.SyncStatus{
+		SafeL2:      eth.L2BlockRef{Number: m.safe},
+		FinalizedL2: eth.L2BlockRef{Number: m.finalized},
+	}
+}
+
+func (m *mockL2Chain) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	return m.status(), nil
+}
+
+func (m *mockL2Chain) OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	if blockNum == m.failBlock {
+		return nil, errors.New("failed to fetch output")
+	}
+	return &eth.OutputResponse{
+		BlockRef: eth.L2BlockRef{Number: blockNum},
+		Status:   m.status(),
+	}, nil
+}
+
+type backlogMetrics struct {
+	metrics.Metricer
+	backlog uint64
+}
+
+func (m *backlogMetrics) RecordProposalBacklog(backlog uint64) {
+	m.backlog = backlog
+}
+
+func newTestSubmitter(t *testing.T, l2oo *mockL2OOContract, l2 *mockL2Chain, maxCatchUpOutputs uint64) (*L2OutputSubmitter, *backlogMetrics) {
+	m := &backlogMetrics{Metricer: metrics.NoopMetrics}
+	return &L2OutputSubmitter{
+		log:                testlog.Logger(t, log.LvlCrit),
+		metr:               m,
+		rollupClient:       l2,
+		l2ooContract:       l2oo,
+		submissionInterval: 10,
+		maxCatchUpOutputs:  maxCatchUpOutputs,
+	}, m
+}
+
+func requireOutputBlocks(t *testing.T, expected []uint64, outputs []*eth.OutputResponse) {
+	blocks := make([]uint64, 0, len(outputs))
+	for _, output := range outputs {
+		blocks = append(blocks, output.BlockRef.Number)
+	}
+	require.Equal(t, expected, blocks)
+}
+
+// proposeOutputs advances the next checkpoint of the L2OutputOracle past the
+// proposed outputs.
+func proposeOutputs(l2oo *mockL2OOContract, outputs []*eth.OutputResponse, interval uint64) {
+	if len(outputs) > 0 {
+		l2oo.nextBlockNumber = outputs[len(outputs)-1].BlockRef.Number + interval
+	}
+}
+
+func TestFetchBacklog_Drain(t *testing.T) {
+	l2oo := &mockL2OOContract{nextBlockNumber: 10}
+	l2 := &mockL2Chain{safe: 100, finalized: 100}
+	l, m := newTestSubmitter(t, l2oo, l2, 4)
+
+	var rounds [][]uint64
+	for {
+		outputs, err := l.FetchBacklog(context.Background())
+		require.NoError(t, err)
+		if len(outputs) == 0 {
+			break
+		}
+		var blocks []uint64
+		for _, output := range outputs {
+			blocks = append(blocks, output.BlockRef.Number)
+		}
+		rounds = append(rounds, blocks)
+		proposeOutputs(l2oo, outputs, l.submissionInterval)
+	}
+	require.Equal(t, [][]uint64{
+		{10, 20, 30, 40},
+		{50, 60, 70, 80},
+		{90, 100},
+	}, rounds)
+	require.Zero(t, m.backlog)
+}
+
+func TestFetchBacklog_BacklogMetric(t *testing.T) {
+	l2oo := &mockL2OOContract{nextBlockNumber: 10}
+	l2 := &mockL2Chain{safe: 100, finalized: 95}
+	l, m := newTestSubmitter(t, l2oo, l2, 4)
+
+	outputs, err := l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	requireOutputBlocks(t, []uint64{10, 20, 30, 40}, outputs)
+	require.Equal(t, uint64(9), m.backlog)
+}
+
+func TestFetchBacklog_CatchUpDisabled(t *testing.T) {
+	l2oo := &mockL2OOContract{nextBlockNumber: 10}
+	l2 := &mockL2Chain{safe: 100, finalized: 100}
+	l, _ := newTestSubmitter(t, l2oo, l2, 1)
+
+	outputs, err := l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	requireOutputBlocks(t, []uint64{10}, outputs)
+}
+
+func TestFetchBacklog_StopAtNotFinalized(t *testing.T) {
+	l2oo := &mockL2OOContract{nextBlockNumber: 10}
+	l2 := &mockL2Chain{safe: 45, finalized: 35}
+	l, _ := newTestSubmitter(t, l2oo, l2, 10)
+
+	// only the finalized outputs are proposed
+	outputs, err := l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	requireOutputBlocks(t, []uint64{10, 20, 30}, outputs)
+	proposeOutputs(l2oo, outputs, l.submissionInterval)
+
+	outputs, err = l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, outputs)
+
+	// the next output is proposed once it is finalized
+	l2.finalized = 40
+	outputs, err = l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	requireOutputBlocks(t, []uint64{40}, outputs)
+
+	// or once it is safe, if non-finalized outputs are allowed
+	l.allowNonFinalized = true
+	proposeOutputs(l2oo, outputs, l.submissionInterval)
+	l2.safe = 60
+	outputs, err = l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	requireOutputBlocks(t, []uint64{50, 60}, outputs)
+}
+
+func TestFetchBacklog_StopAtNotFinalizedOutput(t *testing.T) {
+	l2oo := &mockL2OOContract{nextBlockNumber: 10}
+	l2 := &mockL2Chain{safe: 100, finalized: 100}
+	l, _ := newTestSubmitter(t, l2oo, l2, 10)
+
+	// the finalized head moves back, e.g. on a rollup node reset, after the
+	// proposal range got fetched: outputs that aren't finalized aren't proposed
+	status := l2.status()
+	status.FinalizedL2.Number = 25
+	l.rollupClient = &fixedStatusL2Chain{mockL2Chain: l2, fixed: status}
+	outputs, err := l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	requireOutputBlocks(t, []uint64{10, 20}, outputs)
+}
+
+// fixedStatusL2Chain returns outputs with a fixed sync status, that is
+// different from the status returned by SyncStatus.
+type fixedStatusL2Chain struct {
+	*mockL2Chain
+	fixed *eth.SyncStatus
+}
+
+func (m *fixedStatusL2Chain) OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	output, err := m.mockL2Chain.OutputAtBlock(ctx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	output.Status = m.fixed
+	return output, nil
+}
+
+func TestFetchBacklog_ErrorMidBacklog(t *testing.T) {
+	l2oo := &mockL2OOContract{nextBlockNumber: 10}
+	l2 := &mockL2Chain{safe: 60, finalized: 60, failBlock: 40}
+	l, _ := newTestSubmitter(t, l2oo, l2, 10)
+
+	// the outputs before the failing output are proposed
+	outputs, err := l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	requireOutputBlocks(t, []uint64{10, 20, 30}, outputs)
+	proposeOutputs(l2oo, outputs, l.submissionInterval)
+
+	// the error is returned if the first output fails
+	_, err = l.FetchBacklog(context.Background())
+	require.Error(t, err)
+
+	// and the catch-up is retried from the failing output
+	l2.failBlock = 0
+	outputs, err = l.FetchBacklog(context.Background())
+	require.NoError(t, err)
+	requireOutputBlocks(t, []uint64{40, 50, 60}, outputs)
+}
+
+// mockL1 executes proposals in nonce order. A proposal succeeds if it has
+// enough gas and proposes the next checkpoint of the L2OutputOracle, else it
+// reverts. Transactions with a held nonce are not executed.
+type mockL1 struct {
+	mu sync.Mutex
+
+	l2ooABI     abi.ABI
+	interval    uint64
+	nextBlock   uint64
+	requiredGas map[uint64]uint64
+	hold        map[uint64]bool
+	onSend      func(tx *types.Transaction)
+
+	nonce     uint64
+	queued    map[uint64]*types.Transaction
+	receipts  map[common.Hash]*types.Receipt
+	header    *types.Header
+	estimates int
+}
+
+func newMockL1(l2ooABI abi.ABI, interval uint64, nextBlock uint64, requiredGas map[uint64]uint64) *mockL1 {
+	return &mockL1{
+		l2ooABI:     l2ooABI,
+		interval:    interval,
+		nextBlock:   nextBlock,
+		requiredGas: requiredGas,
+		hold:        make(map[uint64]bool),
+		queued:      make(map[uint64]*types.Transaction),
+		receipts:    make(map[common.Hash]*types.Receipt),
+		header:      &types.Header{Number: big.NewInt(1), BaseFee: big.NewInt(params.GWei)},
+	}
+}
+
+// reorg replaces the L1 block that the proposals refer to.
+func (m *mockL1) reorg() {
+	m.header = &types.Header{Number: m.header.Number, BaseFee: m.header.BaseFee, Extra: []byte("reorg")}
+}
+
+func (m *mockL1) l1Ref() eth.L1BlockRef {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return eth.L1BlockRef{Hash: m.header.Hash(), Number: m.header.Number.Uint64()}
+}
+
+func (m *mockL1) execute(tx *types.Transaction) {
+	args, err := m.l2ooABI.Methods["proposeL2Output"].Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		panic(err)
+	}
+	block := args[1].(*big.Int).Uint64()
+	receipt := &types.Receipt{TxHash: tx.Hash(), BlockNumber: big.NewInt(1), GasUsed: 30_000}
+	switch {
+	case tx.Gas() < m.requiredGas[block]:
+		receipt.GasUsed = tx.Gas()
+	case block == m.nextBlock:
+		receipt.Status = types.ReceiptStatusSuccessful
+		receipt.GasUsed = m.requiredGas[block]
+		m.nextBlock += m.interval
+	}
+	m.receipts[tx.Hash()] = receipt
+}
+
+func (m *mockL1) BlockNumber(ctx context.Context) (uint64, error) {
+	return 1, nil
+}
+
+func (m *mockL1) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if receipt, ok := m.receipts[txHash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (m *mockL1) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tx.Nonce() < m.nonce {
+		return core.ErrNonceTooLow
+	}
+	if m.onSend != nil {
+		m.onSend(tx)
+	}
+	m.queued[tx.Nonce()] = tx
+	for next, ok := m.queued[m.nonce]; ok && !m.hold[m.nonce]; next, ok = m.queued[m.nonce] {
+		m.execute(next)
+		delete(m.queued, m.nonce)
+		m.nonce++
+	}
+	return nil
+}
+
+func (m *mockL1) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.header, nil
+}
+
+func (m *mockL1) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(params.GWei), nil
+}
+
+func (m *mockL1) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nonce, nil
+}
+
+func (m *mockL1) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.estimates++
+	return m.requiredGas[m.nextBlock], nil
+}
+
+func newTestBacklogSubmitter(t *testing.T, requiredGas map[uint64]uint64) (*L2OutputSubmitter, *mockL1) {
+	l2ooABI, err := bindings.L2OutputOracleMetaData.GetAbi()
+	require.NoError(t, err)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(900)
+	signer := types.LatestSignerForChainID(chainID)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	l, _ := newTestSubmitter(t, &mockL2OOContract{}, &mockL2Chain{}, 10)
+	l.l2ooABI = *l2ooABI
+	l.from = from
+	l.chainID = chainID
+	l.txMgrConfig = txmgr.Config{
+		ResubmissionTimeout:       time.Minute,
+		ReceiptQueryInterval:      10 * time.Millisecond,
+		NumConfirmations:          1,
+		SafeAbortNonceTooLowCount: 3,
+		Signer: func(ctx context.Context, addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return types.SignTx(tx, signer, key)
+		},
+		From: from,
+	}
+	return l, newMockL1(*l2ooABI, l.submissionInterval, 10, requiredGas)
+}
+
+func backlogOutputs(l1 *mockL1, blocks ...uint64) []*eth.OutputResponse {
+	var outputs []*eth.OutputResponse
+	for _, block := range blocks {
+		outputs = append(outputs, &eth.OutputResponse{
+			BlockRef: eth.L2BlockRef{Number: block},
+			Status:   &eth.SyncStatus{CurrentL1: l1.l1Ref()},
+		})
+	}
+	return outputs
+}
+
+func TestProposeBacklog_ReestimateOutOfGas(t *testing.T) {
+	// the proposal of block 20 needs more gas than the estimate of block 10
+	l, l1 := newTestBacklogSubmitter(t, map[uint64]uint64{10: 100_000, 20: 150_000, 30: 100_000})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	l.proposeBacklog(ctx, l1, backlogOutputs(l1, 10, 20, 30))
+	require.Equal(t, uint64(40), l1.nextBlock, "all outputs proposed")
+	require.Equal(t, 2, l1.estimates, "gas re-estimated after running out of gas")
+	require.Equal(t, uint64(5), l1.nonce, "block 20 and 30 proposed twice")
+}
+
+func TestProposeBacklog_ReorgWhilePending(t *testing.T) {
+	l, l1 := newTestBacklogSubmitter(t, map[uint64]uint64{10: 100_000, 20: 100_000})
+	// the proposal of block 20 stays pending, and the L1 block of the proposals
+	// gets reorged after it is sent
+	l1.hold[1] = true
+	l1.onSend = func(tx *types.Transaction) {
+		if tx.Nonce() == 1 {
+			l1.reorg()
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		l.proposeBacklog(ctx, l1, backlogOutputs(l1, 10, 20))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("catch-up kept waiting on the proposal of a reorged L1 block")
+	}
+	require.Equal(t, uint64(20), l1.nextBlock)
+}
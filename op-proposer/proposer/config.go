@@ -1,6 +1,7 @@
 package proposer
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -28,6 +29,7 @@ type Config struct {
 	L1Client           *ethclient.Client
 	RollupClient       *sources.RollupClient
 	AllowNonFinalized  bool
+	MaxCatchUpOutputs  uint64
 	From               common.Address
 	SignerFnFactory    opcrypto.SignerFactory
 }
@@ -64,6 +66,10 @@ type CLIConfig struct {
 	// transaction.
 	ResubmissionTimeout time.Duration
 
+	// TxCancelTimeout is the duration after which a transaction that isn't
+	// mined yet is cancelled. 0 disables cancellation.
+	TxCancelTimeout time.Duration
+
 	// Mnemonic is the HD seed used to derive the wallet private keys for both
 	// the sequence and proposer. Must be used in conjunction with
 	// SequencerHDPath and ProposerHDPath.
@@ -76,7 +82,7 @@ type CLIConfig struct {
 	// PrivateKey is the private key used for l2output transactions.
 	PrivateKey string
 
-	RPCConfig oprpc.CLIConfig
+	RPCConfig oprpc.AdminCLIConfig
 
 	/* Optional Params */
 
@@ -84,6 +90,26 @@ type CLIConfig struct {
 	// for L2 blocks derived from non-finalized L1 data.
 	AllowNonFinalized bool
 
+	// Stopped starts the proposer in a stopped state. It can be started with
+	// the admin_startProposer RPC.
+	Stopped bool
+
+	// MaxCatchUpOutputs is the maximum number of backlogged outputs that are
+	// proposed back-to-back, without waiting for each to confirm. Catch-up is
+	// disabled if it's less than 2.
+	MaxCatchUpOutputs uint64
+
+	// ValidatorMode runs the output validator instead of the output submitter.
+	ValidatorMode bool
+
+	// ValidatorWebhookURL is posted an alert on every output mismatch found by
+	// the validator.
+	ValidatorWebhookURL string
+
+	// ValidatorConfirmationDepth is the number of L1 blocks that a proposal
+	// must be buried under before the validator checks it.
+	ValidatorConfirmationDepth uint64
+
 	LogConfig oplog.CLIConfig
 
 	MetricsConfig opmetrics.CLIConfig
@@ -107,9 +133,23 @@ func (c CLIConfig) Check() error {
 	if err := c.PprofConfig.Check(); err != nil {
 		return err
 	}
+	// The validator doesn't send transactions, so it needs neither the
+	// proposer key nor the tx manager settings.
+	if c.ValidatorMode {
+		return nil
+	}
 	if err := c.SignerConfig.Check(); err != nil {
 		return err
 	}
+	if c.NumConfirmations == 0 {
+		return errors.New("num confirmations must be set")
+	}
+	if c.SafeAbortNonceTooLowCount == 0 {
+		return errors.New("safe abort nonce too low count must be set")
+	}
+	if c.ResubmissionTimeout == 0 {
+		return errors.New("resubmission timeout must be set")
+	}
 	return nil
 }
 
@@ -128,11 +168,17 @@ func NewConfig(ctx *cli.Context) CLIConfig {
 		L2OutputHDPath:            ctx.GlobalString(flags.L2OutputHDPathFlag.Name),
 		PrivateKey:                ctx.GlobalString(flags.PrivateKeyFlag.Name),
 		// Optional Flags
-		AllowNonFinalized: ctx.GlobalBool(flags.AllowNonFinalizedFlag.Name),
-		RPCConfig:         oprpc.ReadCLIConfig(ctx),
-		LogConfig:         oplog.ReadCLIConfig(ctx),
-		MetricsConfig:     opmetrics.ReadCLIConfig(ctx),
-		PprofConfig:       oppprof.ReadCLIConfig(ctx),
-		SignerConfig:      opsigner.ReadCLIConfig(ctx),
+		TxCancelTimeout:            ctx.GlobalDuration(flags.TxCancelTimeoutFlag.Name),
+		AllowNonFinalized:          ctx.GlobalBool(flags.AllowNonFinalizedFlag.Name),
+		Stopped:                    ctx.GlobalBool(flags.StoppedFlag.Name),
+		MaxCatchUpOutputs:          ctx.GlobalUint64(flags.MaxCatchUpOutputsFlag.Name),
+		ValidatorMode:              ctx.GlobalBool(flags.ValidatorFlag.Name),
+		ValidatorWebhookURL:        ctx.GlobalString(flags.ValidatorWebhookURLFlag.Name),
+		ValidatorConfirmationDepth: ctx.GlobalUint64(flags.ValidatorConfirmationDepthFlag.Name),
+		RPCConfig:                  oprpc.ReadAdminCLIConfig(ctx),
+		LogConfig:                  oplog.ReadCLIConfig(ctx),
+		MetricsConfig:              opmetrics.ReadCLIConfig(ctx),
+		PprofConfig:                oppprof.ReadCLIConfig(ctx),
+		SignerConfig:               opsigner.ReadCLIConfig(ctx),
 	}
 }
@@ -0,0 +1,45 @@
+package proposer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+func validCLIConfig() CLIConfig {
+	return CLIConfig{
+		NumConfirmations:          1,
+		SafeAbortNonceTooLowCount: 3,
+		ResubmissionTimeout:       time.Minute,
+		LogConfig:                 oplog.DefaultCLIConfig(),
+	}
+}
+
+func TestCLIConfigCheck(t *testing.T) {
+	require.NoError(t, validCLIConfig().Check())
+
+	cfg := validCLIConfig()
+	cfg.ResubmissionTimeout = 0
+	require.ErrorContains(t, cfg.Check(), "resubmission timeout")
+
+	cfg = validCLIConfig()
+	cfg.SignerConfig = opsigner.CLIConfig{Endpoint: "http://localhost:8080"}
+	require.ErrorContains(t, cfg.Check(), "signer")
+}
+
+// TestCLIConfigCheckValidatorMode checks that the validator mode requires
+// neither the signer nor the tx manager settings, because it doesn't send
+// transactions.
+func TestCLIConfigCheckValidatorMode(t *testing.T) {
+	cfg := CLIConfig{
+		ValidatorMode: true,
+		LogConfig:     oplog.DefaultCLIConfig(),
+		// incomplete signer config
+		SignerConfig: opsigner.CLIConfig{Endpoint: "http://localhost:8080"},
+	}
+	require.NoError(t, cfg.Check())
+}
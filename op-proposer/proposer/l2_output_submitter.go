@@ -19,14 +19,15 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/urfave/cli"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
-	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-proposer/rpc"
 	opcrypto "github.com/ethereum-optimism/optimism/op-service/crypto"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
-	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
@@ -36,6 +37,12 @@ const (
 	// defaultDialTimeout is default duration the service will wait on
 	// startup to make a connection to either the L1 or L2 backends.
 	defaultDialTimeout = 5 * time.Second
+	// defaultSendTxTimeout is the duration that the proposer waits for a
+	// proposal transaction to confirm if tx cancellation is disabled.
+	defaultSendTxTimeout = 100 * time.Second
+	// txCancelMargin is the time, in addition to the tx cancel timeout, that
+	// the proposer waits for a stuck proposal transaction to get cancelled.
+	txCancelMargin = 5 * time.Minute
 )
 
 var supportedL2OutputVersion = eth.Bytes32{}
@@ -49,25 +56,59 @@ func Main(version string, cliCtx *cli.Context) error {
 	}
 
 	l := oplog.NewLogger(cfg.LogConfig)
-	l.Info("Initializing L2 Output Submitter")
+	m := metrics.NewMetrics("default")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	l2OutputSubmitter, err := NewL2OutputSubmitterFromCLIConfig(cfg, l)
-	if err != nil {
-		l.Error("Unable to create the L2 Output Submitter", "error", err)
-		return err
-	}
+	rpcCfg := cfg.RPCConfig
+	server := oprpc.NewServer(rpcCfg.ListenAddr, rpcCfg.ListenPort, version, oprpc.WithLogger(l))
 
-	l.Info("Starting L2 Output Submitter")
-	ctx, cancel := context.WithCancel(context.Background())
+	if cfg.ValidatorMode {
+		l.Info("Initializing Output Validator")
+		validator, err := NewOutputValidatorFromCLIConfig(cfg, l, m)
+		if err != nil {
+			l.Error("Unable to create the Output Validator", "error", err)
+			return err
+		}
+		if err := validator.Start(); err != nil {
+			l.Error("Unable to start Output Validator", "error", err)
+			return err
+		}
+		defer validator.Stop()
+		l.Info("Output Validator started")
+	} else {
+		l.Info("Initializing L2 Output Submitter")
+		l2OutputSubmitter, err := NewL2OutputSubmitterFromCLIConfig(cfg, l, m)
+		if err != nil {
+			l.Error("Unable to create the L2 Output Submitter", "error", err)
+			return err
+		}
 
-	if err := l2OutputSubmitter.Start(); err != nil {
-		cancel()
-		l.Error("Unable to start L2 Output Submitter", "error", err)
-		return err
+		if !cfg.Stopped {
+			if err := l2OutputSubmitter.Start(); err != nil {
+				l.Error("Unable to start L2 Output Submitter", "error", err)
+				return err
+			}
+		}
+		defer l2OutputSubmitter.StopIfRunning()
+
+		server.AddAPI(gethrpc.API{
+			Namespace: "proposer",
+			Service:   rpc.NewProposerAPI(l2OutputSubmitter),
+		})
+		if rpcCfg.EnableAdmin {
+			server.AddAPI(gethrpc.API{
+				Namespace: "admin",
+				Service:   rpc.NewAdminAPI(l2OutputSubmitter),
+			})
+			l.Info("Admin RPC enabled")
+		}
+
+		if cfg.MetricsConfig.Enabled {
+			m.StartBalanceMetrics(ctx, l, l2OutputSubmitter.l1Client, l2OutputSubmitter.from)
+		}
 	}
-	defer l2OutputSubmitter.Stop()
 
-	l.Info("L2 Output Submitter started")
 	pprofConfig := cfg.PprofConfig
 	if pprofConfig.Enabled {
 		l.Info("starting pprof", "addr", pprofConfig.ListenAddr, "port", pprofConfig.ListenPort)
@@ -78,26 +119,23 @@ func Main(version string, cliCtx *cli.Context) error {
 		}()
 	}
 
-	registry := opmetrics.NewRegistry()
 	metricsCfg := cfg.MetricsConfig
 	if metricsCfg.Enabled {
 		l.Info("starting metrics server", "addr", metricsCfg.ListenAddr, "port", metricsCfg.ListenPort)
 		go func() {
-			if err := opmetrics.ListenAndServe(ctx, registry, metricsCfg.ListenAddr, metricsCfg.ListenPort); err != nil {
+			if err := m.Serve(ctx, metricsCfg.ListenAddr, metricsCfg.ListenPort); err != nil {
 				l.Error("error starting metrics server", err)
 			}
 		}()
-		addr := l2OutputSubmitter.from
-		opmetrics.LaunchBalanceMetrics(ctx, l, registry, "", l2OutputSubmitter.l1Client, addr)
 	}
 
-	rpcCfg := cfg.RPCConfig
-	server := oprpc.NewServer(rpcCfg.ListenAddr, rpcCfg.ListenPort, version, oprpc.WithLogger(l))
 	if err := server.Start(); err != nil {
-		cancel()
 		return fmt.Errorf("error starting RPC server: %w", err)
 	}
 
+	m.RecordInfo(version)
+	m.RecordUp()
+
 	interruptChannel := make(chan os.Signal, 1)
 	signal.Notify(interruptChannel, []os.Signal{
 		os.Interrupt,
@@ -106,17 +144,46 @@ func Main(version string, cliCtx *cli.Context) error {
 		syscall.SIGQUIT,
 	}...)
 	<-interruptChannel
-	cancel()
+	_ = server.Stop()
 
 	return nil
 }
 
+// L2OOContract is the L2OutputOracle interface used by the L2 Output Submitter.
+type L2OOContract interface {
+	NextBlockNumber(opts *bind.CallOpts) (*big.Int, error)
+	LatestBlockNumber(opts *bind.CallOpts) (*big.Int, error)
+	ProposeL2Output(opts *bind.TransactOpts, outputRoot [32]byte, l2BlockNumber *big.Int, l1BlockHash [32]byte, l1BlockNumber *big.Int) (*types.Transaction, error)
+}
+
+// RollupClient is the rollup node interface used by the L2 Output Submitter.
+type RollupClient interface {
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+}
+
 // L2OutputSubmitter is responsible for proposing outputs
 type L2OutputSubmitter struct {
 	txMgr txmgr.TxManager
 	wg    sync.WaitGroup
 	done  chan struct{}
 	log   log.Logger
+	metr  metrics.Metricer
+
+	mutex   sync.Mutex
+	running bool
+	// forcing is set while a proposal is forced by ProposeOutputAt, which
+	// prevents the proposer from being started concurrently.
+	forcing bool
+
+	// pendingTx is the hash of the proposal transaction that is being sent, if
+	// any. Back-to-back proposals of a catch-up are not tracked.
+	pendingTxMu sync.Mutex
+	pendingTx   *common.Hash
+
+	// txMgrConfig and chainID are used to create the tx queues of catch-ups
+	txMgrConfig txmgr.Config
+	chainID     *big.Int
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -124,10 +191,14 @@ type L2OutputSubmitter struct {
 	// L1Client is used to submit transactions to
 	l1Client *ethclient.Client
 	// RollupClient is used to retrieve output roots from
-	rollupClient *sources.RollupClient
+	rollupClient RollupClient
 
-	l2ooContract    *bindings.L2OutputOracle
+	l2ooContract    L2OOContract
 	rawL2ooContract *bind.BoundContract
+	l2ooAddr        common.Address
+	l2ooABI         abi.ABI
+	// submissionInterval is the number of L2 blocks between checkpoints
+	submissionInterval uint64
 
 	// AllowNonFinalized enables the proposal of safe, but non-finalized L2 blocks.
 	// The L1 block-hash embedded in the proposal TX is checked and should ensure the proposal
@@ -140,10 +211,13 @@ type L2OutputSubmitter struct {
 	signerFn opcrypto.SignerFn
 	// How frequently to poll L2 for new finalized outputs
 	pollInterval time.Duration
+	// maxCatchUpOutputs is the maximum number of backlogged outputs that are
+	// proposed back-to-back. Catch-up is disabled if it's less than 2.
+	maxCatchUpOutputs uint64
 }
 
 // NewL2OutputSubmitterFromCLIConfig creates a new L2 Output Submitter given the CLI Config
-func NewL2OutputSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger) (*L2OutputSubmitter, error) {
+func NewL2OutputSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metricer) (*L2OutputSubmitter, error) {
 	signer, fromAddress, err := opcrypto.SignerFactoryFromConfig(l, cfg.PrivateKey, cfg.Mnemonic, cfg.L2OutputHDPath, cfg.SignerConfig)
 	if err != nil {
 		return nil, err
@@ -169,6 +243,7 @@ func NewL2OutputSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger) (*L2OutputSu
 	txMgrConfg := txmgr.Config{
 		ResubmissionTimeout:       cfg.ResubmissionTimeout,
 		ReceiptQueryInterval:      time.Second,
+		CancelTimeout:             cfg.TxCancelTimeout,
 		NumConfirmations:          cfg.NumConfirmations,
 		SafeAbortNonceTooLowCount: cfg.SafeAbortNonceTooLowCount,
 		From:                      fromAddress,
@@ -181,22 +256,20 @@ func NewL2OutputSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger) (*L2OutputSu
 		L1Client:           l1Client,
 		RollupClient:       rollupClient,
 		AllowNonFinalized:  cfg.AllowNonFinalized,
+		MaxCatchUpOutputs:  cfg.MaxCatchUpOutputs,
 		From:               fromAddress,
 		SignerFnFactory:    signer,
 	}
 
-	return NewL2OutputSubmitter(proposerCfg, l)
+	return NewL2OutputSubmitter(proposerCfg, l, m)
 }
 
 // NewL2OutputSubmitter creates a new L2 Output Submitter
-func NewL2OutputSubmitter(cfg Config, l log.Logger) (*L2OutputSubmitter, error) {
-	ctx, cancel := context.WithCancel(context.Background())
-
-	cCtx, cCancel := context.WithTimeout(ctx, defaultDialTimeout)
+func NewL2OutputSubmitter(cfg Config, l log.Logger, m metrics.Metricer) (*L2OutputSubmitter, error) {
+	cCtx, cCancel := context.WithTimeout(context.Background(), defaultDialTimeout)
 	chainID, err := cfg.L1Client.ChainID(cCtx)
 	cCancel()
 	if err != nil {
-		cancel()
 		return nil, err
 	}
 	signer := cfg.SignerFnFactory(chainID)
@@ -204,54 +277,183 @@ func NewL2OutputSubmitter(cfg Config, l log.Logger) (*L2OutputSubmitter, error)
 
 	l2ooContract, err := bindings.NewL2OutputOracle(cfg.L2OutputOracleAddr, cfg.L1Client)
 	if err != nil {
-		cancel()
 		return nil, err
 	}
 
 	version, err := l2ooContract.Version(&bind.CallOpts{})
 	if err != nil {
-		cancel()
 		return nil, err
 	}
 	log.Info("Connected to L2OutputOracle", "address", cfg.L2OutputOracleAddr, "version", version)
 
+	submissionInterval, err := l2ooContract.SUBMISSIONINTERVAL(&bind.CallOpts{})
+	if err != nil {
+		return nil, err
+	}
+
 	parsed, err := abi.JSON(strings.NewReader(bindings.L2OutputOracleMetaData.ABI))
 	if err != nil {
-		cancel()
 		return nil, err
 	}
 	rawL2ooContract := bind.NewBoundContract(cfg.L2OutputOracleAddr, parsed, cfg.L1Client, cfg.L1Client, cfg.L1Client)
 
 	return &L2OutputSubmitter{
-		txMgr:  txmgr.NewSimpleTxManager("proposer", l, cfg.TxManagerConfig, cfg.L1Client),
-		done:   make(chan struct{}),
-		log:    l,
-		ctx:    ctx,
-		cancel: cancel,
+		txMgr: txmgr.NewSimpleTxManager("proposer", l, m, cfg.TxManagerConfig, cfg.L1Client),
+		log:   l,
+		metr:  m,
+
+		txMgrConfig: cfg.TxManagerConfig,
+		chainID:     chainID,
 
 		l1Client:     cfg.L1Client,
 		rollupClient: cfg.RollupClient,
 
-		l2ooContract:    l2ooContract,
-		rawL2ooContract: rawL2ooContract,
+		l2ooContract:       l2ooContract,
+		rawL2ooContract:    rawL2ooContract,
+		l2ooAddr:           cfg.L2OutputOracleAddr,
+		l2ooABI:            parsed,
+		submissionInterval: submissionInterval.Uint64(),
 
 		allowNonFinalized: cfg.AllowNonFinalized,
 		from:              cfg.From,
 		signerFn:          signer,
 		pollInterval:      cfg.PollInterval,
+		maxCatchUpOutputs: cfg.MaxCatchUpOutputs,
 	}, nil
 }
 
 func (l *L2OutputSubmitter) Start() error {
+	l.log.Info("Starting L2 Output Submitter")
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.running {
+		return errors.New("proposer is already running")
+	}
+	if l.forcing {
+		return errors.New("a forced proposal is in progress")
+	}
+	l.running = true
+
+	l.done = make(chan struct{})
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+
 	l.wg.Add(1)
 	go l.loop()
+
+	l.log.Info("L2 Output Submitter started")
+
 	return nil
 }
 
-func (l *L2OutputSubmitter) Stop() {
+func (l *L2OutputSubmitter) StopIfRunning() {
+	_ = l.Stop()
+}
+
+func (l *L2OutputSubmitter) Stop() error {
+	l.log.Info("Stopping L2 Output Submitter")
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.running {
+		return errors.New("proposer is not running")
+	}
+	l.running = false
+
 	l.cancel()
 	close(l.done)
 	l.wg.Wait()
+
+	l.log.Info("L2 Output Submitter stopped")
+
+	return nil
+}
+
+// Status returns the proposal status of the L2 Output Submitter.
+func (l *L2OutputSubmitter) Status(ctx context.Context) (rpc.Status, error) {
+	l.mutex.Lock()
+	running := l.running
+	l.mutex.Unlock()
+
+	callOpts := &bind.CallOpts{
+		From:    l.from,
+		Context: ctx,
+	}
+	lastProposedBlock, err := l.l2ooContract.LatestBlockNumber(callOpts)
+	if err != nil {
+		return rpc.Status{}, fmt.Errorf("failed to get latest block number: %w", err)
+	}
+	nextCheckpointBlock, currentBlockNumber, err := l.fetchProposalRange(ctx)
+	if err != nil {
+		return rpc.Status{}, err
+	}
+
+	status := rpc.Status{
+		Running:             running,
+		LastProposedBlock:   lastProposedBlock.Uint64(),
+		NextCheckpointBlock: nextCheckpointBlock.Uint64(),
+		HeadBlock:           currentBlockNumber.Uint64(),
+		PendingTxHash:       l.pendingTxHash(),
+	}
+	if status.HeadBlock > status.LastProposedBlock {
+		status.Lag = status.HeadBlock - status.LastProposedBlock
+	}
+	return status, nil
+}
+
+// ProposeOutputAt proposes the output at the given L2 block, whether it is
+// finalized or not. The block must be the next checkpoint block of the
+// L2OutputOracle. It is meant for recovery and may only be used while the L2
+// Output Submitter is stopped. It blocks until the proposal is confirmed, and
+// the L2 Output Submitter can't be started in the meantime.
+func (l *L2OutputSubmitter) ProposeOutputAt(ctx context.Context, blockNumber uint64) (common.Hash, error) {
+	l.mutex.Lock()
+	if l.running {
+		l.mutex.Unlock()
+		return common.Hash{}, errors.New("proposer must be stopped to force a proposal")
+	}
+	if l.forcing {
+		l.mutex.Unlock()
+		return common.Hash{}, errors.New("a forced proposal is already in progress")
+	}
+	l.forcing = true
+	l.mutex.Unlock()
+	defer func() {
+		l.mutex.Lock()
+		l.forcing = false
+		l.mutex.Unlock()
+	}()
+
+	nextCheckpointBlock, err := l.l2ooContract.NextBlockNumber(&bind.CallOpts{From: l.from, Context: ctx})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get next block number: %w", err)
+	}
+	if nextCheckpointBlock.Uint64() != blockNumber {
+		return common.Hash{}, fmt.Errorf("can only propose the next checkpoint block %v, not %d", nextCheckpointBlock, blockNumber)
+	}
+	output, err := l.rollupClient.OutputAtBlock(ctx, blockNumber)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to fetch output at block %d: %w", blockNumber, err)
+	}
+	if output.Version != supportedL2OutputVersion {
+		return common.Hash{}, fmt.Errorf("unsupported l2 output version: %s", output.Version)
+	}
+	if output.BlockRef.Number != blockNumber {
+		return common.Hash{}, fmt.Errorf("invalid blockNumber: expected %d, blockNumber of block is %d", blockNumber, output.BlockRef.Number)
+	}
+	l.log.Warn("Forcing proposal", "l2_block", output.BlockRef, "output_root", output.OutputRoot,
+		"l2_safe", output.Status.SafeL2, "l2_finalized", output.Status.FinalizedL2)
+
+	tx, err := l.CreateProposalTx(ctx, output)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := l.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
 }
 
 // UpdateGasPrice signs an otherwise identical txn to the one provided but with
@@ -274,6 +476,21 @@ func (l *L2OutputSubmitter) UpdateGasPrice(ctx context.Context, tx *types.Transa
 // FetchNextOutputInfo gets the block number of the next proposal.
 // It returns: the next block number, if the proposal should be made, error
 func (l *L2OutputSubmitter) FetchNextOutputInfo(ctx context.Context) (*eth.OutputResponse, bool, error) {
+	nextCheckpointBlock, currentBlockNumber, err := l.fetchProposalRange(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	// Ensure that we do not submit a block in the future
+	if currentBlockNumber.Cmp(nextCheckpointBlock) < 0 {
+		l.log.Info("proposer submission interval has not elapsed", "currentBlockNumber", currentBlockNumber, "nextBlockNumber", nextCheckpointBlock)
+		return nil, false, nil
+	}
+	return l.fetchOutput(ctx, nextCheckpointBlock.Uint64())
+}
+
+// fetchProposalRange returns the block number of the next checkpoint and the
+// latest L2 block number that may be proposed.
+func (l *L2OutputSubmitter) fetchProposalRange(ctx context.Context) (*big.Int, *big.Int, error) {
 	callOpts := &bind.CallOpts{
 		From:    l.from,
 		Context: ctx,
@@ -281,13 +498,13 @@ func (l *L2OutputSubmitter) FetchNextOutputInfo(ctx context.Context) (*eth.Outpu
 	nextCheckpointBlock, err := l.l2ooContract.NextBlockNumber(callOpts)
 	if err != nil {
 		l.log.Error("proposer unable to get next block number", "err", err)
-		return nil, false, err
+		return nil, nil, err
 	}
 	// Fetch the current L2 heads
 	status, err := l.rollupClient.SyncStatus(ctx)
 	if err != nil {
 		l.log.Error("proposer unable to get sync status", "err", err)
-		return nil, false, err
+		return nil, nil, err
 	}
 	// Use either the finalized or safe head depending on the config. Finalized head is default & safer.
 	var currentBlockNumber *big.Int
@@ -296,23 +513,23 @@ func (l *L2OutputSubmitter) FetchNextOutputInfo(ctx context.Context) (*eth.Outpu
 	} else {
 		currentBlockNumber = new(big.Int).SetUint64(status.FinalizedL2.Number)
 	}
-	// Ensure that we do not submit a block in the future
-	if currentBlockNumber.Cmp(nextCheckpointBlock) < 0 {
-		l.log.Info("proposer submission interval has not elapsed", "currentBlockNumber", currentBlockNumber, "nextBlockNumber", nextCheckpointBlock)
-		return nil, false, nil
-	}
+	return nextCheckpointBlock, currentBlockNumber, nil
+}
 
-	output, err := l.rollupClient.OutputAtBlock(ctx, nextCheckpointBlock.Uint64())
+// fetchOutput fetches the output at the given checkpoint block.
+// It returns: the output, if the proposal should be made, error
+func (l *L2OutputSubmitter) fetchOutput(ctx context.Context, checkpointBlock uint64) (*eth.OutputResponse, bool, error) {
+	output, err := l.rollupClient.OutputAtBlock(ctx, checkpointBlock)
 	if err != nil {
-		l.log.Error("failed to fetch output at block %d: %w", nextCheckpointBlock, err)
+		l.log.Error("failed to fetch output at block %d: %w", checkpointBlock, err)
 		return nil, false, err
 	}
 	if output.Version != supportedL2OutputVersion {
 		l.log.Error("unsupported l2 output version: %s", output.Version)
 		return nil, false, errors.New("unsupported l2 output version")
 	}
-	if output.BlockRef.Number != nextCheckpointBlock.Uint64() { // sanity check, e.g. in case of bad RPC caching
-		l.log.Error("invalid blockNumber: next blockNumber is %v, blockNumber of block is %v", nextCheckpointBlock, output.BlockRef.Number)
+	if output.BlockRef.Number != checkpointBlock { // sanity check, e.g. in case of bad RPC caching
+		l.log.Error("invalid blockNumber: next blockNumber is %v, blockNumber of block is %v", checkpointBlock, output.BlockRef.Number)
 		return nil, false, errors.New("invalid blockNumber")
 	}
 
@@ -362,13 +579,16 @@ func (l *L2OutputSubmitter) CreateProposalTx(ctx context.Context, output *eth.Ou
 
 // SendTransaction sends a transaction through the transaction manager which handles automatic
 // price bumping.
-// It also hardcodes a timeout of 100s.
+// It waits 100s for the transaction to confirm, or until the transaction got
+// cancelled if tx cancellation is enabled.
 func (l *L2OutputSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
 	// Wait until one of our submitted transactions confirms. If no
 	// receipt is received it's likely our gas price was too low.
-	cCtx, cancel := context.WithTimeout(ctx, 100*time.Second)
+	cCtx, cancel := context.WithTimeout(ctx, l.sendTxTimeout())
 	defer cancel()
 	l.log.Info("Sending transaction", "tx_hash", tx.Hash())
+	l.setPendingTxHash(tx.Hash())
+	defer l.clearPendingTxHash()
 	receipt, err := l.txMgr.Send(cCtx, tx)
 	if err != nil {
 		l.log.Error("proposer unable to publish tx", "err", err)
@@ -380,6 +600,35 @@ func (l *L2OutputSubmitter) SendTransaction(ctx context.Context, tx *types.Trans
 	return nil
 }
 
+// sendTxTimeout returns the duration that SendTransaction waits for a proposal
+// transaction. If tx cancellation is enabled, it outlasts the cancel timeout,
+// so that the transaction manager keeps bumping the fees until it cancels a
+// stuck transaction itself.
+func (l *L2OutputSubmitter) sendTxTimeout() time.Duration {
+	if l.txMgrConfig.CancelTimeout != 0 {
+		return l.txMgrConfig.CancelTimeout + txCancelMargin
+	}
+	return defaultSendTxTimeout
+}
+
+func (l *L2OutputSubmitter) setPendingTxHash(hash common.Hash) {
+	l.pendingTxMu.Lock()
+	defer l.pendingTxMu.Unlock()
+	l.pendingTx = &hash
+}
+
+func (l *L2OutputSubmitter) clearPendingTxHash() {
+	l.pendingTxMu.Lock()
+	defer l.pendingTxMu.Unlock()
+	l.pendingTx = nil
+}
+
+func (l *L2OutputSubmitter) pendingTxHash() *common.Hash {
+	l.pendingTxMu.Lock()
+	defer l.pendingTxMu.Unlock()
+	return l.pendingTx
+}
+
 // loop is responsible for creating & submitting the next outputs
 func (l *L2OutputSubmitter) loop() {
 	defer l.wg.Done()
@@ -391,15 +640,17 @@ func (l *L2OutputSubmitter) loop() {
 	for {
 		select {
 		case <-ticker.C:
-			cCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-			output, shouldPropose, err := l.FetchNextOutputInfo(cCtx)
+			cCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+			outputs, err := l.FetchBacklog(cCtx)
 			cancel()
-			if err != nil {
+			if err != nil || len(outputs) == 0 {
 				break
 			}
-			if !shouldPropose {
+			if len(outputs) > 1 {
+				l.proposeBacklog(ctx, l.l1Client, outputs)
 				break
 			}
+			output := outputs[0]
 
 			cCtx, cancel = context.WithTimeout(ctx, 30*time.Second)
 			tx, err := l.CreateProposalTx(cCtx, output)
@@ -408,13 +659,9 @@ func (l *L2OutputSubmitter) loop() {
 				l.log.Error("Failed to create proposal transaction", "err", err)
 				break
 			}
-			cCtx, cancel = context.WithTimeout(ctx, 10*time.Minute)
-			if err := l.SendTransaction(cCtx, tx); err != nil {
+			if err := l.SendTransaction(ctx, tx); err != nil {
 				l.log.Error("Failed to send proposal transaction", "err", err)
-				cancel()
 				break
-			} else {
-				cancel()
 			}
 
 		case <-l.done:
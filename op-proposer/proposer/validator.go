@@ -0,0 +1,305 @@
+package proposer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+)
+
+// maxLogRange is the maximum number of L1 blocks that are scanned for
+// OutputProposed events in a single log query.
+const maxLogRange = 1000
+
+// outputProposal is an output that got proposed to the L2OutputOracle.
+type outputProposal struct {
+	Index         uint64      `json:"l2OutputIndex"`
+	L2BlockNumber uint64      `json:"l2BlockNumber"`
+	OutputRoot    eth.Bytes32 `json:"outputRoot"`
+	// L1BlockNumber is the L1 block that the proposal got included in. It is 0
+	// for the latest proposal that is validated at startup.
+	L1BlockNumber uint64 `json:"l1BlockNumber"`
+}
+
+// validatorL1Client is the L1 client interface used by the OutputValidator.
+type validatorL1Client interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// validatorRollupClient is the rollup node interface used by the OutputValidator.
+type validatorRollupClient interface {
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+}
+
+// MismatchAlert is the payload that is posted to the webhook if a proposed
+// output doesn't match the output computed by the rollup node.
+type MismatchAlert struct {
+	outputProposal
+	ExpectedOutputRoot eth.Bytes32 `json:"expectedOutputRoot"`
+}
+
+// OutputValidator watches the outputs that are proposed to the L2OutputOracle
+// and validates them against the outputs computed by the rollup node. It
+// doesn't require the proposer key, so it can be run as an independent
+// watchdog of the proposer.
+type OutputValidator struct {
+	wg   sync.WaitGroup
+	done chan struct{}
+	log  log.Logger
+	metr metrics.Metricer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// L1Client is used to query the L1 head from
+	l1Client validatorL1Client
+	// RollupClient is used to retrieve output roots from
+	rollupClient validatorRollupClient
+
+	l2ooContract *bindings.L2OutputOracle
+
+	// webhookURL is posted a MismatchAlert on every mismatch, if set
+	webhookURL string
+	httpClient *http.Client
+
+	// How frequently to poll L1 for new proposals
+	pollInterval time.Duration
+	// confirmationDepth is the number of L1 blocks that a proposal must be
+	// buried under before it is validated, so that proposals that get reorged
+	// out of L1 don't raise false alerts.
+	confirmationDepth uint64
+
+	// nextL1Block is the next L1 block to scan for OutputProposed events
+	nextL1Block uint64
+	// pending are the proposals that are not validated yet, in proposal order
+	pending []outputProposal
+}
+
+// NewOutputValidatorFromCLIConfig creates a new Output Validator given the CLI Config
+func NewOutputValidatorFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metricer) (*OutputValidator, error) {
+	l2ooAddress, err := parseAddress(cfg.L2OOAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	l1Client, err := dialEthClientWithTimeout(ctx, cfg.L1EthRpc)
+	if err != nil {
+		return nil, err
+	}
+
+	rollupClient, err := dialRollupClientWithTimeout(ctx, cfg.RollupRpc)
+	if err != nil {
+		return nil, err
+	}
+
+	l2ooContract, err := bindings.NewL2OutputOracle(l2ooAddress, l1Client)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutputValidator{
+		done:   make(chan struct{}),
+		log:    l,
+		metr:   m,
+		ctx:    ctx,
+		cancel: cancel,
+
+		l1Client:     l1Client,
+		rollupClient: rollupClient,
+		l2ooContract: l2ooContract,
+
+		webhookURL:        cfg.ValidatorWebhookURL,
+		httpClient:        &http.Client{Timeout: 10 * time.Second},
+		pollInterval:      cfg.PollInterval,
+		confirmationDepth: cfg.ValidatorConfirmationDepth,
+	}, nil
+}
+
+// Start queues the latest confirmed proposal for validation and starts watching
+// for new proposals from the current confirmed L1 head on.
+func (v *OutputValidator) Start() error {
+	cCtx, cancel := context.WithTimeout(v.ctx, 30*time.Second)
+	defer cancel()
+	head, err := v.confirmedL1Head(cCtx)
+	if err != nil {
+		return err
+	}
+	v.nextL1Block = head + 1
+
+	callOpts := &bind.CallOpts{Context: cCtx, BlockNumber: new(big.Int).SetUint64(head)}
+	nextIndex, err := v.l2ooContract.NextOutputIndex(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to get next output index: %w", err)
+	}
+	if nextIndex.Sign() > 0 {
+		latestIndex := new(big.Int).Sub(nextIndex, big.NewInt(1))
+		output, err := v.l2ooContract.GetL2Output(callOpts, latestIndex)
+		if err != nil {
+			return fmt.Errorf("failed to get latest output: %w", err)
+		}
+		v.pending = append(v.pending, outputProposal{
+			Index:         latestIndex.Uint64(),
+			L2BlockNumber: output.L2BlockNumber.Uint64(),
+			OutputRoot:    output.OutputRoot,
+		})
+	}
+	v.log.Info("Starting output validator", "l1_head", head, "next_output_index", nextIndex,
+		"confirmation_depth", v.confirmationDepth)
+
+	v.wg.Add(1)
+	go v.loop()
+	return nil
+}
+
+func (v *OutputValidator) Stop() {
+	v.cancel()
+	close(v.done)
+	v.wg.Wait()
+}
+
+func (v *OutputValidator) loop() {
+	defer v.wg.Done()
+
+	ticker := time.NewTicker(v.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := v.fetchProposals(v.ctx); err != nil {
+				v.log.Error("Failed to fetch output proposals", "err", err)
+			}
+			v.validatePending(v.ctx)
+		case <-v.done:
+			return
+		}
+	}
+}
+
+// confirmedL1Head returns the latest L1 block that is buried under at least
+// confirmationDepth blocks.
+func (v *OutputValidator) confirmedL1Head(ctx context.Context) (uint64, error) {
+	head, err := v.l1Client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get L1 head: %w", err)
+	}
+	if head < v.confirmationDepth {
+		return 0, nil
+	}
+	return head - v.confirmationDepth, nil
+}
+
+// fetchProposals queues all OutputProposed events from the next unscanned L1
+// block up to the confirmed L1 head, at most maxLogRange blocks at a time.
+func (v *OutputValidator) fetchProposals(ctx context.Context) error {
+	cCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	head, err := v.confirmedL1Head(cCtx)
+	if err != nil {
+		return err
+	}
+
+	for v.nextL1Block <= head {
+		end := v.nextL1Block + maxLogRange - 1
+		if end > head {
+			end = head
+		}
+		it, err := v.l2ooContract.FilterOutputProposed(&bind.FilterOpts{
+			Start:   v.nextL1Block,
+			End:     &end,
+			Context: cCtx,
+		}, nil, nil, nil)
+		if err != nil {
+			return fmt.Errorf("failed to filter OutputProposed events in L1 blocks %d-%d: %w", v.nextL1Block, end, err)
+		}
+		// The proposals are only queued once the whole range got scanned, so
+		// that they aren't queued twice when the range is scanned again.
+		var proposals []outputProposal
+		for it.Next() {
+			ev := it.Event
+			proposals = append(proposals, outputProposal{
+				Index:         ev.L2OutputIndex.Uint64(),
+				L2BlockNumber: ev.L2BlockNumber.Uint64(),
+				OutputRoot:    ev.OutputRoot,
+				L1BlockNumber: ev.Raw.BlockNumber,
+			})
+		}
+		err = it.Error()
+		it.Close()
+		if err != nil {
+			return fmt.Errorf("failed to iterate OutputProposed events: %w", err)
+		}
+		v.pending = append(v.pending, proposals...)
+		v.nextL1Block = end + 1
+	}
+	return nil
+}
+
+// validatePending validates the pending proposals in order. It stops at the
+// first proposal whose L2 block isn't safe yet on the rollup node.
+func (v *OutputValidator) validatePending(ctx context.Context) {
+	for len(v.pending) > 0 {
+		p := v.pending[0]
+		cCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		output, err := v.rollupClient.OutputAtBlock(cCtx, p.L2BlockNumber)
+		cancel()
+		if err != nil {
+			v.log.Error("Failed to fetch output", "l2_block", p.L2BlockNumber, "err", err)
+			return
+		}
+		if output.BlockRef.Number > output.Status.SafeL2.Number {
+			v.log.Debug("Not validating output yet, L2 block is not safe",
+				"l2_block", p.L2BlockNumber, "l2_safe", output.Status.SafeL2)
+			return
+		}
+		v.pending = v.pending[1:]
+
+		if output.OutputRoot == p.OutputRoot {
+			v.log.Info("Validated output", "index", p.Index, "l2_block", p.L2BlockNumber, "output_root", p.OutputRoot)
+			v.metr.RecordOutputValidated(p.L2BlockNumber, true)
+			continue
+		}
+		v.log.Error("Proposed output doesn't match rollup node output",
+			"index", p.Index, "l2_block", p.L2BlockNumber, "l1_block", p.L1BlockNumber,
+			"proposed_output_root", p.OutputRoot, "expected_output_root", output.OutputRoot)
+		v.metr.RecordOutputValidated(p.L2BlockNumber, false)
+		if v.webhookURL != "" {
+			v.sendAlert(ctx, MismatchAlert{outputProposal: p, ExpectedOutputRoot: output.OutputRoot})
+		}
+	}
+}
+
+func (v *OutputValidator) sendAlert(ctx context.Context, alert MismatchAlert) {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		v.log.Error("Failed to encode mismatch alert", "err", err)
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", v.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		v.log.Error("Failed to create mismatch alert request", "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := v.httpClient.Do(req)
+	if err != nil {
+		v.log.Error("Failed to send mismatch alert", "err", err)
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		v.log.Error("Mismatch alert webhook returned non-2xx status code", "status", res.StatusCode)
+	}
+}
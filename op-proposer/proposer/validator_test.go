@@ -0,0 +1,222 @@
+package proposer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+)
+
+type mockL1Client struct {
+	head uint64
+}
+
+func (m *mockL1Client) BlockNumber(ctx context.Context) (uint64, error) {
+	return m.head, nil
+}
+
+// mockRollupClient returns the outputs of a rollup node that is synced up to
+// the safe L2 block.
+type mockRollupClient struct {
+	outputs map[uint64]eth.Bytes32
+	safe    uint64
+	err     error
+}
+
+func (m *mockRollupClient) OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &eth.OutputResponse{
+		OutputRoot: m.outputs[blockNum],
+		BlockRef:   eth.L2BlockRef{Number: blockNum},
+		Status:     &eth.SyncStatus{SafeL2: eth.L2BlockRef{Number: m.safe}},
+	}, nil
+}
+
+type validatedOutput struct {
+	l2BlockNumber uint64
+	ok            bool
+}
+
+type validatorMetrics struct {
+	metrics.Metricer
+	validated []validatedOutput
+}
+
+func (m *validatorMetrics) RecordOutputValidated(l2BlockNumber uint64, ok bool) {
+	m.validated = append(m.validated, validatedOutput{l2BlockNumber, ok})
+}
+
+func newTestValidator(t *testing.T, rollup *mockRollupClient) (*OutputValidator, *validatorMetrics) {
+	m := &validatorMetrics{Metricer: metrics.NoopMetrics}
+	return &OutputValidator{
+		log:          testlog.Logger(t, log.LvlCrit),
+		metr:         m,
+		l1Client:     &mockL1Client{},
+		rollupClient: rollup,
+		httpClient:   &http.Client{Timeout: time.Second},
+	}, m
+}
+
+func TestOutputValidator_Match(t *testing.T) {
+	rollup := &mockRollupClient{
+		outputs: map[uint64]eth.Bytes32{10: {0x01}, 20: {0x02}},
+		safe:    20,
+	}
+	v, m := newTestValidator(t, rollup)
+	v.pending = []outputProposal{
+		{Index: 0, L2BlockNumber: 10, OutputRoot: eth.Bytes32{0x01}},
+		{Index: 1, L2BlockNumber: 20, OutputRoot: eth.Bytes32{0x02}},
+	}
+
+	v.validatePending(context.Background())
+	require.Empty(t, v.pending)
+	require.Equal(t, []validatedOutput{{10, true}, {20, true}}, m.validated)
+}
+
+func TestOutputValidator_Mismatch(t *testing.T) {
+	alerts := make(chan MismatchAlert, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert MismatchAlert
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		alerts <- alert
+	}))
+	defer srv.Close()
+
+	rollup := &mockRollupClient{
+		outputs: map[uint64]eth.Bytes32{10: {0x01}},
+		safe:    10,
+	}
+	v, m := newTestValidator(t, rollup)
+	v.webhookURL = srv.URL
+	proposal := outputProposal{Index: 3, L2BlockNumber: 10, OutputRoot: eth.Bytes32{0xff}, L1BlockNumber: 100}
+	v.pending = []outputProposal{proposal}
+
+	v.validatePending(context.Background())
+	require.Empty(t, v.pending)
+	require.Equal(t, []validatedOutput{{10, false}}, m.validated)
+	select {
+	case alert := <-alerts:
+		require.Equal(t, MismatchAlert{outputProposal: proposal, ExpectedOutputRoot: eth.Bytes32{0x01}}, alert)
+	default:
+		t.Fatal("expected a mismatch alert")
+	}
+}
+
+func TestOutputValidator_NotSynced(t *testing.T) {
+	rollup := &mockRollupClient{
+		outputs: map[uint64]eth.Bytes32{10: {0x01}, 20: {0x02}},
+		safe:    15,
+	}
+	v, m := newTestValidator(t, rollup)
+	v.pending = []outputProposal{
+		{Index: 0, L2BlockNumber: 10, OutputRoot: eth.Bytes32{0x01}},
+		{Index: 1, L2BlockNumber: 20, OutputRoot: eth.Bytes32{0x02}},
+	}
+
+	// the second output is kept until its L2 block is safe
+	v.validatePending(context.Background())
+	require.Equal(t, []outputProposal{{Index: 1, L2BlockNumber: 20, OutputRoot: eth.Bytes32{0x02}}}, v.pending)
+	require.Equal(t, []validatedOutput{{10, true}}, m.validated)
+
+	// and it's kept if the rollup node fails to return the output
+	rollup.err = errors.New("rollup node unavailable")
+	rollup.safe = 20
+	v.validatePending(context.Background())
+	require.Len(t, v.pending, 1)
+	require.Len(t, m.validated, 1)
+
+	rollup.err = nil
+	v.validatePending(context.Background())
+	require.Empty(t, v.pending)
+	require.Equal(t, []validatedOutput{{10, true}, {20, true}}, m.validated)
+}
+
+func TestOutputValidator_ConfirmedL1Head(t *testing.T) {
+	v, _ := newTestValidator(t, &mockRollupClient{})
+	v.l1Client = &mockL1Client{head: 100}
+	v.confirmationDepth = 10
+
+	head, err := v.confirmedL1Head(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(90), head)
+
+	v.l1Client = &mockL1Client{head: 5}
+	head, err = v.confirmedL1Head(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, head)
+
+	// nothing is scanned while the chain is shorter than the confirmation depth
+	v.nextL1Block = 1
+	require.NoError(t, v.fetchProposals(context.Background()))
+	require.Equal(t, uint64(1), v.nextL1Block)
+}
+
+// mockLogBackend returns the configured logs from FilterLogs. All other
+// contract backend methods are unimplemented.
+type mockLogBackend struct {
+	bind.ContractBackend
+	logs []types.Log
+}
+
+func (m *mockLogBackend) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return m.logs, nil
+}
+
+func outputProposedLog(t *testing.T, index uint64, l2BlockNumber uint64, outputRoot eth.Bytes32) types.Log {
+	abi, err := bindings.L2OutputOracleMetaData.GetAbi()
+	require.NoError(t, err)
+	return types.Log{
+		Topics: []common.Hash{
+			abi.Events["OutputProposed"].ID,
+			common.Hash(outputRoot),
+			common.BigToHash(new(big.Int).SetUint64(index)),
+			common.BigToHash(new(big.Int).SetUint64(l2BlockNumber)),
+		},
+		Data:        common.BigToHash(big.NewInt(1234)).Bytes(),
+		BlockNumber: 50,
+	}
+}
+
+// TestOutputValidator_FetchProposalsIteratorError checks that proposals of an
+// L1 block range that fails to be scanned aren't queued, so that they aren't
+// queued twice once the range is scanned again.
+func TestOutputValidator_FetchProposalsIteratorError(t *testing.T) {
+	valid := outputProposedLog(t, 0, 10, eth.Bytes32{0x01})
+	invalid := outputProposedLog(t, 1, 20, eth.Bytes32{0x02})
+	invalid.Data = []byte{0x01}
+
+	backend := &mockLogBackend{logs: []types.Log{valid, invalid}}
+	l2oo, err := bindings.NewL2OutputOracle(common.Address{0x42}, backend)
+	require.NoError(t, err)
+	v, _ := newTestValidator(t, &mockRollupClient{})
+	v.l1Client = &mockL1Client{head: 100}
+	v.l2ooContract = l2oo
+	v.nextL1Block = 1
+
+	require.Error(t, v.fetchProposals(context.Background()))
+	require.Empty(t, v.pending)
+	require.Equal(t, uint64(1), v.nextL1Block)
+
+	backend.logs = []types.Log{valid}
+	require.NoError(t, v.fetchProposals(context.Background()))
+	require.Equal(t, []outputProposal{{Index: 0, L2BlockNumber: 10, OutputRoot: eth.Bytes32{0x01}, L1BlockNumber: 50}}, v.pending)
+	require.Equal(t, uint64(101), v.nextL1Block)
+}
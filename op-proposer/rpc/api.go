@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type proposerClient interface {
+	Start() error
+	Stop() error
+	Status(ctx context.Context) (Status, error)
+	ProposeOutputAt(ctx context.Context, blockNumber uint64) (common.Hash, error)
+}
+
+// Status is the proposal status of the proposer.
+type Status struct {
+	Running bool `json:"running"`
+	// LastProposedBlock is the L2 block number of the latest output in the
+	// L2OutputOracle.
+	LastProposedBlock uint64 `json:"last_proposed_block"`
+	// NextCheckpointBlock is the L2 block number of the next output to propose.
+	NextCheckpointBlock uint64 `json:"next_checkpoint_block"`
+	// HeadBlock is the latest L2 block number that may be proposed, the
+	// finalized head, or the safe head if non-finalized proposals are allowed.
+	HeadBlock uint64 `json:"head_block"`
+	// Lag is the number of L2 blocks that the last proposed block is behind
+	// the head block.
+	Lag uint64 `json:"lag"`
+	// PendingTxHash is the hash of the proposal transaction that is being
+	// sent, if any.
+	PendingTxHash *common.Hash `json:"pending_tx_hash,omitempty"`
+}
+
+type adminAPI struct {
+	p proposerClient
+}
+
+func NewAdminAPI(dr proposerClient) *adminAPI {
+	return &adminAPI{
+		p: dr,
+	}
+}
+
+func (a *adminAPI) StartProposer(_ context.Context) error {
+	return a.p.Start()
+}
+
+func (a *adminAPI) StopProposer(_ context.Context) error {
+	return a.p.Stop()
+}
+
+// ProposeOutputAt forces the proposal of the output at the given L2 block,
+// which must be the next checkpoint block, regardless of its finality. The
+// proposer must be stopped. It returns the hash of the confirmed proposal
+// transaction.
+func (a *adminAPI) ProposeOutputAt(ctx context.Context, blockNumber hexutil.Uint64) (common.Hash, error) {
+	return a.p.ProposeOutputAt(ctx, uint64(blockNumber))
+}
+
+type proposerAPI struct {
+	p proposerClient
+}
+
+func NewProposerAPI(dr proposerClient) *proposerAPI {
+	return &proposerAPI{
+		p: dr,
+	}
+}
+
+// Status returns the proposal status of the proposer.
+func (a *proposerAPI) Status(ctx context.Context) (Status, error) {
+	return a.p.Status(ctx)
+}
@@ -4,14 +4,15 @@ import (
 	"github.com/urfave/cli"
 
 	opservice "github.com/ethereum-optimism/optimism/op-service"
-	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
 )
 
 const (
 	EnableAdminFlagName = "rpc.enable-admin"
 )
 
-func CLIFlags(envPrefix string) []cli.Flag {
+// AdminCLIFlags returns the flags of the RPC server of a service with an
+// admin API, in addition to the flags returned by CLIFlags.
+func AdminCLIFlags(envPrefix string) []cli.Flag {
 	return []cli.Flag{
 		cli.BoolFlag{
 			Name:   EnableAdminFlagName,
@@ -21,14 +22,15 @@ func CLIFlags(envPrefix string) []cli.Flag {
 	}
 }
 
-type CLIConfig struct {
-	oprpc.CLIConfig
+// AdminCLIConfig is the RPC server config of a service with an admin API.
+type AdminCLIConfig struct {
+	CLIConfig
 	EnableAdmin bool
 }
 
-func ReadCLIConfig(ctx *cli.Context) CLIConfig {
-	return CLIConfig{
-		CLIConfig:   oprpc.ReadCLIConfig(ctx),
+func ReadAdminCLIConfig(ctx *cli.Context) AdminCLIConfig {
+	return AdminCLIConfig{
+		CLIConfig:   ReadCLIConfig(ctx),
 		EnableAdmin: ctx.GlobalBool(EnableAdminFlagName),
 	}
 }
@@ -0,0 +1,55 @@
+package txmgr
+
+import (
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+)
+
+const (
+	// TxCancellationPublished is recorded when a cancellation tx got published
+	// for a stuck transaction.
+	TxCancellationPublished = "published"
+	// TxCancellationConfirmed is recorded when a cancellation tx got confirmed,
+	// so the stuck transaction is cancelled.
+	TxCancellationConfirmed = "confirmed"
+	// TxCancellationFailed is recorded when a cancellation tx couldn't be
+	// crafted or published.
+	TxCancellationFailed = "failed"
+)
+
+type Metricer interface {
+	RecordTxCancellation(stage string)
+}
+
+// TxMetrics provides transaction manager metrics. It's a metrics module that's
+// supposed to be embedded into a service metrics type. The service metrics type
+// should set the full namespace and create the factory before calling
+// MakeTxMetrics.
+type TxMetrics struct {
+	// label by published, confirmed, failed
+	TxCancellationEvs opmetrics.EventVec
+}
+
+var _ Metricer = (*TxMetrics)(nil)
+
+// MakeTxMetrics returns a new TxMetrics, initializing its prometheus fields
+// using factory.
+//
+// ns is the fully qualified namespace, e.g. "op_batcher_default".
+func MakeTxMetrics(ns string, factory opmetrics.Factory) TxMetrics {
+	return TxMetrics{
+		TxCancellationEvs: opmetrics.NewEventVec(factory, ns, "tx_cancellation", "Stuck tx cancellation", []string{"stage"}),
+	}
+}
+
+func (m *TxMetrics) RecordTxCancellation(stage string) {
+	m.TxCancellationEvs.Record(stage)
+}
+
+// NoopTxMetrics can be embedded in a noop version of a metric implementation
+// to have a noop Metricer.
+type NoopTxMetrics struct{}
+
+func (*NoopTxMetrics) RecordTxCancellation(string) {}
+
+// NoopMetrics is a Metricer that doesn't record anything.
+var NoopMetrics Metricer = new(NoopTxMetrics)
@@ -0,0 +1,333 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxNonceTooLowRetries is the number of times a tx candidate is re-crafted
+// with a fresh nonce after its nonce got used by another transaction.
+const maxNonceTooLowRetries = 3
+
+// networkTimeout is the timeout of the backend requests made while crafting a
+// transaction.
+const networkTimeout = 10 * time.Second
+
+// TxCandidate is a transaction that the Queue crafts, signs and sends. The
+// nonce and fees are set by the Queue.
+type TxCandidate struct {
+	// To is the recipient of the transaction.
+	To common.Address
+	// TxData is the calldata of the transaction.
+	TxData []byte
+	// GasLimit is the gas limit of the transaction. It must be set, as the
+	// Queue doesn't estimate gas.
+	GasLimit uint64
+	// FeePolicy optionally adjusts the suggested gas tip cap, given the base
+	// fee of the L1 head. If it returns an error, the transaction isn't
+	// crafted and the error is reported as the result. If nil, the suggested
+	// tip is used. Fee bumps of the transaction are limited to the adjusted
+	// tip too, and skipped while the fee policy returns an error.
+	FeePolicy func(baseFee, gasTipCap *big.Int) (*big.Int, error)
+}
+
+// TxReceipt is the result of sending a tx candidate with the given ID. Either
+// Receipt or Err is set.
+type TxReceipt[T any] struct {
+	// ID is the ID that the tx candidate was queued with.
+	ID T
+	// Receipt is the receipt of the confirmed transaction.
+	Receipt *types.Receipt
+	// Err is set if the transaction couldn't be crafted or confirmed.
+	Err error
+}
+
+// QueueBackend is the set of methods that the Queue uses in addition to the
+// ETHBackend of the transaction manager, to assign nonces.
+type QueueBackend interface {
+	ETHBackend
+
+	// PendingNonceAt returns the next nonce of the account, including
+	// transactions in the pending state.
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// Queue sends tx candidates in parallel with the SimpleTxManager. It assigns
+// nonces locally, in the order in which the candidates are queued, so that
+// callers don't need to manage nonces themselves.
+//
+// If a transaction fails, the local nonce is resynchronized with the pending
+// nonce of the backend before the next transaction is crafted, so that the
+// nonce gap left by the failed transaction is filled. Nonces of transactions
+// that are still in flight are skipped, so they never get assigned twice.
+// Candidates whose nonce got used by another transaction are re-crafted with a
+// fresh nonce.
+type Queue[T any] struct {
+	ctx     context.Context
+	mgr     *SimpleTxManager
+	backend QueueBackend
+	chainID *big.Int
+	l       log.Logger
+
+	// pending limits the number of concurrently sent transactions. nil if
+	// unlimited.
+	pending chan struct{}
+	wg      sync.WaitGroup
+
+	// onPublish is called with every transaction before it is published. nil
+	// if not set.
+	onPublish func(id T, tx *types.Transaction)
+
+	// nonceMu guards the nonce state and serializes crafting, so that nonces
+	// are assigned in queuing order.
+	nonceMu sync.Mutex
+	// nonce is the next nonce to assign. nil if it must be fetched from the
+	// backend first.
+	nonce *uint64
+	// inFlight are the nonces of the crafted transactions that aren't
+	// confirmed or failed yet.
+	inFlight map[uint64]struct{}
+}
+
+// NewQueue creates a new Queue that sends transactions from cfg.From. The
+// passed context is the lifetime context of all sent transactions. At most
+// maxPending transactions are sent concurrently, 0 means no limit.
+func NewQueue[T any](ctx context.Context, name string, l log.Logger, m Metricer, cfg Config, backend QueueBackend, chainID *big.Int, maxPending uint64) *Queue[T] {
+	q := &Queue[T]{
+		ctx:      ctx,
+		mgr:      NewSimpleTxManager(name, l, m, cfg, backend),
+		backend:  backend,
+		chainID:  chainID,
+		l:        l.New("service", name),
+		inFlight: make(map[uint64]struct{}),
+	}
+	if maxPending > 0 {
+		q.pending = make(chan struct{}, maxPending)
+	}
+	return q
+}
+
+// SetPublishHook sets a function that is called with every transaction before
+// it is published: the initially crafted or resumed transaction, every fee bump
+// of it, and every re-crafted transaction. It allows callers to track all
+// transactions that might get included for a candidate. It is called from the
+// sending goroutines, and must be set before the first transaction is sent.
+func (q *Queue[T]) SetPublishHook(onPublish func(id T, tx *types.Transaction)) {
+	q.onPublish = onPublish
+}
+
+// Send crafts a transaction from the candidate and sends it in the background.
+// It blocks until fewer than the maximum number of transactions are pending.
+// The result is sent to receiptCh, unless the Queue's context is done.
+func (q *Queue[T]) Send(id T, candidate TxCandidate, receiptCh chan<- TxReceipt[T]) {
+	q.SendWithCallback(id, candidate, q.receiptCallback(receiptCh))
+}
+
+// SendWithCallback is like Send, but calls cb with the result instead. cb is
+// called from a background goroutine, or synchronously if crafting the
+// transaction fails.
+func (q *Queue[T]) SendWithCallback(id T, candidate TxCandidate, cb func(TxReceipt[T])) {
+	if _, err := q.trySend(id, candidate, cb); err != nil {
+		cb(TxReceipt[T]{ID: id, Err: err})
+	}
+}
+
+// TrySend is like Send, but returns the errors that occur while crafting the
+// transaction instead of sending them to receiptCh, so that it can be called
+// from the goroutine that reads receiptCh. On success, it returns the crafted
+// transaction. The transaction may be re-crafted with a different nonce if its
+// nonce gets used by another transaction.
+func (q *Queue[T]) TrySend(id T, candidate TxCandidate, receiptCh chan<- TxReceipt[T]) (*types.Transaction, error) {
+	return q.trySend(id, candidate, q.receiptCallback(receiptCh))
+}
+
+// Resume sends a transaction that was crafted and sent before, e.g. by a
+// previous run of the service, and is still pending. Its nonce is skipped by
+// newly crafted transactions while it is in flight. The result is sent to
+// receiptCh, unless the Queue's context is done.
+func (q *Queue[T]) Resume(id T, tx *types.Transaction, receiptCh chan<- TxReceipt[T]) {
+	if err := q.acquire(); err != nil {
+		q.receiptCallback(receiptCh)(TxReceipt[T]{ID: id, Err: err})
+		return
+	}
+	q.nonceMu.Lock()
+	q.inFlight[tx.Nonce()] = struct{}{}
+	q.nonceMu.Unlock()
+	q.sendAsync(id, tx, nil, q.receiptCallback(receiptCh))
+}
+
+func (q *Queue[T]) trySend(id T, candidate TxCandidate, cb func(TxReceipt[T])) (*types.Transaction, error) {
+	if err := q.acquire(); err != nil {
+		return nil, err
+	}
+	// Craft synchronously, so that nonces are assigned in queuing order.
+	tx, err := q.craftTx(candidate)
+	if err != nil {
+		q.release()
+		return nil, err
+	}
+	q.sendAsync(id, tx, &candidate, cb)
+	return tx, nil
+}
+
+func (q *Queue[T]) sendAsync(id T, tx *types.Transaction, candidate *TxCandidate, cb func(TxReceipt[T])) {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		defer q.release()
+		receipt, err := q.send(id, tx, candidate)
+		cb(TxReceipt[T]{ID: id, Receipt: receipt, Err: err})
+	}()
+}
+
+func (q *Queue[T]) receiptCallback(receiptCh chan<- TxReceipt[T]) func(TxReceipt[T]) {
+	return func(r TxReceipt[T]) {
+		select {
+		case receiptCh <- r:
+		case <-q.ctx.Done():
+		}
+	}
+}
+
+// Wait blocks until all sent transactions got confirmed or failed.
+func (q *Queue[T]) Wait() {
+	q.wg.Wait()
+}
+
+// acquire blocks until fewer than the maximum number of transactions are
+// pending, or the Queue's context is done.
+func (q *Queue[T]) acquire() error {
+	if q.pending == nil {
+		return nil
+	}
+	select {
+	case q.pending <- struct{}{}:
+		return nil
+	case <-q.ctx.Done():
+		return q.ctx.Err()
+	}
+}
+
+func (q *Queue[T]) release() {
+	if q.pending != nil {
+		<-q.pending
+	}
+}
+
+// send sends the transaction with the transaction manager. If its nonce got
+// used by another transaction and the candidate is known, the candidate is
+// re-crafted with a fresh nonce and sent again. If configured, sending is
+// aborted after the TxSendTimeout.
+func (q *Queue[T]) send(id T, tx *types.Transaction, candidate *TxCandidate) (*types.Receipt, error) {
+	ctx, cancel := q.ctx, context.CancelFunc(func() {})
+	if q.mgr.Config.TxSendTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, q.mgr.Config.TxSendTimeout)
+	}
+	defer cancel()
+	var onPublish func(*types.Transaction)
+	if q.onPublish != nil {
+		onPublish = func(tx *types.Transaction) { q.onPublish(id, tx) }
+	}
+	var feePolicy func(baseFee, gasTipCap *big.Int) (*big.Int, error)
+	if candidate != nil {
+		feePolicy = candidate.FeePolicy
+	}
+	for i := 0; ; i++ {
+		receipt, err := q.mgr.send(ctx, tx, onPublish, feePolicy)
+		// The nonce of a failed transaction may be left unused, so the local
+		// nonce has to be resynchronized.
+		q.nonceDone(tx.Nonce(), err != nil)
+		if err == nil {
+			return receipt, nil
+		}
+		if candidate == nil || !errors.Is(err, core.ErrNonceTooLow) || i >= maxNonceTooLowRetries {
+			return nil, err
+		}
+		q.l.Warn("Nonce of transaction got used, resending with new nonce", "nonce", tx.Nonce(), "err", err)
+		if tx, err = q.craftTx(*candidate); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// craftTx creates and signs a transaction from the candidate, at the currently
+// suggested fees as adjusted by the candidate's fee policy, and with the next
+// local nonce.
+func (q *Queue[T]) craftTx(candidate TxCandidate) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(q.ctx, networkTimeout)
+	defer cancel()
+
+	gasTipCap, err := q.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas tip cap: %w", err)
+	} else if gasTipCap == nil {
+		return nil, errors.New("the suggested tip was nil")
+	}
+	head, err := q.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L1 head: %w", err)
+	} else if head.BaseFee == nil {
+		return nil, errors.New("txmgr does not support pre-london blocks that do not have a basefee")
+	}
+	if candidate.FeePolicy != nil {
+		if gasTipCap, err = candidate.FeePolicy(head.BaseFee, gasTipCap); err != nil {
+			return nil, err
+		}
+	}
+
+	q.nonceMu.Lock()
+	defer q.nonceMu.Unlock()
+
+	if q.nonce == nil {
+		nonce, err := q.backend.PendingNonceAt(ctx, q.mgr.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nonce: %w", err)
+		}
+		q.nonce = &nonce
+	}
+	for {
+		if _, ok := q.inFlight[*q.nonce]; !ok {
+			break
+		}
+		*q.nonce++
+	}
+
+	to := candidate.To
+	rawTx := &types.DynamicFeeTx{
+		ChainID:   q.chainID,
+		Nonce:     *q.nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: CalcGasFeeCap(head.BaseFee, gasTipCap),
+		Gas:       candidate.GasLimit,
+		To:        &to,
+		Data:      candidate.TxData,
+	}
+	tx, err := q.mgr.Signer(ctx, q.mgr.From, types.NewTx(rawTx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	q.inFlight[*q.nonce] = struct{}{}
+	*q.nonce++
+	return tx, nil
+}
+
+// nonceDone marks the transaction with the given nonce as no longer in flight.
+// If it failed, the next crafted transaction fetches its nonce from the
+// backend again.
+func (q *Queue[T]) nonceDone(nonce uint64, failed bool) {
+	q.nonceMu.Lock()
+	defer q.nonceMu.Unlock()
+	delete(q.inFlight, nonce)
+	if failed {
+		q.nonce = nil
+	}
+}
@@ -0,0 +1,327 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// queueBackend extends the mockBackend with nonce tracking and records all
+// published transactions.
+type queueBackend struct {
+	*mockBackend
+
+	mu           sync.Mutex
+	pendingNonce uint64
+	nonceErr     error
+	sent         map[common.Hash]*types.Transaction
+}
+
+func newQueueBackend() *queueBackend {
+	b := &queueBackend{
+		mockBackend: newMockBackend(newGasPricer(1)),
+		sent:        make(map[common.Hash]*types.Transaction),
+	}
+	b.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if tx.Nonce() < b.pendingNonce {
+			return core.ErrNonceTooLow
+		}
+		b.sent[tx.Hash()] = tx
+		txHash := tx.Hash()
+		b.mine(&txHash, tx.GasFeeCap())
+		return nil
+	})
+	return b
+}
+
+func (b *queueBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pendingNonce, b.nonceErr
+}
+
+func (b *queueBackend) sentTx(txHash common.Hash) *types.Transaction {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sent[txHash]
+}
+
+func newTestQueue(t *testing.T, backend *queueBackend, maxPending uint64) *Queue[int] {
+	cfg := configWithNumConfs(1)
+	cfg.ResubmissionTimeout = 100 * time.Millisecond
+	cfg.ReceiptQueryInterval = 10 * time.Millisecond
+	cfg.SafeAbortNonceTooLowCount = 1
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return NewQueue[int](ctx, "TEST", testlog.Logger(t, log.LvlCrit), NoopMetrics, cfg, backend, big.NewInt(1), maxPending)
+}
+
+// TestQueueSequentialNonces asserts that the Queue assigns sequential nonces in
+// queuing order and reports all receipts.
+func TestQueueSequentialNonces(t *testing.T) {
+	t.Parallel()
+
+	backend := newQueueBackend()
+	backend.pendingNonce = 7
+	q := newTestQueue(t, backend, 2)
+
+	const numTxs = 5
+	receiptCh := make(chan TxReceipt[int], numTxs)
+	for i := 0; i < numTxs; i++ {
+		q.Send(i, TxCandidate{To: common.Address{0x42}, TxData: []byte{byte(i)}, GasLimit: 21_000 + uint64(i)}, receiptCh)
+	}
+	q.Wait()
+	close(receiptCh)
+
+	nonces := make(map[int]uint64)
+	for r := range receiptCh {
+		require.NoError(t, r.Err)
+		tx := backend.sentTx(r.Receipt.TxHash)
+		require.NotNil(t, tx)
+		require.Equal(t, []byte{byte(r.ID)}, tx.Data())
+		require.Equal(t, 21_000+uint64(r.ID), tx.Gas())
+		nonces[r.ID] = tx.Nonce()
+	}
+	require.Len(t, nonces, numTxs)
+	for i := 0; i < numTxs; i++ {
+		require.Equal(t, uint64(7+i), nonces[i])
+	}
+}
+
+// TestQueueNonceTooLowResend asserts that the Queue resynchronizes its nonce
+// with the backend after a transaction fails with ErrNonceTooLow.
+func TestQueueNonceTooLowResend(t *testing.T) {
+	t.Parallel()
+
+	backend := newQueueBackend()
+	q := newTestQueue(t, backend, 0)
+	// The queue's local nonce is 0, but the account already used nonces up to 4.
+	zero := uint64(0)
+	q.nonce = &zero
+	backend.pendingNonce = 5
+
+	receiptCh := make(chan TxReceipt[int], 1)
+	q.Send(0, TxCandidate{GasLimit: 21_000}, receiptCh)
+	r := <-receiptCh
+	require.NoError(t, r.Err)
+	require.Equal(t, uint64(5), backend.sentTx(r.Receipt.TxHash).Nonce())
+	require.Equal(t, uint64(6), *q.nonce)
+}
+
+// TestQueuePublishHook asserts that the publish hook gets called with every
+// published transaction, including re-crafted ones.
+func TestQueuePublishHook(t *testing.T) {
+	t.Parallel()
+
+	backend := newQueueBackend()
+	q := newTestQueue(t, backend, 0)
+	zero := uint64(0)
+	q.nonce = &zero
+	backend.pendingNonce = 5
+
+	var (
+		mu        sync.Mutex
+		published []*types.Transaction
+	)
+	q.SetPublishHook(func(id int, tx *types.Transaction) {
+		require.Equal(t, 3, id)
+		mu.Lock()
+		defer mu.Unlock()
+		published = append(published, tx)
+	})
+
+	receiptCh := make(chan TxReceipt[int], 1)
+	q.Send(3, TxCandidate{GasLimit: 21_000}, receiptCh)
+	r := <-receiptCh
+	require.NoError(t, r.Err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, published, 2)
+	require.Equal(t, uint64(0), published[0].Nonce())
+	require.Equal(t, uint64(5), published[1].Nonce())
+	require.Equal(t, r.Receipt.TxHash, published[1].Hash())
+}
+
+// TestQueueCraftError asserts that crafting errors are reported as results and
+// don't consume a nonce.
+func TestQueueCraftError(t *testing.T) {
+	t.Parallel()
+
+	backend := newQueueBackend()
+	backend.nonceErr = errors.New("boom")
+	q := newTestQueue(t, backend, 1)
+
+	receiptCh := make(chan TxReceipt[int], 1)
+	q.Send(0, TxCandidate{GasLimit: 21_000}, receiptCh)
+	r := <-receiptCh
+	require.ErrorIs(t, r.Err, backend.nonceErr)
+	require.Nil(t, r.Receipt)
+	require.Nil(t, q.nonce)
+
+	// The pending slot got released.
+	backend.mu.Lock()
+	backend.nonceErr = nil
+	backend.mu.Unlock()
+	q.Send(1, TxCandidate{GasLimit: 21_000}, receiptCh)
+	r = <-receiptCh
+	require.NoError(t, r.Err)
+}
+
+// TestQueueResyncSkipsInFlightNonces asserts that nonces of transactions that
+// are still in flight aren't assigned again after the nonce got resynchronized
+// with the backend.
+func TestQueueResyncSkipsInFlightNonces(t *testing.T) {
+	t.Parallel()
+
+	backend := newQueueBackend()
+	backend.pendingNonce = 3
+	q := newTestQueue(t, backend, 0)
+	// Transactions with nonces 3 and 4 are still in flight when the local
+	// nonce gets resynchronized after another transaction failed.
+	q.inFlight[3] = struct{}{}
+	q.inFlight[4] = struct{}{}
+
+	receiptCh := make(chan TxReceipt[int], 1)
+	tx, err := q.TrySend(0, TxCandidate{GasLimit: 21_000}, receiptCh)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), tx.Nonce())
+	r := <-receiptCh
+	require.NoError(t, r.Err)
+	require.Equal(t, tx.Hash(), r.Receipt.TxHash)
+}
+
+// TestQueueFeePolicy asserts that the fee policy of a candidate is applied, and
+// that candidates rejected by their fee policy don't consume a nonce.
+func TestQueueFeePolicy(t *testing.T) {
+	t.Parallel()
+
+	backend := newQueueBackend()
+	q := newTestQueue(t, backend, 1)
+	receiptCh := make(chan TxReceipt[int], 1)
+
+	errDefer := errors.New("deferred")
+	_, err := q.TrySend(0, TxCandidate{
+		GasLimit: 21_000,
+		FeePolicy: func(baseFee, gasTipCap *big.Int) (*big.Int, error) {
+			return nil, errDefer
+		},
+	}, receiptCh)
+	require.ErrorIs(t, err, errDefer)
+	require.Nil(t, q.nonce)
+	require.Empty(t, q.inFlight)
+
+	var policyBaseFee *big.Int
+	tx, err := q.TrySend(1, TxCandidate{
+		GasLimit: 21_000,
+		FeePolicy: func(baseFee, gasTipCap *big.Int) (*big.Int, error) {
+			policyBaseFee = baseFee
+			return gasTipCap, nil
+		},
+	}, receiptCh)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), tx.Nonce())
+	require.NotNil(t, policyBaseFee)
+	r := <-receiptCh
+	require.NoError(t, r.Err)
+	require.Equal(t, 1, r.ID)
+}
+
+// TestQueueFeePolicyBumps asserts that fee bumps of a transaction don't raise
+// its tip above the tip that its fee policy allows.
+func TestQueueFeePolicyBumps(t *testing.T) {
+	t.Parallel()
+
+	backend := newQueueBackend()
+	var (
+		mu    sync.Mutex
+		sends int
+		tips  []*big.Int
+	)
+	backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sends++
+		tips = append(tips, tx.GasTipCap())
+		// Only mine the tx after it got resubmitted a few times.
+		if sends == 4 {
+			txHash := tx.Hash()
+			backend.mine(&txHash, tx.GasFeeCap())
+		}
+		return nil
+	})
+	q := newTestQueue(t, backend, 1)
+
+	var maxTipCap *big.Int
+	receiptCh := make(chan TxReceipt[int], 1)
+	_, err := q.TrySend(0, TxCandidate{
+		GasLimit: 21_000,
+		FeePolicy: func(baseFee, gasTipCap *big.Int) (*big.Int, error) {
+			// Cap the tip at the first suggestion.
+			if maxTipCap == nil {
+				maxTipCap = gasTipCap
+			}
+			return maxTipCap, nil
+		},
+	}, receiptCh)
+	require.NoError(t, err)
+	r := <-receiptCh
+	require.NoError(t, r.Err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, tips, 4)
+	for _, tip := range tips {
+		require.LessOrEqual(t, tip.Cmp(maxTipCap), 0, "tip %v above max %v", tip, maxTipCap)
+	}
+}
+
+// TestQueueResume asserts that a resumed transaction is sent again, and that
+// its nonce isn't assigned to new transactions while it is in flight.
+func TestQueueResume(t *testing.T) {
+	t.Parallel()
+
+	backend := newQueueBackend()
+	q := newTestQueue(t, backend, 0)
+	tip, feeCap := backend.g.sample()
+	resumed := types.NewTx(&types.DynamicFeeTx{Nonce: 0, Gas: 21_000, GasTipCap: tip, GasFeeCap: feeCap})
+
+	// keep the resumed tx in flight until the new tx got crafted
+	release := make(chan struct{})
+	send := backend.send
+	backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		if tx.Hash() == resumed.Hash() {
+			<-release
+		}
+		return send(ctx, tx)
+	})
+
+	receiptCh := make(chan TxReceipt[int], 2)
+	q.Resume(0, resumed, receiptCh)
+	tx, err := q.TrySend(1, TxCandidate{GasLimit: 21_000}, receiptCh)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), tx.Nonce())
+	close(release)
+
+	q.Wait()
+	for i := 0; i < 2; i++ {
+		r := <-receiptCh
+		require.NoError(t, r.Err)
+		if r.ID == 0 {
+			require.Equal(t, resumed.Hash(), r.Receipt.TxHash)
+		}
+	}
+}
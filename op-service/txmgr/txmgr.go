@@ -3,14 +3,18 @@ package txmgr
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 
 	opcrypto "github.com/ethereum-optimism/optimism/op-service/crypto"
 )
@@ -23,6 +27,10 @@ const priceBump int64 = 15
 var priceBumpPercent = big.NewInt(100 + priceBump)
 var oneHundred = big.NewInt(100)
 
+// ErrTxCancelled is returned by Send if the transaction got cancelled because
+// it wasn't mined within the CancelTimeout.
+var ErrTxCancelled = errors.New("transaction cancelled")
+
 // UpdateGasPriceSendTxFunc defines a function signature for publishing a
 // desired tx with a specific gas price. Implementations of this signature
 // should also return promptly when the context is canceled.
@@ -52,6 +60,16 @@ type Config struct {
 	// confirmation.
 	SafeAbortNonceTooLowCount uint64
 
+	// CancelTimeout is the duration after which a transaction that hasn't been
+	// mined yet is cancelled, by replacing it with a zero-value self-transfer
+	// at the same nonce. This frees up the nonce for later transactions. 0
+	// disables cancellation.
+	CancelTimeout time.Duration
+
+	// TxSendTimeout is the maximum duration that the Queue spends on sending a
+	// single transaction, including resubmissions. 0 means no timeout.
+	TxSendTimeout time.Duration
+
 	// Signer is used to sign transactions when the gas price is increased.
 	Signer opcrypto.SignerFn
 	From   common.Address
@@ -67,7 +85,9 @@ type TxManager interface {
 	//
 	// The initial transaction MUST be signed & ready to submit.
 	//
-	// NOTE: Send should be called by AT MOST one caller at a time.
+	// NOTE: Send should be called by AT MOST one caller at a time, unless the
+	// nonces of the sent transactions are managed by the caller, like the Queue
+	// does.
 	Send(ctx context.Context, tx *types.Transaction) (*types.Receipt, error)
 }
 
@@ -99,6 +119,7 @@ type SimpleTxManager struct {
 
 	backend ETHBackend
 	l       log.Logger
+	metr    Metricer
 }
 
 // IncreaseGasPrice takes the previous transaction & potentially clones then signs it with a higher tip.
@@ -109,6 +130,14 @@ type SimpleTxManager struct {
 // We do not re-estimate the amount of gas used because for some stateful transactions (like output proposals) the
 // act of including the transaction renders the repeat of the transaction invalid.
 func (m *SimpleTxManager) IncreaseGasPrice(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	return m.increaseGasPrice(ctx, tx, nil)
+}
+
+// increaseGasPrice is like IncreaseGasPrice, but applies the optional fee
+// policy of the transaction, see TxCandidate.FeePolicy, to the suggested tip.
+// The tip is never bumped above the tip that the fee policy allows, and the
+// transaction isn't bumped while the fee policy defers its submission.
+func (m *SimpleTxManager) increaseGasPrice(ctx context.Context, tx *types.Transaction, feePolicy func(baseFee, gasTipCap *big.Int) (*big.Int, error)) (*types.Transaction, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -122,6 +151,23 @@ func (m *SimpleTxManager) IncreaseGasPrice(ctx context.Context, tx *types.Transa
 		gasTipCap = tip
 	}
 
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	} else if head.BaseFee == nil {
+		return nil, errors.New("txmgr does not support pre-london blocks that do not have a basefee")
+	}
+
+	// maxTipCap is the highest tip that the fee policy allows, nil if unlimited.
+	var maxTipCap *big.Int
+	if feePolicy != nil {
+		if maxTipCap, err = feePolicy(head.BaseFee, gasTipCap); err != nil {
+			m.l.Debug("Not bumping the fees of the tx", "reason", err)
+			return tx, nil
+		}
+		gasTipCap = maxTipCap
+	}
+
 	// Return the same transaction if we don't update any fields.
 	// We do this because ethereum signatures are not deterministic and therefore the transaction hash will change
 	// when we re-sign the tx. We don't want to do that because we want to see ErrAlreadyKnown instead of ErrReplacementUnderpriced
@@ -136,18 +182,16 @@ func (m *SimpleTxManager) IncreaseGasPrice(ctx context.Context, tx *types.Transa
 		gasTipCap = tx.GasTipCap()
 		reusedTip = true
 	} else if thresholdTip.Cmp(gasTipCap) > 0 {
+		if maxTipCap != nil && thresholdTip.Cmp(maxTipCap) > 0 {
+			m.l.Debug("Not bumping the tip above the max tip of the fee policy", "previous", tx.GasTipCap(), "max", maxTipCap)
+			return tx, nil
+		}
 		m.l.Debug("Overriding the tip to enforce a price bump", "previous", tx.GasTipCap(), "suggested", gasTipCap, "new", thresholdTip)
 		gasTipCap = thresholdTip
 	}
 
-	if head, err := m.backend.HeaderByNumber(ctx, nil); err != nil {
-		return nil, err
-	} else if head.BaseFee == nil {
-		return nil, errors.New("txmgr does not support pre-london blocks that do not have a basefee")
-	} else {
-		// CalcGasFeeCap ensure that the fee cap is large enough for the tip.
-		gasFeeCap = CalcGasFeeCap(head.BaseFee, gasTipCap)
-	}
+	// CalcGasFeeCap ensure that the fee cap is large enough for the tip.
+	gasFeeCap = CalcGasFeeCap(head.BaseFee, gasTipCap)
 
 	// new = old * (100 + priceBump) / 100
 	// Enforce a min priceBump on the feeCap
@@ -192,7 +236,7 @@ func (m *SimpleTxManager) IncreaseGasPrice(ctx context.Context, tx *types.Transa
 }
 
 // NewSimpleTxManager initializes a new SimpleTxManager with the passed Config.
-func NewSimpleTxManager(name string, l log.Logger, cfg Config, backend ETHBackend) *SimpleTxManager {
+func NewSimpleTxManager(name string, l log.Logger, m Metricer, cfg Config, backend ETHBackend) *SimpleTxManager {
 	if cfg.NumConfirmations == 0 {
 		panic("txmgr: NumConfirmations cannot be zero")
 	}
@@ -202,6 +246,7 @@ func NewSimpleTxManager(name string, l log.Logger, cfg Config, backend ETHBacken
 		Config:  cfg,
 		backend: backend,
 		l:       l.New("service", name),
+		metr:    m,
 	}
 }
 
@@ -214,8 +259,33 @@ func NewSimpleTxManager(name string, l log.Logger, cfg Config, backend ETHBacken
 // When the transaction is resubmitted the tx manager will re-sign the transaction at a different gas pricing
 // but retain the gas used, the nonce, and the data.
 //
-// NOTE: Send should be called by AT MOST one caller at a time.
+// If the transaction's nonce got used by another transaction, Send returns an
+// error wrapping core.ErrNonceTooLow. If the transaction isn't mined within the
+// CancelTimeout, it gets cancelled and Send returns an error wrapping
+// ErrTxCancelled. If the passed context expires before the CancelTimeout, Send
+// keeps watching the transaction until the CancelTimeout and cancels it only
+// then.
+//
+// NOTE: Send should be called by AT MOST one caller at a time, unless the
+// nonces of the sent transactions are managed by the caller.
 func (m *SimpleTxManager) Send(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	return m.send(ctx, tx, nil, nil)
+}
+
+// send is like Send, but calls onPublish, if not nil, with every version of the
+// transaction before it is published, i.e. with the initial transaction and
+// with every fee bump of it. The fee bumps follow the optional fee policy, see
+// increaseGasPrice.
+func (m *SimpleTxManager) send(ctx context.Context, tx *types.Transaction, onPublish func(*types.Transaction), feePolicy func(baseFee, gasTipCap *big.Int) (*big.Int, error)) (*types.Receipt, error) {
+	// Every published version of the transaction is kept, because any of them
+	// may get mined.
+	var published []*types.Transaction
+	publish := func(tx *types.Transaction) {
+		published = append(published, tx)
+		if onPublish != nil {
+			onPublish(tx)
+		}
+	}
 
 	// Initialize a wait group to track any spawned goroutines, and ensure
 	// we properly clean up any dangling resources this method generates.
@@ -286,12 +356,21 @@ func (m *SimpleTxManager) Send(ctx context.Context, tx *types.Transaction) (*typ
 	// Submit and wait for the receipt at our first gas price in the
 	// background, before entering the event loop and waiting out the
 	// resubmission timeout.
+	publish(tx)
 	wg.Add(1)
 	go sendTxAsync(tx)
 
 	ticker := time.NewTicker(m.ResubmissionTimeout)
 	defer ticker.Stop()
 
+	// A nil channel blocks forever, so cancellation is disabled by default.
+	var cancelDeadline <-chan time.Time
+	if m.CancelTimeout != 0 {
+		cancelTimer := time.NewTimer(m.CancelTimeout)
+		defer cancelTimer.Stop()
+		cancelDeadline = cancelTimer.C
+	}
+
 	for {
 		select {
 
@@ -307,20 +386,42 @@ func (m *SimpleTxManager) Send(ctx context.Context, tx *types.Transaction) (*typ
 			}
 
 			// Increase the gas price & submit the new transaction
-			newTx, err := m.IncreaseGasPrice(ctx, tx)
+			newTx, err := m.increaseGasPrice(ctx, tx, feePolicy)
 			if err != nil {
 				m.l.Error("Failed to increase the gas price for the tx", "err", err)
 				// Don't `continue` here so we resubmit the transaction with the same gas price.
-			} else {
+			} else if newTx.Hash() != tx.Hash() {
 				// Save the tx so we know it's gas price.
 				tx = newTx
+				publish(tx)
 			}
 			wg.Add(1)
 			go sendTxAsync(tx)
 
+		// The transaction didn't get mined in time, so it might be stuck and
+		// block its nonce.
+		case <-cancelDeadline:
+			cancelDeadline = nil
+			// A mined transaction only waits for confirmations.
+			if sendState.IsWaitingForConfirmation() {
+				continue
+			}
+			return m.cancelTx(ctx, tx, receiptChan)
+
 		// The passed context has been canceled, i.e. in the event of a
-		// shutdown.
+		// shutdown, or the submission got aborted.
 		case <-ctx.Done():
+			if sendState.ShouldAbortImmediately() {
+				return nil, fmt.Errorf("aborted transaction sending: %w", core.ErrNonceTooLow)
+			}
+			// If the caller gave up waiting before the CancelTimeout, the
+			// transaction may still get mined. It is watched on a context
+			// that outlives the caller's until the CancelTimeout passes, and
+			// only cancelled then if it's actually stuck.
+			if cancelDeadline != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) &&
+				!sendState.IsWaitingForConfirmation() {
+				return m.cancelDetached(published, cancelDeadline, &wg)
+			}
 			return nil, ctx.Err()
 
 		// The transaction has confirmed.
@@ -330,6 +431,156 @@ func (m *SimpleTxManager) Send(ctx context.Context, tx *types.Transaction) (*typ
 	}
 }
 
+// cancelTxTimeout is the maximum duration of cancelling a stuck transaction
+// after the caller of Send gave up waiting for it.
+const cancelTxTimeout = 5 * time.Minute
+
+// cancelDetached watches the published versions txs of a transaction after
+// the caller of Send gave up waiting for it. If any of them gets mined before
+// cancelDeadline fires, its receipt is returned. Otherwise, the latest version
+// gets cancelled like in cancelTx. The sending goroutines got canceled with the
+// caller's context, so the versions are watched on a detached context, and the
+// watching goroutines are tracked by wg.
+func (m *SimpleTxManager) cancelDetached(txs []*types.Transaction, cancelDeadline <-chan time.Time, wg *sync.WaitGroup) (*types.Receipt, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Only one version can be mined, since they all have the same nonce.
+	minedChan := make(chan *types.Receipt, 1)
+	for _, tx := range txs {
+		wg.Add(1)
+		go func(tx *types.Transaction) {
+			defer wg.Done()
+			if receipt, _ := m.waitMined(ctx, tx, nil); receipt != nil {
+				select {
+				case minedChan <- receipt:
+				default:
+				}
+			}
+		}(tx)
+	}
+
+	tx := txs[len(txs)-1]
+
+	m.l.Info("Caller stopped waiting for transaction, watching it until the cancel timeout",
+		"txHash", tx.Hash(), "nonce", tx.Nonce())
+	select {
+	case receipt := <-minedChan:
+		return receipt, nil
+	case <-cancelDeadline:
+	}
+	cCtx, cCancel := context.WithTimeout(ctx, cancelTxTimeout)
+	defer cCancel()
+	return m.cancelTx(cCtx, tx, minedChan)
+}
+
+// cancelTx replaces the stuck transaction tx with a zero-value self-transfer
+// at the same nonce and waits for either of them to be mined. If the original
+// transaction gets mined after all, its receipt is returned. Otherwise, an error
+// wrapping ErrTxCancelled is returned once the cancellation is confirmed.
+func (m *SimpleTxManager) cancelTx(ctx context.Context, tx *types.Transaction, receiptChan <-chan *types.Receipt) (*types.Receipt, error) {
+	log := m.l.New("txHash", tx.Hash(), "nonce", tx.Nonce())
+	log.Warn("Transaction not mined in time, cancelling it", "timeout", m.CancelTimeout)
+
+	cancelTx, err := m.craftCancelTx(ctx, tx)
+	if err != nil {
+		m.metr.RecordTxCancellation(TxCancellationFailed)
+		return nil, fmt.Errorf("failed to craft cancellation tx: %w", err)
+	}
+	log = log.New("cancelTxHash", cancelTx.Hash())
+
+	err = m.backend.SendTransaction(ctx, cancelTx)
+	switch {
+	case err == nil:
+		m.metr.RecordTxCancellation(TxCancellationPublished)
+		log.Info("Published cancellation tx", "gasTipCap", cancelTx.GasTipCap(), "gasFeeCap", cancelTx.GasFeeCap())
+	case strings.Contains(err.Error(), core.ErrNonceTooLow.Error()):
+		// The original transaction got mined in the meantime, so wait for
+		// its receipt.
+		log.Info("Transaction mined while cancelling it")
+	default:
+		m.metr.RecordTxCancellation(TxCancellationFailed)
+		return nil, fmt.Errorf("failed to publish cancellation tx: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cancelReceiptChan := make(chan *types.Receipt, 1)
+	if err == nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if receipt, _ := m.waitMined(ctx, cancelTx, nil); receipt != nil {
+				cancelReceiptChan <- receipt
+			}
+		}()
+	}
+
+	select {
+	case receipt := <-receiptChan:
+		log.Info("Transaction confirmed instead of its cancellation")
+		return receipt, nil
+	case receipt := <-cancelReceiptChan:
+		m.metr.RecordTxCancellation(TxCancellationConfirmed)
+		log.Warn("Transaction cancelled", "block", receipt.BlockNumber)
+		return nil, fmt.Errorf("%w: nonce %d used by cancellation tx %s", ErrTxCancelled, tx.Nonce(), cancelTx.Hash())
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// craftCancelTx creates a zero-value self-transfer with the nonce of tx. Its
+// fees are bumped by at least the price bump over the fees of tx, so that it
+// replaces tx in the mempool.
+func (m *SimpleTxManager) craftCancelTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	gasTipCap, err := m.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	} else if gasTipCap == nil {
+		return nil, errors.New("the suggested tip was nil")
+	}
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	} else if head.BaseFee == nil {
+		return nil, errors.New("txmgr does not support pre-london blocks that do not have a basefee")
+	}
+
+	gasTipCap = maxBig(gasTipCap, bumpFee(tx.GasTipCap()))
+	gasFeeCap := maxBig(CalcGasFeeCap(head.BaseFee, gasTipCap), bumpFee(tx.GasFeeCap()))
+
+	to := m.From
+	rawTx := &types.DynamicFeeTx{
+		ChainID:   tx.ChainId(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       params.TxGas,
+		To:        &to,
+		Value:     new(big.Int),
+	}
+	return m.Signer(ctx, m.From, types.NewTx(rawTx))
+}
+
+// bumpFee returns the fee increased by the price bump.
+func bumpFee(fee *big.Int) *big.Int {
+	// new = old * (100 + priceBump) / 100
+	bumped := new(big.Int).Mul(priceBumpPercent, fee)
+	return bumped.Div(bumped, oneHundred)
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
 // waitMined implements the core functionality of WaitMined, with the option to
 // pass in a SendState to record whether or not the transaction is mined.
 func (m *SimpleTxManager) waitMined(ctx context.Context, tx *types.Transaction, sendState *SendState) (*types.Receipt, error) {
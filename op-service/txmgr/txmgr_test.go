@@ -19,6 +19,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // testHarness houses the necessary resources to test the SimpleTxManager.
@@ -34,7 +35,7 @@ type testHarness struct {
 func newTestHarnessWithConfig(t *testing.T, cfg Config) *testHarness {
 	g := newGasPricer(3)
 	backend := newMockBackend(g)
-	mgr := NewSimpleTxManager("TEST", testlog.Logger(t, log.LvlCrit), cfg, backend)
+	mgr := NewSimpleTxManager("TEST", testlog.Logger(t, log.LvlCrit), NoopMetrics, cfg, backend)
 
 	return &testHarness{
 		cfg:       cfg,
@@ -649,6 +650,67 @@ func TestIncreaseGasPriceEnforcesMinBump(t *testing.T) {
 	require.True(t, newTx.GasTipCap().Cmp(tx.GasTipCap()) > 0, "new tx tip must be larger")
 }
 
+// TestIncreaseGasPriceFeePolicy asserts that fee bumps don't raise the tip
+// above the max tip of the fee policy, and are skipped while the fee policy
+// defers the submission.
+func TestIncreaseGasPriceFeePolicy(t *testing.T) {
+	t.Parallel()
+
+	borkedBackend := failingBackend{
+		gasTip:  big.NewInt(300),
+		baseFee: big.NewInt(460),
+	}
+
+	mgr := &SimpleTxManager{
+		Config: Config{
+			ResubmissionTimeout:       time.Second,
+			ReceiptQueryInterval:      50 * time.Millisecond,
+			NumConfirmations:          1,
+			SafeAbortNonceTooLowCount: 3,
+			Signer: func(ctx context.Context, from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				return tx, nil
+			},
+			From: common.Address{},
+		},
+		name:    "TEST",
+		backend: &borkedBackend,
+		l:       testlog.Logger(t, log.LvlCrit),
+	}
+	maxTipCap := big.NewInt(200)
+	var deferred bool
+	feePolicy := func(baseFee, gasTipCap *big.Int) (*big.Int, error) {
+		if deferred {
+			return nil, errors.New("deferred")
+		}
+		if gasTipCap.Cmp(maxTipCap) > 0 {
+			return maxTipCap, nil
+		}
+		return gasTipCap, nil
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasTipCap: big.NewInt(100),
+		GasFeeCap: big.NewInt(1000),
+	})
+
+	// the suggested tip is capped
+	ctx := context.Background()
+	newTx, err := mgr.increaseGasPrice(ctx, tx, feePolicy)
+	require.NoError(t, err)
+	require.Equal(t, maxTipCap, newTx.GasTipCap())
+
+	// a tx at the max tip isn't bumped above it
+	bumpedTx, err := mgr.increaseGasPrice(ctx, newTx, feePolicy)
+	require.NoError(t, err)
+	require.Same(t, newTx, bumpedTx)
+
+	// a deferred tx isn't bumped
+	deferred = true
+	bumpedTx, err = mgr.increaseGasPrice(ctx, tx, feePolicy)
+	require.NoError(t, err)
+	require.Same(t, tx, bumpedTx)
+}
+
 // TestIncreaseGasPriceEnforcesMinBumpForBothOnTipIncrease asserts that if the gasTip goes up,
 // but the baseFee doesn't, both values are increased by 10%
 func TestIncreaseGasPriceEnforcesMinBumpForBothOnTipIncrease(t *testing.T) {
@@ -848,3 +910,281 @@ func TestIncreaseGasPriceReusesTransaction(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, tx.Hash(), newTx.Hash())
 }
+
+type cancellationMetrics struct {
+	mu     sync.Mutex
+	stages []string
+}
+
+func (m *cancellationMetrics) RecordTxCancellation(stage string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stages = append(m.stages, stage)
+}
+
+// TestTxMgrCancelStuckTx asserts that a tx that isn't mined within the
+// CancelTimeout gets replaced by a zero-value self-transfer at the same nonce
+// with bumped fees.
+func TestTxMgrCancelStuckTx(t *testing.T) {
+	t.Parallel()
+
+	cfg := configWithNumConfs(1)
+	cfg.CancelTimeout = 200 * time.Millisecond
+	cfg.From = common.Address{0x12}
+	g := newGasPricer(3)
+	backend := newMockBackend(g)
+	m := new(cancellationMetrics)
+	mgr := NewSimpleTxManager("TEST", testlog.Logger(t, log.LvlCrit), m, cfg, backend)
+
+	gasTipCap, gasFeeCap := g.sample()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		Nonce:     3,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       100_000,
+		To:        &common.Address{0x42},
+		Data:      []byte{0x01},
+	})
+
+	var mu sync.Mutex
+	var cancelTx *types.Transaction
+	backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		// Only the cancellation tx gets mined.
+		if *tx.To() == cfg.From {
+			mu.Lock()
+			cancelTx = tx
+			mu.Unlock()
+			txHash := tx.Hash()
+			backend.mine(&txHash, tx.GasFeeCap())
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	receipt, err := mgr.Send(ctx, tx)
+	require.ErrorIs(t, err, ErrTxCancelled)
+	require.Nil(t, receipt)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotNil(t, cancelTx)
+	require.Equal(t, tx.Nonce(), cancelTx.Nonce())
+	require.Zero(t, cancelTx.Value().Sign())
+	require.Empty(t, cancelTx.Data())
+	require.Equal(t, params.TxGas, cancelTx.Gas())
+	require.GreaterOrEqual(t, cancelTx.GasTipCap().Cmp(bumpFee(tx.GasTipCap())), 0)
+	require.GreaterOrEqual(t, cancelTx.GasFeeCap().Cmp(bumpFee(tx.GasFeeCap())), 0)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Equal(t, []string{TxCancellationPublished, TxCancellationConfirmed}, m.stages)
+}
+
+// TestTxMgrCancelStuckTxOnContextTimeout asserts that a tx that isn't mined
+// before the caller's context expires gets cancelled once the CancelTimeout
+// elapses, and not before.
+func TestTxMgrCancelStuckTxOnContextTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := configWithNumConfs(1)
+	cfg.CancelTimeout = 500 * time.Millisecond
+	cfg.From = common.Address{0x12}
+	g := newGasPricer(3)
+	backend := newMockBackend(g)
+	m := new(cancellationMetrics)
+	mgr := NewSimpleTxManager("TEST", testlog.Logger(t, log.LvlCrit), m, cfg, backend)
+
+	gasTipCap, gasFeeCap := g.sample()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		Nonce:     3,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       100_000,
+		To:        &common.Address{0x42},
+	})
+
+	var mu sync.Mutex
+	var cancelTx *types.Transaction
+	backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		// Only the cancellation tx gets mined, and its context must not be the
+		// expired context of the caller.
+		if *tx.To() == cfg.From {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			mu.Lock()
+			cancelTx = tx
+			mu.Unlock()
+			txHash := tx.Hash()
+			backend.mine(&txHash, tx.GasFeeCap())
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	receipt, err := mgr.Send(ctx, tx)
+	require.ErrorIs(t, err, ErrTxCancelled)
+	require.Nil(t, receipt)
+	require.GreaterOrEqual(t, time.Since(start), cfg.CancelTimeout)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotNil(t, cancelTx)
+	require.Equal(t, tx.Nonce(), cancelTx.Nonce())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Equal(t, []string{TxCancellationPublished, TxCancellationConfirmed}, m.stages)
+}
+
+// TestTxMgrMinedAfterContextTimeout asserts that a tx that gets mined after the
+// caller's context expired, but before the CancelTimeout, isn't cancelled.
+func TestTxMgrMinedAfterContextTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := configWithNumConfs(1)
+	cfg.CancelTimeout = time.Hour
+	g := newGasPricer(3)
+	backend := newMockBackend(g)
+	m := new(cancellationMetrics)
+	mgr := NewSimpleTxManager("TEST", testlog.Logger(t, log.LvlCrit), m, cfg, backend)
+
+	gasTipCap, gasFeeCap := g.sample()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		To:        &common.Address{0x42},
+	})
+	backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error { return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	time.AfterFunc(300*time.Millisecond, func() {
+		txHash := tx.Hash()
+		backend.mine(&txHash, tx.GasFeeCap())
+	})
+	receipt, err := mgr.Send(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), receipt.TxHash)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Empty(t, m.stages)
+}
+
+// TestTxMgrFirstVersionMinedAfterContextTimeout asserts that a tx isn't
+// cancelled if an earlier fee-bumped version of it gets mined after the
+// caller's context expired.
+func TestTxMgrFirstVersionMinedAfterContextTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := configWithNumConfs(1)
+	cfg.ResubmissionTimeout = 20 * time.Millisecond
+	cfg.CancelTimeout = time.Hour
+	g := newGasPricer(3)
+	backend := newMockBackend(g)
+	m := new(cancellationMetrics)
+	mgr := NewSimpleTxManager("TEST", testlog.Logger(t, log.LvlCrit), m, cfg, backend)
+
+	gasTipCap, gasFeeCap := g.sample()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		To:        &common.Address{0x42},
+	})
+	var mu sync.Mutex
+	var published []common.Hash
+	backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(published) == 0 || published[len(published)-1] != tx.Hash() {
+			published = append(published, tx.Hash())
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	time.AfterFunc(300*time.Millisecond, func() {
+		txHash := tx.Hash()
+		backend.mine(&txHash, tx.GasFeeCap())
+	})
+	receipt, err := mgr.Send(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), receipt.TxHash)
+
+	mu.Lock()
+	require.Greater(t, len(published), 1, "the tx must have been fee bumped")
+	mu.Unlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Empty(t, m.stages)
+}
+
+// TestTxMgrNoCancelOnShutdown asserts that a tx isn't cancelled if the caller's
+// context gets canceled, e.g. during a shutdown.
+func TestTxMgrNoCancelOnShutdown(t *testing.T) {
+	t.Parallel()
+
+	cfg := configWithNumConfs(1)
+	cfg.CancelTimeout = time.Hour
+	g := newGasPricer(3)
+	backend := newMockBackend(g)
+	m := new(cancellationMetrics)
+	mgr := NewSimpleTxManager("TEST", testlog.Logger(t, log.LvlCrit), m, cfg, backend)
+
+	gasTipCap, gasFeeCap := g.sample()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		To:        &common.Address{0x42},
+	})
+	backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error { return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	receipt, err := mgr.Send(ctx, tx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, receipt)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Empty(t, m.stages)
+}
+
+// TestTxMgrCancelMinedTx asserts that a tx that is mined while being cancelled
+// is reported as confirmed.
+func TestTxMgrCancelMinedTx(t *testing.T) {
+	t.Parallel()
+
+	cfg := configWithNumConfs(1)
+	cfg.CancelTimeout = 200 * time.Millisecond
+	g := newGasPricer(3)
+	backend := newMockBackend(g)
+	mgr := NewSimpleTxManager("TEST", testlog.Logger(t, log.LvlCrit), NoopMetrics, cfg, backend)
+
+	gasTipCap, gasFeeCap := g.sample()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		To:        &common.Address{0x42},
+	})
+	txHash := tx.Hash()
+	backend.setTxSender(func(ctx context.Context, sent *types.Transaction) error {
+		// The original tx gets mined once its cancellation is published.
+		if sent.Hash() != txHash {
+			backend.mine(&txHash, tx.GasFeeCap())
+			return core.ErrNonceTooLow
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	receipt, err := mgr.Send(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, txHash, receipt.TxHash)
+}
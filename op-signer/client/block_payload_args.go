@@ -0,0 +1,50 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BlockPayloadArgs represents the arguments to sign an L2 block payload that
+// is gossiped on the p2p network of the rollup nodes.
+type BlockPayloadArgs struct {
+	// Domain is the signing domain of the message, separating block signatures
+	// from signatures of other messages.
+	Domain common.Hash `json:"domain"`
+	// ChainID is the L2 chain ID of the block.
+	ChainID *hexutil.Big `json:"chainId"`
+	// PayloadHash is the keccak256 hash of the encoded block payload.
+	PayloadHash common.Hash `json:"payloadHash"`
+	// SenderAddress is the address of the key to sign with.
+	SenderAddress *common.Address `json:"senderAddress"`
+}
+
+// Check ensures that all arguments are set.
+func (args *BlockPayloadArgs) Check() error {
+	switch {
+	case args.ChainID == nil:
+		return errors.New("missing chain ID")
+	case args.SenderAddress == nil:
+		return errors.New("missing sender address")
+	case args.PayloadHash == (common.Hash{}):
+		return errors.New("missing payload hash")
+	}
+	return nil
+}
+
+// SigningHash returns the hash that is signed, keccak256(domain || chain_id || payload_hash),
+// as verified by the rollup nodes.
+func (args *BlockPayloadArgs) SigningHash() (common.Hash, error) {
+	chainID := args.ChainID.ToInt()
+	if chainID.BitLen() > 256 {
+		return common.Hash{}, errors.New("chain ID is too large")
+	}
+	var msgInput [32 + 32 + 32]byte
+	copy(msgInput[:32], args.Domain[:])
+	chainID.FillBytes(msgInput[32:64])
+	copy(msgInput[64:], args.PayloadHash[:])
+	return crypto.Keccak256Hash(msgInput[:]), nil
+}
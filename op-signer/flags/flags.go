@@ -0,0 +1,83 @@
+package flags
+
+import (
+	"github.com/urfave/cli"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+)
+
+const envVarPrefix = "OP_SIGNER"
+
+const (
+	KeyBackendKeystore = "keystore"
+	KeyBackendSoftHSM  = "softhsm"
+)
+
+var (
+	/* Required Flags */
+
+	ClientsConfigFlag = cli.StringFlag{
+		Name: "clients-config",
+		Usage: "Path to the JSON file with the authorized clients, identified by the common name of their " +
+			"TLS client certificate, and their allowlists",
+		Required: true,
+		EnvVar:   opservice.PrefixEnvVar(envVarPrefix, "CLIENTS_CONFIG"),
+	}
+	AuditLogFlag = cli.StringFlag{
+		Name:     "audit-log",
+		Usage:    "Path to the audit log file that all signing requests are appended to",
+		Required: true,
+		EnvVar:   opservice.PrefixEnvVar(envVarPrefix, "AUDIT_LOG"),
+	}
+
+	/* Optional Flags */
+
+	KeyBackendFlag = cli.StringFlag{
+		Name:   "key-backend",
+		Usage:  "Backend of the signing keys: " + KeyBackendKeystore + " or " + KeyBackendSoftHSM,
+		Value:  KeyBackendKeystore,
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "KEY_BACKEND"),
+	}
+	KeystoreDirFlag = cli.StringFlag{
+		Name:   "keystore.dir",
+		Usage:  "Directory of the encrypted keystore files, used by the keystore key backend",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "KEYSTORE_DIR"),
+	}
+	KeystorePasswordFileFlag = cli.StringFlag{
+		Name:   "keystore.password-file",
+		Usage:  "Path to the file with the password that unlocks all keystore files",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "KEYSTORE_PASSWORD_FILE"),
+	}
+	SoftHSMKeysFileFlag = cli.StringFlag{
+		Name: "softhsm.keys-file",
+		Usage: "Path to the JSON file mapping key labels to hex-encoded private keys, used by the softhsm " +
+			"key backend. For testing only, the keys are held in memory unprotected.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "SOFTHSM_KEYS_FILE"),
+	}
+)
+
+var requiredFlags = []cli.Flag{
+	ClientsConfigFlag,
+	AuditLogFlag,
+}
+
+var optionalFlags = []cli.Flag{
+	KeyBackendFlag,
+	KeystoreDirFlag,
+	KeystorePasswordFileFlag,
+	SoftHSMKeysFileFlag,
+}
+
+func init() {
+	optionalFlags = append(optionalFlags, oprpc.CLIFlags(envVarPrefix)...)
+	optionalFlags = append(optionalFlags, optls.CLIFlags(envVarPrefix)...)
+	optionalFlags = append(optionalFlags, oplog.CLIFlags(envVarPrefix)...)
+
+	Flags = append(requiredFlags, optionalFlags...)
+}
+
+// Flags contains the list of configuration options available to the binary.
+var Flags []cli.Flag
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AuditEntry is the audit record of a single signing request.
+type AuditEntry struct {
+	Time    time.Time       `json:"time"`
+	Client  string          `json:"client"`
+	Method  string          `json:"method"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	ChainID *hexutil.Big    `json:"chainId"`
+	Nonce   hexutil.Uint64  `json:"nonce"`
+	// TxHash is the hash of the signed transaction. Only set if the request
+	// got signed.
+	TxHash *common.Hash `json:"txHash,omitempty"`
+	// PayloadHash is the hash of the block payload of a block payload signing
+	// request.
+	PayloadHash *common.Hash `json:"payloadHash,omitempty"`
+	// Error is the reason why the request got rejected. Only set if the
+	// request didn't get signed.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLog records all signing requests.
+type AuditLog interface {
+	// Record appends the entry to the audit log. If it fails, the signature
+	// must not be handed out.
+	Record(entry AuditEntry) error
+}
+
+// FileAuditLog is an AuditLog that appends JSON encoded entries, one per line,
+// to a file. Every entry is synced to disk before Record returns.
+type FileAuditLog struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenFileAuditLog opens the audit log file at path for appending. The file is
+// created if it doesn't exist yet.
+func OpenFileAuditLog(path string) (*FileAuditLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &FileAuditLog{f: f}, nil
+}
+
+func (l *FileAuditLog) Record(entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.f.Write(data); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := l.f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+	return nil
+}
+
+func (l *FileAuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ClientConfig is the authorization of a single client.
+type ClientConfig struct {
+	// Name is the common name of the client's TLS certificate.
+	Name string `json:"name"`
+	// From is the address of the key that the client may sign with.
+	From common.Address `json:"from"`
+	// ChainIDs are the chain IDs that the client may sign transactions for.
+	ChainIDs []uint64 `json:"chainIDs"`
+	// ToAddresses are the recipients that the client may sign transactions
+	// for. Contract creations are never allowed.
+	ToAddresses []common.Address `json:"toAddresses"`
+	// BlockPayloads allows the client to sign L2 block payloads for the p2p
+	// network of the rollup nodes, for the listed chain IDs.
+	BlockPayloads bool `json:"blockPayloads,omitempty"`
+}
+
+// ClientsConfig is the authorization config of all clients.
+type ClientsConfig struct {
+	Clients []ClientConfig `json:"clients"`
+}
+
+// LoadClientsConfig reads the ClientsConfig from the JSON file at path.
+func LoadClientsConfig(path string) (*ClientsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clients config: %w", err)
+	}
+	var cfg ClientsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode clients config: %w", err)
+	}
+	if err := cfg.Check(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Check ensures that the [ClientsConfig] is valid.
+func (c *ClientsConfig) Check() error {
+	names := make(map[string]bool)
+	for _, client := range c.Clients {
+		if client.Name == "" {
+			return errors.New("client name must not be empty")
+		}
+		if names[client.Name] {
+			return fmt.Errorf("duplicate client %q", client.Name)
+		}
+		names[client.Name] = true
+		if len(client.ChainIDs) == 0 || (len(client.ToAddresses) == 0 && !client.BlockPayloads) {
+			return fmt.Errorf("client %q must allow at least one chain ID, and to address or block payloads", client.Name)
+		}
+	}
+	return nil
+}
+
+// Authorize checks that the named client may sign a transaction from the given
+// address, to the given recipient on the given chain. It returns an error
+// wrapping ErrUnauthorized if not.
+func (c *ClientsConfig) Authorize(name string, from common.Address, to *common.Address, chainID *big.Int) error {
+	client, err := c.authorizeClient(name, from, chainID)
+	if err != nil {
+		return err
+	}
+	if to == nil {
+		return fmt.Errorf("%w: contract creation", ErrUnauthorized)
+	}
+	for _, addr := range client.ToAddresses {
+		if addr == *to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: client %q may not sign transactions to %s", ErrUnauthorized, name, to)
+}
+
+// AuthorizeBlockPayload checks that the named client may sign an L2 block
+// payload with the key of the given address on the given chain. It returns an
+// error wrapping ErrUnauthorized if not.
+func (c *ClientsConfig) AuthorizeBlockPayload(name string, from common.Address, chainID *big.Int) error {
+	client, err := c.authorizeClient(name, from, chainID)
+	if err != nil {
+		return err
+	}
+	if !client.BlockPayloads {
+		return fmt.Errorf("%w: client %q may not sign block payloads", ErrUnauthorized, name)
+	}
+	return nil
+}
+
+// authorizeClient returns the config of the named client, if it may sign with
+// the key of the given address on the given chain.
+func (c *ClientsConfig) authorizeClient(name string, from common.Address, chainID *big.Int) (*ClientConfig, error) {
+	var client *ClientConfig
+	for i := range c.Clients {
+		if c.Clients[i].Name == name {
+			client = &c.Clients[i]
+			break
+		}
+	}
+	if client == nil {
+		return nil, fmt.Errorf("%w: unknown client %q", ErrUnauthorized, name)
+	}
+	if from != client.From {
+		return nil, fmt.Errorf("%w: client %q may not sign for %s", ErrUnauthorized, name, from)
+	}
+	if !containsChainID(client.ChainIDs, chainID) {
+		return nil, fmt.Errorf("%w: client %q may not sign for chain %v", ErrUnauthorized, name, chainID)
+	}
+	return client, nil
+}
+
+func containsChainID(ids []uint64, chainID *big.Int) bool {
+	if !chainID.IsUint64() {
+		return false
+	}
+	for _, id := range ids {
+		if id == chainID.Uint64() {
+			return true
+		}
+	}
+	return false
+}
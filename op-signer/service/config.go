@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	"github.com/ethereum-optimism/optimism/op-signer/flags"
+)
+
+// CLIConfig is a well typed config that is parsed from the CLI params.
+type CLIConfig struct {
+	// ClientsConfig is the path to the JSON file with the authorized clients.
+	ClientsConfig string
+
+	// AuditLog is the path to the audit log file.
+	AuditLog string
+
+	// KeyBackend is the name of the backend of the signing keys.
+	KeyBackend string
+
+	// KeystoreDir is the directory of the keystore files of the keystore
+	// key backend.
+	KeystoreDir string
+
+	// KeystorePasswordFile is the path to the file with the password of the
+	// keystore files.
+	KeystorePasswordFile string
+
+	// SoftHSMKeysFile is the path to the JSON file with the keys of the
+	// softhsm key backend.
+	SoftHSMKeysFile string
+
+	RPCConfig oprpc.CLIConfig
+
+	TLSConfig optls.CLIConfig
+
+	LogConfig oplog.CLIConfig
+}
+
+func (c CLIConfig) Check() error {
+	if err := c.RPCConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.TLSConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.LogConfig.Check(); err != nil {
+		return err
+	}
+	switch c.KeyBackend {
+	case flags.KeyBackendKeystore:
+		if c.KeystoreDir == "" || c.KeystorePasswordFile == "" {
+			return errors.New("keystore key backend requires the keystore dir and password file")
+		}
+	case flags.KeyBackendSoftHSM:
+		if c.SoftHSMKeysFile == "" {
+			return errors.New("softhsm key backend requires the keys file")
+		}
+	default:
+		return fmt.Errorf("unknown key backend %q", c.KeyBackend)
+	}
+	return nil
+}
+
+// NewConfig parses the Config from the provided flags or environment variables.
+func NewConfig(ctx *cli.Context) CLIConfig {
+	return CLIConfig{
+		ClientsConfig:        ctx.GlobalString(flags.ClientsConfigFlag.Name),
+		AuditLog:             ctx.GlobalString(flags.AuditLogFlag.Name),
+		KeyBackend:           ctx.GlobalString(flags.KeyBackendFlag.Name),
+		KeystoreDir:          ctx.GlobalString(flags.KeystoreDirFlag.Name),
+		KeystorePasswordFile: ctx.GlobalString(flags.KeystorePasswordFileFlag.Name),
+		SoftHSMKeysFile:      ctx.GlobalString(flags.SoftHSMKeysFileFlag.Name),
+		RPCConfig:            oprpc.ReadCLIConfig(ctx),
+		TLSConfig:            optls.ReadCLIConfig(ctx),
+		LogConfig:            oplog.ReadCLIConfig(ctx),
+	}
+}
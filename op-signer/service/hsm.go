@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// HSM is a minimal PKCS#11-style interface to a hardware security module. The
+// private keys never leave the module. They are referenced by their labels and
+// only used to sign digests.
+type HSM interface {
+	// KeyLabels returns the labels of all secp256k1 keys of the module.
+	KeyLabels() ([]string, error)
+	// PublicKey returns the public key of the key with the given label.
+	PublicKey(label string) (*ecdsa.PublicKey, error)
+	// Sign signs the digest with the key with the given label, like the
+	// CKM_ECDSA mechanism. It returns the raw [R || S] signature, without a
+	// recovery id. S is not required to be in the lower half of the curve
+	// order.
+	Sign(label string, digest []byte) ([]byte, error)
+}
+
+// HSMBackend is a KeyBackend that signs with the keys of an HSM.
+type HSMBackend struct {
+	hsm HSM
+	// labels maps the address of each key to its label
+	labels map[common.Address]string
+	// pubKeys maps the address of each key to its uncompressed public key
+	pubKeys map[common.Address][]byte
+}
+
+var _ KeyBackend = (*HSMBackend)(nil)
+
+// NewHSMBackend creates a KeyBackend with all keys of the HSM.
+func NewHSMBackend(hsm HSM) (*HSMBackend, error) {
+	labels, err := hsm.KeyLabels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HSM keys: %w", err)
+	}
+	b := &HSMBackend{
+		hsm:     hsm,
+		labels:  make(map[common.Address]string),
+		pubKeys: make(map[common.Address][]byte),
+	}
+	for _, label := range labels {
+		pub, err := hsm.PublicKey(label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get public key %q: %w", label, err)
+		}
+		addr := crypto.PubkeyToAddress(*pub)
+		b.labels[addr] = label
+		b.pubKeys[addr] = crypto.FromECDSAPub(pub)
+	}
+	return b, nil
+}
+
+func (b *HSMBackend) Accounts() []common.Address {
+	var addrs []common.Address
+	for addr := range b.labels {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	return addrs
+}
+
+// SignHash signs the hash with the HSM and converts the raw signature into the
+// Ethereum format. S is normalized to the lower half of the curve order and
+// the recovery id is determined by recovering the public key.
+func (b *HSMBackend) SignHash(account common.Address, hash []byte) ([]byte, error) {
+	label, ok := b.labels[account]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAccount, account)
+	}
+	raw, err := b.hsm.Sign(label, hash)
+	if err != nil {
+		return nil, fmt.Errorf("HSM failed to sign: %w", err)
+	}
+	if len(raw) != 64 {
+		return nil, fmt.Errorf("invalid HSM signature length %d", len(raw))
+	}
+
+	secp256k1N := crypto.S256().Params().N
+	s := new(big.Int).SetBytes(raw[32:])
+	if s.Cmp(new(big.Int).Rsh(secp256k1N, 1)) > 0 {
+		s.Sub(secp256k1N, s)
+	}
+	sig := make([]byte, 65)
+	copy(sig[:32], raw[:32])
+	s.FillBytes(sig[32:64])
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.Ecrecover(hash, sig)
+		if err == nil && bytes.Equal(pub, b.pubKeys[account]) {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("HSM signature doesn't match key %q", label)
+}
+
+// SoftHSM is a software stand-in for an HSM, to be used for testing. Its keys
+// are held in memory unprotected.
+type SoftHSM struct {
+	keys map[string]*ecdsa.PrivateKey
+}
+
+var _ HSM = (*SoftHSM)(nil)
+
+// NewSoftHSM creates a SoftHSM with the given keys, mapped by their labels.
+func NewSoftHSM(keys map[string]*ecdsa.PrivateKey) *SoftHSM {
+	return &SoftHSM{keys: keys}
+}
+
+// LoadSoftHSM creates a SoftHSM from the JSON file at path, which maps key
+// labels to hex-encoded private keys.
+func LoadSoftHSM(path string) (*SoftHSM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read softhsm keys: %w", err)
+	}
+	var hexKeys map[string]string
+	if err := json.Unmarshal(data, &hexKeys); err != nil {
+		return nil, fmt.Errorf("failed to decode softhsm keys: %w", err)
+	}
+	keys := make(map[string]*ecdsa.PrivateKey)
+	for label, hexKey := range hexKeys {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid softhsm key %q: %w", label, err)
+		}
+		keys[label] = key
+	}
+	return NewSoftHSM(keys), nil
+}
+
+func (h *SoftHSM) KeyLabels() ([]string, error) {
+	var labels []string
+	for label := range h.keys {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+func (h *SoftHSM) PublicKey(label string) (*ecdsa.PublicKey, error) {
+	key, ok := h.keys[label]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", label)
+	}
+	return &key.PublicKey, nil
+}
+
+func (h *SoftHSM) Sign(label string, digest []byte) ([]byte, error) {
+	key, ok := h.keys[label]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", label)
+	}
+	sig, err := crypto.Sign(digest, key)
+	if err != nil {
+		return nil, err
+	}
+	// Drop the recovery id, like a real HSM.
+	return sig[:64], nil
+}
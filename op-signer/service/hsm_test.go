@@ -0,0 +1,63 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// highSHSM is an HSM that returns signatures with S in the upper half of the
+// curve order, like many real HSMs do.
+type highSHSM struct {
+	*SoftHSM
+}
+
+func (h highSHSM) Sign(label string, digest []byte) ([]byte, error) {
+	sig, err := h.SoftHSM.Sign(label, digest)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).SetBytes(sig[32:])
+	s.Sub(crypto.S256().Params().N, s)
+	s.FillBytes(sig[32:])
+	return sig, nil
+}
+
+func TestHSMBackendSignHash(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	soft := NewSoftHSM(map[string]*ecdsa.PrivateKey{"key": key})
+
+	for name, hsm := range map[string]HSM{"low-s": soft, "high-s": highSHSM{soft}} {
+		t.Run(name, func(t *testing.T) {
+			b, err := NewHSMBackend(hsm)
+			require.NoError(t, err)
+			require.Equal(t, []common.Address{addr}, b.Accounts())
+
+			// sign many hashes to hit both recovery ids
+			for i := 0; i < 16; i++ {
+				hash := crypto.Keccak256([]byte{byte(i)})
+				sig, err := b.SignHash(addr, hash)
+				require.NoError(t, err)
+				require.Len(t, sig, 65)
+
+				pub, err := crypto.SigToPub(hash, sig)
+				require.NoError(t, err)
+				require.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+				require.True(t, crypto.ValidateSignatureValues(sig[64], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), true))
+			}
+		})
+	}
+}
+
+func TestHSMBackendUnknownAccount(t *testing.T) {
+	b, err := NewHSMBackend(NewSoftHSM(nil))
+	require.NoError(t, err)
+	_, err = b.SignHash(common.Address{1}, make([]byte, 32))
+	require.ErrorIs(t, err, ErrUnknownAccount)
+}
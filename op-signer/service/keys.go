@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrUnknownAccount = errors.New("unknown account")
+
+// KeyBackend holds the signing keys of the signer service.
+type KeyBackend interface {
+	// Accounts returns the addresses of all keys of the backend.
+	Accounts() []common.Address
+	// SignHash signs the 32 byte hash with the key of the given account. It
+	// returns the signature in the [R || S || V] format, with V being 0 or 1.
+	SignHash(account common.Address, hash []byte) ([]byte, error)
+}
+
+// KeystoreBackend is a KeyBackend of encrypted keystore files in a local
+// directory. All keys are unlocked at startup.
+type KeystoreBackend struct {
+	ks *keystore.KeyStore
+}
+
+var _ KeyBackend = (*KeystoreBackend)(nil)
+
+// NewKeystoreBackend loads all keystore files in dir and unlocks them with the
+// password in passwordFile.
+func NewKeystoreBackend(dir string, passwordFile string) (*KeystoreBackend, error) {
+	data, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore password: %w", err)
+	}
+	password := strings.TrimRight(string(data), "\r\n")
+
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	if len(ks.Accounts()) == 0 {
+		return nil, fmt.Errorf("no keys in keystore %s", dir)
+	}
+	for _, acc := range ks.Accounts() {
+		if err := ks.Unlock(acc, password); err != nil {
+			return nil, fmt.Errorf("failed to unlock key %s: %w", acc.Address, err)
+		}
+	}
+	return &KeystoreBackend{ks: ks}, nil
+}
+
+func (b *KeystoreBackend) Accounts() []common.Address {
+	var addrs []common.Address
+	for _, acc := range b.ks.Accounts() {
+		addrs = append(addrs, acc.Address)
+	}
+	return addrs
+}
+
+func (b *KeystoreBackend) SignHash(account common.Address, hash []byte) ([]byte, error) {
+	if !b.ks.HasAddress(account) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAccount, account)
+	}
+	return b.ks.SignHash(accounts.Account{Address: account}, hash)
+}
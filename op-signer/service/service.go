@@ -0,0 +1,132 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli"
+
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/tls/certman"
+	"github.com/ethereum-optimism/optimism/op-signer/flags"
+)
+
+// Main is the entrypoint into the signer service. This method executes the
+// service and blocks until the service exits.
+func Main(version string, cliCtx *cli.Context) error {
+	cfg := NewConfig(cliCtx)
+	if err := cfg.Check(); err != nil {
+		return fmt.Errorf("invalid CLI flags: %w", err)
+	}
+
+	l := oplog.NewLogger(cfg.LogConfig)
+	l.Info("Initializing signer service")
+
+	clients, err := LoadClientsConfig(cfg.ClientsConfig)
+	if err != nil {
+		return err
+	}
+	keys, err := newKeyBackend(cfg)
+	if err != nil {
+		return err
+	}
+	for _, addr := range keys.Accounts() {
+		l.Info("Loaded signing key", "backend", cfg.KeyBackend, "address", addr)
+	}
+	audit, err := OpenFileAuditLog(cfg.AuditLog)
+	if err != nil {
+		return err
+	}
+	defer audit.Close()
+
+	tlsConfig, err := newServerTLSConfig(cfg)
+	if err != nil {
+		return err
+	}
+	// certman watches for newer server certificates and automatically reloads them
+	cm, err := certman.New(l, cfg.TLSConfig.TLSCert, cfg.TLSConfig.TLSKey)
+	if err != nil {
+		return fmt.Errorf("failed to read tls cert or key: %w", err)
+	}
+	if err := cm.Watch(); err != nil {
+		return fmt.Errorf("failed to start certman watcher: %w", err)
+	}
+	defer cm.Stop()
+	tlsConfig.GetCertificate = cm.GetCertificate
+
+	signer := NewSignerService(l, clients, keys, audit)
+	rpcCfg := cfg.RPCConfig
+	server := oprpc.NewServer(
+		rpcCfg.ListenAddr,
+		rpcCfg.ListenPort,
+		version,
+		oprpc.WithLogger(l),
+		oprpc.WithAPIs([]rpc.API{{
+			Namespace: "eth",
+			Service:   signer,
+		}, {
+			Namespace: "opsigner",
+			Service:   NewOpSignerService(signer),
+		}}),
+		oprpc.WithTLSConfig(&oprpc.ServerTLSConfig{
+			Config:    tlsConfig,
+			CLIConfig: &cfg.TLSConfig,
+		}),
+	)
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("error starting RPC server: %w", err)
+	}
+	defer func() { _ = server.Stop() }()
+	l.Info("Signer service started", "endpoint", server.Endpoint())
+
+	interruptChannel := make(chan os.Signal, 1)
+	signal.Notify(interruptChannel, []os.Signal{
+		os.Interrupt,
+		os.Kill,
+		syscall.SIGTERM,
+		syscall.SIGQUIT,
+	}...)
+	<-interruptChannel
+
+	return nil
+}
+
+func newKeyBackend(cfg CLIConfig) (KeyBackend, error) {
+	switch cfg.KeyBackend {
+	case flags.KeyBackendKeystore:
+		return NewKeystoreBackend(cfg.KeystoreDir, cfg.KeystorePasswordFile)
+	case flags.KeyBackendSoftHSM:
+		hsm, err := LoadSoftHSM(cfg.SoftHSMKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewHSMBackend(hsm)
+	default:
+		return nil, fmt.Errorf("unknown key backend %q", cfg.KeyBackend)
+	}
+}
+
+// newServerTLSConfig returns the TLS config of the RPC server, which requires
+// clients to authenticate with a certificate signed by the configured CA.
+func newServerTLSConfig(cfg CLIConfig) (*tls.Config, error) {
+	caCert, err := os.ReadFile(cfg.TLSConfig.TLSCaCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls.ca: %w", err)
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCert) {
+		return nil, errors.New("no certificates in tls.ca")
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  caCertPool,
+	}, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	"github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+// SignerService implements the eth_signTransaction RPC method for
+// authenticated and authorized clients. Block payloads are signed through the
+// OpSignerService.
+type SignerService struct {
+	log     log.Logger
+	clients *ClientsConfig
+	keys    KeyBackend
+	audit   AuditLog
+
+	// clientName returns the name of the client of the request. It defaults to
+	// the common name of the client's TLS certificate.
+	clientName func(ctx context.Context) (string, error)
+}
+
+func NewSignerService(l log.Logger, clients *ClientsConfig, keys KeyBackend, audit AuditLog) *SignerService {
+	return &SignerService{
+		log:        l,
+		clients:    clients,
+		keys:       keys,
+		audit:      audit,
+		clientName: clientNameFromTLS,
+	}
+}
+
+func clientNameFromTLS(ctx context.Context) (string, error) {
+	cert := optls.PeerTLSInfoFromContext(ctx).LeafCertificate
+	if cert == nil {
+		return "", fmt.Errorf("%w: no client certificate", ErrUnauthorized)
+	}
+	return cert.Subject.CommonName, nil
+}
+
+// SignTransaction signs the transaction and returns its binary encoding. The
+// request is recorded in the audit log, whether it got signed or not.
+func (s *SignerService) SignTransaction(ctx context.Context, args client.TransactionArgs) (hexutil.Bytes, error) {
+	if err := checkTransactionArgs(&args); err != nil {
+		return nil, err
+	}
+
+	name, err := s.clientName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chainID := (*big.Int)(args.ChainID)
+	entry := AuditEntry{
+		Time:    time.Now(),
+		Client:  name,
+		Method:  "eth_signTransaction",
+		From:    *args.From,
+		To:      args.To,
+		ChainID: args.ChainID,
+		Nonce:   *args.Nonce,
+	}
+
+	tx, err := s.signTransaction(name, chainID, &args)
+	if err != nil {
+		entry.Error = err.Error()
+		if aerr := s.audit.Record(entry); aerr != nil {
+			s.log.Error("Failed to record rejected request", "client", name, "err", aerr)
+		}
+		s.log.Warn("Rejected signing request", "client", name, "from", entry.From, "to", entry.To, "err", err)
+		return nil, err
+	}
+
+	txHash := tx.Hash()
+	entry.TxHash = &txHash
+	if err := s.audit.Record(entry); err != nil {
+		s.log.Error("Failed to record signature, withholding it", "client", name, "tx", txHash, "err", err)
+		return nil, errors.New("failed to record signature")
+	}
+	s.log.Info("Signed transaction", "client", name, "from", entry.From, "to", entry.To, "nonce", uint64(entry.Nonce), "tx", txHash)
+	return tx.MarshalBinary()
+}
+
+func (s *SignerService) signTransaction(name string, chainID *big.Int, args *client.TransactionArgs) (*types.Transaction, error) {
+	if err := s.clients.Authorize(name, *args.From, args.To, chainID); err != nil {
+		return nil, err
+	}
+
+	tx := args.ToTransaction()
+	signer := types.LatestSignerForChainID(chainID)
+	sig, err := s.keys.SignHash(*args.From, signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+func checkTransactionArgs(args *client.TransactionArgs) error {
+	switch {
+	case args.From == nil:
+		return errors.New("missing from address")
+	case args.ChainID == nil:
+		return errors.New("missing chain ID")
+	case args.Nonce == nil:
+		return errors.New("missing nonce")
+	case args.Gas == nil:
+		return errors.New("missing gas limit")
+	case args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil:
+		return errors.New("missing fee parameters")
+	}
+	return nil
+}
+
+// signBlockPayload signs the L2 block payload and returns the signature. The
+// request is recorded in the audit log, whether it got signed or not.
+func (s *SignerService) signBlockPayload(ctx context.Context, args client.BlockPayloadArgs) (hexutil.Bytes, error) {
+	if err := args.Check(); err != nil {
+		return nil, err
+	}
+
+	name, err := s.clientName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := args.PayloadHash
+	entry := AuditEntry{
+		Time:        time.Now(),
+		Client:      name,
+		Method:      "opsigner_signBlockPayload",
+		From:        *args.SenderAddress,
+		ChainID:     args.ChainID,
+		PayloadHash: &payloadHash,
+	}
+
+	sig, err := s.signBlockPayloadHash(name, &args)
+	if err != nil {
+		entry.Error = err.Error()
+		if aerr := s.audit.Record(entry); aerr != nil {
+			s.log.Error("Failed to record rejected request", "client", name, "err", aerr)
+		}
+		s.log.Warn("Rejected block payload signing request", "client", name, "from", entry.From, "err", err)
+		return nil, err
+	}
+
+	if err := s.audit.Record(entry); err != nil {
+		s.log.Error("Failed to record signature, withholding it", "client", name, "payload", payloadHash, "err", err)
+		return nil, errors.New("failed to record signature")
+	}
+	s.log.Info("Signed block payload", "client", name, "from", entry.From, "payload", payloadHash)
+	return sig, nil
+}
+
+func (s *SignerService) signBlockPayloadHash(name string, args *client.BlockPayloadArgs) ([]byte, error) {
+	if err := s.clients.AuthorizeBlockPayload(name, *args.SenderAddress, args.ChainID.ToInt()); err != nil {
+		return nil, err
+	}
+	hash, err := args.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.keys.SignHash(*args.SenderAddress, hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign block payload: %w", err)
+	}
+	return sig, nil
+}
+
+// OpSignerService implements the opsigner_signBlockPayload RPC method of the
+// SignerService, for authenticated and authorized clients.
+type OpSignerService struct {
+	s *SignerService
+}
+
+func NewOpSignerService(s *SignerService) *OpSignerService {
+	return &OpSignerService{s: s}
+}
+
+// SignBlockPayload signs the L2 block payload and returns the signature in the
+// [R || S || V] format, with V being 0 or 1. The request is recorded in the
+// audit log, whether it got signed or not.
+func (o *OpSignerService) SignBlockPayload(ctx context.Context, args client.BlockPayloadArgs) (hexutil.Bytes, error) {
+	return o.s.signBlockPayload(ctx, args)
+}
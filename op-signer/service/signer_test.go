@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+type memAuditLog struct {
+	entries []AuditEntry
+	err     error
+}
+
+func (l *memAuditLog) Record(entry AuditEntry) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+var (
+	testChainID = big.NewInt(10)
+	testTo      = common.Address{0xaa}
+)
+
+func setupSigner(t *testing.T) (*SignerService, *memAuditLog, common.Address) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	keys, err := NewHSMBackend(NewSoftHSM(map[string]*ecdsa.PrivateKey{"batcher": key}))
+	require.NoError(t, err)
+	clients := &ClientsConfig{Clients: []ClientConfig{{
+		Name:        "batcher",
+		From:        from,
+		ChainIDs:    []uint64{testChainID.Uint64()},
+		ToAddresses: []common.Address{testTo},
+	}}}
+	audit := new(memAuditLog)
+	s := NewSignerService(testlog.Logger(t, log.LvlInfo), clients, keys, audit)
+	s.clientName = func(context.Context) (string, error) { return "batcher", nil }
+	return s, audit, from
+}
+
+func testTxArgs(from common.Address, to *common.Address, chainID *big.Int) client.TransactionArgs {
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21_000,
+		To:        to,
+		Data:      []byte{1, 2, 3},
+	})
+	return *client.NewTransactionArgsFromTransaction(chainID, from, tx)
+}
+
+func TestSignTransaction(t *testing.T) {
+	s, audit, from := setupSigner(t)
+	to := testTo
+
+	raw, err := s.SignTransaction(context.Background(), testTxArgs(from, &to, testChainID))
+	require.NoError(t, err)
+
+	var tx types.Transaction
+	require.NoError(t, tx.UnmarshalBinary(raw))
+	sender, err := types.Sender(types.LatestSignerForChainID(testChainID), &tx)
+	require.NoError(t, err)
+	require.Equal(t, from, sender)
+	require.Equal(t, uint64(7), tx.Nonce())
+
+	require.Len(t, audit.entries, 1)
+	entry := audit.entries[0]
+	require.Equal(t, "batcher", entry.Client)
+	require.Equal(t, from, entry.From)
+	require.Equal(t, hexutil.Uint64(7), entry.Nonce)
+	require.Equal(t, tx.Hash(), *entry.TxHash)
+	require.Empty(t, entry.Error)
+}
+
+func TestSignTransactionUnauthorized(t *testing.T) {
+	other := common.Address{0xbb}
+	to := testTo
+	tests := []struct {
+		name    string
+		client  string
+		from    func(common.Address) common.Address
+		to      *common.Address
+		chainID *big.Int
+	}{
+		{"unknown client", "proposer", nil, &to, testChainID},
+		{"wrong from", "batcher", func(common.Address) common.Address { return other }, &to, testChainID},
+		{"wrong to", "batcher", nil, &other, testChainID},
+		{"contract creation", "batcher", nil, nil, testChainID},
+		{"wrong chain ID", "batcher", nil, &to, big.NewInt(11)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, audit, from := setupSigner(t)
+			s.clientName = func(context.Context) (string, error) { return tt.client, nil }
+			if tt.from != nil {
+				from = tt.from(from)
+			}
+
+			raw, err := s.SignTransaction(context.Background(), testTxArgs(from, tt.to, tt.chainID))
+			require.ErrorIs(t, err, ErrUnauthorized)
+			require.Nil(t, raw)
+
+			require.Len(t, audit.entries, 1)
+			require.Nil(t, audit.entries[0].TxHash)
+			require.NotEmpty(t, audit.entries[0].Error)
+		})
+	}
+}
+
+func TestSignTransactionAuditFailure(t *testing.T) {
+	s, audit, from := setupSigner(t)
+	audit.err = errors.New("disk full")
+	to := testTo
+
+	raw, err := s.SignTransaction(context.Background(), testTxArgs(from, &to, testChainID))
+	require.Error(t, err)
+	require.Nil(t, raw)
+}
+
+func TestSignTransactionNoClientCert(t *testing.T) {
+	s, audit, from := setupSigner(t)
+	s.clientName = clientNameFromTLS
+	to := testTo
+
+	_, err := s.SignTransaction(context.Background(), testTxArgs(from, &to, testChainID))
+	require.ErrorIs(t, err, ErrUnauthorized)
+	require.Empty(t, audit.entries)
+}
+
+func TestSignTransactionMissingArgs(t *testing.T) {
+	s, _, from := setupSigner(t)
+	to := testTo
+	args := testTxArgs(from, &to, testChainID)
+	args.Nonce = nil
+
+	_, err := s.SignTransaction(context.Background(), args)
+	require.ErrorContains(t, err, "missing nonce")
+}
+
+func testBlockPayloadArgs(from common.Address, chainID *big.Int) client.BlockPayloadArgs {
+	return client.BlockPayloadArgs{
+		Domain:        common.Hash{},
+		ChainID:       (*hexutil.Big)(chainID),
+		PayloadHash:   crypto.Keccak256Hash([]byte("payload")),
+		SenderAddress: &from,
+	}
+}
+
+func TestSignBlockPayload(t *testing.T) {
+	s, audit, from := setupSigner(t)
+	s.clients.Clients[0].BlockPayloads = true
+	args := testBlockPayloadArgs(from, testChainID)
+
+	sig, err := NewOpSignerService(s).SignBlockPayload(context.Background(), args)
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+
+	hash, err := args.SigningHash()
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(hash[:], sig)
+	require.NoError(t, err)
+	require.Equal(t, from, crypto.PubkeyToAddress(*pub))
+
+	require.Len(t, audit.entries, 1)
+	entry := audit.entries[0]
+	require.Equal(t, "opsigner_signBlockPayload", entry.Method)
+	require.Equal(t, args.PayloadHash, *entry.PayloadHash)
+	require.Empty(t, entry.Error)
+}
+
+func TestSignBlockPayloadUnauthorized(t *testing.T) {
+	tests := []struct {
+		name          string
+		blockPayloads bool
+		from          func(common.Address) common.Address
+		chainID       *big.Int
+	}{
+		{"block payloads not allowed", false, nil, testChainID},
+		{"wrong from", true, func(common.Address) common.Address { return common.Address{0xbb} }, testChainID},
+		{"wrong chain ID", true, nil, big.NewInt(11)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, audit, from := setupSigner(t)
+			s.clients.Clients[0].BlockPayloads = tt.blockPayloads
+			if tt.from != nil {
+				from = tt.from(from)
+			}
+
+			sig, err := NewOpSignerService(s).SignBlockPayload(context.Background(), testBlockPayloadArgs(from, tt.chainID))
+			require.ErrorIs(t, err, ErrUnauthorized)
+			require.Nil(t, sig)
+
+			require.Len(t, audit.entries, 1)
+			require.NotEmpty(t, audit.entries[0].Error)
+		})
+	}
+}
+
+func TestSignBlockPayloadMissingArgs(t *testing.T) {
+	s, _, from := setupSigner(t)
+	s.clients.Clients[0].BlockPayloads = true
+	args := testBlockPayloadArgs(from, testChainID)
+	args.ChainID = nil
+
+	_, err := NewOpSignerService(s).SignBlockPayload(context.Background(), args)
+	require.ErrorContains(t, err, "missing chain ID")
+}